@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newDependenciesCmd(e shipyard.Engine) *cobra.Command {
+	var variables []string
+	var variablesFile string
+
+	dependenciesCmd := &cobra.Command{
+		Use:   "dependencies [file] | [directory]",
+		Short: "List the external dependencies of a blueprint",
+		Long: `List the external dependencies of a blueprint.
+Shows the images, remote sources, Helm charts, host paths, and host ports
+the blueprint needs, use this to check a blueprint can be run on another
+machine or in an air-gapped environment.`,
+		Example: `
+  shipyard dependencies ./blueprint
+	`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// parse the vars into a map
+			vars := map[string]string{}
+			for _, v := range variables {
+				parts := strings.Split(v, "=")
+				if len(parts) == 2 {
+					vars[parts[0]] = parts[1]
+				}
+			}
+
+			d, err := e.ExternalDependencies(args[0], vars, variablesFile)
+			if err != nil {
+				return fmt.Errorf("Unable to determine dependencies: %s", err)
+			}
+
+			printDependencies(cmd, "Images", d.Images)
+			printDependencies(cmd, "Sources", d.Sources)
+			printDependencies(cmd, "Helm charts", d.HelmCharts)
+			printDependencies(cmd, "Host paths", d.HostPaths)
+			printDependencies(cmd, "Host ports", d.Ports)
+
+			return nil
+		},
+	}
+
+	dependenciesCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	dependenciesCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+
+	return dependenciesCmd
+}
+
+func printDependencies(cmd *cobra.Command, title string, deps []string) {
+	if len(deps) == 0 {
+		return
+	}
+
+	cmd.Printf("%s (%d):\n", title, len(deps))
+	for _, d := range deps {
+		cmd.Printf("  %s\n", d)
+	}
+
+	cmd.Println("")
+}
@@ -5,12 +5,18 @@ import (
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
 	"github.com/shipyard-run/shipyard/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 func newDestroyCmd(cc clients.Connector) *cobra.Command {
-	return &cobra.Command{
+	var verify bool
+	var continueOnError bool
+	var resource string
+	var force bool
+
+	destroyCmd := &cobra.Command{
 		Use:   "destroy [file]",
 		Short: "Destroy the current stack or file",
 		Long: `Destroy the current stack or file. 
@@ -18,6 +24,16 @@ func newDestroyCmd(cc clients.Connector) *cobra.Command {
 	in the file will be destroyed`,
 		Example: `yard destroy`,
 		Run: func(cmd *cobra.Command, args []string) {
+			// destroy a single resource leaving the rest of the stack
+			if resource != "" {
+				err := engine.DestroyResource(resource, force)
+				if err != nil {
+					hclog.Default().Error("Unable to destroy resource", "ref", resource, "error", err)
+				}
+
+				return
+			}
+
 			dst := ""
 			if len(args) > 0 {
 				dst = args[0]
@@ -26,18 +42,27 @@ func newDestroyCmd(cc clients.Connector) *cobra.Command {
 			// When destroying a stack all the config
 			// which is created with apply is copied
 			// to the state folder
-			var err error
-			if dst == "" {
-				err = engine.Destroy(dst, true)
-			} else {
-				err = engine.Destroy(dst, false)
-			}
+			err := engine.DestroyWithOptions(dst, shipyard.DestroyOptions{
+				AllResources:    dst == "",
+				ContinueOnError: continueOnError,
+			})
 
 			if err != nil {
 				hclog.Default().Error("Unable to destroy stack", "error", err)
 				return
 			}
 
+			if verify {
+				survived, err := engine.VerifyDestroy()
+				if err != nil {
+					hclog.Default().Error("Unable to verify destroyed resources", "error", err)
+				}
+
+				for _, s := range survived {
+					hclog.Default().Warn("Resource has been removed from the state but still exists", "ref", s)
+				}
+			}
+
 			if dst == "" {
 				// clean up the data folder
 				os.RemoveAll(utils.GetDataFolder(""))
@@ -55,4 +80,11 @@ func newDestroyCmd(cc clients.Connector) *cobra.Command {
 			}
 		},
 	}
+
+	destroyCmd.Flags().BoolVarP(&continueOnError, "continue-on-error", "", false, "When set resources which fail to destroy do not stop the remaining resources being destroyed, failed resources are kept in the state")
+	destroyCmd.Flags().StringVarP(&resource, "resource", "", "", "Destroy only the resource with the given id e.g. k8s_cluster.k3s and remove it from the state")
+	destroyCmd.Flags().BoolVarP(&force, "force", "", false, "When used with --resource the resource is destroyed even when other resources depend on it")
+	destroyCmd.Flags().BoolVarP(&verify, "verify", "", false, "When set Shipyard checks that the destroyed resources no longer exist and reports any which remain")
+
+	return destroyCmd
 }
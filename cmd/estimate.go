@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newEstimateCmd(e shipyard.Engine) *cobra.Command {
+	var variables []string
+	var variablesFile string
+
+	estimateCmd := &cobra.Command{
+		Use:   "estimate [file] | [directory]",
+		Short: "Estimate how long it will take to run a blueprint",
+		Long: `Estimate how long it will take to run a blueprint.
+The estimate uses the time taken to create resources in previous runs, when
+there is no history a default time for the resource type is used.`,
+		Example: `
+  shipyard estimate ./blueprint
+	`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// parse the vars into a map
+			vars := map[string]string{}
+			for _, v := range variables {
+				parts := strings.Split(v, "=")
+				if len(parts) == 2 {
+					vars[parts[0]] = parts[1]
+				}
+			}
+
+			d, err := e.EstimateApplyDuration(args[0], vars, variablesFile)
+			if err != nil {
+				return fmt.Errorf("Unable to estimate run time: %s", err)
+			}
+
+			cmd.Printf("Estimated time to run blueprint: %s\n", d.Round(time.Second))
+
+			return nil
+		},
+	}
+
+	estimateCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	estimateCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+
+	return estimateCmd
+}
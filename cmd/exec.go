@@ -51,7 +51,7 @@ func newExecCmd(dt clients.ContainerTasks) *cobra.Command {
 			}
 
 			switch r.Info().Type {
-			case config.TypeContainer:
+			case config.TypeContainer, config.TypeSidecar:
 				return createContainerShell(r, dt, command)
 			case config.TypeK8sCluster:
 				pod := ""
@@ -105,7 +105,7 @@ func createContainerShell(r config.Resource, dt clients.ContainerTasks, command
 	}
 
 	// find the container id
-	ids, err := dt.FindContainerIDs(r.Info().Name, config.TypeContainer)
+	ids, err := dt.FindContainerIDs(r.Info().Name, r.Info().Type)
 	if err != nil || len(ids) == 0 {
 		return fmt.Errorf("Unable to find container %s", r.Info().Name)
 	}
@@ -113,6 +113,11 @@ func createContainerShell(r config.Resource, dt clients.ContainerTasks, command
 	in, stdout, _ := term.StdStreams()
 	err = dt.CreateShell(ids[0], command, in, stdout, stdout)
 	if err != nil {
+		// return the exit code of the command so it is used by the process
+		if ee, ok := err.(clients.ExecExitError); ok {
+			return ee
+		}
+
 		return fmt.Errorf("Could not execute command for container %s. Error: %s", ids[0], err)
 	}
 
@@ -181,6 +186,10 @@ func createK8sShell(r config.Resource, dt clients.ContainerTasks, pod, container
 	in, stdout, _ := term.StdStreams()
 	err = dt.CreateShell(tools, append(exec, command...), in, stdout, stdout)
 	if err != nil {
+		if ee, ok := err.(clients.ExecExitError); ok {
+			return ee
+		}
+
 		return fmt.Errorf("Could not execute command for cluster %s. Error: %s", clusterName, err)
 	}
 
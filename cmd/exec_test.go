@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/docker/docker/pkg/ioutils"
+	"github.com/shipyard-run/shipyard/pkg/clients"
 	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
 	"github.com/shipyard-run/shipyard/pkg/config"
 	"github.com/shipyard-run/shipyard/pkg/utils"
@@ -263,3 +264,17 @@ var baseState = `
   ]
 }
 `
+
+func TestExecReturnsExitCodeFromCommand(t *testing.T) {
+	c, mt, cleanup := setupExec(baseState)
+	defer cleanup()
+
+	removeOn(&mt.Mock, "CreateShell")
+	mt.On("CreateShell", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(clients.ExecExitError{ExitCode: 3})
+
+	c.SetArgs([]string{"container.consul", "--", "false"})
+
+	err := c.Execute()
+	assert.Error(t, err)
+	assert.Equal(t, 3, ExitCode(err))
+}
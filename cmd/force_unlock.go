@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var forceUnlockCmd = &cobra.Command{
+	Use:   "force-unlock",
+	Short: "Remove the lock on the state",
+	Long: `Remove the lock on the state.
+The state is locked while run and destroy are changing resources, use this
+command to remove a lock left behind by a process which is no longer running.
+Removing the lock while another process is running can corrupt the state.`,
+	Example: `
+  shipyard force-unlock
+	`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := config.UnlockState()
+		if err != nil {
+			return fmt.Errorf("Unable to remove state lock: %s", err)
+		}
+
+		cmd.Println("State lock removed")
+
+		return nil
+	},
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newGraphCmd(e shipyard.Engine) *cobra.Command {
+	var variables []string
+	var variablesFile string
+
+	graphCmd := &cobra.Command{
+		Use:   "graph [file] | [directory]",
+		Short: "Output the dependency graph for a blueprint",
+		Long: `Output the dependency graph for a blueprint in Graphviz DOT format.
+The state is not read or changed.`,
+		Example: `
+  shipyard graph ./blueprint | dot -Tpng > graph.png
+	`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// parse the vars into a map
+			vars := map[string]string{}
+			for _, v := range variables {
+				parts := strings.Split(v, "=")
+				if len(parts) == 2 {
+					vars[parts[0]] = parts[1]
+				}
+			}
+
+			g, err := e.Graph(args[0], vars, variablesFile)
+			if err != nil {
+				return fmt.Errorf("Unable to create graph: %s", err)
+			}
+
+			cmd.Print(g)
+
+			return nil
+		},
+	}
+
+	graphCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	graphCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+
+	return graphCmd
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newHealthCmd(e shipyard.Engine) *cobra.Command {
+	return &cobra.Command{
+		Use:   "health",
+		Short: "Run the health checks for the current environment",
+		Long: `Run the health checks defined by the resources in the current environment.
+No resources are created or changed, the command exits with an error if any check fails.`,
+		Example: `
+  shipyard health
+	`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, err := e.RunHealthChecks()
+			if err != nil {
+				return fmt.Errorf("Unable to run health checks: %s", err)
+			}
+
+			failed := 0
+			for _, r := range res {
+				if r.Healthy() {
+					cmd.Printf("[PASS] %s %s %s\n", r.Resource, r.Check, r.Target)
+					continue
+				}
+
+				failed++
+				cmd.Printf("[FAIL] %s %s %s: %s\n", r.Resource, r.Check, r.Target, r.Error)
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d health checks failed", failed, len(res))
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newImportK8sCmd(e shipyard.Engine) *cobra.Command {
+	var namespace string
+	var output string
+
+	importCmd := &cobra.Command{
+		Use:   "import-k8s [cluster]",
+		Short: "Generate a blueprint from the resources deployed to a Kubernetes cluster",
+		Long: `Generate a blueprint from the Deployments, StatefulSets, Services, and ConfigMaps
+deployed to a namespace in a Kubernetes cluster. The manifests and a blueprint file
+are written to the output folder.
+
+Resources installed by Helm are added as helm resources, the chart for these resources
+is set to the name of the chart and must be updated to point to the chart source.`,
+		Example: `
+  shipyard import-k8s k8s_cluster.k3s --namespace default --output ./imported
+	`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, err := e.ImportK8s(args[0], namespace)
+			if err != nil {
+				return fmt.Errorf("Unable to import resources: %s", err)
+			}
+
+			out, err := filepath.Abs(output)
+			if err != nil {
+				return err
+			}
+
+			err = os.MkdirAll(filepath.Join(out, namespace), os.ModePerm)
+			if err != nil {
+				return fmt.Errorf("Unable to create output folder: %s", err)
+			}
+
+			hcl := bytes.NewBufferString("")
+
+			for _, r := range res {
+				switch v := r.(type) {
+				case *config.Helm:
+					fmt.Fprintf(hcl, "helm %s {\n", strconv.Quote(v.Name))
+					fmt.Fprintf(hcl, "  cluster   = %s\n", strconv.Quote(v.Cluster))
+					fmt.Fprintf(hcl, "  chart     = %s\n", strconv.Quote(v.Chart))
+					fmt.Fprintf(hcl, "  namespace = %s\n", strconv.Quote(v.Namespace))
+					fmt.Fprintf(hcl, "}\n\n")
+
+				case *config.K8sConfig:
+					// copy the manifests so the blueprint is self contained
+					paths := []string{}
+					for _, p := range v.Paths {
+						d, err := ioutil.ReadFile(p)
+						if err != nil {
+							return fmt.Errorf("Unable to read manifest %s: %s", p, err)
+						}
+
+						err = ioutil.WriteFile(filepath.Join(out, namespace, filepath.Base(p)), d, os.ModePerm)
+						if err != nil {
+							return fmt.Errorf("Unable to write manifest %s: %s", p, err)
+						}
+
+						paths = append(paths, strconv.Quote("./"+namespace+"/"+filepath.Base(p)))
+					}
+
+					fmt.Fprintf(hcl, "k8s_config %s {\n", strconv.Quote(v.Name))
+					fmt.Fprintf(hcl, "  cluster = %s\n\n", strconv.Quote(v.Cluster))
+					fmt.Fprintf(hcl, "  paths = [\n")
+					for _, p := range paths {
+						fmt.Fprintf(hcl, "    %s,\n", p)
+					}
+					fmt.Fprintf(hcl, "  ]\n\n")
+					fmt.Fprintf(hcl, "  wait_until_ready = %t\n", v.WaitUntilReady)
+					fmt.Fprintf(hcl, "}\n\n")
+				}
+			}
+
+			bp := filepath.Join(out, namespace+".hcl")
+			err = ioutil.WriteFile(bp, hcl.Bytes(), os.ModePerm)
+			if err != nil {
+				return fmt.Errorf("Unable to write blueprint: %s", err)
+			}
+
+			cmd.Printf("Imported %d resources to %s\n", len(res), bp)
+
+			return nil
+		},
+	}
+
+	importCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Kubernetes namespace to import")
+	importCmd.Flags().StringVarP(&output, "output", "o", ".", "Folder to write the blueprint and manifests to")
+
+	return importCmd
+}
@@ -1,8 +1,8 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
-	"encoding/binary"
 	"fmt"
 	"io"
 	"math/rand"
@@ -11,7 +11,6 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/docker/docker/api/types"
 	"github.com/fatih/color"
 	"github.com/hashicorp/go-hclog"
 	"github.com/spf13/cobra"
@@ -22,7 +21,9 @@ import (
 	"github.com/shipyard-run/shipyard/pkg/utils"
 )
 
-func newLogCmd(engine shipyard.Engine, dc clients.Docker, stdout, stderr io.Writer) *cobra.Command {
+func newLogCmd(engine shipyard.Engine, ct clients.ContainerTasks, kc clients.Kubernetes, stdout, stderr io.Writer) *cobra.Command {
+	opts := logOptions{}
+
 	logCmd := &cobra.Command{
 		Use:     "log <command> ",
 		Short:   "Tails logs for running shipyard resources",
@@ -34,15 +35,35 @@ func newLogCmd(engine shipyard.Engine, dc clients.Docker, stdout, stderr io.Writ
 
 	# Tail logs for a specific resource
 	shipyard log container.nginx
+
+	# Show the last 100 lines of stderr for a resource without following
+	shipyard log --follow=false --tail 100 --stdout=false container.nginx
+
+	# Show the logs for the pods of a Helm chart, the pods are found using the
+	# selectors in the health_check block
+	shipyard log helm.consul
 	`,
 		Args:              cobra.ArbitraryArgs,
 		ValidArgsFunction: getResources,
-		RunE:              newLogCmdFunc(dc, stdout, stderr),
+		RunE:              newLogCmdFunc(ct, kc, &opts, stdout, stderr),
 	}
 
+	logCmd.Flags().BoolVarP(&opts.follow, "follow", "f", true, "Follow the log output")
+	logCmd.Flags().StringVarP(&opts.tail, "tail", "", "40", "Number of lines to show from the end of the logs, or all")
+	logCmd.Flags().BoolVarP(&opts.stdout, "stdout", "", true, "Show the stdout log stream")
+	logCmd.Flags().BoolVarP(&opts.stderr, "stderr", "", true, "Show the stderr log stream")
+
 	return logCmd
 }
 
+// logOptions are the flags for the log command
+type logOptions struct {
+	follow bool
+	tail   string
+	stdout bool
+	stderr bool
+}
+
 var termColors = []color.Attribute{
 	color.FgRed,
 	color.FgGreen,
@@ -62,7 +83,7 @@ func getResources(cmd *cobra.Command, args []string, complete string) ([]string,
 	return loggable, cobra.ShellCompDirectiveNoFileComp
 }
 
-func newLogCmdFunc(dc clients.Docker, stdout, stderr io.Writer) func(cmd *cobra.Command, args []string) error {
+func newLogCmdFunc(ct clients.ContainerTasks, kc clients.Kubernetes, opts *logOptions, stdout, stderr io.Writer) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		log := hclog.Default()
 		sigs := make(chan os.Signal, 1)
@@ -70,9 +91,14 @@ func newLogCmdFunc(dc clients.Docker, stdout, stderr io.Writer) func(cmd *cobra.
 		waitGroup := sync.WaitGroup{}
 
 		var loggable []string
+		var pods []config.Resource
 
 		if len(args) == 1 {
-			loggable = []string{args[0]}
+			var err error
+			loggable, pods, err = getLoggableForResource(args[0])
+			if err != nil {
+				return err
+			}
 		} else {
 			var err error
 			loggable, err = getLoggable()
@@ -81,28 +107,47 @@ func newLogCmdFunc(dc clients.Docker, stdout, stderr io.Writer) func(cmd *cobra.
 			}
 		}
 
-		ctx := context.Background()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
 
 		for _, r := range loggable {
-			rc, err := dc.ContainerLogs(
-				ctx,
-				r,
-				types.ContainerLogsOptions{
-					ShowStdout: true,
-					ShowStderr: true,
-					Follow:     true,
-					Tail:       "40",
-				},
-			)
-
-			if err == nil {
+			c := color.New(getRandomColor())
+			name := strings.TrimSuffix(r, ".shipyard.run")
+
+			// streams which are not shown are not requested
+			var outW, errW io.Writer
+			if opts.stdout {
+				outW = &logWriter{w: stdout, name: name, color: c}
+			}
+
+			if opts.stderr {
+				errW = &logWriter{w: stderr, name: name, color: c}
+			}
+
+			waitGroup.Add(1)
+			go func(id string, outW, errW io.Writer) {
+				defer waitGroup.Done()
+
+				err := ct.ContainerLogsFollow(ctx, id, opts.tail, opts.follow, outW, errW)
+				if err != nil {
+					log.Error("Unable to get logs for container", "error", err)
+				}
+			}(r, outW, errW)
+		}
+
+		for _, r := range pods {
+			rcs, err := getPodLogs(ctx, kc, r)
+			if err != nil {
+				log.Error("Unable to get logs for pods", "ref", r.Info().Name, "error", err)
+				continue
+			}
+
+			for name, rc := range rcs {
 				waitGroup.Add(1)
-				go func(rc io.ReadCloser, name string, c color.Attribute, log hclog.Logger) {
-					writeLogOutput(rc, stdout, stderr, name, c, log)
+				go func(rc io.ReadCloser, name string, c color.Attribute) {
+					writePodLogOutput(rc, stdout, name, c)
 					waitGroup.Done()
-				}(rc, r, getRandomColor(), log)
-			} else {
-				log.Error("Unable to get logs for container", "error", err)
+				}(rc, name, getRandomColor())
 			}
 		}
 
@@ -120,6 +165,89 @@ func newLogCmdFunc(dc clients.Docker, stdout, stderr io.Writer) func(cmd *cobra.
 	}
 }
 
+// getLoggableForResource returns the containers to log for the given argument which
+// is either a resource id e.g. container.consul or the name of a container.
+// Kubernetes resources such as Helm charts have no containers and are returned
+// separately so that the logs for their pods can be shown.
+func getLoggableForResource(id string) ([]string, []config.Resource, error) {
+	c := config.New()
+	err := c.FromJSON(utils.StatePath())
+	if err != nil {
+		// without state the id can only be the name of a container
+		return []string{id}, nil, nil
+	}
+
+	r, err := c.FindResource(id)
+	if err != nil {
+		// not a resource, assume it is the name of a container
+		return []string{id}, nil, nil
+	}
+
+	if r.Info().Disabled {
+		return nil, nil, fmt.Errorf("resource %s is disabled", id)
+	}
+
+	switch r.Info().Type {
+	case config.TypeHelm, config.TypeK8sConfig:
+		return nil, []config.Resource{r}, nil
+	}
+
+	loggable := resourceContainers(r)
+	if len(loggable) == 0 {
+		return nil, nil, fmt.Errorf("resource %s does not have any logs", id)
+	}
+
+	return loggable, nil, nil
+}
+
+// getPodLogs returns the log streams for the pods matching the health check
+// selectors for a Helm or Kubernetes config resource keyed by pod name
+func getPodLogs(ctx context.Context, kc clients.Kubernetes, r config.Resource) (map[string]io.ReadCloser, error) {
+	var cluster string
+	var hc *config.HealthCheck
+
+	switch v := r.(type) {
+	case *config.Helm:
+		cluster, hc = v.Cluster, v.HealthCheck
+	case *config.K8sConfig:
+		cluster, hc = v.Cluster, v.HealthCheck
+	}
+
+	if hc == nil || len(hc.Pods) == 0 {
+		return nil, fmt.Errorf("resource has no health_check pod selectors to find pods")
+	}
+
+	cr, err := r.FindDependentResource(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	_, kcPath, _ := utils.CreateKubeConfigPath(cr.Info().Name)
+	kc, err = kc.SetConfig(kcPath)
+	if err != nil {
+		return nil, err
+	}
+
+	rcs := map[string]io.ReadCloser{}
+	for _, sel := range hc.Pods {
+		pl, err := kc.GetPods(sel)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range pl.Items {
+			rc, err := kc.GetPodLogs(ctx, p.Name, p.Namespace)
+			if err != nil {
+				return nil, err
+			}
+
+			rcs[p.Name] = rc
+		}
+	}
+
+	return rcs, nil
+}
+
 // if this methods returns and error, it will get returned as shell-completion data
 // otherwise fmt.println() gets lost
 func getLoggable() ([]string, error) {
@@ -136,76 +264,72 @@ func getLoggable() ([]string, error) {
 
 	loggable := []string{}
 	for _, r := range resources {
-		switch r.Info().Type {
-		case config.TypeContainer:
-			if !r.Info().Disabled {
-				loggable = append(loggable, utils.FQDN(r.Info().Name, string(r.Info().Type)))
-			}
-		case config.TypeK8sCluster:
-			if !r.Info().Disabled {
-				loggable = append(loggable, fmt.Sprintf("%s.%s", "server", utils.FQDN(r.Info().Name, string(r.Info().Type))))
-			}
-		case config.TypeNomadCluster:
-			if !r.Info().Disabled {
-				loggable = append(loggable, fmt.Sprintf("%s.%s", "server", utils.FQDN(r.Info().Name, string(r.Info().Type))))
-
-				// add the client nodes
-				nomad := r.(*config.NomadCluster)
-				for n := 0; n < nomad.ClientNodes; n++ {
-					loggable = append(loggable, fmt.Sprintf("%d.%s.%s", n+1, "client", utils.FQDN(r.Info().Name, string(r.Info().Type))))
-				}
-			}
-		case config.TypeSidecar:
-			if !r.Info().Disabled {
-				loggable = append(loggable, utils.FQDN(r.Info().Name, string(r.Info().Type)))
-			}
-		case config.TypeK8sIngress:
-			if !r.Info().Disabled {
-				loggable = append(loggable, utils.FQDN(r.Info().Name, string(r.Info().Type)))
-			}
-		case config.TypeNomadIngress:
-			if !r.Info().Disabled {
-				loggable = append(loggable, utils.FQDN(r.Info().Name, string(r.Info().Type)))
-			}
-		case config.TypeContainerIngress:
-			if !r.Info().Disabled {
-				loggable = append(loggable, utils.FQDN(r.Info().Name, string(r.Info().Type)))
-			}
-		case config.TypeImageCache:
-			loggable = append(loggable, utils.FQDN(r.Info().Name, string(r.Info().Type)))
-		}
+		loggable = append(loggable, resourceContainers(r)...)
 	}
+
 	return loggable, nil
 }
 
+// resourceContainers returns the names of the containers for the resource
+// which have logs, disabled resources have no containers
+func resourceContainers(r config.Resource) []string {
+	fqdn := utils.FQDN(r.Info().Name, string(r.Info().Type))
+
+	// the image cache can not be disabled
+	if r.Info().Disabled && r.Info().Type != config.TypeImageCache {
+		return nil
+	}
+
+	switch r.Info().Type {
+	case config.TypeContainer, config.TypeSidecar, config.TypeK8sIngress, config.TypeNomadIngress, config.TypeContainerIngress, config.TypeImageCache:
+		return []string{fqdn}
+	case config.TypeK8sCluster:
+		return []string{fmt.Sprintf("%s.%s", "server", fqdn)}
+	case config.TypeNomadCluster:
+		names := []string{fmt.Sprintf("%s.%s", "server", fqdn)}
+
+		// add the client nodes
+		nomad := r.(*config.NomadCluster)
+		for n := 0; n < nomad.ClientNodes; n++ {
+			names = append(names, fmt.Sprintf("%d.%s.%s", n+1, "client", fqdn))
+		}
+
+		return names
+	}
+
+	return nil
+}
+
 func getRandomColor() color.Attribute {
 	return termColors[rand.Intn(len(termColors)-1)]
 }
 
-func writeLogOutput(rc io.ReadCloser, stdout, stderr io.Writer, name string, c color.Attribute, log hclog.Logger) {
-	hdr := make([]byte, 8)
-	colorWriter := color.New(c)
+// logWriter writes the output from a container prefixed with the name of the
+// container, Docker writes each frame of the log stream separately
+type logWriter struct {
+	w     io.Writer
+	name  string
+	color *color.Color
+}
 
-	for {
-		_, err := rc.Read(hdr)
-		if err != nil {
-			log.Error("Unable to read from log stream", "name", name, "error", err)
-			return
-		}
+func (l *logWriter) Write(p []byte) (int, error) {
+	_, err := l.color.Fprintf(l.w, "[%s]   %s", l.name, string(p))
+	if err != nil {
+		return 0, err
+	}
 
-		var w io.Writer
-		switch hdr[0] {
-		case 1:
-			w = stdout
-		default:
-			w = stderr
-		}
+	return len(p), nil
+}
 
-		count := binary.BigEndian.Uint32(hdr[4:])
-		dat := make([]byte, count)
-		_, err = rc.Read(dat)
+// writePodLogOutput writes each line from the pod log stream to stdout, pod
+// logs are not multiplexed so stdout and stderr can not be separated
+func writePodLogOutput(rc io.ReadCloser, stdout io.Writer, name string, c color.Attribute) {
+	defer rc.Close()
+
+	colorWriter := color.New(c)
 
-		name = strings.TrimSuffix(name, ".shipyard.run")
-		colorWriter.Fprintf(w, "[%s]   %s", name, string(dat))
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		colorWriter.Fprintf(stdout, "[%s]   %s\n", name, scanner.Text())
 	}
 }
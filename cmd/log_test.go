@@ -2,17 +2,18 @@ package cmd
 
 import (
 	"bytes"
-	"encoding/binary"
 	"io"
 	"sync"
 	"testing"
 
-	"github.com/docker/docker/api/types"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/shipyard-run/shipyard/pkg/clients"
 	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -39,7 +40,7 @@ func (tw *testWriter) Write(p []byte) (n int, err error) {
 	return tw.Buffer.Write(p)
 }
 
-func setupLog(t *testing.T, logStream int) (*cobra.Command, *mocks.MockDocker, *bytes.Buffer, *bytes.Buffer) {
+func setupLog(t *testing.T, logStream int) (*cobra.Command, *mocks.MockContainerTasks, *bytes.Buffer, *bytes.Buffer) {
 	// setup the statefile
 	t.Cleanup(setupState(logState))
 
@@ -47,101 +48,145 @@ func setupLog(t *testing.T, logStream int) (*cobra.Command, *mocks.MockDocker, *
 	stdout := newTestWriter()
 	stderr := newTestWriter()
 
-	log := createLogOutput(logStream)
+	mt := &mocks.MockContainerTasks{}
+	mt.On("ContainerLogsFollow", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			// the stdout writer is the fifth argument and stderr the sixth
+			w, _ := args.Get(5).(io.Writer)
+			if logStream == logStdOut {
+				w, _ = args.Get(4).(io.Writer)
+			}
 
-	md := &mocks.MockDocker{}
+			if w == nil {
+				return
+			}
 
-	md.On("ContainerLogs", mock.Anything, mock.Anything, mock.Anything).Once().Return(
-		io.NopCloser(bytes.NewBuffer(log)),
-		nil,
-	)
+			for _, l := range logLines {
+				w.Write([]byte(l))
+			}
+		}).
+		Return(nil)
 
-	md.On("ContainerLogs", mock.Anything, mock.Anything, mock.Anything).Once().Return(
-		io.NopCloser(bytes.NewBuffer(log)),
-		nil,
-	)
+	lc := newLogCmd(nil, mt, nil, stdout, stderr)
 
-	md.On("ContainerLogs", mock.Anything, mock.Anything, mock.Anything).Once().Return(
-		io.NopCloser(bytes.NewBuffer(log)),
-		nil,
-	)
+	return lc, mt, stdout.Buffer, stderr.Buffer
+}
+
+func TestLogWithAllCallsDockerLog(t *testing.T) {
+	lc, mt, _, _ := setupLog(t, logStdOut)
 
-	md.On("ContainerLogs", mock.Anything, mock.Anything, mock.Anything).Once().Return(
-		io.NopCloser(bytes.NewBuffer(log)),
-		nil,
-	)
+	// call the command
+	err := lc.Execute()
+	require.NoError(t, err)
 
-	md.On("ContainerLogs", mock.Anything, mock.Anything, mock.Anything).Once().Return(
-		io.NopCloser(bytes.NewBuffer(log)),
-		nil,
-	)
+	// check that the logs were requested with the default options
+	mt.AssertNumberOfCalls(t, "ContainerLogsFollow", 6)
+	mt.AssertCalled(t, "ContainerLogsFollow", mock.Anything, "consul.container.shipyard.run", "40", true, mock.Anything, mock.Anything)
+	mt.AssertCalled(t, "ContainerLogsFollow", mock.Anything, "docker-cache.image-cache.shipyard.run", "40", true, mock.Anything, mock.Anything)
+	mt.AssertCalled(t, "ContainerLogsFollow", mock.Anything, "server.dev.k8s-cluster.shipyard.run", "40", true, mock.Anything, mock.Anything)
+	mt.AssertCalled(t, "ContainerLogsFollow", mock.Anything, "server.dev.nomad-cluster.shipyard.run", "40", true, mock.Anything, mock.Anything)
+	mt.AssertCalled(t, "ContainerLogsFollow", mock.Anything, "1.client.dev.nomad-cluster.shipyard.run", "40", true, mock.Anything, mock.Anything)
+	mt.AssertCalled(t, "ContainerLogsFollow", mock.Anything, "2.client.dev.nomad-cluster.shipyard.run", "40", true, mock.Anything, mock.Anything)
+}
 
-	md.On("ContainerLogs", mock.Anything, mock.Anything, mock.Anything).Once().Return(
-		io.NopCloser(bytes.NewBuffer(log)),
-		nil,
-	)
+func TestLogWithSpecificResourceCallsDockerLog(t *testing.T) {
+	lc, mt, _, _ := setupLog(t, logStdErr)
 
-	lc := newLogCmd(nil, md, stdout, stderr)
+	// call the command
+	lc.SetArgs([]string{"consul.container.shipyard.run"})
+	err := lc.Execute()
+	require.NoError(t, err)
 
-	return lc, md, stdout.Buffer, stderr.Buffer
+	// check that the logs were written to stdout
+	mt.AssertNumberOfCalls(t, "ContainerLogsFollow", 1)
+	mt.AssertCalled(t, "ContainerLogsFollow", mock.Anything, "consul.container.shipyard.run", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
-// createLogOutput creates a byte array that is formatted as a docker log
-func createLogOutput(logStream int) []byte {
-	out := []byte{}
-	for _, line := range logLines {
-		hdr := make([]byte, 8)
+func TestLogWithResourceIDCallsDockerLogForContainers(t *testing.T) {
+	lc, mt, _, _ := setupLog(t, logStdOut)
 
-		// stdout
-		hdr[0] = byte(logStream)
+	lc.SetArgs([]string{"nomad_cluster.dev"})
+	err := lc.Execute()
+	require.NoError(t, err)
 
-		// line length
-		l := uint32(len(line))
-		binary.BigEndian.PutUint32(hdr[4:], l)
-		out = append(out, hdr...)
+	mt.AssertNumberOfCalls(t, "ContainerLogsFollow", 3)
+	mt.AssertCalled(t, "ContainerLogsFollow", mock.Anything, "server.dev.nomad-cluster.shipyard.run", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mt.AssertCalled(t, "ContainerLogsFollow", mock.Anything, "1.client.dev.nomad-cluster.shipyard.run", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mt.AssertCalled(t, "ContainerLogsFollow", mock.Anything, "2.client.dev.nomad-cluster.shipyard.run", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
 
-		out = append(out, []byte(line)...)
-	}
+func TestLogWithDisabledResourceReturnsError(t *testing.T) {
+	lc, mt, _, _ := setupLog(t, logStdOut)
 
-	return out
+	lc.SetArgs([]string{"container.consul_disabled"})
+	err := lc.Execute()
+	require.Error(t, err)
+
+	mt.AssertNumberOfCalls(t, "ContainerLogsFollow", 0)
 }
 
-func TestLogWithAllCallsDockerLog(t *testing.T) {
-	lc, md, _, _ := setupLog(t, logStdOut)
+func TestLogWithFlagsSetsDockerLogOptions(t *testing.T) {
+	lc, mt, _, _ := setupLog(t, logStdOut)
 
-	// call the command
+	lc.SetArgs([]string{"--follow=false", "--tail", "100", "--stdout=false", "container.consul"})
 	err := lc.Execute()
 	require.NoError(t, err)
 
-	// check that the docker client was called
-	logOptions := types.ContainerLogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-		Follow:     true,
-		Tail:       "40",
-	}
+	mt.AssertCalled(t, "ContainerLogsFollow", mock.Anything, "consul.container.shipyard.run", "100", false, mock.Anything, mock.Anything)
 
-	md.AssertNumberOfCalls(t, "ContainerLogs", 6)
-	md.AssertCalled(t, "ContainerLogs", mock.Anything, "consul.container.shipyard.run", logOptions)
-	md.AssertCalled(t, "ContainerLogs", mock.Anything, "docker-cache.image-cache.shipyard.run", logOptions)
-	md.AssertCalled(t, "ContainerLogs", mock.Anything, "server.dev.k8s-cluster.shipyard.run", logOptions)
-	md.AssertCalled(t, "ContainerLogs", mock.Anything, "server.dev.nomad-cluster.shipyard.run", logOptions)
-	md.AssertCalled(t, "ContainerLogs", mock.Anything, "1.client.dev.nomad-cluster.shipyard.run", logOptions)
-	md.AssertCalled(t, "ContainerLogs", mock.Anything, "2.client.dev.nomad-cluster.shipyard.run", logOptions)
+	// stdout is not requested
+	calls := getCalls(&mt.Mock, "ContainerLogsFollow")
+	require.Len(t, calls, 1)
+	require.Nil(t, calls[0].Arguments.Get(4))
+	require.NotNil(t, calls[0].Arguments.Get(5))
 }
 
-func TestLogWithSpecificResourceCallsDockerLog(t *testing.T) {
-	lc, md, _, _ := setupLog(t, logStdErr)
+func TestLogWithHelmResourceWritesPodLogs(t *testing.T) {
+	t.Cleanup(setupState(logPodState))
+
+	stdout := newTestWriter()
+	stderr := newTestWriter()
+
+	mt := &mocks.MockContainerTasks{}
+
+	mk := &clients.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("GetPods", "app=consul").Return(&v1.PodList{
+		Items: []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "consul-0", Namespace: "default"}}},
+	}, nil)
+	mk.On("GetPodLogs", mock.Anything, "consul-0", "default").Return(nil, nil)
+
+	lc := newLogCmd(nil, mt, mk, stdout, stderr)
+	lc.SetArgs([]string{"helm.consul"})
 
-	// call the command
-	lc.SetArgs([]string{"consul.container.shipyard.run"})
 	err := lc.Execute()
 	require.NoError(t, err)
 
-	// check that the logs were written to stdout
-	md.AssertNumberOfCalls(t, "ContainerLogs", 1)
-	md.AssertCalled(t, "ContainerLogs", mock.Anything, "consul.container.shipyard.run", mock.Anything)
+	mt.AssertNotCalled(t, "ContainerLogsFollow", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.Contains(t, stdout.Buffer.String(), "[consul-0]   Running pod ...")
+}
+
+var logPodState = `
+{
+ "resources": [
+    {
+      "name": "dev",
+      "type": "k8s_cluster",
+      "status": "applied"
+    },
+    {
+      "name": "consul",
+      "type": "helm",
+      "status": "applied",
+      "cluster": "k8s_cluster.dev",
+      "health_check": {
+        "timeout": "60s",
+        "pods": ["app=consul"]
+      }
+    }
+  ]
 }
+`
 
 //func TestLogWithInvalidSpecificResourceReturnsError(t *testing.T) {
 //	lc, md, _, _ := setupLog(t, logStdErr)
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newPlanCmd(e shipyard.Engine) *cobra.Command {
+	var variables []string
+	var variablesFile string
+	var jsonOutput bool
+
+	planCmd := &cobra.Command{
+		Use:   "plan [file] | [directory]",
+		Short: "Show the changes run would make to the current environment",
+		Long: `Show the changes run would make to the current environment.
+No resources are created or changed, use --json to output the attribute level changes
+for each resource as a JSON document. The field names in the JSON document are stable,
+the format_version field is incremented when fields are renamed or removed.`,
+		Example: `
+  shipyard plan ./blueprint
+
+  shipyard plan --json ./blueprint > plan.json
+	`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// parse the vars into a map
+			vars := map[string]string{}
+			for _, v := range variables {
+				parts := strings.Split(v, "=")
+				if len(parts) == 2 {
+					vars[parts[0]] = parts[1]
+				}
+			}
+
+			p, err := e.Plan(args[0], vars, variablesFile)
+			if err != nil {
+				return fmt.Errorf("Unable to create plan: %s", err)
+			}
+
+			if jsonOutput {
+				d, err := json.MarshalIndent(p, "", "  ")
+				if err != nil {
+					return fmt.Errorf("Unable to serialize plan: %s", err)
+				}
+
+				cmd.Println(string(d))
+				return nil
+			}
+
+			cmd.Print(p.String())
+
+			return nil
+		},
+	}
+
+	planCmd.Flags().BoolVarP(&jsonOutput, "json", "", false, "Output the plan as JSON")
+	planCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	planCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+
+	return planCmd
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newPolicyCmd(e shipyard.Engine) *cobra.Command {
+	var variables []string
+	var variablesFile string
+	var policyFile string
+
+	policyCmd := &cobra.Command{
+		Use:   "policy [file] | [directory]",
+		Short: "Validate a blueprint against a policy file",
+		Long: `Validate the resources in a blueprint against the rules in a policy file.
+No resources are created, the command exits with an error if any resource violates a rule.`,
+		Example: `
+  shipyard policy --policy ./policy.hcl ./blueprint
+	`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if policyFile == "" {
+				return fmt.Errorf("Please specify the policy file using the --policy flag")
+			}
+
+			// parse the vars into a map
+			vars := map[string]string{}
+			for _, v := range variables {
+				parts := strings.Split(v, "=")
+				if len(parts) == 2 {
+					vars[parts[0]] = parts[1]
+				}
+			}
+
+			violations, err := e.Policy(args[0], policyFile, vars, variablesFile)
+			if err != nil {
+				return fmt.Errorf("Unable to validate policy: %s", err)
+			}
+
+			for _, v := range violations {
+				cmd.Printf("[FAIL] %s\n", v)
+			}
+
+			if len(violations) > 0 {
+				return fmt.Errorf("Blueprint has %d policy violations", len(violations))
+			}
+
+			cmd.Println("Blueprint complies with policy")
+
+			return nil
+		},
+	}
+
+	policyCmd.Flags().StringVarP(&policyFile, "policy", "", "", "Path to the HCL policy file containing the rules to validate")
+	policyCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	policyCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+
+	return policyCmd
+}
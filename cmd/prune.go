@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newPruneCmd(e shipyard.Engine) *cobra.Command {
+	var force bool
+
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Removes Docker containers created by Shipyard which are not in the state",
+		Long: `Finds Docker containers created by Shipyard which are not tracked by the state,
+for example containers left behind when Shipyard was interrupted.
+The containers are only listed, use --force to remove them.`,
+		Example: `
+  # list orphaned containers
+  shipyard prune
+
+  # remove orphaned containers
+  shipyard prune --force
+	`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			orphans, err := e.Prune(force)
+			if err != nil {
+				return fmt.Errorf("Unable to prune containers: %s", err)
+			}
+
+			if len(orphans) == 0 {
+				cmd.Println("No orphaned containers found")
+				return nil
+			}
+
+			for _, o := range orphans {
+				cmd.Println(o)
+			}
+
+			if force {
+				cmd.Printf("Removed %d orphaned containers\n", len(orphans))
+				return nil
+			}
+
+			cmd.Printf("Found %d orphaned containers, run 'shipyard prune --force' to remove them\n", len(orphans))
+
+			return nil
+		},
+	}
+
+	pruneCmd.Flags().BoolVarP(&force, "force", "", false, "Remove the orphaned containers")
+
+	return pruneCmd
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/shipyard/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPruneListsOrphansWithoutForce(t *testing.T) {
+	me := &mocks.Engine{}
+	me.On("Prune", false).Return([]string{"consul.container.shipyard.run"}, nil)
+
+	out := bytes.NewBufferString("")
+
+	c := newPruneCmd(me)
+	c.SetOut(out)
+
+	err := c.Execute()
+	assert.NoError(t, err)
+
+	me.AssertCalled(t, "Prune", false)
+	assert.Contains(t, out.String(), "consul.container.shipyard.run")
+	assert.Contains(t, out.String(), "shipyard prune --force")
+}
+
+func TestPruneWithForceRemovesOrphans(t *testing.T) {
+	me := &mocks.Engine{}
+	me.On("Prune", true).Return([]string{"consul.container.shipyard.run"}, nil)
+
+	out := bytes.NewBufferString("")
+
+	c := newPruneCmd(me)
+	c.SetOut(out)
+	c.SetArgs([]string{"--force"})
+
+	err := c.Execute()
+	assert.NoError(t, err)
+
+	me.AssertCalled(t, "Prune", true)
+	assert.Contains(t, out.String(), "Removed 1 orphaned containers")
+}
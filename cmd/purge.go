@@ -14,23 +14,30 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func newPurgeCmd(dt clients.Docker, il clients.ImageLog, l hclog.Logger) *cobra.Command {
+var purgeVolumes bool
+
+func newPurgeCmd(dt clients.Docker, ct clients.ContainerTasks, il clients.ImageLog, l hclog.Logger) *cobra.Command {
 	purgeCmd := &cobra.Command{
 		Use:   "purge",
 		Short: "Purges Docker images, Helm charts, and Blueprints downloaded by Shipyard",
 		Long:  "Purges Docker images, Helm charts, and Blueprints downloaded by Shipyard",
 		Example: `
   shipyard purge
+
+  # also remove dangling anonymous volumes left by destroyed containers
+  shipyard purge --volumes
 	`,
 		Args:         cobra.ArbitraryArgs,
-		RunE:         newPurgeCmdFunc(dt, il, l),
+		RunE:         newPurgeCmdFunc(dt, ct, il, l),
 		SilenceUsage: true,
 	}
 
+	purgeCmd.Flags().BoolVarP(&purgeVolumes, "volumes", "", false, "Remove dangling anonymous Docker volumes")
+
 	return purgeCmd
 }
 
-func newPurgeCmdFunc(dt clients.Docker, il clients.ImageLog, l hclog.Logger) func(cmd *cobra.Command, args []string) error {
+func newPurgeCmdFunc(dt clients.Docker, ct clients.ContainerTasks, il clients.ImageLog, l hclog.Logger) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		images, _ := il.Read(clients.ImageTypeDocker)
 
@@ -44,7 +51,6 @@ func newPurgeCmdFunc(dt clients.Docker, il clients.ImageLog, l hclog.Logger) fun
 				l.Error("Unable to delete", "image", i, "error", err)
 			}
 		}
-		il.Clear()
 
 		// Remove any images which have been built
 		filter := filters.NewArgs()
@@ -74,6 +80,30 @@ func newPurgeCmdFunc(dt clients.Docker, il clients.ImageLog, l hclog.Logger) fun
 			bHasError = true
 		}
 
+		if purgeVolumes {
+			l.Info("Removing dangling volumes")
+			vols, err := ct.RemoveDanglingVolumes()
+			for _, v := range vols {
+				l.Debug("Removed volume", "name", v)
+			}
+
+			if err != nil {
+				l.Error("Unable to remove dangling volumes", "error", err)
+				bHasError = true
+			}
+		}
+
+		// the log records the volumes kept by removed containers, these are
+		// only forgotten once they have been removed
+		volumes, _ := il.Read(clients.ImageTypeVolume)
+		il.Clear()
+
+		if !purgeVolumes {
+			for _, v := range volumes {
+				il.Log(v, clients.ImageTypeVolume)
+			}
+		}
+
 		hcp := utils.GetBlueprintLocalFolder("")
 		l.Info("Removing cached blueprints", "path", hcp)
 		err = os.RemoveAll(hcp)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
 	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
 	"github.com/shipyard-run/shipyard/pkg/utils"
 	"github.com/spf13/cobra"
@@ -15,6 +16,11 @@ import (
 )
 
 func setupPurgeCommand(t *testing.T) (*cobra.Command, *mocks.MockDocker, *mocks.ImageLog, func()) {
+	pc, md, _, mi, cleanup := setupPurgeCommandWithTasks(t)
+	return pc, md, mi, cleanup
+}
+
+func setupPurgeCommandWithTasks(t *testing.T) (*cobra.Command, *mocks.MockDocker, *mocks.MockContainerTasks, *mocks.ImageLog, func()) {
 	home := os.Getenv("HOME")
 
 	// create a fake home folder
@@ -45,12 +51,17 @@ func setupPurgeCommand(t *testing.T) (*cobra.Command, *mocks.MockDocker, *mocks.
 	mockImageLog := &mocks.ImageLog{}
 	mockImageLog.On("Read", mock.Anything).Return([]string{"one", "two"}, nil)
 	mockImageLog.On("Clear").Return(nil)
+	mockImageLog.On("Log", mock.Anything, mock.Anything).Return(nil)
 
-	pc := newPurgeCmd(mockDocker, mockImageLog, hclog.NewNullLogger())
+	mockTasks := &mocks.MockContainerTasks{}
+	mockTasks.On("RemoveDanglingVolumes").Return([]string{"abc"}, nil)
 
-	return pc, mockDocker, mockImageLog, func() {
+	pc := newPurgeCmd(mockDocker, mockTasks, mockImageLog, hclog.NewNullLogger())
+
+	return pc, mockDocker, mockTasks, mockImageLog, func() {
 		os.RemoveAll(dir)
 		os.Setenv("HOME", home)
+		purgeVolumes = false
 	}
 }
 
@@ -97,3 +108,46 @@ func TestPurgeRemovesHelmCharts(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NoDirExists(t, utils.GetHelmLocalFolder(""))
 }
+
+func TestPurgeDoesNotRemoveVolumesByDefault(t *testing.T) {
+	pc, _, mt, _, cleanup := setupPurgeCommandWithTasks(t)
+	defer cleanup()
+
+	err := pc.Execute()
+
+	assert.NoError(t, err)
+	mt.AssertNotCalled(t, "RemoveDanglingVolumes")
+}
+
+func TestPurgeRemovesDanglingVolumesWithFlag(t *testing.T) {
+	pc, _, mt, _, cleanup := setupPurgeCommandWithTasks(t)
+	defer cleanup()
+
+	pc.SetArgs([]string{"--volumes"})
+	err := pc.Execute()
+
+	assert.NoError(t, err)
+	mt.AssertCalled(t, "RemoveDanglingVolumes")
+}
+
+func TestPurgeKeepsLoggedVolumesWithoutFlag(t *testing.T) {
+	pc, _, _, mi, cleanup := setupPurgeCommandWithTasks(t)
+	defer cleanup()
+
+	err := pc.Execute()
+
+	assert.NoError(t, err)
+	mi.AssertCalled(t, "Clear")
+	mi.AssertCalled(t, "Log", "one", clients.ImageTypeVolume)
+}
+
+func TestPurgeForgetsLoggedVolumesWithFlag(t *testing.T) {
+	pc, _, _, mi, cleanup := setupPurgeCommandWithTasks(t)
+	defer cleanup()
+
+	pc.SetArgs([]string{"--volumes"})
+	err := pc.Execute()
+
+	assert.NoError(t, err)
+	mi.AssertNotCalled(t, "Log", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newRebuildStateCmd(e shipyard.Engine) *cobra.Command {
+	var variables []string
+	var variablesFile string
+
+	rebuildCmd := &cobra.Command{
+		Use:   "rebuild-state [file] | [directory]",
+		Short: "Rebuild the state file from the running resources",
+		Long: `Rebuild the state file from the running resources.
+Use this command to recover an environment when the state file has been lost or corrupted.
+Resources which can not be matched to a running object are created on the next run.`,
+		Example: `
+  shipyard rebuild-state ./blueprint
+	`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// parse the vars into a map
+			vars := map[string]string{}
+			for _, v := range variables {
+				parts := strings.Split(v, "=")
+				if len(parts) == 2 {
+					vars[parts[0]] = parts[1]
+				}
+			}
+
+			err := e.RebuildState(args[0], vars, variablesFile)
+			if err != nil {
+				return fmt.Errorf("Unable to rebuild state: %s", err)
+			}
+
+			cmd.Println("State rebuilt")
+
+			return nil
+		},
+	}
+
+	rebuildCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	rebuildCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+
+	return rebuildCmd
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newReconcileCmd(e shipyard.Engine) *cobra.Command {
+	var variables []string
+	var variablesFile string
+	var interval time.Duration
+
+	reconcileCmd := &cobra.Command{
+		Use:   "reconcile [file] | [directory]",
+		Short: "Keep the resources in a blueprint running",
+		Long: `Keep the resources in a blueprint running.
+The blueprint is applied at the given interval, resources which have been
+removed since the last run are recreated. Press Ctrl-C to stop.`,
+		Example: `
+  shipyard reconcile --interval 5m ./blueprint
+	`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval <= 0 {
+				return fmt.Errorf("Interval must be greater than 0")
+			}
+
+			// parse the vars into a map
+			vars := map[string]string{}
+			for _, v := range variables {
+				parts := strings.Split(v, "=")
+				if len(parts) == 2 {
+					vars[parts[0]] = parts[1]
+				}
+			}
+
+			sigs := make(chan os.Signal, 1)
+			signal.Notify(sigs, os.Interrupt)
+			defer signal.Stop(sigs)
+
+			go func() {
+				<-sigs
+				e.StopReconcile()
+			}()
+
+			return e.Reconcile(args[0], interval, vars, variablesFile)
+		},
+	}
+
+	reconcileCmd.Flags().DurationVarP(&interval, "interval", "", 1*time.Minute, "How often to check the resources, e.g. --interval=5m")
+	reconcileCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	reconcileCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+
+	return reconcileCmd
+}
@@ -1,199 +1,120 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
-	"os"
-
 	"time"
 
-	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/filters"
 	"github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients"
 	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
 	"github.com/shipyard-run/shipyard/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
-var resumeCmd = &cobra.Command{
-	Use:   "resume",
-	Short: "Resume a paused session and restart all resources",
-	Long:  `Resume a paused session and restart all resources`,
-	Example: `
+func newResumeCmd(e shipyard.Engine, l hclog.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume",
+		Short: "Resume a paused session and restart all resources",
+		Long:  `Resume a paused session and restart all resources`,
+		Example: `
   shipyard resume
 	`,
-	Args: cobra.ArbitraryArgs,
-	Run: func(cmd *cobra.Command, args []string) {
-		var err error
-		fmt.Println("Resuming session")
-
-		l := createLogger()
-
-		// create a docker client
-		c, err := clients.NewDocker()
-		if err != nil {
-			l.Error("Unable to connect to Docker daemon", "error", err)
-			os.Exit(1)
-		}
-
-		cl, err := getContainers(c, "exited")
-		if err != nil {
-			l.Error("Unable to get container status", "error", err)
-			os.Exit(1)
-		}
+		Args:         cobra.ArbitraryArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Println("Resuming session")
 
-		// start the containers
-		for _, con := range cl {
-			err := c.ContainerStart(context.Background(), con.ID, types.ContainerStartOptions{})
+			restarted, err := e.Resume()
 			if err != nil {
-				l.Error("Unable to start container", "name", con.Names[0], "error", err)
-				os.Exit(1)
+				return fmt.Errorf("Unable to resume resources: %s", err)
 			}
-		}
 
-		l.Info("Checking health of containers")
-		// wait for containers to get healthy
-		_, err = checkStatus(c)
-		if err != nil {
-			l.Error("Uable to check health of containers", "error", err)
-			os.Exit(1)
-		}
-
-		// get the health checks from the config and test
-		con := config.New()
-		err = con.FromJSON(utils.StatePath())
-		if err != nil {
-			l.Error("Unable to load state", "error", err)
-			os.Exit(1)
-		}
+			// get the health checks from the config and test
+			st, err := config.LoadTyped(utils.StatePath())
+			if err != nil {
+				return fmt.Errorf("Unable to load state: %s", err)
+			}
 
-		for _, res := range con.Resources {
-			switch res.Info().Type {
-			case config.TypeHelm:
-				co := res.(*config.Helm)
-				hc := co.HealthCheck
-
-				if hc != nil && len(hc.Pods) != 0 {
-					l.Debug("Health check pods in Helm chart", "chart", co.Info().Name)
-					err := healthCheckHelm(co)
-					if err != nil {
-						l.Error("Unable to check health of helm chart", "error", err)
-						os.Exit(1)
-					}
-				}
-			case config.TypeK8sConfig:
-				co := res.(*config.K8sConfig)
-				hc := co.HealthCheck
-
-				if hc != nil && len(hc.Pods) != 0 {
-					l.Debug("Health check pods in Kubernetes config", "chart", co.Info().Name)
-					err := healthCheckK8sConfig(co)
-					if err != nil {
-						l.Error("Unable to check health of k8s_config chart", "error", err)
-						os.Exit(1)
-					}
+			for _, h := range st.Helm() {
+				err := healthCheckResource(h, h.HealthCheck, restarted, l)
+				if err != nil {
+					return fmt.Errorf("Unable to check health of helm chart: %s", err)
 				}
 			}
 
-		}
-
-	},
-}
-
-func checkStatus(c clients.Docker) (bool, error) {
-	st := time.Now()
-
-	for {
-		if time.Now().Sub(st) > (60 * time.Second) {
-			return false, fmt.Errorf("Health check timeout waiting for containers to start failed")
-		}
-
-		// get the container status and check if running
-		cl, err := getContainers(c, "")
-		if err != nil {
-			return false, err
-		}
-
-		allRunning := true
-		for _, con := range cl {
-			if con.State != "running" {
-				allRunning = false
-				break
+			for _, k := range st.K8sConfig() {
+				err := healthCheckResource(k, k.HealthCheck, restarted, l)
+				if err != nil {
+					return fmt.Errorf("Unable to check health of k8s_config: %s", err)
+				}
 			}
-		}
-
-		if allRunning {
-			return true, nil
-		}
 
-		// wait 1s then try again
-		time.Sleep(1 * time.Second)
+			return nil
+		},
 	}
 }
 
-func getContainers(c clients.Docker, status string) ([]types.Container, error) {
-	filters := filters.NewArgs()
-	filters.Add("name", "shipyard")
-
-	if status != "" {
-		filters.Add("status", status)
+// needsHealthCheck returns true when the resource was not successfully applied
+// or when the cluster it depends on was restarted.
+// restarted is the list of resources which were started by the resume command
+func needsHealthCheck(r config.Resource, restarted []config.Resource) bool {
+	if r.Info().Status != config.Applied {
+		return true
 	}
 
-	cl, err := c.ContainerList(
-		context.Background(),
-		types.ContainerListOptions{
-			Filters: filters,
-		},
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return cl, nil
-}
-
-// TODO: HealthChecks should really be moved to a central universal functional call
-// copy pasta for now
-func healthCheckHelm(h *config.Helm) error {
-	kc := clients.NewKubernetes(500*time.Second, hclog.Default())
-	cl, err := h.FindDependentResource(h.Cluster)
-	if err != nil {
-		return nil
+	var cluster string
+	switch r.Info().Type {
+	case config.TypeHelm:
+		cluster = r.(*config.Helm).Cluster
+	case config.TypeK8sConfig:
+		cluster = r.(*config.K8sConfig).Cluster
+	default:
+		return true
 	}
 
-	_, conf, _ := utils.CreateKubeConfigPath(cl.Info().Name)
-	kc, err = kc.SetConfig(conf)
+	cl, err := r.FindDependentResource(cluster)
 	if err != nil {
-		return nil
+		// unable to determine the cluster, check to be safe
+		return true
 	}
 
-	err = kc.HealthCheckPods(h.HealthCheck.Pods, 500*time.Second)
-	if err != nil {
-		return err
+	for _, rr := range restarted {
+		if rr.Info().Name == cl.Info().Name && rr.Info().Type == cl.Info().Type {
+			return true
+		}
 	}
 
-	return nil
+	return false
 }
 
-func healthCheckK8sConfig(h *config.K8sConfig) error {
-	kc := clients.NewKubernetes(500*time.Second, hclog.Default())
-	cl, err := h.FindDependentResource(h.Cluster)
-	if err != nil {
+// healthCheckResource checks the pods defined in the health check of a
+// Kubernetes resource, resources which were not restarted and were previously
+// healthy do not need to be checked again
+func healthCheckResource(r config.Resource, hc *config.HealthCheck, restarted []config.Resource, l hclog.Logger) error {
+	if hc == nil || (len(hc.Pods) == 0 && len(hc.Conditions) == 0) {
 		return nil
 	}
 
-	_, conf, _ := utils.CreateKubeConfigPath(cl.Info().Name)
-	kc, err = kc.SetConfig(conf)
-	if err != nil {
+	if !needsHealthCheck(r, restarted) {
+		l.Debug("Skipping health check for unchanged resource", "ref", r.Info().Name, "type", r.Info().Type)
 		return nil
 	}
 
-	err = kc.HealthCheckPods(h.HealthCheck.Pods, 500*time.Second)
-	if err != nil {
-		return err
+	l.Debug("Health check pods and conditions", "ref", r.Info().Name, "type", r.Info().Type)
+
+	// use the timeout for the resource when set
+	timeout := 500 * time.Second
+	if r.Info().Timeout != "" {
+		d, err := time.ParseDuration(r.Info().Timeout)
+		if err != nil {
+			return fmt.Errorf("Unable to parse timeout for %s.%s: %s", r.Info().Type, r.Info().Name, err)
+		}
+
+		timeout = d
 	}
 
-	return nil
+	kc := clients.NewKubernetes(timeout, hclog.Default())
+
+	return kc.HealthCheckResource(r, timeout)
 }
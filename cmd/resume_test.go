@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupResumeConfig(status config.Status) *config.Helm {
+	c := config.New()
+
+	cl := config.NewK8sCluster("k3s")
+	c.AddResource(cl)
+
+	h := config.NewHelm("consul")
+	h.Cluster = "k8s_cluster.k3s"
+	h.Status = status
+	c.AddResource(h)
+
+	return h
+}
+
+func TestNeedsHealthCheckReturnsTrueWhenClusterRestarted(t *testing.T) {
+	h := setupResumeConfig(config.Applied)
+	cl, _ := h.FindDependentResource(h.Cluster)
+
+	assert.True(t, needsHealthCheck(h, []config.Resource{cl}))
+}
+
+func TestNeedsHealthCheckReturnsFalseWhenClusterNotRestarted(t *testing.T) {
+	h := setupResumeConfig(config.Applied)
+	co := config.NewContainer("consul")
+
+	assert.False(t, needsHealthCheck(h, []config.Resource{co}))
+}
+
+func TestNeedsHealthCheckReturnsTrueWhenResourceFailed(t *testing.T) {
+	h := setupResumeConfig(config.Failed)
+
+	assert.True(t, needsHealthCheck(h, []config.Resource{}))
+}
+
+func TestHealthCheckResourceSkipsWhenNoPods(t *testing.T) {
+	h := setupResumeConfig(config.Failed)
+	h.HealthCheck = &config.HealthCheck{}
+
+	err := healthCheckResource(h, h.HealthCheck, []config.Resource{}, hclog.NewNullLogger())
+	assert.NoError(t, err)
+}
+
+func TestHealthCheckResourceReturnsErrorWithInvalidTimeout(t *testing.T) {
+	h := setupResumeConfig(config.Failed)
+	h.Timeout = "soon"
+	h.HealthCheck = &config.HealthCheck{Pods: []string{"app=consul"}}
+
+	err := healthCheckResource(h, h.HealthCheck, []config.Resource{}, hclog.NewNullLogger())
+	assert.Error(t, err)
+}
@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	
 	"github.com/hashicorp/go-hclog"
 	gvm "github.com/shipyard-run/version-manager"
 	
+	"github.com/shipyard-run/shipyard/pkg/clients"
 	"github.com/shipyard-run/shipyard/pkg/shipyard"
 	"github.com/shipyard-run/shipyard/pkg/utils"
 	
@@ -25,6 +27,10 @@ var engine shipyard.Engine
 var logger hclog.Logger
 var engineClients *shipyard.Clients
 
+// quiet suppresses all log output except errors, output written by the
+// commands such as plans and blueprint information is not affected
+var quiet bool
+
 var version string // set by build process
 var date string    // set by build process
 var commit string  // set by build process
@@ -34,13 +40,21 @@ func init() {
 	var vm gvm.Versions
 
 	// setup dependencies
-	logger = createLogger()
+	logger = createLogger(logSinksFromEnv()...)
 	engine, vm = createEngine(logger)
 	engineClients = engine.GetClients()
 
 	//cobra.OnInitialize(configure)
 
 	//rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file (default is $HOME/.shipyard/config)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only log errors, useful when running Shipyard from scripts. Command output such as plans is still written")
+
+	// the logger is created before the flags are parsed, set the level once they are known
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		if quiet {
+			logger.SetLevel(hclog.Error)
+		}
+	}
 
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(checkCmd)
@@ -48,18 +62,32 @@ func init() {
 	rootCmd.AddCommand(newEnvCmd(engine))
 	rootCmd.AddCommand(newRunCmd(engine, engineClients.Getter, engineClients.HTTP, engineClients.Browser, vm, engineClients.Connector, logger))
 	rootCmd.AddCommand(newTestCmd(engine, engineClients.Getter, engineClients.HTTP, engineClients.Browser, logger))
-	rootCmd.AddCommand(pauseCmd)
-	rootCmd.AddCommand(resumeCmd)
+	rootCmd.AddCommand(newPauseCmd(engine))
+	rootCmd.AddCommand(newResumeCmd(engine, logger))
 	rootCmd.AddCommand(newGetCmd(engineClients.Getter))
 	rootCmd.AddCommand(newDestroyCmd(engineClients.Connector))
-	rootCmd.AddCommand(statusCmd)
-	rootCmd.AddCommand(newPurgeCmd(engineClients.Docker, engineClients.ImageLog, logger))
+	rootCmd.AddCommand(newStatusCmd(engine))
+	rootCmd.AddCommand(newHealthCmd(engine))
+	rootCmd.AddCommand(newPurgeCmd(engineClients.Docker, engineClients.ContainerTasks, engineClients.ImageLog, logger))
 	rootCmd.AddCommand(taintCmd)
+	rootCmd.AddCommand(forceUnlockCmd)
+	rootCmd.AddCommand(newRebuildStateCmd(engine))
+	rootCmd.AddCommand(newPolicyCmd(engine))
+	rootCmd.AddCommand(newPlanCmd(engine))
+	rootCmd.AddCommand(newValidateCmd(engine))
+	rootCmd.AddCommand(newGraphCmd(engine))
+	rootCmd.AddCommand(newEstimateCmd(engine))
+	rootCmd.AddCommand(newDependenciesCmd(engine))
+	rootCmd.AddCommand(newReconcileCmd(engine))
+	rootCmd.AddCommand(newPruneCmd(engine))
+	rootCmd.AddCommand(newSnapshotCmd(engine))
+	rootCmd.AddCommand(newRestoreCmd(engine))
+	rootCmd.AddCommand(newImportK8sCmd(engine))
 	rootCmd.AddCommand(newExecCmd(engineClients.ContainerTasks))
 	rootCmd.AddCommand(newVersionCmd(vm))
 	rootCmd.AddCommand(uninstallCmd)
 	rootCmd.AddCommand(newPushCmd(engineClients.ContainerTasks, engineClients.Kubernetes, engineClients.HTTP, engineClients.Nomad, logger))
-	rootCmd.AddCommand(newLogCmd(engine, engineClients.Docker, os.Stdout, os.Stderr), completionCmd)
+	rootCmd.AddCommand(newLogCmd(engine, engineClients.ContainerTasks, engineClients.Kubernetes, os.Stdout, os.Stderr), completionCmd)
 	
 	// add the server commands
 	rootCmd.AddCommand(connectorCmd)
@@ -111,16 +139,58 @@ func createEngine(l hclog.Logger) (shipyard.Engine, gvm.Versions) {
 	return engine, vm
 }
 
-func createLogger() hclog.Logger {
+// logSink is an additional destination for log output
+type logSink struct {
+	Output     io.Writer
+	JSONFormat bool
+}
+
+// createLogger creates a logger which writes human readable output to stderr,
+// log messages are also written to any additional sinks
+func createLogger(sinks ...logSink) hclog.Logger {
+	// LOG_FORMAT=json writes JSON for log collectors
+	lo := shipyard.LogOptions{Level: os.Getenv("LOG_LEVEL"), JSON: os.Getenv("LOG_FORMAT") == "json"}
 
-	opts := &hclog.LoggerOptions{Color: hclog.AutoColor}
+	l, err := shipyard.NewLogger(lo)
+	if err != nil {
+		fmt.Printf("%s, using the default level\n", err)
 
-	// set the log level
-	if lev := os.Getenv("LOG_LEVEL"); lev != "" {
-		opts.Level = hclog.LevelFromString(lev)
+		lo.Level = ""
+		l, _ = shipyard.NewLogger(lo)
 	}
 
-	return hclog.New(opts)
+	il, ok := l.(hclog.InterceptLogger)
+	if !ok {
+		return l
+	}
+
+	for _, s := range sinks {
+		il.RegisterSink(hclog.NewSinkAdapter(&hclog.LoggerOptions{
+			Level:      hclog.LevelFromString(lo.Level),
+			Output:     s.Output,
+			JSONFormat: s.JSONFormat,
+		}))
+	}
+
+	return il
+}
+
+// logSinksFromEnv returns the additional log sinks configured by the environment,
+// setting LOG_FILE writes JSON formatted logs to the given file
+func logSinksFromEnv() []logSink {
+	sinks := []logSink{}
+
+	if lf := os.Getenv("LOG_FILE"); lf != "" {
+		f, err := os.OpenFile(lf, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			fmt.Printf("Unable to open log file %s: %s\n", lf, err)
+			return sinks
+		}
+
+		sinks = append(sinks, logSink{Output: f, JSONFormat: true})
+	}
+
+	return sinks
 }
 
 // Execute the root command
@@ -131,13 +201,28 @@ func Execute(v, c, d string) error {
 
 	err := rootCmd.Execute()
 
-	if err != nil {
+	// a failed exec is an error in the command not in Shipyard
+	if _, ok := err.(clients.ExecExitError); err != nil && !ok {
 		fmt.Println(discordHelp)
 	}
 
 	return err
 }
 
+// ExitCode returns the exit code for the process when Execute returns err,
+// commands run with exec return the exit code of the command
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	if ee, ok := err.(clients.ExecExitError); ok {
+		return ee.ExitCode
+	}
+
+	return 1
+}
+
 var discordHelp = `
 ### For help and support join our community on Discord: https://discord.gg/ZuEFPJU69D ###
 `
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateLoggerWritesToAllSinks(t *testing.T) {
+	human := bytes.NewBufferString("")
+	js := bytes.NewBufferString("")
+
+	l := createLogger(logSink{Output: human}, logSink{Output: js, JSONFormat: true})
+	l.Info("Creating resources", "resource", "container.consul")
+
+	assert.Contains(t, human.String(), "Creating resources")
+	assert.Contains(t, human.String(), "resource=container.consul")
+
+	out := map[string]interface{}{}
+	err := json.Unmarshal(js.Bytes(), &out)
+	assert.NoError(t, err)
+	assert.Equal(t, "Creating resources", out["@message"])
+	assert.Equal(t, "container.consul", out["resource"])
+}
+
+func TestQuietSetsLoggerLevelBeforeRun(t *testing.T) {
+	l := logger
+	defer func() { logger = l }()
+
+	quiet = true
+	defer func() { quiet = false }()
+
+	out := bytes.NewBufferString("")
+	logger = createLogger(logSink{Output: out})
+
+	// the flags are parsed after the logger is created
+	assert.True(t, logger.IsInfo())
+
+	rootCmd.PersistentPreRun(rootCmd, nil)
+
+	assert.False(t, logger.IsInfo())
+	assert.True(t, logger.IsError())
+}
+
+func TestExitCodeReturnsCodeForError(t *testing.T) {
+	assert.Equal(t, 0, ExitCode(nil))
+	assert.Equal(t, 1, ExitCode(fmt.Errorf("boom")))
+	assert.Equal(t, 2, ExitCode(clients.ExecExitError{ExitCode: 2}))
+}
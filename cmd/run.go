@@ -31,6 +31,10 @@ func newRunCmd(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients
 	var runVersion string
 	var variables []string
 	var variablesFile string
+	var overlays []string
+	var strategy string
+	var targets []string
+	var rollback bool
 
 	runCmd := &cobra.Command{
 		Use:   "run [file] [directory] ...",
@@ -45,9 +49,15 @@ func newRunCmd(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients
 
   # Create a stack from a blueprint in GitHub
   shipyard run github.com/shipyard-run/blueprints//vault-k8s
+
+  # Create a stack from a base blueprint with an environment specific overlay
+  shipyard run ./base --overlay ./overlays/dev
+
+  # Create only a container and the resources it depends on
+  shipyard run ./-stack --target container.app
 	`,
 		Args:         cobra.ArbitraryArgs,
-		RunE:         newRunCmdFunc(e, bp, hc, bc, vm, cc, &noOpen, &force, &runVersion, &y, &variables, &variablesFile, l),
+		RunE:         newRunCmdFunc(e, bp, hc, bc, vm, cc, &noOpen, &force, &runVersion, &y, &variables, &variablesFile, &overlays, &strategy, &targets, &rollback, l),
 		SilenceUsage: true,
 	}
 
@@ -57,11 +67,15 @@ func newRunCmd(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients
 	runCmd.Flags().BoolVarP(&force, "force-update", "", false, "When set to true Shipyard ignores cached images or files and will download all resources")
 	runCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
 	runCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+	runCmd.Flags().StringSliceVarP(&overlays, "overlay", "", nil, "Merge the resources from another blueprint into the blueprint, resources in the overlay replace resources with the same name. Can be specified multiple times")
+	runCmd.Flags().StringVarP(&strategy, "strategy", "", string(shipyard.StrategyDependency), "Order in which resources are created, one of dependency, type-grouped, file-order. Dependencies between resources are always respected")
+	runCmd.Flags().StringSliceVarP(&targets, "target", "", nil, "Only create the resource with the given id and the resources it depends on, e.g --target container.app. Can be specified multiple times")
+	runCmd.Flags().BoolVarP(&rollback, "rollback", "", false, "When set, resources created by the run are destroyed and the previous state is restored if the run fails")
 
 	return runCmd
 }
 
-func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients.System, vm gvm.Versions, cc clients.Connector, noOpen *bool, force *bool, runVersion *string, autoApprove *bool, variables *[]string, variablesFile *string, l hclog.Logger) func(cmd *cobra.Command, args []string) error {
+func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients.System, vm gvm.Versions, cc clients.Connector, noOpen *bool, force *bool, runVersion *string, autoApprove *bool, variables *[]string, variablesFile *string, overlays *[]string, strategy *string, targets *[]string, rollback *bool, l hclog.Logger) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		// create the shipyard and sub folders in the users home directory
 		utils.CreateFolders()
@@ -144,19 +158,33 @@ func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc cli
 			cmd.Println("Running configuration from: ", dst)
 			cmd.Println("")
 
-			if !utils.IsLocalFolder(dst) && !utils.IsHCLFile(dst) {
-				// fetch the remote server from github
-				err := bp.Get(dst, utils.GetBlueprintLocalFolder(dst))
+			dst, err = getBlueprintPath(bp, dst)
+			if err != nil {
+				return err
+			}
+		}
+
+		paths := []string{dst}
+		if overlays != nil {
+			for _, o := range *overlays {
+				cmd.Println("Applying overlay from: ", o)
+
+				op, err := getBlueprintPath(bp, o)
 				if err != nil {
-					return fmt.Errorf("Unable to retrieve blueprint: %s", err)
+					return err
 				}
 
-				dst = utils.GetBlueprintLocalFolder(dst)
+				paths = append(paths, op)
 			}
 		}
 
 		// Parse the config to check it is valid
-		err = e.ParseConfigWithVariables(dst, vars, *variablesFile)
+		if len(paths) > 1 {
+			err = e.ParseConfigWithOverlays(paths, vars, *variablesFile)
+		} else {
+			err = e.ParseConfigWithVariables(dst, vars, *variablesFile)
+		}
+
 		if err != nil {
 			return fmt.Errorf("Unable to read config: %s", err)
 		}
@@ -188,7 +216,28 @@ func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc cli
 			}
 		}()
 
-		res, err := e.ApplyWithVariables(dst, vars, *variablesFile)
+		opts := shipyard.ApplyOptions{Variables: vars, VariablesFile: *variablesFile, RefreshImages: *force}
+		if strategy != nil {
+			opts.Strategy = shipyard.ApplyStrategy(*strategy)
+		}
+
+		if targets != nil {
+			opts.Targets = *targets
+		}
+
+		if rollback != nil {
+			opts.Rollback = *rollback
+		}
+
+		var res []config.Resource
+		if opts.RefreshImages || len(opts.Targets) > 0 || opts.Rollback || (opts.Strategy != "" && opts.Strategy != shipyard.StrategyDependency) {
+			res, err = e.ApplyWithOptions(paths, opts)
+		} else if len(paths) > 1 {
+			res, err = e.ApplyWithOverlays(paths, vars, *variablesFile)
+		} else {
+			res, err = e.ApplyWithVariables(dst, vars, *variablesFile)
+		}
+
 		if err != nil {
 			return fmt.Errorf("Unable to apply blueprint: %s", err)
 		}
@@ -349,6 +398,22 @@ func bluePrintInState() bool {
 	return sc.Blueprint != nil
 }
 
+// getBlueprintPath returns the local path for a blueprint, remote blueprints
+// are downloaded to the local blueprint folder
+func getBlueprintPath(bp clients.Getter, dst string) (string, error) {
+	if utils.IsLocalFolder(dst) || utils.IsHCLFile(dst) || utils.IsYAMLFile(dst) {
+		return dst, nil
+	}
+
+	// fetch the remote server from github
+	err := bp.Get(dst, utils.GetBlueprintLocalFolder(dst))
+	if err != nil {
+		return "", fmt.Errorf("Unable to retrieve blueprint: %s", err)
+	}
+
+	return utils.GetBlueprintLocalFolder(dst), nil
+}
+
 func runWithOtherVersion(
 	version string,
 	autoApprove bool,
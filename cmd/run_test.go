@@ -78,6 +78,7 @@ func setupRun(t *testing.T, timeout string) (*cobra.Command, *runMocks) {
 	mockEngine := &mocks.Engine{}
 	mockEngine.On("ParseConfigWithVariables", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mockEngine.On("ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	mockEngine.On("ApplyWithOptions", mock.Anything, mock.Anything).Return(nil, nil)
 	mockEngine.On("GetClients", mock.Anything).Return(clients)
 	mockEngine.On("ResourceCountForType", mock.Anything).Return(0)
 
@@ -260,6 +261,40 @@ func TestRunSetsVariablesFileWhenPresent(t *testing.T) {
 	rm.engine.AssertCalled(t, "ApplyWithVariables", "/tmp", mock.Anything, tmpFile.Name())
 }
 
+func TestRunWithForceUpdateRefreshesImages(t *testing.T) {
+	rf, rm := setupRun(t, "")
+	rf.SetArgs([]string{"--force-update", "/tmp"})
+
+	err := rf.Execute()
+	assert.NoError(t, err)
+
+	opts := getCalls(&rm.engine.Mock, "ApplyWithOptions")[0].Arguments[1].(shipyard.ApplyOptions)
+	assert.True(t, opts.RefreshImages)
+}
+
+func TestRunWithStrategyCallsApplyWithOptions(t *testing.T) {
+	rf, rm := setupRun(t, "")
+	rf.SetArgs([]string{"--strategy=file-order", "/tmp"})
+
+	err := rf.Execute()
+	assert.NoError(t, err)
+
+	rm.engine.AssertCalled(t, "ApplyWithOptions", []string{"/tmp"}, mock.Anything)
+	opts := getCalls(&rm.engine.Mock, "ApplyWithOptions")[0].Arguments[1].(shipyard.ApplyOptions)
+	assert.Equal(t, shipyard.StrategyFileOrder, opts.Strategy)
+}
+
+func TestRunWithTargetCallsApplyWithOptions(t *testing.T) {
+	rf, rm := setupRun(t, "")
+	rf.SetArgs([]string{"--target=container.app", "/tmp"})
+
+	err := rf.Execute()
+	assert.NoError(t, err)
+
+	opts := getCalls(&rm.engine.Mock, "ApplyWithOptions")[0].Arguments[1].(shipyard.ApplyOptions)
+	assert.Equal(t, []string{"container.app"}, opts.Targets)
+}
+
 func TestRunSetsDestinationToDownloadedBlueprintFromArgsWhenRemote(t *testing.T) {
 	rf, rm := setupRun(t, "")
 	rf.SetArgs([]string{"github.com/shipyard-run/blueprints//vault-k8s"})
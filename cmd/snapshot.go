@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newSnapshotCmd(e shipyard.Engine) *cobra.Command {
+	return &cobra.Command{
+		Use:   "snapshot <resource> <dest>",
+		Short: "Saves the data in the volumes for a container",
+		Long: `Archives the data in the volumes and bind folders for a container resource,
+each volume is written to a separate file in the folder dest.
+The data can be restored with 'shipyard restore'.`,
+		Example: `
+  # snapshot the data for a container
+  shipyard snapshot container.postgres ./snapshots/postgres
+	`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := e.SnapshotData(args[0], args[1])
+			if err != nil {
+				return fmt.Errorf("Unable to snapshot data for %s: %s", args[0], err)
+			}
+
+			cmd.Printf("Saved snapshot for %s to %s\n", args[0], args[1])
+
+			return nil
+		},
+	}
+}
+
+func newRestoreCmd(e shipyard.Engine) *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <resource> <src>",
+		Short: "Restores the data in the volumes for a container from a snapshot",
+		Long: `Restores the data in the volumes and bind folders for a container resource
+from a snapshot created with 'shipyard snapshot', volumes without a snapshot
+in the folder src are not changed.`,
+		Example: `
+  # restore the data for a container
+  shipyard restore container.postgres ./snapshots/postgres
+	`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := e.RestoreData(args[0], args[1])
+			if err != nil {
+				return fmt.Errorf("Unable to restore data for %s: %s", args[0], err)
+			}
+
+			cmd.Printf("Restored snapshot for %s from %s\n", args[0], args[1])
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/shipyard/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotCallsSnapshotData(t *testing.T) {
+	me := &mocks.Engine{}
+	me.On("SnapshotData", "container.postgres", "./snapshots").Return(nil)
+
+	out := bytes.NewBufferString("")
+
+	c := newSnapshotCmd(me)
+	c.SetOut(out)
+	c.SetArgs([]string{"container.postgres", "./snapshots"})
+
+	err := c.Execute()
+	assert.NoError(t, err)
+
+	me.AssertCalled(t, "SnapshotData", "container.postgres", "./snapshots")
+	assert.Contains(t, out.String(), "Saved snapshot for container.postgres")
+}
+
+func TestSnapshotWithoutDestReturnsError(t *testing.T) {
+	me := &mocks.Engine{}
+
+	c := newSnapshotCmd(me)
+	c.SetOut(bytes.NewBufferString(""))
+	c.SetErr(bytes.NewBufferString(""))
+	c.SetArgs([]string{"container.postgres"})
+
+	err := c.Execute()
+	assert.Error(t, err)
+
+	me.AssertNotCalled(t, "SnapshotData")
+}
+
+func TestRestoreReturnsErrorWhenRestoreFails(t *testing.T) {
+	me := &mocks.Engine{}
+	me.On("RestoreData", "container.postgres", "./snapshots").Return(fmt.Errorf("boom"))
+
+	c := newRestoreCmd(me)
+	c.SetOut(bytes.NewBufferString(""))
+	c.SetErr(bytes.NewBufferString(""))
+	c.SetArgs([]string{"container.postgres", "./snapshots"})
+
+	err := c.Execute()
+	assert.Error(t, err)
+
+	me.AssertCalled(t, "RestoreData", "container.postgres", "./snapshots")
+}
@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"io"
+	"sort"
 
 	"github.com/hokaccha/go-prettyjson"
 	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
 	"github.com/shipyard-run/shipyard/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -27,110 +29,140 @@ const (
 	White   = "\033[1;37m%s\033[0m"
 )
 
-var jsonFlag bool
-var resourceType string
-
-var statusCmd = &cobra.Command{
-	Use:   "status",
-	Short: "Show the status of the current stack",
-	Long:  `Show the status of the current stack`,
-	Args:  cobra.NoArgs,
-	Run: func(cmd *cobra.Command, args []string) {
-		// load the stack
-		c := config.New()
-		err := c.FromJSON(utils.StatePath())
-		if err != nil {
-			fmt.Println("Unable to load state", err)
-			os.Exit(1)
-		}
-
-		if jsonFlag {
-			s, err := prettyjson.Marshal(c)
+func newStatusCmd(e shipyard.Engine) *cobra.Command {
+	var jsonFlag bool
+	var resourceType string
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the status of the current stack",
+		Long:  `Show the status of the current stack`,
+		Example: `
+  shipyard status --type container
+	`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// load the stack
+			c := config.New()
+			err := c.FromJSON(utils.StatePath())
 			if err != nil {
-				fmt.Println("Unable to load state", err)
-				os.Exit(1)
+				return fmt.Errorf("Unable to load state: %s", err)
 			}
 
-			fmt.Println(string(s))
-		} else {
-			fmt.Println()
-			fmt.Printf("%-13s %-30s %s\n", "STATUS", "RESOURCE", "FQDN")
-
-			createdCount := 0
-			failedCount := 0
-			pendingCount := 0
-
-			// sort the resources
-			resources := map[config.ResourceType][]config.Resource{}
+			if jsonFlag {
+				// do not display passwords and other sensitive values
+				rc, err := c.Redacted()
+				if err != nil {
+					return fmt.Errorf("Unable to load state: %s", err)
+				}
 
-			for _, r := range c.Resources {
-				if resources[r.Info().Type] == nil {
-					resources[r.Info().Type] = []config.Resource{}
+				s, err := prettyjson.Marshal(rc)
+				if err != nil {
+					return fmt.Errorf("Unable to load state: %s", err)
 				}
 
-				resources[r.Info().Type] = append(resources[r.Info().Type], r)
+				cmd.Println(string(s))
+				return nil
 			}
 
-			for _, ress := range resources {
-				for _, r := range ress {
-					if resourceType != "" && string(r.Info().Type) != resourceType {
-						continue
-					}
-
-					status := fmt.Sprintf(White, "[ PENDING ]  ")
-					switch r.Info().Status {
-					case config.Applied:
-						status = fmt.Sprintf(Green, "[ CREATED ]  ")
-						createdCount++
-					case config.Failed:
-						status = fmt.Sprintf(Red, "[ FAILED ]   ")
-						failedCount++
-					case config.Disabled:
-						status = fmt.Sprintf(Teal, "[ DISABLED ] ")
-						failedCount++
-					default:
-						pendingCount++
-					}
-
-					res := fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name)
-					fqdn := utils.FQDN(r.Info().Name, string(r.Info().Type))
-
-					switch r.Info().Type {
-					case config.TypeNomadCluster:
-						fmt.Printf("%-13s %-30s %s\n", status, res, fmt.Sprintf("%s.%s", "server", utils.FQDN(r.Info().Name, string(r.Info().Type))))
-
-						// add the client nodes
-						nomad := r.(*config.NomadCluster)
-						for n := 0; n < nomad.ClientNodes; n++ {
-							fmt.Printf("%-13s %-30s %s\n", "", "", fmt.Sprintf("%d.%s.%s", n+1, "client", utils.FQDN(r.Info().Name, string(r.Info().Type))))
-						}
-					case config.TypeK8sCluster:
-						fmt.Printf("%-13s %-30s %s\n", status, res, fmt.Sprintf("%s.%s", "server", utils.FQDN(r.Info().Name, string(r.Info().Type))))
-					case config.TypeContainer:
-						fallthrough
-					case config.TypeSidecar:
-						fallthrough
-					case config.TypeK8sIngress:
-						fallthrough
-					case config.TypeNomadIngress:
-						fallthrough
-					case config.TypeContainerIngress:
-						fallthrough
-					case config.TypeImageCache:
-						fmt.Printf("%-13s %-30s %s\n", status, res, fqdn)
-					default:
-						fmt.Printf("%-13s %-30s %s\n", status, res, "")
-					}
-				}
+			// the engine reports the status for resources which have not been processed
+			err = e.ParseConfig("")
+			if err != nil {
+				return fmt.Errorf("Unable to load state: %s", err)
 			}
 
-			fmt.Println()
-			fmt.Printf("Pending: %d Created: %d Failed: %d\n", pendingCount, createdCount, failedCount)
-		}
-	},
-}
+			printStatus(cmd.OutOrStdout(), c, e.Statuses(), resourceType)
+
+			return nil
+		},
+	}
 
-func init() {
 	statusCmd.Flags().BoolVarP(&jsonFlag, "json", "", false, "Output the status as JSON")
 	statusCmd.Flags().StringVarP(&resourceType, "type", "", "", "Resource type used to filter status list")
+
+	return statusCmd
+}
+
+// printStatus writes a table of the resources in c sorted by id with the
+// status from statuses, when resourceType is set only resources of that type
+// are written
+func printStatus(w io.Writer, c *config.Config, statuses map[string]string, resourceType string) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%-13s %-30s %-16s %s\n", "STATUS", "RESOURCE", "TYPE", "FQDN")
+
+	createdCount := 0
+	failedCount := 0
+	pendingCount := 0
+
+	// sort the resources
+	resources := []config.Resource{}
+	for _, r := range c.Resources {
+		if resourceType != "" && string(r.Info().Type) != resourceType {
+			continue
+		}
+
+		resources = append(resources, r)
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		return resourceID(resources[i]) < resourceID(resources[j])
+	})
+
+	for _, r := range resources {
+		res := resourceID(r)
+
+		status := fmt.Sprintf(White, "[ PENDING ]  ")
+		switch config.Status(statuses[res]) {
+		case config.Applied:
+			status = fmt.Sprintf(Green, "[ CREATED ]  ")
+			createdCount++
+		case config.Failed:
+			status = fmt.Sprintf(Red, "[ FAILED ]   ")
+			failedCount++
+		case config.Disabled:
+			status = fmt.Sprintf(Teal, "[ DISABLED ] ")
+			failedCount++
+		default:
+			pendingCount++
+		}
+
+		ty := string(r.Info().Type)
+		fqdn := utils.FQDN(r.Info().Name, ty)
+
+		switch r.Info().Type {
+		case config.TypeNomadCluster:
+			fmt.Fprintf(w, "%-13s %-30s %-16s %s\n", status, res, ty, fmt.Sprintf("%s.%s", "server", fqdn))
+
+			// add the client nodes
+			nomad := r.(*config.NomadCluster)
+			for n := 0; n < nomad.ClientNodes; n++ {
+				fmt.Fprintf(w, "%-13s %-30s %-16s %s\n", "", "", "", fmt.Sprintf("%d.%s.%s", n+1, "client", fqdn))
+			}
+		case config.TypeK8sCluster:
+			fmt.Fprintf(w, "%-13s %-30s %-16s %s\n", status, res, ty, fmt.Sprintf("%s.%s", "server", fqdn))
+		case config.TypeContainer:
+			fallthrough
+		case config.TypeSidecar:
+			fallthrough
+		case config.TypeK8sIngress:
+			fallthrough
+		case config.TypeNomadIngress:
+			fallthrough
+		case config.TypeContainerIngress:
+			fallthrough
+		case config.TypeImageCache:
+			fmt.Fprintf(w, "%-13s %-30s %-16s %s\n", status, res, ty, fqdn)
+		default:
+			fmt.Fprintf(w, "%-13s %-30s %-16s %s\n", status, res, ty, "")
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Pending: %d Created: %d Failed: %d\n", pendingCount, createdCount, failedCount)
+}
+
+// resourceID returns the id for a resource e.g. container.consul
+func resourceID(r config.Resource) string {
+	return fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name)
 }
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupStatusConfig() *config.Config {
+	c := config.New()
+	c.AddResource(config.NewNetwork("cloud"))
+	c.AddResource(config.NewContainer("consul"))
+	c.AddResource(config.NewContainer("vault"))
+
+	return c
+}
+
+func TestPrintStatusWritesSortedResourcesWithStatus(t *testing.T) {
+	out := bytes.NewBufferString("")
+	statuses := map[string]string{
+		"container.consul": string(config.Applied),
+		"container.vault":  string(config.Failed),
+		"network.cloud":    string(config.PendingCreation),
+	}
+
+	printStatus(out, setupStatusConfig(), statuses, "")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Contains(t, lines[1], "CREATED")
+	assert.Contains(t, lines[1], "container.consul")
+	assert.Contains(t, lines[1], "consul.container.shipyard.run")
+	assert.Contains(t, lines[2], "FAILED")
+	assert.Contains(t, lines[2], "container.vault")
+	assert.Contains(t, lines[3], "PENDING")
+	assert.Contains(t, lines[3], "network.cloud")
+	assert.Contains(t, out.String(), "Pending: 1 Created: 1 Failed: 1")
+}
+
+func TestPrintStatusFiltersByType(t *testing.T) {
+	out := bytes.NewBufferString("")
+
+	printStatus(out, setupStatusConfig(), map[string]string{}, "network")
+
+	assert.Contains(t, out.String(), "network.cloud")
+	assert.NotContains(t, out.String(), "container.consul")
+	assert.Contains(t, out.String(), "Pending: 1 Created: 0 Failed: 0")
+}
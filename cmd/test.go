@@ -277,6 +277,10 @@ func (cr *CucumberRunner) iRunApplyAtPathWithVersion(fp, version string) error {
 		&approve,
 		&cr.variables,
 		&cr.variablesFile,
+		nil,
+		nil,
+		nil,
+		nil,
 		cr.l,
 	)
 
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newValidateCmd(e shipyard.Engine) *cobra.Command {
+	var variables []string
+	var variablesFile string
+
+	validateCmd := &cobra.Command{
+		Use:   "validate [file] | [directory]",
+		Short: "Check the configuration is valid",
+		Long: `Check the configuration is valid.
+The state is not read or changed so validate can be run on machines which
+do not have an existing environment, e.g. in a pre-commit hook.`,
+		Example: `
+  shipyard validate ./blueprint
+	`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// parse the vars into a map
+			vars := map[string]string{}
+			for _, v := range variables {
+				parts := strings.Split(v, "=")
+				if len(parts) == 2 {
+					vars[parts[0]] = parts[1]
+				}
+			}
+
+			err := e.Validate(args[0], vars, variablesFile)
+			if err != nil {
+				return err
+			}
+
+			cmd.Println("Configuration is valid")
+
+			return nil
+		},
+	}
+
+	validateCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	validateCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+
+	return validateCmd
+}
@@ -1,11 +1,17 @@
 package clients
 
 import (
+	"context"
 	"io"
+	"time"
 
 	"github.com/shipyard-run/shipyard/pkg/config"
 )
 
+// DefaultStopTimeout is the time a container is given to stop gracefully
+// before it is killed when it is removed
+const DefaultStopTimeout = 30 * time.Second
+
 // ContainerTasks is a task oriented client which abstracts
 // the underlying container technology from the providers
 // this allows different concrete implementations such as Docker, or ContainerD
@@ -16,6 +22,10 @@ import (
 // this may be composed of many individual SDK calls.
 type ContainerTasks interface {
 	SetForcePull(bool)
+	// SetWorkspace sets the workspace used to name, label, and find containers
+	SetWorkspace(name string)
+	// Workspace returns the workspace used to name, label, and find containers
+	Workspace() string
 	// CreateContainer creates a new container for the given configuration
 	// if successful CreateContainer returns the ID of the created container and a nil error
 	// if not successful CreateContainer returns a blank string for the id and an error message
@@ -25,6 +35,13 @@ type ContainerTasks interface {
 	ContainerInfo(id string) (interface{}, error)
 	// RemoveContainer stops and removes a running container
 	RemoveContainer(id string, force bool) error
+	// RemoveContainerWithOptions stops and removes a running container,
+	// when removeVolumes is false any anonymous volumes for the container are preserved.
+	// stopTimeout is the time the container is given to stop, 0 uses DefaultStopTimeout
+	RemoveContainerWithOptions(id string, force, removeVolumes bool, stopTimeout time.Duration) error
+	// RemoveDanglingVolumes removes volumes created by Shipyard which are no longer
+	// referenced by a container, returns the names of the removed volumes
+	RemoveDanglingVolumes() ([]string, error)
 	// BuildContainer builds a container based on the given configuration
 	// If a cahced image already exists Build will noop
 	// When force is specificed BuildContainer will rebuild the container regardless of cached images
@@ -44,10 +61,19 @@ type ContainerTasks interface {
 	PullImage(image config.Image, force bool) error
 	// FindContainerIDs returns the Container IDs for the given identifier
 	FindContainerIDs(name string, typeName config.ResourceType) ([]string, error)
+	// FindResourceContainerIDs returns the IDs of the containers in the active
+	// workspace labelled with the resource id e.g. k8s_cluster.k3s, this includes
+	// the containers created by the resource for child resources
+	FindResourceContainerIDs(resourceID string) ([]string, error)
 	// ContainerLogs attaches to the container and streams the logs to the returned
 	// io.ReadCloser.
 	// Returns an error if the container is not running
 	ContainerLogs(id string, stdOut, stdErr bool) (io.ReadCloser, error)
+	// ContainerLogsFollow writes the logs for the container to stdOut and stdErr,
+	// blocking and following new output until ctx is cancelled or the container stops.
+	// tail limits the number of existing lines shown, when follow is false only the
+	// existing logs are written
+	ContainerLogsFollow(ctx context.Context, id, tail string, follow bool, stdOut, stdErr io.Writer) error
 	// CopyFromContainer allows the copying of a file from a container
 	CopyFromContainer(id, src, dst string) error
 	// CopyToContainer allows a file to be copied into a container
@@ -60,6 +86,12 @@ type ContainerTasks interface {
 
 	//CopyFilesToVolume copies the files to the path in a Docker volume
 	CopyFilesToVolume(volume string, files []string, path string, force bool) ([]string, error)
+	// SnapshotVolume archives the contents of a volume or local folder to the
+	// gzipped tar file dest
+	SnapshotVolume(ctx context.Context, volumeOrPath, dest string) error
+	// RestoreVolume restores a snapshot created by SnapshotVolume to a volume or
+	// local folder
+	RestoreVolume(ctx context.Context, src, volumeOrPath string) error
 	// Execute command allows the execution of commands in a running docker container
 	// id is the id of the container to execute the command in
 	// command is a slice of strings to execute
@@ -3,6 +3,7 @@ package clients
 import (
 	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/base64"
 	"fmt"
@@ -12,6 +13,8 @@ import (
 	gosignal "os/signal"
 	"path"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -25,6 +28,7 @@ import (
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/pkg/signal"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/docker/pkg/term"
 	"github.com/docker/go-connections/nat"
 	"github.com/hashicorp/go-hclog"
@@ -41,11 +45,36 @@ type DockerTasks struct {
 	l     hclog.Logger
 	tg    *TarGz
 	force bool
+
+	// workspace is used to name and label the containers and networks
+	workspace string
+
+	// pullProgress is called with the progress of image pulls
+	pullProgress PullProgressFunc
+	pullInterval time.Duration
+}
+
+// DockerTasksOption sets optional configuration for DockerTasks
+type DockerTasksOption func(*DockerTasks)
+
+// WithTimeout sets a deadline for Docker API calls, if the daemon does not respond
+// within the timeout the operation returns an error rather than blocking forever
+func WithTimeout(t time.Duration) DockerTasksOption {
+	return func(d *DockerTasks) {
+		d.c = NewTimeoutDocker(d.c, t)
+	}
 }
 
 // NewDockerTasks creates a DockerTasks with the given Docker client
-func NewDockerTasks(c Docker, il ImageLog, tg *TarGz, l hclog.Logger) *DockerTasks {
-	return &DockerTasks{c: c, il: il, tg: tg, l: l}
+func NewDockerTasks(c Docker, il ImageLog, tg *TarGz, l hclog.Logger, opts ...DockerTasksOption) *DockerTasks {
+	d := &DockerTasks{c: c, il: il, tg: tg, l: l, workspace: utils.Workspace(), pullInterval: pullProgressInterval}
+	d.pullProgress = d.logPullProgress
+
+	for _, o := range opts {
+		o(d)
+	}
+
+	return d
 }
 
 // SetForcePull sets a global override for the DockerTasks, when set to true
@@ -54,6 +83,17 @@ func (d *DockerTasks) SetForcePull(force bool) {
 	d.force = force
 }
 
+// SetWorkspace sets the workspace used to name and find containers, by default
+// the workspace selected by the environment is used
+func (d *DockerTasks) SetWorkspace(name string) {
+	d.workspace = name
+}
+
+// Workspace returns the workspace used to name and find containers
+func (d *DockerTasks) Workspace() string {
+	return d.workspace
+}
+
 // CreateContainer creates a new Docker container for the given configuation
 func (d *DockerTasks) CreateContainer(c *config.Container) (string, error) {
 	d.l.Debug("Creating Docker Container", "ref", c.Name)
@@ -80,6 +120,11 @@ func (d *DockerTasks) CreateContainer(c *config.Container) (string, error) {
 		user = fmt.Sprintf("%s:%s", c.RunAs.User, c.RunAs.Group)
 	}
 
+	// containers are labelled with their name so they can be found without
+	// depending on the naming scheme, see FindContainerIDs
+	labels := ResourceLabels(c.Info().OwnerID(), c.Type, d.workspace)
+	labels[LabelName] = c.Name
+
 	// create the container config
 	dc := &container.Config{
 		Hostname:     c.Name,
@@ -91,13 +136,30 @@ func (d *DockerTasks) CreateContainer(c *config.Container) (string, error) {
 		AttachStdout: true,
 		AttachStderr: true,
 		User:         user,
+		Labels:       labels,
 	}
 
 	// create the host and network configs
 	hc := &container.HostConfig{}
 	nc := &network.NetworkingConfig{}
 
-	if c.MaxRestartCount > 0 {
+	// set custom nameservers for the container
+	if len(c.DNS) > 0 {
+		hc.DNS = c.DNS
+	}
+
+	// set the logging driver for the container
+	if c.LogDriver != "" {
+		hc.LogConfig = container.LogConfig{Type: c.LogDriver, Config: c.LogOpts}
+	}
+
+	switch {
+	case c.Restart != "":
+		hc.RestartPolicy = container.RestartPolicy{Name: c.Restart}
+		if c.Restart == "on-failure" {
+			hc.RestartPolicy.MaximumRetryCount = c.MaxRestartCount
+		}
+	case c.MaxRestartCount > 0:
 		hc.RestartPolicy = container.RestartPolicy{Name: "on-failure", MaximumRetryCount: c.MaxRestartCount}
 	}
 
@@ -161,13 +223,35 @@ func (d *DockerTasks) CreateContainer(c *config.Container) (string, error) {
 			}
 		}
 
+		// SELinux labels are not supported by the mount API and can only be
+		// set using the bind syntax src:dest:options
+		if t == mount.TypeBind && vc.SELinuxLabel != "" {
+			opts := []string{vc.SELinuxLabel}
+			if vc.ReadOnly {
+				opts = append(opts, "ro")
+			}
+
+			if vc.Propagation != "" {
+				opts = append(opts, vc.Propagation)
+			}
+
+			hc.Binds = append(hc.Binds, fmt.Sprintf("%s:%s:%s", vc.Source, vc.Destination, strings.Join(opts, ",")))
+			continue
+		}
+
 		// create the mount
-		mounts = append(mounts, mount.Mount{
+		m := mount.Mount{
 			Type:     t,
 			Source:   vc.Source,
 			Target:   vc.Destination,
 			ReadOnly: vc.ReadOnly,
-		})
+		}
+
+		if t == mount.TypeBind && vc.Propagation != "" {
+			m.BindOptions = &mount.BindOptions{Propagation: mount.Propagation(vc.Propagation)}
+		}
+
+		mounts = append(mounts, m)
 	}
 
 	hc.Mounts = mounts
@@ -223,12 +307,25 @@ func (d *DockerTasks) CreateContainer(c *config.Container) (string, error) {
 		}
 	}
 
+	// has a network mode been set, if so the container uses this directly
+	// and is not attached to any networks
+	if c.NetworkMode != "" {
+		d.l.Debug("Setting container network mode", "ref", c.Name, "network_mode", c.NetworkMode)
+
+		hc.NetworkMode = container.NetworkMode(c.NetworkMode)
+
+		// host and container networking can not be used with a hostname
+		if hc.NetworkMode.IsHost() || hc.NetworkMode.IsContainer() {
+			dc.Hostname = ""
+		}
+	}
+
 	cont, err := d.c.ContainerCreate(
 		context.Background(),
 		dc,
 		hc,
 		nc,
-		utils.FQDN(c.Name, string(c.Type)),
+		utils.WorkspaceFQDN(c.Name, string(c.Type), d.workspace),
 	)
 	if err != nil {
 		return "", err
@@ -236,8 +333,8 @@ func (d *DockerTasks) CreateContainer(c *config.Container) (string, error) {
 
 	// first remove the container from the bridge network if we are adding custom networks
 	// all containers should have custom networks
-	// only add networks if we are not adding the container network
-	if len(c.Networks) > 0 && !hc.NetworkMode.IsContainer() {
+	// only add networks if we are not using the container network or a custom network mode
+	if len(c.Networks) > 0 && hc.NetworkMode == "" {
 		err := d.c.NetworkDisconnect(context.Background(), "bridge", cont.ID, true)
 		if err != nil {
 			return "", xerrors.Errorf("Unable to remove container from the default bridge network: %w", err)
@@ -254,7 +351,7 @@ func (d *DockerTasks) CreateContainer(c *config.Container) (string, error) {
 				return "", xerrors.Errorf("Network not found: %w", err)
 			}
 
-			err = d.AttachNetwork(net.Info().Name, cont.ID, n.Aliases, n.IPAddress)
+			err = d.attachNetwork(d.networkName(net), cont.ID, n.Aliases, n.IPAddress, n.MacAddress)
 
 			if err != nil {
 				// if we fail to connect to the network roll back the container
@@ -301,15 +398,19 @@ func (d *DockerTasks) PullImage(image config.Image, force bool) error {
 			return xerrors.Errorf("unable to list images in local Docker cache: %w", err)
 		}
 
-		// if we have images do not pull
-		if len(sum) > 0 {
+		// if we have images do not pull, unless a platform has been set which
+		// does not match the cached image
+		if len(sum) > 0 && d.imageMatchesPlatform(image) {
 			d.l.Debug("Image exists in local cache", "image", image.Name)
 
 			return nil
 		}
 	}
 
-	ipo := types.ImagePullOptions{}
+	ipo := types.ImagePullOptions{Platform: image.Platform}
+	if ipo.Platform == "" {
+		ipo.Platform = hostPlatform()
+	}
 
 	// if the username and password is not null make an authenticated
 	// image pull
@@ -317,34 +418,85 @@ func (d *DockerTasks) PullImage(image config.Image, force bool) error {
 		ipo.RegistryAuth = createRegistryAuth(image.Username, image.Password)
 	}
 
-	d.l.Debug("Pulling image", "image", image.Name)
+	d.l.Debug("Pulling image", "image", image.Name, "platform", ipo.Platform)
 
 	out, err := d.c.ImagePull(context.Background(), in, ipo)
 	if err != nil {
 		return xerrors.Errorf("Error pulling image: %w", err)
 	}
 
+	defer out.Close()
+
+	// the image has been pulled when the progress stream ends
+	err = d.readPullProgress(in, out)
+	if err != nil {
+		return err
+	}
+
 	// update the image log
 	err = d.il.Log(in, ImageTypeDocker)
 	if err != nil {
 		d.l.Error("Unable to add image name to cache", "error", err)
 	}
 
-	// write the output to /dev/null
-	// TODO this stuff needs to be logged correctly
-	io.Copy(ioutil.Discard, out)
-
 	return nil
 }
 
-// FindContainerIDs returns the Container IDs for the given identifier
-func (d *DockerTasks) FindContainerIDs(containerName string, typeName config.ResourceType) ([]string, error) {
-	fullName := utils.FQDN(containerName, string(typeName))
+// imageMatchesPlatform returns true when the platform for the image is not set
+// or the os and architecture of the image in the local cache match the platform.
+// Containers are created from the cached image so pulling the image for the
+// platform ensures the container runs on the correct architecture.
+func (d *DockerTasks) imageMatchesPlatform(image config.Image) bool {
+	if image.Platform == "" {
+		return true
+	}
+
+	ii, _, err := d.c.ImageInspectWithRaw(context.Background(), image.Name)
+	if err != nil {
+		d.l.Debug("Unable to inspect image", "image", image.Name, "error", err)
+		return false
+	}
 
+	parts := strings.Split(image.Platform, "/")
+
+	return ii.Os == parts[0] && ii.Architecture == parts[1]
+}
+
+// hostPlatform returns the Docker platform for the machine running Shipyard
+func hostPlatform() string {
+	return "linux/" + runtime.GOARCH
+}
+
+// FindContainerIDs returns the Container IDs for the given identifier, containers
+// are found using their labels, containers created before labels were added to
+// containers are found using their fully qualified name
+func (d *DockerTasks) FindContainerIDs(containerName string, typeName config.ResourceType) ([]string, error) {
 	args := filters.NewArgs()
+	args.Add("label", fmt.Sprintf("%s=%s", LabelName, containerName))
+	args.Add("label", fmt.Sprintf("%s=%s", LabelType, typeName))
+
+	// the image cache is shared by all workspaces
+	if string(typeName) != utils.CacheResourceType {
+		args.Add("label", fmt.Sprintf("%s=%s", LabelWorkspace, d.workspace))
+	}
+
+	ids, err := d.listContainerIDs(args)
+	if err != nil || ids != nil {
+		return ids, err
+	}
+
+	fullName := utils.WorkspaceFQDN(containerName, string(typeName), d.workspace)
+
+	args = filters.NewArgs()
 	// By default Docker will wildcard searches, use regex to return the absolute
 	args.Add("name", fmt.Sprintf("^/%s$", fullName))
 
+	return d.listContainerIDs(args)
+}
+
+// listContainerIDs returns the ids of the containers matching the filter,
+// nil is returned when no containers match
+func (d *DockerTasks) listContainerIDs(args filters.Args) ([]string, error) {
 	opts := types.ContainerListOptions{Filters: args, All: true}
 
 	cl, err := d.c.ContainerList(context.Background(), opts)
@@ -364,16 +516,52 @@ func (d *DockerTasks) FindContainerIDs(containerName string, typeName config.Res
 	return nil, nil
 }
 
+// FindResourceContainerIDs returns the IDs of the containers in the active
+// workspace which are labelled with the given resource id
+func (d *DockerTasks) FindResourceContainerIDs(resourceID string) ([]string, error) {
+	args := filters.NewArgs()
+	args.Add("label", fmt.Sprintf("%s=%s", LabelResourceID, resourceID))
+	args.Add("label", fmt.Sprintf("%s=%s", LabelWorkspace, d.workspace))
+
+	cl, err := d.c.ContainerList(context.Background(), types.ContainerListOptions{Filters: args, All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, c := range cl {
+		ids = append(ids, c.ID)
+	}
+
+	return ids, nil
+}
+
 // RemoveContainer with the given id
 func (d *DockerTasks) RemoveContainer(id string, force bool) error {
+	return d.RemoveContainerWithOptions(id, force, true, 0)
+}
+
+// RemoveContainerWithOptions removes the container with the given id, when
+// removeVolumes is false anonymous volumes are not removed with the container.
+// The container is given stopTimeout to stop gracefully before it is killed,
+// when stopTimeout is 0 DefaultStopTimeout is used.
+func (d *DockerTasks) RemoveContainerWithOptions(id string, force, removeVolumes bool, stopTimeout time.Duration) error {
+	if !removeVolumes {
+		d.logAnonymousVolumes(id)
+	}
+
 	var err error
 	if !force {
 		// try and shutdown graceful
-		timeout := 30 * time.Second
+		timeout := DefaultStopTimeout
+		if stopTimeout > 0 {
+			timeout = stopTimeout
+		}
+
 		err = d.c.ContainerStop(context.Background(), id, &timeout)
 		if err == nil {
 			d.l.Debug("Container stopped gracefully, removing", "container", id)
-			err = d.c.ContainerRemove(context.Background(), id, types.ContainerRemoveOptions{Force: false, RemoveVolumes: true})
+			err = d.c.ContainerRemove(context.Background(), id, types.ContainerRemoveOptions{Force: false, RemoveVolumes: removeVolumes})
 			if err == nil {
 				return nil
 			}
@@ -384,7 +572,32 @@ func (d *DockerTasks) RemoveContainer(id string, force bool) error {
 
 	// unable to shutdown graceful try force
 	d.l.Debug("Forcefully remove", "container", id)
-	return d.c.ContainerRemove(context.Background(), id, types.ContainerRemoveOptions{Force: true, RemoveVolumes: true})
+	return d.c.ContainerRemove(context.Background(), id, types.ContainerRemoveOptions{Force: true, RemoveVolumes: removeVolumes})
+}
+
+// logAnonymousVolumes records the anonymous volumes used by a container created
+// by Shipyard so that RemoveDanglingVolumes can remove them once the container
+// has been removed
+func (d *DockerTasks) logAnonymousVolumes(id string) {
+	cj, err := d.c.ContainerInspect(context.Background(), id)
+	if err != nil || cj.Config == nil {
+		return
+	}
+
+	if _, ok := cj.Config.Labels[LabelResourceID]; !ok {
+		return
+	}
+
+	for _, m := range cj.Mounts {
+		if m.Type != mount.TypeVolume || !anonymousVolume.MatchString(m.Name) {
+			continue
+		}
+
+		err := d.il.Log(m.Name, ImageTypeVolume)
+		if err != nil {
+			d.l.Warn("Unable to record volume", "container", id, "volume", m.Name, "error", err)
+		}
+	}
 }
 
 func (d *DockerTasks) BuildContainer(config *config.Container, force bool) (string, error) {
@@ -420,6 +633,14 @@ func (d *DockerTasks) BuildContainer(config *config.Container, force bool) (stri
 		Tags:       []string{imageName},
 	}
 
+	if len(config.Build.Args) > 0 {
+		buildOpts.BuildArgs = map[string]*string{}
+		for k, v := range config.Build.Args {
+			v := v
+			buildOpts.BuildArgs[k] = &v
+		}
+	}
+
 	var buf bytes.Buffer
 	d.tg.Compress(&buf, &TarGzOptions{OmitRoot: true}, config.Build.Context)
 
@@ -465,6 +686,7 @@ func (d *DockerTasks) CreateVolume(name string) (string, error) {
 		Name:       vn,
 		Driver:     "local", //TODO: allow setting driver + opts
 		DriverOpts: map[string]string{},
+		Labels:     ResourceLabels(name, "volume", d.workspace),
 	}
 
 	vol, err := d.c.VolumeCreate(context.Background(), volumeCreateOptions)
@@ -483,11 +705,96 @@ func (d *DockerTasks) RemoveVolume(name string) error {
 	return d.c.VolumeRemove(context.Background(), vn, true)
 }
 
+// RemoveDanglingVolumes removes volumes created by Shipyard which are not used by any
+// container. Volumes are removed when they have Shipyard labels or when they are
+// anonymous volumes recorded when a Shipyard container was removed, volumes
+// created by other tools are never removed.
+func (d *DockerTasks) RemoveDanglingVolumes() ([]string, error) {
+	args := filters.NewArgs()
+	args.Add("dangling", "true")
+
+	vols, err := d.c.VolumeList(context.Background(), args)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to list dangling volumes: %w", err)
+	}
+
+	// ignore errors as the log may not exist
+	logged, _ := d.il.Read(ImageTypeVolume)
+
+	owned := map[string]bool{}
+	for _, v := range logged {
+		owned[v] = true
+	}
+
+	removed := []string{}
+	for _, v := range vols.Volumes {
+		if _, ok := v.Labels[LabelResourceID]; !ok && !owned[v.Name] {
+			continue
+		}
+
+		d.l.Debug("Removing dangling volume", "name", v.Name)
+
+		err := d.c.VolumeRemove(context.Background(), v.Name, false)
+		if err != nil {
+			return removed, xerrors.Errorf("unable to remove volume %s: %w", v.Name, err)
+		}
+
+		removed = append(removed, v.Name)
+	}
+
+	return removed, nil
+}
+
+var anonymousVolume = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
 // ContainerLogs streams the logs for the container to the returned io.ReadCloser
 func (d *DockerTasks) ContainerLogs(id string, stdOut, stdErr bool) (io.ReadCloser, error) {
 	return d.c.ContainerLogs(context.Background(), id, types.ContainerLogsOptions{ShowStderr: stdErr, ShowStdout: stdOut})
 }
 
+// ContainerLogsFollow writes the logs for the container to stdOut and stdErr following
+// new output until ctx is cancelled or the container stops. Docker multiplexes both
+// streams into a single response which is separated before writing, when a writer
+// is nil that stream is not requested. tail is the number of lines to show from the
+// end of the logs, an empty string or "all" shows every line. When follow is false
+// the existing logs are written without waiting for new output.
+func (d *DockerTasks) ContainerLogsFollow(ctx context.Context, id, tail string, follow bool, stdOut, stdErr io.Writer) error {
+	opts := types.ContainerLogsOptions{ShowStdout: stdOut != nil, ShowStderr: stdErr != nil, Follow: follow, Tail: tail}
+
+	rc, err := d.c.ContainerLogs(ctx, id, opts)
+	if err != nil {
+		return xerrors.Errorf("Unable to get logs for container %s: %w", id, err)
+	}
+	defer rc.Close()
+
+	// closing the stream unblocks the copy when the context is cancelled
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			rc.Close()
+		case <-done:
+		}
+	}()
+
+	if stdOut == nil {
+		stdOut = ioutil.Discard
+	}
+
+	if stdErr == nil {
+		stdErr = ioutil.Discard
+	}
+
+	_, err = stdcopy.StdCopy(stdOut, stdErr, rc)
+	if err != nil && ctx.Err() == nil {
+		return xerrors.Errorf("Unable to read logs for container %s: %w", id, err)
+	}
+
+	return nil
+}
+
 // CopyFromContainer copies a file from a container
 func (d *DockerTasks) CopyFromContainer(id, src, dst string) error {
 	d.l.Debug("Copying file from", "id", id, "src", src, "dst", dst)
@@ -604,7 +911,7 @@ func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, pat
 			}
 		}
 
-		err = d.CopyFileToContainer(utils.FQDN(cc.Name, string(cc.Type)), f, destPath)
+		err = d.CopyFileToContainer(utils.WorkspaceFQDN(cc.Name, string(cc.Type), d.workspace), f, destPath)
 		if err != nil {
 			return nil, fmt.Errorf("Unable to copy file %s to container: %s", f, err)
 		}
@@ -615,6 +922,158 @@ func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, pat
 	return imported, nil
 }
 
+// SnapshotVolume archives the contents of a Docker volume or a local folder
+// to a gzipped tar file at dest.
+// If volumeOrPath is an absolute path it is treated as a local folder which is
+// used for bind mounts, otherwise volumeOrPath is treated as the name of a Docker volume.
+func (d *DockerTasks) SnapshotVolume(ctx context.Context, volumeOrPath, dest string) error {
+	d.l.Debug("Creating snapshot", "source", volumeOrPath, "dest", dest)
+
+	// Docker volume names can not be absolute paths
+	if filepath.IsAbs(volumeOrPath) {
+		fi, err := os.Stat(volumeOrPath)
+		if err != nil || !fi.IsDir() {
+			return xerrors.Errorf("unable to snapshot %s, the path is not a folder", volumeOrPath)
+		}
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return xerrors.Errorf("unable to create snapshot file: %w", err)
+	}
+	defer f.Close()
+
+	// local folders can be archived directly
+	if filepath.IsAbs(volumeOrPath) {
+		err := d.tg.Compress(f, &TarGzOptions{OmitRoot: true}, volumeOrPath)
+		if err != nil {
+			return xerrors.Errorf("unable to archive folder %s: %w", volumeOrPath, err)
+		}
+
+		return nil
+	}
+
+	tmpID, err := d.createVolumeContainer(volumeOrPath)
+	if err != nil {
+		return err
+	}
+	defer d.RemoveContainer(tmpID, true)
+
+	// copy the contents of the volume, Docker returns a tar stream
+	reader, _, err := d.c.CopyFromContainer(ctx, tmpID, "/data/.")
+	if err != nil {
+		return xerrors.Errorf("unable to copy data from volume %s: %w", volumeOrPath, err)
+	}
+	defer reader.Close()
+
+	zw := gzip.NewWriter(f)
+
+	_, err = io.Copy(zw, reader)
+	if err != nil {
+		return xerrors.Errorf("unable to write snapshot: %w", err)
+	}
+
+	return zw.Close()
+}
+
+// RestoreVolume restores a snapshot created with SnapshotVolume into
+// a Docker volume or local folder, any existing contents are removed so
+// that the volume or folder only contains the snapshot
+func (d *DockerTasks) RestoreVolume(ctx context.Context, src, volumeOrPath string) error {
+	d.l.Debug("Restoring snapshot", "source", src, "dest", volumeOrPath)
+
+	f, err := os.Open(src)
+	if err != nil {
+		return xerrors.Errorf("unable to open snapshot file: %w", err)
+	}
+	defer f.Close()
+
+	// Docker volume names can not be absolute paths
+	if filepath.IsAbs(volumeOrPath) {
+		err := clearFolder(volumeOrPath)
+		if err != nil {
+			return xerrors.Errorf("unable to remove the contents of folder %s: %w", volumeOrPath, err)
+		}
+
+		err = d.tg.Uncompress(f, volumeOrPath)
+		if err != nil {
+			return xerrors.Errorf("unable to restore folder %s: %w", volumeOrPath, err)
+		}
+
+		return nil
+	}
+
+	tmpID, err := d.createVolumeContainer(volumeOrPath)
+	if err != nil {
+		return err
+	}
+	defer d.RemoveContainer(tmpID, true)
+
+	err = d.ExecuteCommand(tmpID, []string{"find", "/data", "-mindepth", "1", "-delete"}, nil, "/", "", "", nil)
+	if err != nil {
+		return xerrors.Errorf("unable to remove the contents of volume %s: %w", volumeOrPath, err)
+	}
+
+	// Docker accepts gzipped tar archives so the snapshot can be sent as is
+	err = d.c.CopyToContainer(ctx, tmpID, "/data", f, types.CopyToContainerOptions{})
+	if err != nil {
+		return xerrors.Errorf("unable to copy data to volume %s: %w", volumeOrPath, err)
+	}
+
+	return nil
+}
+
+// clearFolder removes the contents of the folder at path, the folder
+// is created when it does not exist
+func clearFolder(path string) error {
+	err := os.MkdirAll(path, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return err
+	}
+
+	for _, fi := range files {
+		err := os.RemoveAll(filepath.Join(path, fi.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createVolumeContainer creates a temporary container with the given volume
+// mounted at /data, it is the responsibility of the caller to remove the container
+func (d *DockerTasks) createVolumeContainer(volume string) (string, error) {
+	err := d.PullImage(config.Image{Name: "alpine:latest"}, false)
+	if err != nil {
+		return "", xerrors.Errorf("Unable pull alpine:latest for accessing volume: %w", err)
+	}
+
+	cc := config.NewContainer(fmt.Sprintf("%d-volume", time.Now().UnixNano()))
+
+	cc.Image = &config.Image{Name: "alpine:latest"}
+	cc.Volumes = []config.Volume{
+		config.Volume{
+			Source:      volume,
+			Destination: "/data",
+			Type:        "volume",
+		},
+	}
+	cc.Command = []string{"tail", "-f", "/dev/null"}
+
+	id, err := d.CreateContainer(cc)
+	if err != nil {
+		return "", xerrors.Errorf("Unable to create dummy container for accessing volume: %w", err)
+	}
+
+	return id, nil
+}
+
 // CopyFileToContainer copies the file at path filename to the container containerID and
 // stores it in the container at the path path.
 func (d *DockerTasks) CopyFileToContainer(containerID, filename, path string) error {
@@ -759,7 +1218,18 @@ func (d *DockerTasks) ExecuteCommand(id string, command []string, env []string,
 
 // TODO: this is all exploritory, works but needs a major tidy
 
-// CreateShell creates an interactive shell inside a container
+// ExecExitError is returned when a command run in a container exits with
+// a non zero exit code
+type ExecExitError struct {
+	ExitCode int
+}
+
+func (e ExecExitError) Error() string {
+	return fmt.Sprintf("container exec failed with exit code %d", e.ExitCode)
+}
+
+// CreateShell creates an interactive shell inside a container, when the
+// command exits with a non zero code an ExecExitError is returned
 // https://github.com/docker/cli/blob/ae1618713f83e7da07317d579d0675f578de22fa/cli/command/container/exec.go
 func (d *DockerTasks) CreateShell(id string, command []string, stdin io.ReadCloser, stdout io.Writer, stderr io.Writer) error {
 	execid, err := d.c.ContainerExecCreate(context.Background(), id, types.ExecConfig{
@@ -833,7 +1303,7 @@ func (d *DockerTasks) CreateShell(id string, command []string, stdin io.ReadClos
 			}
 
 			streamCancel()
-			return xerrors.Errorf("container exec failed with exit code %d", i.ExitCode)
+			return ExecExitError{ExitCode: i.ExitCode}
 		}
 
 		time.Sleep(1 * time.Second)
@@ -882,6 +1352,10 @@ func (d *DockerTasks) resizeTTY(id string, out *streams.Out) error {
 }
 
 func (d *DockerTasks) AttachNetwork(net, containerid string, aliases []string, ipaddress string) error {
+	return d.attachNetwork(net, containerid, aliases, ipaddress, "")
+}
+
+func (d *DockerTasks) attachNetwork(net, containerid string, aliases []string, ipaddress, macaddress string) error {
 	d.l.Debug("Attaching container to network", "ref", containerid, "network", net)
 	es := &network.EndpointSettings{NetworkID: net}
 
@@ -896,9 +1370,25 @@ func (d *DockerTasks) AttachNetwork(net, containerid string, aliases []string, i
 		es.IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: ipaddress}
 	}
 
+	// are we setting a fixed mac address
+	if macaddress != "" {
+		d.l.Debug("Assigning mac address", "ref", containerid, "network", net, "mac_address", macaddress)
+		es.MacAddress = macaddress
+	}
+
 	return d.c.NetworkConnect(context.Background(), net, containerid, es)
 }
 
+// networkName returns the name of the Docker network for the resource n, networks
+// managed by Shipyard include the workspace in their name
+func (d *DockerTasks) networkName(n config.Resource) string {
+	if nw, ok := n.(*config.Network); ok && !nw.External {
+		return utils.NetworkName(nw.Name, d.workspace)
+	}
+
+	return n.Info().Name
+}
+
 // ListNetworks lists the networks a container is attached to
 func (d *DockerTasks) ListNetworks(id string) []config.NetworkAttachment {
 	return nil
@@ -955,26 +1445,18 @@ func createPublishedPortRanges(ps []config.PortRange) (publishedPorts, error) {
 	}
 
 	for _, p := range ps {
-		// split the range
-		parts := strings.Split(p.Range, "-")
-		if len(parts) != 2 {
-			return pp, fmt.Errorf("Invalid port range, range should be written start-end, e.g 80-82")
+		err := p.Validate()
+		if err != nil {
+			return pp, err
 		}
 
-		// ensure the start is less than the end
-		start, serr := strconv.Atoi(parts[0])
-		end, eerr := strconv.Atoi(parts[1])
-
-		if serr != nil || eerr != nil {
-			return pp, fmt.Errorf(
-				"Invalid port range, range should be numbers and written start-end, e.g 80-82",
-			)
-		}
+		start, end, _ := config.ParsePortRange(p.Range)
 
-		if start > end {
-			return pp, fmt.Errorf(
-				"Invalid port range, start and end ports should be numeric and written start-end, e.g 80-82",
-			)
+		// ports in the container are published to the same port on the
+		// host unless a different host range is specified
+		hostStart := start
+		if p.HostRange != "" {
+			hostStart, _, _ = config.ParsePortRange(p.HostRange)
 		}
 
 		// range is ok, generate ports
@@ -983,11 +1465,11 @@ func createPublishedPortRanges(ps []config.PortRange) (publishedPorts, error) {
 			dp, _ := nat.NewPort(p.Protocol, port)
 			pp.ExposedPorts[dp] = struct{}{}
 
-			if p.EnableHost {
+			if p.EnableHost || p.HostRange != "" {
 				pb := []nat.PortBinding{
 					nat.PortBinding{
 						HostIP:   "0.0.0.0",
-						HostPort: port,
+						HostPort: strconv.Itoa(hostStart + i - start),
 					},
 				}
 
@@ -46,3 +46,14 @@ func TestCreateShellCreatesExec(t *testing.T) {
 	md.AssertCalled(t, "ContainerExecCreate", mock.Anything, "abc", mock.Anything)
 
 }
+
+func TestCreateShellReturnsExitCodeWhenCommandFails(t *testing.T) {
+	p, md := setupShellMocks()
+	removeOn(&md.Mock, "ContainerExecInspect")
+	md.On("ContainerExecInspect", mock.Anything, mock.Anything).Return(types.ContainerExecInspect{ExitCode: 127}, nil)
+
+	in := ioutil.NopCloser(bytes.NewReader([]byte("abc")))
+
+	err := p.CreateShell("abc", []string{"nope"}, in, ioutil.Discard, ioutil.Discard)
+	assert.Equal(t, ExecExitError{ExitCode: 127}, err)
+}
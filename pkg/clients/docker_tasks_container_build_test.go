@@ -93,3 +93,18 @@ func TestBuildListsImagesAndBuildsWhenNotExistsCustomDockerfile(t *testing.T) {
 	params := getCalls(&md.Mock, "ImageBuild")[0].Arguments[2].(types.ImageBuildOptions)
 	assert.Equal(t, "./Dockerfile-test", params.Dockerfile)
 }
+
+func TestBuildPassesBuildArgs(t *testing.T) {
+	md := testBuildMockSetup()
+
+	cc := config.NewContainer("test")
+	cc.Build = &config.Build{Context: "./context", Args: map[string]string{"VERSION": "1.2.3"}}
+
+	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
+
+	_, err := dt.BuildContainer(cc, true)
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ImageBuild")[0].Arguments[2].(types.ImageBuildOptions)
+	assert.Equal(t, "1.2.3", *params.BuildArgs["VERSION"])
+}
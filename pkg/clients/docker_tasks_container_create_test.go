@@ -186,6 +186,21 @@ func TestContainerAttachesToUserNetwork(t *testing.T) {
 	assert.Nil(t, nc.IPAMConfig) // unless an IP address is set this will be nil
 }
 
+func TestContainerAttachesToUserNetworkWithMacAddress(t *testing.T) {
+	cc, _, _, md, mic := createContainerConfig()
+	cc.Networks = []config.NetworkAttachment{
+		config.NetworkAttachment{Name: "network.testnet", MacAddress: "02:42:ac:11:00:02"},
+	}
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "NetworkConnect")[0].Arguments
+	nc := params[3].(*network.EndpointSettings)
+
+	assert.Equal(t, "02:42:ac:11:00:02", nc.MacAddress)
+}
+
 func TestContainerAttachesToContainerNetwork(t *testing.T) {
 	cc, _, _, md, mic := createContainerConfig()
 	cc.Networks = []config.NetworkAttachment{config.NetworkAttachment{Name: "container.testcontainer2"}}
@@ -202,6 +217,25 @@ func TestContainerAttachesToContainerNetwork(t *testing.T) {
 	assert.Equal(t, hc.NetworkMode, container.NetworkMode("container:abc"))
 }
 
+func TestContainerSetsNetworkModeAndDoesNotAttachNetworks(t *testing.T) {
+	cc, _, _, md, mic := createContainerConfig()
+	cc.Networks = []config.NetworkAttachment{}
+	cc.NetworkMode = "host"
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "NetworkDisconnect", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	md.AssertNotCalled(t, "NetworkConnect", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	params := getCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	dc := params[1].(*container.Config)
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, container.NetworkMode("host"), hc.NetworkMode)
+	assert.Equal(t, "", dc.Hostname)
+}
+
 func TestContainerAttachesToContainerNetworkReturnsErrorWhenListError(t *testing.T) {
 	cc, _, _, md, mic := createContainerConfig()
 	cc.Networks = []config.NetworkAttachment{config.NetworkAttachment{Name: "container.testcontainer2"}}
@@ -295,6 +329,35 @@ func TestContainerAttachesVolumeMounts(t *testing.T) {
 	assert.Equal(t, mount.TypeBind, hc.Mounts[0].Type)
 }
 
+func TestContainerSetsVolumePropagation(t *testing.T) {
+	cc, _, _, md, mic := createContainerConfig()
+	cc.Volumes[0].Propagation = "rshared"
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Len(t, hc.Mounts, 1)
+	assert.Equal(t, mount.PropagationRShared, hc.Mounts[0].BindOptions.Propagation)
+}
+
+func TestContainerAttachesVolumeWithSELinuxLabelAsBind(t *testing.T) {
+	cc, _, _, md, mic := createContainerConfig()
+	cc.Volumes[0].SELinuxLabel = "Z"
+	cc.Volumes[0].ReadOnly = true
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Len(t, hc.Mounts, 0)
+	assert.Equal(t, []string{fmt.Sprintf("%s:%s:Z,ro", cc.Volumes[0].Source, cc.Volumes[0].Destination)}, hc.Binds)
+}
+
 func TestContainerCreatesDirectoryForVolume(t *testing.T) {
 	tmpFolder := fmt.Sprintf("%s/%d", utils.ShipyardTemp(), time.Now().UnixNano())
 	defer os.RemoveAll(tmpFolder)
@@ -388,6 +451,28 @@ func TestContainerPublishesPortsRanges(t *testing.T) {
 	// check the port bindings for the local machine are nil
 	assert.Nil(t, hc.PortBindings[exp])
 }
+
+func TestContainerPublishesPortRangesToHostRange(t *testing.T) {
+	cc, _, _, md, mic := createContainerConfig()
+	cc.PortRanges = []config.PortRange{
+		config.PortRange{Range: "9000-9002", HostRange: "19000-19002", Protocol: "tcp"},
+	}
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	exp, err := nat.NewPort("tcp", "9000")
+	assert.NoError(t, err)
+	assert.Equal(t, "19000", hc.PortBindings[exp][0].HostPort)
+
+	exp, err = nat.NewPort("tcp", "9002")
+	assert.NoError(t, err)
+	assert.Equal(t, "19002", hc.PortBindings[exp][0].HostPort)
+}
+
 func TestContainerConfiguresResources(t *testing.T) {
 	cc, _, _, md, mic := createContainerConfig()
 
@@ -403,6 +488,34 @@ func TestContainerConfiguresResources(t *testing.T) {
 	assert.Equal(t, hc.Resources.CpusetCpus, "1,4")
 }
 
+func TestContainerConfiguresDNS(t *testing.T) {
+	cc, _, _, md, mic := createContainerConfig()
+	cc.DNS = []string{"10.5.0.2", "8.8.8.8"}
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, []string{"10.5.0.2", "8.8.8.8"}, hc.DNS)
+}
+
+func TestContainerConfiguresLogDriver(t *testing.T) {
+	cc, _, _, md, mic := createContainerConfig()
+	cc.LogDriver = "fluentd"
+	cc.LogOpts = map[string]string{"fluentd-address": "localhost:24224"}
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, "fluentd", hc.LogConfig.Type)
+	assert.Equal(t, "localhost:24224", hc.LogConfig.Config["fluentd-address"])
+}
+
 func TestContainerConfiguresRetryWhenCountGreater0(t *testing.T) {
 	cc, _, _, md, mic := createContainerConfig()
 	cc.MaxRestartCount = 10
@@ -431,6 +544,35 @@ func TestContainerNotConfiguresRetryWhen0(t *testing.T) {
 	assert.Equal(t, hc.RestartPolicy.MaximumRetryCount, 0)
 }
 
+func TestContainerConfiguresRestartPolicy(t *testing.T) {
+	cc, _, _, md, mic := createContainerConfig()
+	cc.Restart = "always"
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, "always", hc.RestartPolicy.Name)
+	assert.Equal(t, 0, hc.RestartPolicy.MaximumRetryCount)
+}
+
+func TestContainerConfiguresOnFailureRestartPolicyWithCount(t *testing.T) {
+	cc, _, _, md, mic := createContainerConfig()
+	cc.Restart = "on-failure"
+	cc.MaxRestartCount = 3
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, "on-failure", hc.RestartPolicy.Name)
+	assert.Equal(t, 3, hc.RestartPolicy.MaximumRetryCount)
+}
+
 func TestContainerAddUserWhenSpecified(t *testing.T) {
 	cc, _, _, md, mic := createContainerConfig()
 	cc.RunAs = &config.User{
@@ -2,10 +2,15 @@ package clients
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"testing"
+	"time"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
 	"github.com/stretchr/testify/assert"
@@ -26,3 +31,73 @@ func TestContainerLogsCalled(t *testing.T) {
 	assert.NotNil(t, rc)
 	assert.Error(t, err)
 }
+
+func TestContainerLogsFollowSeparatesStreams(t *testing.T) {
+	stream := bytes.NewBuffer(nil)
+	stdcopy.NewStdWriter(stream, stdcopy.Stdout).Write([]byte("out\n"))
+	stdcopy.NewStdWriter(stream, stdcopy.Stderr).Write([]byte("err\n"))
+
+	md := &mocks.MockDocker{}
+	md.On("ContainerLogs", mock.Anything, mock.Anything, mock.Anything).Return(
+		ioutil.NopCloser(stream),
+		nil,
+	)
+
+	dt := NewDockerTasks(md, &mocks.ImageLog{}, &TarGz{}, hclog.NewNullLogger())
+
+	stdOut := bytes.NewBuffer(nil)
+	stdErr := bytes.NewBuffer(nil)
+
+	err := dt.ContainerLogsFollow(context.Background(), "123", "", true, stdOut, stdErr)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "out\n", stdOut.String())
+	assert.Equal(t, "err\n", stdErr.String())
+
+	md.AssertCalled(t, "ContainerLogs", mock.Anything, "123", types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Follow: true})
+}
+
+func TestContainerLogsFollowSetsTailAndFollow(t *testing.T) {
+	md := &mocks.MockDocker{}
+	md.On("ContainerLogs", mock.Anything, mock.Anything, mock.Anything).Return(
+		ioutil.NopCloser(bytes.NewBuffer(nil)),
+		nil,
+	)
+
+	dt := NewDockerTasks(md, &mocks.ImageLog{}, &TarGz{}, hclog.NewNullLogger())
+
+	err := dt.ContainerLogsFollow(context.Background(), "123", "100", false, nil, ioutil.Discard)
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "ContainerLogs", mock.Anything, "123", types.ContainerLogsOptions{ShowStdout: false, ShowStderr: true, Follow: false, Tail: "100"})
+}
+
+func TestContainerLogsFollowClosesStreamWhenContextCancelled(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	md := &mocks.MockDocker{}
+	md.On("ContainerLogs", mock.Anything, mock.Anything, mock.Anything).Return(pr, nil)
+
+	dt := NewDockerTasks(md, &mocks.ImageLog{}, &TarGz{}, hclog.NewNullLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+
+	go func() {
+		errs <- dt.ContainerLogsFollow(ctx, "123", "", true, ioutil.Discard, nil)
+	}()
+
+	cancel()
+
+	select {
+	case err := <-errs:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("ContainerLogsFollow did not return after the context was cancelled")
+	}
+
+	// the stream has been closed
+	_, err := pw.Write([]byte("log"))
+	assert.Error(t, err)
+}
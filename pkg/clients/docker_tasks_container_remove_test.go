@@ -2,9 +2,13 @@ package clients
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
 	clients "github.com/shipyard-run/shipyard/pkg/clients/mocks"
@@ -67,3 +71,87 @@ func TestContainerRemoveFailsCallsRemoveForcefully(t *testing.T) {
 
 	md.AssertNumberOfCalls(t, "ContainerRemove", 1)
 }
+
+func TestContainerRemoveWithOptionsPreservesVolumes(t *testing.T) {
+	md := &mocks.MockDocker{}
+	mic := &clients.ImageLog{}
+	dt := NewDockerTasks(md, mic, &TarGz{}, hclog.NewNullLogger())
+
+	md.On("ContainerInspect", mock.Anything, "test").Return(types.ContainerJSON{}, nil)
+	md.On("ContainerRemove", mock.Anything, "test", types.ContainerRemoveOptions{Force: false, RemoveVolumes: false}).Return(nil)
+	md.On("ContainerStop", mock.Anything, "test", mock.Anything).Return(nil)
+
+	dt.RemoveContainerWithOptions("test", false, false, 0)
+
+	md.AssertCalled(t, "ContainerRemove", mock.Anything, "test", types.ContainerRemoveOptions{Force: false, RemoveVolumes: false})
+}
+
+func TestContainerRemoveWithOptionsLogsAnonymousVolumesForShipyardContainers(t *testing.T) {
+	md := &mocks.MockDocker{}
+	mic := &clients.ImageLog{}
+	mic.On("Log", mock.Anything, mock.Anything).Return(nil)
+	dt := NewDockerTasks(md, mic, &TarGz{}, hclog.NewNullLogger())
+
+	anon := strings.Repeat("a1", 32)
+
+	md.On("ContainerInspect", mock.Anything, "test").Return(types.ContainerJSON{
+		Config: &container.Config{Labels: ResourceLabels("container.consul", "container", "default")},
+		Mounts: []types.MountPoint{
+			{Type: mount.TypeVolume, Name: anon},
+			{Type: mount.TypeVolume, Name: "data.volume.shipyard.run"},
+			{Type: mount.TypeBind, Source: "/tmp"},
+		},
+	}, nil)
+	md.On("ContainerRemove", mock.Anything, "test", mock.Anything).Return(nil)
+	md.On("ContainerStop", mock.Anything, "test", mock.Anything).Return(nil)
+
+	dt.RemoveContainerWithOptions("test", false, false, 0)
+
+	mic.AssertCalled(t, "Log", anon, ImageTypeVolume)
+	mic.AssertNumberOfCalls(t, "Log", 1)
+}
+
+func TestContainerRemoveWithOptionsDoesNotLogVolumesForOtherContainers(t *testing.T) {
+	md := &mocks.MockDocker{}
+	mic := &clients.ImageLog{}
+	dt := NewDockerTasks(md, mic, &TarGz{}, hclog.NewNullLogger())
+
+	md.On("ContainerInspect", mock.Anything, "test").Return(types.ContainerJSON{
+		Config: &container.Config{},
+		Mounts: []types.MountPoint{{Type: mount.TypeVolume, Name: strings.Repeat("a1", 32)}},
+	}, nil)
+	md.On("ContainerRemove", mock.Anything, "test", mock.Anything).Return(nil)
+	md.On("ContainerStop", mock.Anything, "test", mock.Anything).Return(nil)
+
+	dt.RemoveContainerWithOptions("test", false, false, 0)
+
+	mic.AssertNotCalled(t, "Log", mock.Anything, mock.Anything)
+}
+
+func TestContainerRemoveUsesDefaultStopTimeout(t *testing.T) {
+	md := &mocks.MockDocker{}
+	mic := &clients.ImageLog{}
+	dt := NewDockerTasks(md, mic, &TarGz{}, hclog.NewNullLogger())
+
+	md.On("ContainerRemove", mock.Anything, "test", mock.Anything).Return(nil)
+	md.On("ContainerStop", mock.Anything, "test", mock.Anything).Return(nil)
+
+	dt.RemoveContainer("test", false)
+
+	timeout := DefaultStopTimeout
+	md.AssertCalled(t, "ContainerStop", mock.Anything, "test", &timeout)
+}
+
+func TestContainerRemoveWithOptionsUsesStopTimeout(t *testing.T) {
+	md := &mocks.MockDocker{}
+	mic := &clients.ImageLog{}
+	dt := NewDockerTasks(md, mic, &TarGz{}, hclog.NewNullLogger())
+
+	md.On("ContainerRemove", mock.Anything, "test", mock.Anything).Return(nil)
+	md.On("ContainerStop", mock.Anything, "test", mock.Anything).Return(nil)
+
+	dt.RemoveContainerWithOptions("test", false, true, 90*time.Second)
+
+	timeout := 90 * time.Second
+	md.AssertCalled(t, "ContainerStop", mock.Anything, "test", &timeout)
+}
@@ -29,9 +29,10 @@ func TestFindContainerIDsReturnsID(t *testing.T) {
 	// assert that the docker api call was made
 	md.AssertNumberOfCalls(t, "ContainerList", 1)
 
-	// ensure that the FQDN was passed as an argument
+	// ensure that the labels were passed as an argument
 	args := getCalls(&md.Mock, "ContainerList")[0].Arguments[1].(types.ContainerListOptions)
-	assert.Equal(t, "^/test.cloud.shipyard.run$", args.Filters.Get("name")[0])
+	assert.ElementsMatch(t, []string{"run.shipyard.name=test", "run.shipyard.type=cloud", "run.shipyard.workspace=default"}, args.Filters.Get("label"))
+	assert.True(t, args.All)
 
 	// ensure that the id has been returned
 	assert.Len(t, ids, 2)
@@ -39,6 +40,42 @@ func TestFindContainerIDsReturnsID(t *testing.T) {
 	assert.Equal(t, "123", ids[1])
 }
 
+func TestFindContainerIDsFallsBackToNameWhenNotLabelled(t *testing.T) {
+	md := &mocks.MockDocker{}
+	md.On("ContainerList", mock.Anything, mock.Anything).Return([]types.Container{}, nil).Once()
+	md.On("ContainerList", mock.Anything, mock.Anything).Return(
+		[]types.Container{
+			types.Container{ID: "abc"},
+		},
+		nil,
+	)
+
+	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
+
+	ids, err := dt.FindContainerIDs("test", "cloud")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abc"}, ids)
+
+	md.AssertNumberOfCalls(t, "ContainerList", 2)
+
+	// ensure that the FQDN was passed as an argument
+	args := getCalls(&md.Mock, "ContainerList")[1].Arguments[1].(types.ContainerListOptions)
+	assert.Equal(t, "^/test.cloud.shipyard.run$", args.Filters.Get("name")[0])
+}
+
+func TestFindContainerIDsDoesNotFilterImageCacheByWorkspace(t *testing.T) {
+	md := &mocks.MockDocker{}
+	md.On("ContainerList", mock.Anything, mock.Anything).Return([]types.Container{types.Container{ID: "abc"}}, nil)
+
+	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
+
+	_, err := dt.FindContainerIDs("docker-cache", "image_cache")
+	assert.NoError(t, err)
+
+	args := getCalls(&md.Mock, "ContainerList")[0].Arguments[1].(types.ContainerListOptions)
+	assert.ElementsMatch(t, []string{"run.shipyard.name=docker-cache", "run.shipyard.type=image_cache"}, args.Filters.Get("label"))
+}
+
 func TestFindContainerIDsReturnsErrorWhenDockerFail(t *testing.T) {
 	md := &mocks.MockDocker{}
 	md.On("ContainerList", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
@@ -70,3 +107,24 @@ func TestFindContainerIDsReturnsNilWhenEmpty(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Nil(t, ids)
 }
+
+func TestFindResourceContainerIDsFiltersByLabel(t *testing.T) {
+	md := &mocks.MockDocker{}
+	md.On("ContainerList", mock.Anything, mock.Anything).Return(
+		[]types.Container{
+			types.Container{ID: "abc"},
+			types.Container{ID: "123"},
+		},
+		nil,
+	)
+
+	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
+
+	ids, err := dt.FindResourceContainerIDs("k8s_cluster.k3s")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abc", "123"}, ids)
+
+	args := getCalls(&md.Mock, "ContainerList")[0].Arguments[1].(types.ContainerListOptions)
+	assert.ElementsMatch(t, []string{"run.shipyard.resource_id=k8s_cluster.k3s", "run.shipyard.workspace=default"}, args.Filters.Get("label"))
+	assert.True(t, args.All)
+}
@@ -58,7 +58,7 @@ func TestPullImageWhenNOTCached(t *testing.T) {
 	md.AssertCalled(t, "ImageList", mock.Anything, types.ImageListOptions{Filters: args})
 
 	// test pulls image replacing the short name with the canonical registry name
-	md.AssertCalled(t, "ImagePull", mock.Anything, makeImageCanonical(cc.Name), types.ImagePullOptions{})
+	md.AssertCalled(t, "ImagePull", mock.Anything, makeImageCanonical(cc.Name), types.ImagePullOptions{Platform: hostPlatform()})
 
 	// test adds to the cache log
 	mic.AssertCalled(t, "Log", mock.Anything, mock.Anything)
@@ -77,7 +77,7 @@ func TestPullImageWithCredentialsWhenNOTCached(t *testing.T) {
 
 	// test pulls image replacing the short name with the canonical registry name
 	// adding credentials to image pull
-	ipo := types.ImagePullOptions{RegistryAuth: createRegistryAuth(cc.Username, cc.Password)}
+	ipo := types.ImagePullOptions{RegistryAuth: createRegistryAuth(cc.Username, cc.Password), Platform: hostPlatform()}
 	md.AssertCalled(t, "ImagePull", mock.Anything, makeImageCanonical(cc.Name), ipo)
 
 }
@@ -119,3 +119,108 @@ func TestPullImageAlwaysWhenForce(t *testing.T) {
 	md.AssertCalled(t, "ImagePull", mock.Anything, mock.Anything, mock.Anything)
 	mic.AssertCalled(t, "Log", mock.Anything, mock.Anything)
 }
+
+func TestPullImageWithPlatform(t *testing.T) {
+	cc, md, mic := createImagePullConfig()
+	cc.Platform = "linux/arm64"
+
+	setupImagePull(t, cc, md, mic, false)
+
+	ipo := getCalls(&md.Mock, "ImagePull")[0].Arguments[2].(types.ImagePullOptions)
+	assert.Equal(t, "linux/arm64", ipo.Platform)
+}
+
+func TestPullImageNothingWhenCachedWithMatchingPlatform(t *testing.T) {
+	cc, md, mic := createImagePullConfig()
+	cc.Platform = "linux/arm64"
+
+	removeOn(&md.Mock, "ImageList")
+	md.On("ImageList", mock.Anything, mock.Anything, mock.Anything).Return([]types.ImageSummary{types.ImageSummary{}}, nil)
+	md.On("ImageInspectWithRaw", mock.Anything, cc.Name).Return(types.ImageInspect{Os: "linux", Architecture: "arm64"}, nil)
+
+	setupImagePull(t, cc, md, mic, false)
+
+	md.AssertNotCalled(t, "ImagePull", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPullImageWhenCachedWithDifferentPlatform(t *testing.T) {
+	cc, md, mic := createImagePullConfig()
+	cc.Platform = "linux/arm64"
+
+	removeOn(&md.Mock, "ImageList")
+	md.On("ImageList", mock.Anything, mock.Anything, mock.Anything).Return([]types.ImageSummary{types.ImageSummary{}}, nil)
+	md.On("ImageInspectWithRaw", mock.Anything, cc.Name).Return(types.ImageInspect{Os: "linux", Architecture: "amd64"}, nil)
+
+	setupImagePull(t, cc, md, mic, false)
+
+	md.AssertCalled(t, "ImagePull", mock.Anything, mock.Anything, mock.Anything)
+}
+
+const pullProgressStream = `{"status":"Pulling from library/consul","id":"1.6.1"}
+{"status":"Downloading","progressDetail":{"current":50,"total":200},"id":"a1b2"}
+{"status":"Downloading","progressDetail":{"current":100,"total":200},"id":"a1b2"}
+{"status":"Downloading","progressDetail":{"current":10,"total":10},"id":"c3d4"}
+{"status":"Download complete","progressDetail":{},"id":"a1b2"}
+`
+
+func TestPullImageReportsLayerProgress(t *testing.T) {
+	cc, md, mic := createImagePullConfig()
+	removeOn(&md.Mock, "ImagePull")
+	md.On("ImagePull", mock.Anything, mock.Anything, mock.Anything).Return(
+		ioutil.NopCloser(strings.NewReader(pullProgressStream)),
+		nil,
+	)
+
+	progress := []PullProgress{}
+	p := NewDockerTasks(md, mic, &TarGz{}, hclog.NewNullLogger(), WithPullProgress(func(pp PullProgress) {
+		progress = append(progress, pp)
+	}))
+	p.pullInterval = 0
+
+	err := p.PullImage(cc, false)
+	assert.NoError(t, err)
+
+	assert.Len(t, progress, 3)
+	assert.Equal(t, "a1b2", progress[0].Layer)
+	assert.Equal(t, 25, progress[0].Percent())
+	assert.Equal(t, 50, progress[1].Percent())
+	assert.Equal(t, "c3d4", progress[2].Layer)
+	assert.Equal(t, 100, progress[2].Percent())
+}
+
+func TestPullImageThrottlesLayerProgress(t *testing.T) {
+	cc, md, mic := createImagePullConfig()
+	removeOn(&md.Mock, "ImagePull")
+	md.On("ImagePull", mock.Anything, mock.Anything, mock.Anything).Return(
+		ioutil.NopCloser(strings.NewReader(pullProgressStream)),
+		nil,
+	)
+
+	progress := []PullProgress{}
+	p := NewDockerTasks(md, mic, &TarGz{}, hclog.NewNullLogger(), WithPullProgress(func(pp PullProgress) {
+		progress = append(progress, pp)
+	}))
+
+	err := p.PullImage(cc, false)
+	assert.NoError(t, err)
+
+	// only the first update for each layer is reported within the interval
+	assert.Len(t, progress, 2)
+}
+
+func TestPullImageReturnsErrorFromStream(t *testing.T) {
+	cc, md, mic := createImagePullConfig()
+	removeOn(&md.Mock, "ImagePull")
+	md.On("ImagePull", mock.Anything, mock.Anything, mock.Anything).Return(
+		ioutil.NopCloser(strings.NewReader(`{"errorDetail":{"message":"manifest unknown"},"error":"manifest unknown"}`)),
+		nil,
+	)
+
+	p := NewDockerTasks(md, mic, &TarGz{}, hclog.NewNullLogger())
+
+	err := p.PullImage(cc, false)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "manifest unknown")
+
+	mic.AssertNotCalled(t, "Log", mock.Anything, mock.Anything)
+}
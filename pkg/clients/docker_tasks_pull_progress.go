@@ -0,0 +1,99 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"golang.org/x/xerrors"
+)
+
+// pullProgressInterval is the minimum time between progress reports for
+// each layer of an image pull
+const pullProgressInterval = 5 * time.Second
+
+// PullProgress is the download progress for a single layer of an image
+type PullProgress struct {
+	// Image is the canonical name of the image being pulled
+	Image string
+	// Layer is the id of the layer
+	Layer string
+	// Current is the number of bytes downloaded
+	Current int64
+	// Total is the size of the layer in bytes
+	Total int64
+}
+
+// Percent returns the percentage of the layer which has been downloaded
+func (p PullProgress) Percent() int {
+	if p.Total == 0 {
+		return 0
+	}
+
+	return int(p.Current * 100 / p.Total)
+}
+
+// PullProgressFunc is called with the progress of the layers of an image pull
+type PullProgressFunc func(p PullProgress)
+
+// WithPullProgress replaces the default logging of image pull progress with f,
+// progress for each layer is reported at most once every five seconds
+func WithPullProgress(f PullProgressFunc) DockerTasksOption {
+	return func(d *DockerTasks) {
+		d.pullProgress = f
+	}
+}
+
+// logPullProgress is the default PullProgressFunc which writes the progress to the log
+func (d *DockerTasks) logPullProgress(p PullProgress) {
+	d.l.Info("Pulling image", "image", p.Image, "layer", p.Layer, "progress", fmt.Sprintf("%d%%", p.Percent()))
+}
+
+// readPullProgress reads the stream returned by ImagePull until the pull has
+// completed, reporting the progress of each layer and returning any error
+// sent by the Docker engine
+func (d *DockerTasks) readPullProgress(image string, r io.Reader) error {
+	last := map[string]time.Time{}
+	dec := json.NewDecoder(r)
+
+	for {
+		jm := jsonmessage.JSONMessage{}
+
+		err := dec.Decode(&jm)
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			// progress can not be read, wait for the pull to complete
+			d.l.Debug("Unable to read image pull progress", "image", image, "error", err)
+			io.Copy(ioutil.Discard, r)
+
+			return nil
+		}
+
+		if jm.Error != nil {
+			return xerrors.Errorf("Error pulling image: %w", jm.Error)
+		}
+
+		if jm.ID == "" || jm.Progress == nil || jm.Progress.Total == 0 {
+			continue
+		}
+
+		if t, ok := last[jm.ID]; ok && time.Since(t) < d.pullInterval {
+			continue
+		}
+
+		last[jm.ID] = time.Now()
+
+		d.pullProgress(PullProgress{
+			Image:   image,
+			Layer:   jm.ID,
+			Current: jm.Progress.Current,
+			Total:   jm.Progress.Total,
+		})
+	}
+}
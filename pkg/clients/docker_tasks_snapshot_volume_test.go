@@ -0,0 +1,179 @@
+package clients
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSnapshotVolumeMocks() *DockerTasks {
+	md, mic := setupContainerMocks()
+
+	md.On("CopyFromContainer", mock.Anything, mock.Anything, mock.Anything).Return(ioutil.NopCloser(strings.NewReader("data")), types.ContainerPathStat{}, nil)
+	md.On("CopyToContainer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	md.On("ContainerExecCreate", mock.Anything, mock.Anything, mock.Anything).Return(types.IDResponse{ID: "abc"}, nil)
+	md.On("ContainerExecAttach", mock.Anything, mock.Anything, mock.Anything).Return(
+		types.HijackedResponse{
+			Conn:   &net.TCPConn{},
+			Reader: bufio.NewReader(bytes.NewReader([]byte{})),
+		},
+		nil,
+	)
+	md.On("ContainerExecStart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	md.On("ContainerExecInspect", mock.Anything, mock.Anything).Return(types.ContainerExecInspect{Running: false, ExitCode: 0}, nil)
+
+	return NewDockerTasks(md, mic, &TarGz{}, hclog.NewNullLogger())
+}
+
+func createSnapshot(t *testing.T, files map[string]string) string {
+	src := t.TempDir()
+	for n, c := range files {
+		err := ioutil.WriteFile(filepath.Join(src, n), []byte(c), os.ModePerm)
+		require.NoError(t, err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	f, err := os.Create(dest)
+	require.NoError(t, err)
+	defer f.Close()
+
+	err = (&TarGz{}).Compress(f, &TarGzOptions{OmitRoot: true}, src)
+	require.NoError(t, err)
+
+	return dest
+}
+
+func TestSnapshotVolumeArchivesLocalFolderWithoutContainer(t *testing.T) {
+	dt := setupSnapshotVolumeMocks()
+	md := dt.c.(*mocks.MockDocker)
+
+	src := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(src, "data.txt"), []byte("abc"), os.ModePerm)
+	require.NoError(t, err)
+
+	dest := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+
+	err = dt.SnapshotVolume(context.Background(), src, dest)
+	require.NoError(t, err)
+
+	assert.FileExists(t, dest)
+	md.AssertNotCalled(t, "ContainerCreate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSnapshotVolumeReturnsErrorWhenPathIsNotAFolder(t *testing.T) {
+	dt := setupSnapshotVolumeMocks()
+	md := dt.c.(*mocks.MockDocker)
+
+	dest := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+
+	err := dt.SnapshotVolume(context.Background(), filepath.Join(t.TempDir(), "missing"), dest)
+	assert.Error(t, err)
+
+	// absolute paths are never used as volume names
+	md.AssertNotCalled(t, "ContainerCreate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSnapshotVolumeCopiesVolumeFromContainer(t *testing.T) {
+	dt := setupSnapshotVolumeMocks()
+	md := dt.c.(*mocks.MockDocker)
+
+	dest := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+
+	err := dt.SnapshotVolume(context.Background(), "data", dest)
+	require.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ContainerCreate")[0].Arguments[2].(*container.HostConfig)
+	assert.Equal(t, "data", params.Mounts[0].Source)
+	assert.Equal(t, "/data", params.Mounts[0].Target)
+
+	md.AssertCalled(t, "CopyFromContainer", mock.Anything, "test", "/data/.")
+	md.AssertCalled(t, "ContainerRemove", mock.Anything, "test", mock.Anything)
+	assert.FileExists(t, dest)
+}
+
+func TestRestoreVolumeReplacesContentsOfLocalFolder(t *testing.T) {
+	dt := setupSnapshotVolumeMocks()
+
+	snapshot := createSnapshot(t, map[string]string{"data.txt": "abc"})
+
+	dest := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(dest, "old.txt"), []byte("old"), os.ModePerm)
+	require.NoError(t, err)
+
+	err = dt.RestoreVolume(context.Background(), snapshot, dest)
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, filepath.Join(dest, "old.txt"))
+
+	d, err := ioutil.ReadFile(filepath.Join(dest, "data.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(d))
+}
+
+func TestRestoreVolumeCreatesMissingLocalFolder(t *testing.T) {
+	dt := setupSnapshotVolumeMocks()
+	md := dt.c.(*mocks.MockDocker)
+
+	snapshot := createSnapshot(t, map[string]string{"data.txt": "abc"})
+	dest := filepath.Join(t.TempDir(), "missing")
+
+	err := dt.RestoreVolume(context.Background(), snapshot, dest)
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(dest, "data.txt"))
+	md.AssertNotCalled(t, "ContainerCreate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRestoreVolumeClearsVolumeBeforeCopying(t *testing.T) {
+	dt := setupSnapshotVolumeMocks()
+	md := dt.c.(*mocks.MockDocker)
+
+	snapshot := createSnapshot(t, map[string]string{"data.txt": "abc"})
+
+	err := dt.RestoreVolume(context.Background(), snapshot, "data")
+	require.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ContainerExecCreate")[0].Arguments[2].(types.ExecConfig)
+	assert.Equal(t, []string{"find", "/data", "-mindepth", "1", "-delete"}, params.Cmd)
+
+	// the volume must be cleared before the snapshot is copied
+	order := []string{}
+	for _, c := range md.Calls {
+		if c.Method == "ContainerExecCreate" || c.Method == "CopyToContainer" {
+			order = append(order, c.Method)
+		}
+	}
+
+	assert.Equal(t, []string{"ContainerExecCreate", "CopyToContainer"}, order)
+	md.AssertCalled(t, "CopyToContainer", mock.Anything, "test", "/data", mock.Anything, mock.Anything)
+}
+
+func TestRestoreVolumeReturnsErrorWhenClearFails(t *testing.T) {
+	dt := setupSnapshotVolumeMocks()
+	md := dt.c.(*mocks.MockDocker)
+
+	removeOn(&md.Mock, "ContainerExecInspect")
+	md.On("ContainerExecInspect", mock.Anything, mock.Anything).Return(types.ContainerExecInspect{Running: false, ExitCode: 1}, nil)
+
+	snapshot := createSnapshot(t, map[string]string{"data.txt": "abc"})
+
+	err := dt.RestoreVolume(context.Background(), snapshot, "data")
+	assert.Error(t, err)
+
+	md.AssertNotCalled(t, "CopyToContainer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -2,6 +2,7 @@ package clients
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/docker/docker/api/types"
@@ -62,3 +63,33 @@ func TestRemoveVolumeRemotesSuccesfully(t *testing.T) {
 
 	md.AssertCalled(t, "VolumeRemove", mock.Anything, "test.volume.shipyard.run", true)
 }
+
+func TestRemoveDanglingVolumesRemovesShipyardVolumes(t *testing.T) {
+	_, _, _, md, mic := createContainerConfig()
+	p := NewDockerTasks(md, mic, &TarGz{}, hclog.NewNullLogger())
+
+	logged := strings.Repeat("a1", 32)
+	other := strings.Repeat("b2", 32)
+
+	mic.On("Read", ImageTypeVolume).Return([]string{logged}, nil)
+
+	removeOn(&md.Mock, "VolumeList")
+	f := filters.NewArgs()
+	f.Add("dangling", "true")
+	md.On("VolumeList", mock.Anything, f).Return(
+		volume.VolumeListOKBody{Volumes: []*types.Volume{
+			&types.Volume{Name: logged},
+			&types.Volume{Name: other},
+			&types.Volume{Name: "data.volume.shipyard.run", Labels: ResourceLabels("data", "volume", "default")},
+			&types.Volume{Name: "images.volume.other"},
+		}},
+		nil,
+	)
+
+	removed, err := p.RemoveDanglingVolumes()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{logged, "data.volume.shipyard.run"}, removed)
+
+	md.AssertNotCalled(t, "VolumeRemove", mock.Anything, other, mock.Anything)
+	md.AssertNotCalled(t, "VolumeRemove", mock.Anything, "images.volume.other", mock.Anything)
+}
@@ -0,0 +1,189 @@
+package clients
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	volumetypes "github.com/docker/docker/api/types/volume"
+)
+
+// DefaultDockerTimeout is the default deadline for Docker API calls
+const DefaultDockerTimeout = 2 * time.Minute
+
+// DockerTimeout returns the timeout for Docker API calls, this can be
+// overridden by setting the DOCKER_TIMEOUT environment variable e.g. 30s
+func DockerTimeout() time.Duration {
+	if t := os.Getenv("DOCKER_TIMEOUT"); t != "" {
+		if d, err := time.ParseDuration(t); err == nil {
+			return d
+		}
+	}
+
+	return DefaultDockerTimeout
+}
+
+// timeoutDocker wraps a Docker client adding a deadline to the context of
+// API calls which should return quickly. Calls which return streams such as
+// ImagePull and ContainerLogs are passed through unchanged as the context
+// must remain valid while the stream is read.
+type timeoutDocker struct {
+	Docker
+	timeout time.Duration
+}
+
+// NewTimeoutDocker returns a Docker client where each request and response
+// API call fails if the daemon does not respond within the timeout
+func NewTimeoutDocker(c Docker, timeout time.Duration) Docker {
+	return &timeoutDocker{Docker: c, timeout: timeout}
+}
+
+func (t *timeoutDocker) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, containerName string) (container.ContainerCreateCreatedBody, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.ContainerCreate(ctx, config, hostConfig, networkingConfig, containerName)
+}
+
+func (t *timeoutDocker) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.ContainerList(ctx, options)
+}
+
+func (t *timeoutDocker) ContainerStart(ctx context.Context, containerID string, options types.ContainerStartOptions) error {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.ContainerStart(ctx, containerID, options)
+}
+
+func (t *timeoutDocker) ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error {
+	// allow the container the full graceful shutdown period
+	d := t.timeout
+	if timeout != nil {
+		d += *timeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	return t.Docker.ContainerStop(ctx, containerID, timeout)
+}
+
+func (t *timeoutDocker) ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.ContainerRemove(ctx, containerID, options)
+}
+
+func (t *timeoutDocker) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.ContainerInspect(ctx, containerID)
+}
+
+func (t *timeoutDocker) ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.ContainerExecCreate(ctx, container, config)
+}
+
+func (t *timeoutDocker) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.ContainerExecInspect(ctx, execID)
+}
+
+func (t *timeoutDocker) ContainerExecResize(ctx context.Context, execID string, config types.ResizeOptions) error {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.ContainerExecResize(ctx, execID, config)
+}
+
+func (t *timeoutDocker) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.NetworkList(ctx, options)
+}
+
+func (t *timeoutDocker) NetworkCreate(ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.NetworkCreate(ctx, name, options)
+}
+
+func (t *timeoutDocker) NetworkRemove(ctx context.Context, networkID string) error {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.NetworkRemove(ctx, networkID)
+}
+
+func (t *timeoutDocker) NetworkConnect(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.NetworkConnect(ctx, networkID, containerID, config)
+}
+
+func (t *timeoutDocker) NetworkDisconnect(ctx context.Context, networkID, containerID string, force bool) error {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.NetworkDisconnect(ctx, networkID, containerID, force)
+}
+
+func (t *timeoutDocker) VolumeList(ctx context.Context, filter filters.Args) (volumetypes.VolumeListOKBody, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.VolumeList(ctx, filter)
+}
+
+func (t *timeoutDocker) VolumeCreate(ctx context.Context, options volumetypes.VolumeCreateBody) (types.Volume, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.VolumeCreate(ctx, options)
+}
+
+func (t *timeoutDocker) VolumeRemove(ctx context.Context, volumeID string, force bool) error {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.VolumeRemove(ctx, volumeID, force)
+}
+
+func (t *timeoutDocker) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.ImageList(ctx, options)
+}
+
+func (t *timeoutDocker) ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.ImageInspectWithRaw(ctx, imageID)
+}
+
+func (t *timeoutDocker) ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Docker.ImageRemove(ctx, imageID, options)
+}
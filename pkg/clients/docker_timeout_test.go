@@ -0,0 +1,67 @@
+package clients
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTimeoutDockerSetsDeadline(t *testing.T) {
+	md := &mocks.MockDocker{}
+	md.On("ContainerStart", mock.Anything, "test", mock.Anything).Return(nil)
+
+	d := NewTimeoutDocker(md, 10*time.Second)
+	err := d.ContainerStart(context.Background(), "test", types.ContainerStartOptions{})
+	assert.NoError(t, err)
+
+	ctx := getCalls(&md.Mock, "ContainerStart")[0].Arguments[0].(context.Context)
+	dl, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(10*time.Second), dl, 1*time.Second)
+}
+
+func TestTimeoutDockerAddsStopTimeoutToDeadline(t *testing.T) {
+	md := &mocks.MockDocker{}
+	md.On("ContainerStop", mock.Anything, "test", mock.Anything).Return(nil)
+
+	stop := 30 * time.Second
+	d := NewTimeoutDocker(md, 10*time.Second)
+	err := d.ContainerStop(context.Background(), "test", &stop)
+	assert.NoError(t, err)
+
+	ctx := getCalls(&md.Mock, "ContainerStop")[0].Arguments[0].(context.Context)
+	dl, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(40*time.Second), dl, 1*time.Second)
+}
+
+func TestDockerTasksWithTimeoutSetsDeadline(t *testing.T) {
+	md := &mocks.MockDocker{}
+	md.On("VolumeRemove", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	dt := NewDockerTasks(md, &mocks.ImageLog{}, &TarGz{}, hclog.NewNullLogger(), WithTimeout(5*time.Second))
+	err := dt.RemoveVolume("test")
+	assert.NoError(t, err)
+
+	ctx := getCalls(&md.Mock, "VolumeRemove")[0].Arguments[0].(context.Context)
+	_, ok := ctx.Deadline()
+	assert.True(t, ok)
+}
+
+func TestDockerTimeoutReadsEnvironment(t *testing.T) {
+	os.Setenv("DOCKER_TIMEOUT", "30s")
+	defer os.Unsetenv("DOCKER_TIMEOUT")
+
+	assert.Equal(t, 30*time.Second, DockerTimeout())
+}
+
+func TestDockerTimeoutReturnsDefault(t *testing.T) {
+	assert.Equal(t, DefaultDockerTimeout, DockerTimeout())
+}
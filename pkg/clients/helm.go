@@ -3,6 +3,7 @@ package clients
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"golang.org/x/xerrors"
@@ -23,7 +24,7 @@ func init() {
 
 // Helm defines an interface for a client which can manage Helm charts
 type Helm interface {
-	Create(kubeConfig, name, namespace string, createNamespace bool, chartPath, valuesPath string, valuesString map[string]string) error
+	Create(kubeConfig, name, namespace string, createNamespace bool, chartPath string, valuesPaths []string, valuesString map[string]string, timeout time.Duration, atomic bool) error
 	Destroy(kubeConfig, name, namespace string) error
 }
 
@@ -36,7 +37,7 @@ func NewHelm(l hclog.Logger) Helm {
 }
 
 // Create a new install of the chart
-func (h *HelmImpl) Create(kubeConfig, name, namespace string, createNamespace bool, chartPath, valuesPath string, valuesString map[string]string) error {
+func (h *HelmImpl) Create(kubeConfig, name, namespace string, createNamespace bool, chartPath string, valuesPaths []string, valuesString map[string]string, timeout time.Duration, atomic bool) error {
 	// set the kubeclient for Helm
 	s := kube.GetConfig(kubeConfig, "default", namespace)
 	cfg := &action.Configuration{}
@@ -53,6 +54,13 @@ func (h *HelmImpl) Create(kubeConfig, name, namespace string, createNamespace bo
 	client.Namespace = namespace
 	client.CreateNamespace = createNamespace
 
+	// atomic installs wait for the release to be ready and uninstall on failure
+	client.Atomic = atomic
+	client.Wait = atomic
+	if timeout > 0 {
+		client.Timeout = timeout
+	}
+
 	settings := cli.EnvSettings{}
 	p := getter.All(&settings)
 	vo := values.Options{}
@@ -63,10 +71,8 @@ func (h *HelmImpl) Create(kubeConfig, name, namespace string, createNamespace bo
 		vo.StringValues = append(vo.StringValues, fmt.Sprintf("%s=%s", k, v))
 	}
 
-	// if we have an overriden values file set it
-	if valuesPath != "" {
-		vo.ValueFiles = []string{valuesPath}
-	}
+	// values files are merged in order with later files taking precedence
+	vo.ValueFiles = valuesPaths
 
 	h.log.Debug("Creating chart from config", "ref", name, "path", chartPath)
 	cp, err := client.ChartPathOptions.LocateChart(chartPath, &settings)
@@ -3,6 +3,7 @@ package clients
 import (
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -16,6 +17,15 @@ type HTTP interface {
 	// If it is not possible to contact the URI or if any status other than the passed codes is returned
 	// by the upstream, then the URI is retried until the timeout elapses.
 	HealthCheckHTTP(uri string, codes []int, timeout time.Duration) error
+	// HealthCheckTCP attempts to open a TCP connection to the given address,
+	// if the connection can be established the method returns a nil error.
+	// Failed connections are retried until the timeout elapses.
+	HealthCheckTCP(address string, timeout time.Duration) error
+	// CheckHTTP makes a single HTTP GET request to the given URI, an error is returned
+	// when the URI can not be contacted or the status is not one of the passed codes
+	CheckHTTP(uri string, codes []int) error
+	// CheckTCP makes a single attempt to open a TCP connection to the given address
+	CheckTCP(address string, timeout time.Duration) error
 	// Do executes a HTTP request and returns the response
 	Do(r *http.Request) (*http.Response, error)
 }
@@ -45,8 +55,8 @@ func (h *HTTPImpl) HealthCheckHTTP(address string, codes []int, timeout time.Dur
 			return fmt.Errorf("Timeout waiting for HTTP healthcheck %s", address)
 		}
 
-		resp, err := h.httpc.Get(address)
-		if err == nil && assertResponseCode(codes, resp.StatusCode) {
+		err := h.CheckHTTP(address, codes)
+		if err == nil {
 			h.l.Debug("Health check complete", "address", address)
 			return nil
 		}
@@ -56,6 +66,53 @@ func (h *HTTPImpl) HealthCheckHTTP(address string, codes []int, timeout time.Dur
 	}
 }
 
+// HealthCheckTCP checks that a TCP connection can be made to the given address
+func (h *HTTPImpl) HealthCheckTCP(address string, timeout time.Duration) error {
+	h.l.Debug("Performing TCP health check for address", "address", address)
+	st := time.Now()
+	for {
+		if time.Now().Sub(st) > timeout {
+			h.l.Error("Timeout wating for TCP healthcheck", "address", address)
+
+			return fmt.Errorf("Timeout waiting for TCP healthcheck %s", address)
+		}
+
+		err := h.CheckTCP(address, timeout)
+		if err == nil {
+			h.l.Debug("Health check complete", "address", address)
+			return nil
+		}
+
+		// backoff
+		time.Sleep(h.backoff)
+	}
+}
+
+// CheckHTTP makes a single HTTP GET request to the given address
+func (h *HTTPImpl) CheckHTTP(address string, codes []int) error {
+	resp, err := h.httpc.Get(address)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if !assertResponseCode(codes, resp.StatusCode) {
+		return fmt.Errorf("Unexpected status code %d from %s", resp.StatusCode, address)
+	}
+
+	return nil
+}
+
+// CheckTCP makes a single attempt to open a TCP connection to the given address
+func (h *HTTPImpl) CheckTCP(address string, timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
 func assertResponseCode(codes []int, responseCode int) bool {
 	for _, c := range codes {
 		if responseCode == c {
@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -66,3 +67,43 @@ func TestHTTPHealthErrorsOnClientError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Len(t, *reqs, 0)
 }
+
+func TestTCPHealthConnects(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	c := NewHTTP(1*time.Millisecond, hclog.NewNullLogger())
+
+	err = c.HealthCheckTCP(l.Addr().String(), 10*time.Millisecond)
+	assert.NoError(t, err)
+}
+
+func TestTCPHealthErrorsWhenUnableToConnect(t *testing.T) {
+	c := NewHTTP(1*time.Millisecond, hclog.NewNullLogger())
+
+	err := c.HealthCheckTCP("127.0.0.2:19091", 10*time.Millisecond)
+	assert.Error(t, err)
+}
+
+func TestCheckHTTPMakesSingleRequest(t *testing.T) {
+	url, reqs, cleanup := testSetupHTTPBasicServer(http.StatusBadRequest, "")
+	defer cleanup()
+
+	c := NewHTTP(1*time.Millisecond, hclog.NewNullLogger())
+
+	err := c.CheckHTTP(url, []int{200})
+	assert.Error(t, err)
+	assert.Len(t, *reqs, 1)
+}
+
+func TestCheckTCPConnects(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	c := NewHTTP(1*time.Millisecond, hclog.NewNullLogger())
+
+	err = c.CheckTCP(l.Addr().String(), 10*time.Millisecond)
+	assert.NoError(t, err)
+}
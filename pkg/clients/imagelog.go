@@ -10,6 +10,10 @@ import (
 // ImageTypeDocker defines a type for a Docker image
 const ImageTypeDocker string = "Docker"
 
+// ImageTypeVolume defines a type for an anonymous volume kept when a Shipyard
+// container is removed
+const ImageTypeVolume string = "Volume"
+
 // ImageLog logs machine images to make cleanup possible
 type ImageLog interface {
 	Log(string, string) error
@@ -10,12 +10,21 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
 	"golang.org/x/xerrors"
 	"helm.sh/helm/v3/pkg/kube"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -24,15 +33,27 @@ type Kubernetes interface {
 	SetConfig(string) (Kubernetes, error)
 	GetPods(string) (*v1.PodList, error)
 	HealthCheckPods(selectors []string, timeout time.Duration) error
+	// HealthCheckResource runs the pod health checks for a Helm or K8sConfig
+	// resource against the cluster the resource is deployed to
+	HealthCheckResource(r config.Resource, timeout time.Duration) error
+	WaitForCondition(apiVersion, kind, namespace, name, condition string, timeout time.Duration) error
 	Apply(files []string, waitUntilReady bool) error
 	Delete(files []string) error
+	// DeleteObject deletes a single object from the cluster, objects which do
+	// not exist are ignored
+	DeleteObject(apiVersion, kind, namespace, name string) error
 	GetPodLogs(ctx context.Context, podName, nameSpace string) (io.ReadCloser, error)
+	// ExportResources returns the Deployments, StatefulSets, Services, and ConfigMaps
+	// in the namespace with any fields set by the server removed
+	ExportResources(namespace string) ([]unstructured.Unstructured, error)
 }
 
 // KubernetesImpl is a concrete implementation of a Kubernetes client
 type KubernetesImpl struct {
 	clientset  *kubernetes.Clientset
 	client     corev1.CoreV1Interface
+	dynamic    dynamic.Interface
+	mapper     *restmapper.DeferredDiscoveryRESTMapper
 	configPath string
 	timeout    time.Duration
 	l          hclog.Logger
@@ -84,8 +105,15 @@ func (k *KubernetesImpl) setConfig() error {
 		return err
 	}
 
+	dc, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
 	k.clientset = clientset
 	k.client = clientset.CoreV1()
+	k.dynamic = dc
+	k.mapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientset.Discovery()))
 
 	return nil
 }
@@ -155,6 +183,120 @@ func (k *KubernetesImpl) Delete(files []string) error {
 	return nil
 }
 
+// DeleteObject deletes the object with the given kind and name, when the object
+// is namespaced and namespace is empty the default namespace is used
+func (k *KubernetesImpl) DeleteObject(apiVersion, kind, namespace, name string) error {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return xerrors.Errorf("invalid api_version %s: %w", apiVersion, err)
+	}
+
+	mapping, err := k.mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: kind}, gv.Version)
+	if err != nil {
+		return xerrors.Errorf("unable to find resource for %s %s: %w", apiVersion, kind, err)
+	}
+
+	var ri dynamic.ResourceInterface = k.dynamic.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if namespace == "" {
+			namespace = "default"
+		}
+
+		ri = k.dynamic.Resource(mapping.Resource).Namespace(namespace)
+	}
+
+	k.l.Debug("Removing Kubernetes object", "kind", kind, "name", name, "namespace", namespace)
+
+	err = ri.Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return xerrors.Errorf("unable to delete %s %s: %w", kind, name, err)
+	}
+
+	return nil
+}
+
+// HealthCheckResource resolves the cluster for the resource, creates a client
+// for the cluster, and checks the pods defined in the resource health check.
+// When timeout is 0 the timeout from the health check is used.
+func (k *KubernetesImpl) HealthCheckResource(r config.Resource, timeout time.Duration) error {
+	var cluster string
+	var hc *config.HealthCheck
+	namespace := "default"
+
+	switch v := r.(type) {
+	case *config.Helm:
+		cluster, hc = v.Cluster, v.HealthCheck
+		if v.Namespace != "" {
+			namespace = v.Namespace
+		}
+	case *config.K8sConfig:
+		cluster, hc = v.Cluster, v.HealthCheck
+	default:
+		return fmt.Errorf("resource type %s does not support pod health checks", r.Info().Type)
+	}
+
+	if hc == nil || (len(hc.Pods) == 0 && len(hc.Conditions) == 0) {
+		return nil
+	}
+
+	if timeout == 0 {
+		d, err := time.ParseDuration(hc.Timeout)
+		if err != nil {
+			return xerrors.Errorf("unable to parse healthcheck duration: %w", err)
+		}
+
+		timeout = d
+	}
+
+	cl, err := r.FindDependentResource(cluster)
+	if err != nil {
+		return xerrors.Errorf("unable to find cluster %s: %w", cluster, err)
+	}
+
+	_, kubeConfig, _ := utils.CreateKubeConfigPath(cl.Info().Name)
+
+	kc, err := k.SetConfig(kubeConfig)
+	if err != nil {
+		return xerrors.Errorf("unable to create Kubernetes client: %w", err)
+	}
+
+	err = kc.HealthCheckPods(hc.Pods, timeout)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range hc.Conditions {
+		err := HealthCheckCondition(kc, c, namespace, timeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HealthCheckCondition waits for the object in the condition to report the condition,
+// objects without a namespace are assumed to be in the namespace of the resource
+func HealthCheckCondition(kc Kubernetes, c config.HealthCheckCondition, namespace string, timeout time.Duration) error {
+	apiVersion, err := c.GroupVersion()
+	if err != nil {
+		return err
+	}
+
+	if c.Namespace != "" {
+		namespace = c.Namespace
+	}
+
+	if c.Timeout != "" {
+		timeout, err = time.ParseDuration(c.Timeout)
+		if err != nil {
+			return xerrors.Errorf("unable to parse condition timeout: %w", err)
+		}
+	}
+
+	return kc.WaitForCondition(apiVersion, c.Kind, namespace, c.Name, c.Condition, timeout)
+}
+
 // HealthCheckPods uses the given selector to check that all pods are started
 // and running.
 // selectors are checked sequentially
@@ -222,6 +364,76 @@ func (k *KubernetesImpl) healthCheckSingle(selector string, timeout time.Duratio
 	return nil
 }
 
+// WaitForCondition blocks until the Kubernetes object defined by apiVersion, kind, and name
+// has a status condition of the given type set to True.
+// namespace is ignored for cluster scoped objects.
+func (k *KubernetesImpl) WaitForCondition(apiVersion, kind, namespace, name, condition string, timeout time.Duration) error {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return xerrors.Errorf("invalid api_version %s: %w", apiVersion, err)
+	}
+
+	k.l.Debug("Waiting for condition", "kind", kind, "name", name, "namespace", namespace, "condition", condition)
+
+	st := time.Now()
+	for {
+		if time.Now().Sub(st) > timeout {
+			return fmt.Errorf("Timeout waiting for condition %s on %s %s", condition, kind, name)
+		}
+
+		ok, err := k.checkCondition(gv, kind, namespace, name, condition)
+		if err != nil {
+			k.l.Debug("Error checking condition, will retry", "kind", kind, "name", name, "error", err)
+		}
+
+		if ok {
+			return nil
+		}
+
+		// backoff
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// checkCondition returns true when the object has the condition with a status of True
+func (k *KubernetesImpl) checkCondition(gv schema.GroupVersion, kind, namespace, name, condition string) (bool, error) {
+	mapping, err := k.mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: kind}, gv.Version)
+	if err != nil {
+		// the kind might be a CRD which has not yet been registered, reset
+		// the discovery cache so that it is refreshed on the next attempt
+		k.mapper.Reset()
+		return false, err
+	}
+
+	var ri dynamic.ResourceInterface = k.dynamic.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		ri = k.dynamic.Resource(mapping.Resource).Namespace(namespace)
+	}
+
+	obj, err := ri.Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return false, err
+	}
+
+	for _, c := range conditions {
+		cm, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if cm["type"] == condition && cm["status"] == "True" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func buildFileList(files []string) ([]string, error) {
 	allFiles := make([]string, 0)
 
@@ -298,3 +510,55 @@ func deleteFile(path string, kc *kube.Client) error {
 
 	return nil
 }
+
+// exportKinds are the resources returned by ExportResources
+var exportKinds = []schema.GroupVersionResource{
+	{Group: "", Version: "v1", Resource: "configmaps"},
+	{Group: "", Version: "v1", Resource: "services"},
+	{Group: "apps", Version: "v1", Resource: "deployments"},
+	{Group: "apps", Version: "v1", Resource: "statefulsets"},
+}
+
+// ExportResources returns the Deployments, StatefulSets, Services, and ConfigMaps in the
+// namespace. Fields which are set by the server such as the status and uid are removed so
+// that the objects can be re-applied to a new cluster.
+func (k *KubernetesImpl) ExportResources(namespace string) ([]unstructured.Unstructured, error) {
+	objs := []unstructured.Unstructured{}
+
+	for _, gvr := range exportKinds {
+		k.l.Debug("Exporting resources", "resource", gvr.Resource, "namespace", namespace)
+
+		list, err := k.dynamic.Resource(gvr).Namespace(namespace).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to list %s in namespace %s: %w", gvr.Resource, namespace, err)
+		}
+
+		for _, o := range list.Items {
+			// skip resources which are created automatically by Kubernetes
+			if (o.GetKind() == "ConfigMap" && o.GetName() == "kube-root-ca.crt") ||
+				(o.GetKind() == "Service" && o.GetName() == "kubernetes") {
+				continue
+			}
+
+			unstructured.RemoveNestedField(o.Object, "status")
+			unstructured.RemoveNestedField(o.Object, "metadata", "uid")
+			unstructured.RemoveNestedField(o.Object, "metadata", "resourceVersion")
+			unstructured.RemoveNestedField(o.Object, "metadata", "creationTimestamp")
+			unstructured.RemoveNestedField(o.Object, "metadata", "managedFields")
+			unstructured.RemoveNestedField(o.Object, "metadata", "selfLink")
+			unstructured.RemoveNestedField(o.Object, "metadata", "generation")
+			unstructured.RemoveNestedField(o.Object, "metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration")
+			unstructured.RemoveNestedField(o.Object, "metadata", "annotations", "deployment.kubernetes.io/revision")
+
+			// cluster ips are allocated by the server
+			if o.GetKind() == "Service" {
+				unstructured.RemoveNestedField(o.Object, "spec", "clusterIP")
+				unstructured.RemoveNestedField(o.Object, "spec", "clusterIPs")
+			}
+
+			objs = append(objs, o)
+		}
+	}
+
+	return objs, nil
+}
@@ -7,8 +7,10 @@ import (
 	"io/ioutil"
 	"time"
 	
+	"github.com/shipyard-run/shipyard/pkg/config"
 	"github.com/stretchr/testify/mock"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 type MockKubernetes struct {
@@ -49,8 +51,36 @@ func (m *MockKubernetes) Delete(files []string) error {
 	return args.Error(0)
 }
 
+func (m *MockKubernetes) DeleteObject(apiVersion, kind, namespace, name string) error {
+	args := m.Called(apiVersion, kind, namespace, name)
+
+	return args.Error(0)
+}
+
 func (m *MockKubernetes) HealthCheckPods(selectors []string, timeout time.Duration) error {
 	args := m.Called(selectors, timeout)
 
 	return args.Error(0)
 }
+
+func (m *MockKubernetes) HealthCheckResource(r config.Resource, timeout time.Duration) error {
+	args := m.Called(r, timeout)
+
+	return args.Error(0)
+}
+
+func (m *MockKubernetes) WaitForCondition(apiVersion, kind, namespace, name, condition string, timeout time.Duration) error {
+	args := m.Called(apiVersion, kind, namespace, name, condition, timeout)
+
+	return args.Error(0)
+}
+
+func (m *MockKubernetes) ExportResources(namespace string) ([]unstructured.Unstructured, error) {
+	args := m.Called(namespace)
+
+	if o, ok := args.Get(0).([]unstructured.Unstructured); ok {
+		return o, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
@@ -2,6 +2,12 @@ package clients
 
 import (
 	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // TODO: implement these tests
@@ -14,6 +20,70 @@ func TestApply(t *testing.T) {
 	t.Skip()
 }
 
+func TestHealthCheckResourceReturnsErrorForUnsupportedType(t *testing.T) {
+	k := NewKubernetes(0, hclog.NewNullLogger())
+
+	err := k.HealthCheckResource(config.NewContainer("test"), 0)
+	assert.Error(t, err)
+}
+
+func TestHealthCheckResourceSkipsWhenNoPods(t *testing.T) {
+	k := NewKubernetes(0, hclog.NewNullLogger())
+
+	h := config.NewHelm("test")
+	h.HealthCheck = &config.HealthCheck{Timeout: "10s"}
+
+	err := k.HealthCheckResource(h, 0)
+	assert.NoError(t, err)
+}
+
+func TestHealthCheckResourceReturnsErrorWhenClusterNotFound(t *testing.T) {
+	k := NewKubernetes(0, hclog.NewNullLogger())
+
+	c := config.New()
+	h := config.NewK8sConfig("test")
+	h.Cluster = "k8s_cluster.missing"
+	h.HealthCheck = &config.HealthCheck{Timeout: "10s", Pods: []string{"app=consul"}}
+	c.AddResource(h)
+
+	err := k.HealthCheckResource(h, 0)
+	assert.Error(t, err)
+}
+
+func TestHealthCheckConditionUsesDefaultAPIVersionAndNamespace(t *testing.T) {
+	mk := &MockKubernetes{}
+	mk.On("WaitForCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	c := config.HealthCheckCondition{Kind: "Deployment", Name: "consul", Condition: "Available"}
+
+	err := HealthCheckCondition(mk, c, "consul", 10*time.Second)
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "WaitForCondition", "apps/v1", "Deployment", "consul", "consul", "Available", 10*time.Second)
+}
+
+func TestHealthCheckConditionUsesConditionNamespaceAndTimeout(t *testing.T) {
+	mk := &MockKubernetes{}
+	mk.On("WaitForCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	c := config.HealthCheckCondition{Kind: "Job", Name: "migrate", Namespace: "jobs", Condition: "Complete", Timeout: "2m"}
+
+	err := HealthCheckCondition(mk, c, "default", 10*time.Second)
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "WaitForCondition", "batch/v1", "Job", "jobs", "migrate", "Complete", 2*time.Minute)
+}
+
+func TestHealthCheckConditionReturnsErrorForUnknownKindWithoutAPIVersion(t *testing.T) {
+	mk := &MockKubernetes{}
+
+	c := config.HealthCheckCondition{Kind: "Certificate", Name: "consul", Condition: "Ready"}
+
+	err := HealthCheckCondition(mk, c, "default", 10*time.Second)
+	assert.Error(t, err)
+	mk.AssertNotCalled(t, "WaitForCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 const guestbookManifest = `
 apiVersion: v1
 kind: Service
@@ -0,0 +1,28 @@
+package clients
+
+import "github.com/shipyard-run/shipyard/pkg/config"
+
+// LabelResourceID is the Docker label containing the id of the resource which
+// created a container, network, or volume e.g. container.consul
+const LabelResourceID = "run.shipyard.resource_id"
+
+// LabelType is the Docker label containing the type of the resource
+const LabelType = "run.shipyard.type"
+
+// LabelName is the Docker label containing the name of a container before it is
+// converted to a fully qualified name e.g. server.k3s
+const LabelName = "run.shipyard.name"
+
+// LabelWorkspace is the Docker label containing the workspace the object was
+// created in
+const LabelWorkspace = "run.shipyard.workspace"
+
+// ResourceLabels returns the labels which are added to every Docker object
+// created for the resource with the given id and type in the workspace
+func ResourceLabels(id string, t config.ResourceType, workspace string) map[string]string {
+	return map[string]string{
+		LabelResourceID: id,
+		LabelType:       string(t),
+		LabelWorkspace:  workspace,
+	}
+}
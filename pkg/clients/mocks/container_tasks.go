@@ -1,9 +1,12 @@
 package mocks
 
 import (
+	"context"
 	"io"
+	"time"
 
 	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -15,6 +18,21 @@ func (m *MockContainerTasks) SetForcePull(f bool) {
 	m.Called(f)
 }
 
+func (m *MockContainerTasks) SetWorkspace(name string) {
+	m.Called(name)
+}
+
+// Workspace returns the default workspace unless the method has been mocked
+func (m *MockContainerTasks) Workspace() string {
+	for _, c := range m.ExpectedCalls {
+		if c.Method == "Workspace" {
+			return m.Called().String(0)
+		}
+	}
+
+	return utils.DefaultWorkspace
+}
+
 func (m *MockContainerTasks) CreateContainer(c *config.Container) (id string, err error) {
 	args := m.Called(c)
 
@@ -33,6 +51,22 @@ func (m *MockContainerTasks) RemoveContainer(id string, force bool) error {
 	return args.Error(0)
 }
 
+func (m *MockContainerTasks) RemoveContainerWithOptions(id string, force, removeVolumes bool, stopTimeout time.Duration) error {
+	args := m.Called(id, force, removeVolumes, stopTimeout)
+
+	return args.Error(0)
+}
+
+func (m *MockContainerTasks) RemoveDanglingVolumes() ([]string, error) {
+	args := m.Called()
+
+	if v, ok := args.Get(0).([]string); ok {
+		return v, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
 func (m *MockContainerTasks) BuildContainer(config *config.Container, force bool) (string, error) {
 	args := m.Called(config, force)
 	return args.String(0), args.Error(1)
@@ -66,6 +100,22 @@ func (m *MockContainerTasks) FindContainerIDs(name string, typeName config.Resou
 	return nil, args.Error(1)
 }
 
+func (m *MockContainerTasks) FindResourceContainerIDs(resourceID string) ([]string, error) {
+	args := m.Called(resourceID)
+
+	if sa, ok := args.Get(0).([]string); ok {
+		return sa, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (d *MockContainerTasks) ContainerLogsFollow(ctx context.Context, id, tail string, follow bool, stdOut, stdErr io.Writer) error {
+	args := d.Called(ctx, id, tail, follow, stdOut, stdErr)
+
+	return args.Error(0)
+}
+
 func (d *MockContainerTasks) ContainerLogs(id string, stdOut, stdErr bool) (io.ReadCloser, error) {
 	args := d.Called(id, stdOut, stdErr)
 
@@ -108,6 +158,18 @@ func (d *MockContainerTasks) CopyFilesToVolume(volume string, files []string, pa
 	return nil, args.Error(1)
 }
 
+func (d *MockContainerTasks) SnapshotVolume(ctx context.Context, volumeOrPath, dest string) error {
+	args := d.Called(ctx, volumeOrPath, dest)
+
+	return args.Error(0)
+}
+
+func (d *MockContainerTasks) RestoreVolume(ctx context.Context, src, volumeOrPath string) error {
+	args := d.Called(ctx, src, volumeOrPath)
+
+	return args.Error(0)
+}
+
 func (d *MockContainerTasks) ExecuteCommand(id string, command []string, env []string, workingDirectory string, user, group string, writer io.Writer) error {
 	args := d.Called(id, command, env, workingDirectory, user, group, writer)
 
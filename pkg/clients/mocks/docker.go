@@ -231,6 +231,16 @@ func (m *MockDocker) ImageList(ctx context.Context, options types.ImageListOptio
 	return []types.ImageSummary{}, args.Error(1)
 }
 
+func (m *MockDocker) ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error) {
+	args := m.Called(ctx, imageID)
+
+	if ii, ok := args.Get(0).(types.ImageInspect); ok {
+		return ii, nil, args.Error(1)
+	}
+
+	return types.ImageInspect{}, nil, args.Error(1)
+}
+
 func (m *MockDocker) ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
 	args := m.Called(ctx, imageID, options)
 
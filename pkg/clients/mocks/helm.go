@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/stretchr/testify/mock"
 )
 
@@ -8,8 +10,8 @@ type MockHelm struct {
 	mock.Mock
 }
 
-func (h *MockHelm) Create(kubeConfig, name, namespace string, createNamespace bool, chartPath, valuesPath string, valueString map[string]string) error {
-	args := h.Called(kubeConfig, name, namespace, createNamespace, chartPath, valuesPath, valueString)
+func (h *MockHelm) Create(kubeConfig, name, namespace string, createNamespace bool, chartPath string, valuesPaths []string, valueString map[string]string, timeout time.Duration, atomic bool) error {
+	args := h.Called(kubeConfig, name, namespace, createNamespace, chartPath, valuesPaths, valueString, timeout, atomic)
 
 	return args.Error(0)
 }
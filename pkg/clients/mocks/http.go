@@ -21,6 +21,24 @@ func (m *MockHTTP) HealthCheckHTTP(uri string, codes []int, timeout time.Duratio
 	return args.Error(0)
 }
 
+func (m *MockHTTP) HealthCheckTCP(address string, timeout time.Duration) error {
+	args := m.Called(address, timeout)
+
+	return args.Error(0)
+}
+
+func (m *MockHTTP) CheckHTTP(uri string, codes []int) error {
+	args := m.Called(uri, codes)
+
+	return args.Error(0)
+}
+
+func (m *MockHTTP) CheckTCP(address string, timeout time.Duration) error {
+	args := m.Called(address, timeout)
+
+	return args.Error(0)
+}
+
 func (m *MockHTTP) Do(r *http.Request) (*http.Response, error) {
 	args := m.Called(r)
 
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// TypeAssert is the resource string for an Assert resource
+const TypeAssert ResourceType = "assert"
+
+// Conditions which can be used by an Assert resource
+const (
+	AssertNotEmpty  = "not_empty"
+	AssertEquals    = "equals"
+	AssertNotEquals = "not_equals"
+	AssertContains  = "contains"
+	AssertMatches   = "matches"
+)
+
+// Assert checks the value of an attribute of another resource once that
+// resource has been created, if the condition does not hold the apply fails.
+// Assertions allow a blueprint to verify the environment came up correctly.
+type Assert struct {
+	ResourceInfo `hcl:",remain" mapstructure:",squash"`
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Resource is the resource to check e.g. container.api or output.api_addr
+	Resource string `hcl:"resource" json:"resource"`
+
+	// Attribute is the path to the attribute to check, nested attributes and
+	// list items are separated by a dot e.g. ports.0.host
+	Attribute string `hcl:"attribute" json:"attribute"`
+
+	// Condition is one of not_empty, equals, not_equals, contains, or matches
+	Condition string `hcl:"condition" json:"condition"`
+
+	// Value to compare the attribute with, not used by not_empty
+	Value string `hcl:"value,optional" json:"value,omitempty"`
+
+	// Message to display when the assertion fails
+	Message string `hcl:"message,optional" json:"message,omitempty"`
+}
+
+// NewAssert creates a new Assert resource with the correct defaults
+func NewAssert(name string) *Assert {
+	return &Assert{ResourceInfo: ResourceInfo{Name: name, Type: TypeAssert, Status: PendingCreation}}
+}
+
+// Validate the config
+func (a *Assert) Validate() error {
+	switch a.Condition {
+	case AssertNotEmpty:
+		return nil
+	case AssertEquals, AssertNotEquals, AssertContains:
+	case AssertMatches:
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("invalid regular expression %s: %s", a.Value, err)
+		}
+	default:
+		return fmt.Errorf("invalid condition %s, condition must be one of %s, %s, %s, %s, %s", a.Condition, AssertNotEmpty, AssertEquals, AssertNotEquals, AssertContains, AssertMatches)
+	}
+
+	if a.Value == "" {
+		return fmt.Errorf("value must be set for condition %s", a.Condition)
+	}
+
+	return nil
+}
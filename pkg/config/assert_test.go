@@ -0,0 +1,81 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCreatesAssert(t *testing.T) {
+	c := NewAssert("abc")
+
+	assert.Equal(t, "abc", c.Name)
+	assert.Equal(t, TypeAssert, c.Type)
+}
+
+func TestAssertCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, assertValid)
+	defer cleanup()
+
+	cc, err := c.FindResource("assert.port")
+	assert.NoError(t, err)
+
+	a := cc.(*Assert)
+	assert.Equal(t, "container.api", a.Resource)
+	assert.Equal(t, "ports.0.host", a.Attribute)
+	assert.Equal(t, AssertEquals, a.Condition)
+	assert.Equal(t, "8080", a.Value)
+	assert.Contains(t, a.DependsOn, "container.api")
+}
+
+func TestAssertInvalidConditionReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	createNamedFile(t, dir, "*.hcl", assertInvalidCondition)
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid condition")
+}
+
+func TestAssertWithoutValueReturnsError(t *testing.T) {
+	a := NewAssert("test")
+	a.Condition = AssertEquals
+
+	assert.Error(t, a.Validate())
+
+	a.Condition = AssertNotEmpty
+	assert.NoError(t, a.Validate())
+}
+
+var assertValid = `
+container "api" {
+  image {
+    name = "nicholasjackson/fake-service:v0.9.0"
+  }
+
+  port {
+    local  = 9090
+    remote = 9090
+    host   = 8080
+  }
+}
+
+assert "port" {
+  resource  = "container.api"
+  attribute = "ports.0.host"
+  condition = "equals"
+  value     = "8080"
+}
+`
+
+var assertInvalidCondition = `
+assert "port" {
+  resource  = "container.api"
+  attribute = "ports.0.host"
+  condition = "bigger"
+  value     = "8080"
+}
+`
@@ -15,6 +15,14 @@ type Blueprint struct {
 	HealthCheckTimeout string   `hcl:"health_check_timeout,optional" json:"health_check_timeout,omitempty" mapstructure:"health_check_timeout"`
 	Environment        []KV     `hcl:"env,block" json:"environment,omitempty"`
 	ShipyardVersion    string   `hcl:"shipyard_version,optional" json:"shipyard_version,omitempty"`
+
+	// MaxParallel limits the number of resources of a type which are created or destroyed concurrently
+	// e.g. max_parallel = { k8s_cluster = 1 }
+	MaxParallel map[string]int `hcl:"max_parallel,optional" json:"max_parallel,omitempty" mapstructure:"max_parallel"`
+
+	// RecreateUnhealthy causes containers which Docker reports as unhealthy to be
+	// destroyed and created on the next apply
+	RecreateUnhealthy bool `hcl:"recreate_unhealthy,optional" json:"recreate_unhealthy,omitempty" mapstructure:"recreate_unhealthy"`
 }
 
 // Validate the Blueprint and return errors
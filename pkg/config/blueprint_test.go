@@ -35,6 +35,14 @@ func TestBlueprintCreatesCorrectly(t *testing.T) {
 	assert.Equal(t, "true", bp.Environment[1].Value)
 }
 
+func TestBlueprintSetsMaxParallel(t *testing.T) {
+	c, cleanup := setupBlueprints(t, blueprintMaxParallel)
+	defer cleanup()
+
+	assert.Equal(t, 1, c.Blueprint.MaxParallel["k8s_cluster"])
+	assert.Equal(t, 10, c.Blueprint.MaxParallel["container"])
+}
+
 func TestBlueprintValidationInvalidBrowser(t *testing.T) {
 	c, cleanup := setupBlueprints(t, blueprintInvalidBrowser)
 	defer cleanup()
@@ -72,3 +80,12 @@ browser_windows = [
 	"https://www.something.com",
 ]
 `
+
+var blueprintMaxParallel = `
+title = "max parallel"
+
+max_parallel = {
+	k8s_cluster = 1
+	container   = 10
+}
+`
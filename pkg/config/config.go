@@ -61,11 +61,43 @@ type ResourceInfo struct {
 	Module string `json:"module,omitempty"`
 	// Enabled determines if a resource is enabled and should be processed
 	Disabled bool `hcl:"disabled,optional" json:"disabled,omitempty"`
+	// Retry allows the creation of a resource to be retried when it fails
+	Retry *Retry `hcl:"retry,block" json:"retry,omitempty"`
+	// Timeout is the maximum time to wait for the resource to be created or destroyed e.g. 5m,
+	// defaults to 300s
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"`
+	// Priority orders resources which do not depend on each other, resources with a
+	// higher priority are created before resources with a lower priority which are at
+	// the same depth in the dependency graph. Defaults to 0
+	Priority int `hcl:"priority,optional" json:"priority,omitempty"`
+	// Attempts is the number of attempts it took to create the resource
+	Attempts int `json:"attempts,omitempty"`
+	// File is the path of the file which defines the resource and Line is the line
+	// of the resource block, these are used to show where a failed resource is defined
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+	// Parent is the id of the resource which created this resource, it is set
+	// for the containers created by clusters and other resources
+	Parent string `json:"-"`
+	// Variables is the list of variables referenced by the resource, this is set when the
+	// resource is parsed and is not saved to the state
+	Variables []string `json:"-"`
 
 	// parent container
 	Config *Config `json:"-"`
 }
 
+// Retry defines how the creation of a resource is retried on failure
+type Retry struct {
+	// Attempts is the maximum number of times Create is attempted
+	Attempts int `hcl:"attempts" json:"attempts"`
+	// Backoff is the initial time to wait between attempts e.g. 5s, the wait is doubled
+	// after each failed attempt
+	Backoff string `hcl:"backoff,optional" json:"backoff,omitempty"`
+	// MaxWait is the longest time to wait between attempts e.g. 30s
+	MaxWait string `hcl:"max_wait,optional" json:"max_wait,omitempty" mapstructure:"max_wait"`
+}
+
 func (r *ResourceInfo) Info() *ResourceInfo {
 	return r
 }
@@ -80,6 +112,18 @@ func (r *ResourceInfo) AddChild(c Resource) {
 
 	// override the childs type so that the names are created correctly
 	c.Info().Type = r.Type
+
+	c.Info().Parent = fmt.Sprintf("%s.%s", r.Type, r.Name)
+}
+
+// OwnerID returns the id of the resource which owns this resource e.g.
+// container.consul, child resources are owned by the resource which created them
+func (r *ResourceInfo) OwnerID() string {
+	if r.Parent != "" {
+		return r.Parent
+	}
+
+	return fmt.Sprintf("%s.%s", r.Type, r.Name)
 }
 
 // Config defines the stack config
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"testing"
 
 	assert "github.com/stretchr/testify/require"
@@ -53,6 +54,18 @@ func TestResourceAddChildSetsDetails(t *testing.T) {
 	assert.Equal(t, c.Resources[0].Info().Type, cl.Type)
 }
 
+func TestResourceOwnerIDReturnsParentForChild(t *testing.T) {
+	c := testSetupConfig(t)
+	cl := NewK8sCluster("newtest")
+
+	assert.Equal(t, "k8s_cluster.newtest", cl.Info().OwnerID())
+
+	c.Resources[0].AddChild(cl)
+
+	p := c.Resources[0].Info()
+	assert.Equal(t, fmt.Sprintf("%s.%s", p.Type, p.Name), cl.Info().OwnerID())
+}
+
 func TestFindResourceFindsCluster(t *testing.T) {
 	c := testSetupConfig(t)
 
@@ -1,5 +1,20 @@
 package config
 
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/builder/dockerignore"
+	"github.com/docker/docker/pkg/fileutils"
+)
+
 // TypeContainer is the resource string for a Container resource
 const TypeContainer ResourceType = "container"
 
@@ -12,15 +27,28 @@ type Container struct {
 
 	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // Attach to the correct network // only when Image is specified
 
-	Image       *Image            `hcl:"image,block" json:"image"`                                                 // Image to use for the container
-	Build       *Build            `hcl:"build,block" json:"build"`                                                 // Enables containers to be built on the fly
-	Entrypoint  []string          `hcl:"entrypoint,optional" json:"entrypoint,omitempty"`                          // entrypoint to use when starting the container
-	Command     []string          `hcl:"command,optional" json:"command,omitempty"`                                // command to use when starting the container
-	Environment []KV              `hcl:"env,block" json:"environment,omitempty"`                                   // environment variables to set when starting the container, // Depricated field
-	EnvVar      map[string]string `hcl:"env_var,optional" json:"env_var,omitempty" mapstructure:"env_var"`         // environment variables to set when starting the container
-	Volumes     []Volume          `hcl:"volume,block" json:"volumes,omitempty"`                                    // volumes to attach to the container
-	Ports       []Port            `hcl:"port,block" json:"ports,omitempty"`                                        // ports to expose
-	PortRanges  []PortRange       `hcl:"port_range,block" json:"port_ranges,omitempty" mapstructure:"port_ranges"` // range of ports to expose
+	// NetworkMode allows the Docker network mode to be set directly [host, none, container:<id>]
+	// when set the container is not attached to any networks
+	NetworkMode string `hcl:"network_mode,optional" json:"network_mode,omitempty" mapstructure:"network_mode"`
+
+	// DNS sets the nameservers for the container, overriding the DNS servers inherited from the network
+	DNS []string `hcl:"dns,optional" json:"dns,omitempty"`
+
+	// LogDriver sets the Docker logging driver for the container [json-file, local, journald, syslog, fluentd, gelf, awslogs, splunk, none]
+	LogDriver string `hcl:"log_driver,optional" json:"log_driver,omitempty" mapstructure:"log_driver"`
+
+	// LogOpts are the options passed to the logging driver
+	LogOpts map[string]string `hcl:"log_opts,optional" json:"log_opts,omitempty" mapstructure:"log_opts"`
+
+	Image       *Image            `hcl:"image,block" json:"image"`                                                          // Image to use for the container
+	Build       *Build            `hcl:"build,block" json:"build"`                                                          // Enables containers to be built on the fly
+	Entrypoint  []string          `hcl:"entrypoint,optional" json:"entrypoint,omitempty"`                                   // entrypoint to use when starting the container
+	Command     []string          `hcl:"command,optional" json:"command,omitempty"`                                         // command to use when starting the container
+	Environment []KV              `hcl:"env,block" json:"environment,omitempty"`                                            // environment variables to set when starting the container, // Depricated field
+	EnvVar      map[string]string `hcl:"env_var,optional" json:"env_var,omitempty" mapstructure:"env_var" sensitive:"true"` // environment variables to set when starting the container
+	Volumes     []Volume          `hcl:"volume,block" json:"volumes,omitempty"`                                             // volumes to attach to the container
+	Ports       []Port            `hcl:"port,block" json:"ports,omitempty"`                                                 // ports to expose
+	PortRanges  []PortRange       `hcl:"port_range,block" json:"port_ranges,omitempty" mapstructure:"port_ranges"`          // range of ports to expose
 
 	Privileged bool `hcl:"privileged,optional" json:"privileged,omitempty"` // run the container in privileged mode?
 
@@ -32,8 +60,35 @@ type Container struct {
 
 	MaxRestartCount int `hcl:"max_restart_count,optional" json:"max_restart_count,omitempty" mapstructure:"max_restart_count"`
 
+	// Init containers run to completion when they are created, resources which depend
+	// on an init container are not created until it has exited successfully
+	Init bool `hcl:"init,optional" json:"init,omitempty"`
+
+	// StopTimeout is the time the container is given to stop gracefully when it is destroyed
+	// before it is killed e.g. 60s, defaults to 30s
+	StopTimeout string `hcl:"stop_timeout,optional" json:"stop_timeout,omitempty" mapstructure:"stop_timeout"`
+
+	// Restart is the Docker restart policy for the container, no, on-failure, always, or unless-stopped.
+	// When not set containers with a max_restart_count use on-failure
+	Restart string `hcl:"restart,optional" json:"restart,omitempty"`
+
+	// RemoveVolumes removes the anonymous volumes for the container when it is destroyed, defaults to true
+	RemoveVolumes *bool `hcl:"remove_volumes,optional" json:"remove_volumes,omitempty" mapstructure:"remove_volumes"`
+
+	// Startup defines a grace period the container must stay running for before it is considered created
+	Startup *Startup `hcl:"startup,block" json:"startup,omitempty"`
+
 	// User block for mapping the user id and group id inside the container
 	RunAs *User `hcl:"run_as,block" json:"run_as,omitempty" mapstructure:"run_as"`
+
+	// Secrets are mounted read only into the container
+	Secrets []FileMount `hcl:"secret,block" json:"secrets,omitempty"`
+
+	// Configs are mounted read only into the container
+	Configs []FileMount `hcl:"config,block" json:"configs,omitempty"`
+
+	// BuildChecksum is the checksum of the Build when the image was last built
+	BuildChecksum string `json:"build_checksum,omitempty" mapstructure:"build_checksum" state:"true"`
 }
 
 type User struct {
@@ -43,6 +98,16 @@ type User struct {
 	Group string `hcl:"group" json:"group,omitempty" mapstructure:"group"`
 }
 
+// Startup allows containers which restart on first boot to stabilize before
+// the container is marked as failed
+type Startup struct {
+	// GracePeriod is the duration the container must be running for e.g. 30s
+	GracePeriod string `hcl:"grace_period" json:"grace_period" mapstructure:"grace_period"`
+	// MaxRestarts is the number of restarts tolerated during the grace period,
+	// restarts require max_restart_count to be set on the container
+	MaxRestarts int `hcl:"max_restarts,optional" json:"max_restarts,omitempty" mapstructure:"max_restarts"`
+}
+
 // NewContainer returns a new Container resource with the correct default options
 func NewContainer(name string) *Container {
 	return &Container{ResourceInfo: ResourceInfo{Name: name, Type: TypeContainer, Status: PendingCreation}}
@@ -52,6 +117,8 @@ type NetworkAttachment struct {
 	Name      string   `hcl:"name" json:"name"`
 	IPAddress string   `hcl:"ip_address,optional" json:"ip_address,omitempty" mapstructure:"ip_address"`
 	Aliases   []string `hcl:"aliases,optional" json:"aliases,omitempty"` // Network aliases for the resource
+	// MacAddress sets a fixed MAC address for the container on the network
+	MacAddress string `hcl:"mac_address,optional" json:"mac_address,omitempty" mapstructure:"mac_address"`
 }
 
 // Resources allows the setting of resource constraints for the Container
@@ -67,6 +134,64 @@ type Volume struct {
 	Destination string `hcl:"destination" json:"destination"`                                         // path to mount the volume inside the container
 	Type        string `hcl:"type,optional" json:"type,omitempty"`                                    // type of the volume to mount [bind, volume, tmpfs]
 	ReadOnly    bool   `hcl:"read_only,optional" json:"read_only,omitempty" mapstructure:"read_only"` // specify that the volume is mounted read only
+	// Propagation sets the mount propagation for bind mounts [private, rprivate, shared, rshared, slave, rslave]
+	Propagation string `hcl:"propagation,optional" json:"propagation,omitempty"`
+	// SELinuxLabel relabels the source of a bind mount on SELinux hosts, z shares the
+	// content between containers, Z makes the content private to the container
+	SELinuxLabel string `hcl:"selinux_label,optional" json:"selinux_label,omitempty" mapstructure:"selinux_label"`
+}
+
+// volumePropagations are the valid values for Volume Propagation
+var volumePropagations = []string{"private", "rprivate", "shared", "rshared", "slave", "rslave"}
+
+// Validate the Volume and return errors
+func (v Volume) Validate() error {
+	switch v.Type {
+	case "", "bind", "volume", "tmpfs":
+	default:
+		return fmt.Errorf("invalid type %s, valid options are bind, volume, or tmpfs", v.Type)
+	}
+
+	bind := v.Type == "" || v.Type == "bind"
+
+	if v.Propagation != "" {
+		if !bind {
+			return fmt.Errorf("propagation can only be set for bind volumes")
+		}
+
+		valid := false
+		for _, p := range volumePropagations {
+			valid = valid || p == v.Propagation
+		}
+
+		if !valid {
+			return fmt.Errorf("invalid propagation %s, valid options are %s", v.Propagation, strings.Join(volumePropagations, ", "))
+		}
+	}
+
+	if v.SELinuxLabel != "" {
+		if !bind {
+			return fmt.Errorf("selinux_label can only be set for bind volumes")
+		}
+
+		if v.SELinuxLabel != "z" && v.SELinuxLabel != "Z" {
+			return fmt.Errorf("invalid selinux_label %s, valid options are z or Z", v.SELinuxLabel)
+		}
+	}
+
+	return nil
+}
+
+// validateVolumes validates each of the volumes
+func validateVolumes(vols []Volume) error {
+	for _, v := range vols {
+		err := v.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid volume %s: %s", v.Destination, err)
+		}
+	}
+
+	return nil
 }
 
 // KV is a key/value type
@@ -78,11 +203,360 @@ type KV struct {
 // Build allows you to define the conditions for building a container
 // on run from a Dockerfile
 type Build struct {
-	File    string `hcl:"file,optional" json:"file,omitempty"` // Location of build file inside build context defaults to ./Dockerfile
-	Context string `hcl:"context" json:"context"`              // Path to build context
+	File    string            `hcl:"file,optional" json:"file,omitempty"` // Location of build file inside build context defaults to ./Dockerfile
+	Context string            `hcl:"context" json:"context"`              // Path to build context
+	Args    map[string]string `hcl:"args,optional" json:"args,omitempty"` // Build arguments passed to the Dockerfile
+}
+
+// Checksum returns a checksum of the build context, Dockerfile location, and build
+// arguments, when any of these change the image needs to be rebuilt.
+// Files excluded by a .dockerignore in the context are not included.
+func (b *Build) Checksum() (string, error) {
+	sum := sha256.New()
+
+	ignore, err := b.ignorePatterns()
+	if err != nil {
+		return "", fmt.Errorf("unable to read .dockerignore in %s: %s", b.Context, err)
+	}
+
+	err = filepath.Walk(b.Context, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(b.Context, path)
+		if err != nil {
+			return err
+		}
+
+		if ignore != nil && rel != "." {
+			ignored, err := ignore.Matches(rel)
+			if err != nil {
+				return err
+			}
+
+			if ignored {
+				// the contents of an ignored folder can only be included by an exception
+				if info.IsDir() && !ignore.Exclusions() {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		fmt.Fprintf(sum, "%s\n", filepath.ToSlash(rel))
+		_, err = io.Copy(sum, f)
+		return err
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("unable to read build context %s: %s", b.Context, err)
+	}
+
+	fmt.Fprintf(sum, "file=%s\n", b.File)
+
+	keys := []string{}
+	for k := range b.Args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(sum, "arg=%s=%s\n", k, b.Args[k])
+	}
+
+	return fmt.Sprintf("%x", sum.Sum(nil)), nil
+}
+
+// ignorePatterns returns the patterns in the .dockerignore file in the build
+// context, or nil when the context does not contain a .dockerignore
+func (b *Build) ignorePatterns() (*fileutils.PatternMatcher, error) {
+	f, err := os.Open(filepath.Join(b.Context, ".dockerignore"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	patterns, err := dockerignore.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return fileutils.NewPatternMatcher(patterns)
 }
 
 // Validate the config
 func (c *Container) Validate() error {
+	for _, n := range c.Networks {
+		if n.MacAddress == "" {
+			continue
+		}
+
+		if _, err := net.ParseMAC(n.MacAddress); err != nil {
+			return fmt.Errorf("invalid mac_address %s for network %s: %s", n.MacAddress, n.Name, err)
+		}
+	}
+
+	if c.Image != nil {
+		err := c.Image.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, d := range c.DNS {
+		if net.ParseIP(d) == nil {
+			return fmt.Errorf("invalid dns server %s, dns must be an IP address", d)
+		}
+	}
+
+	err := c.validateLogDriver()
+	if err != nil {
+		return err
+	}
+
+	err = c.validateRestart()
+	if err != nil {
+		return err
+	}
+
+	err = validateStopTimeout(c.StopTimeout)
+	if err != nil {
+		return err
+	}
+
+	err = c.validateInit()
+	if err != nil {
+		return err
+	}
+
+	err = validateVolumes(c.Volumes)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range c.PortRanges {
+		err := p.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.NetworkMode == "" {
+		return nil
+	}
+
+	switch {
+	case c.NetworkMode == "host", c.NetworkMode == "none":
+	case strings.HasPrefix(c.NetworkMode, "container:") && len(c.NetworkMode) > len("container:"):
+	default:
+		return fmt.Errorf("invalid network_mode %s, valid options are host, none, or container:<id>", c.NetworkMode)
+	}
+
+	// network blocks can not be combined with a network mode as the
+	// container does not use the bridge networks
+	if len(c.Networks) > 0 {
+		return fmt.Errorf("network_mode %s can not be used with network blocks", c.NetworkMode)
+	}
+
+	// containers sharing another containers network namespace can not publish ports
+	if strings.HasPrefix(c.NetworkMode, "container:") && (len(c.Ports) > 0 || len(c.PortRanges) > 0) {
+		return fmt.Errorf("network_mode %s can not be used with port or port_range blocks", c.NetworkMode)
+	}
+
+	return nil
+}
+
+// validateStopTimeout checks the stop timeout is a valid duration
+func validateStopTimeout(st string) error {
+	if st == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(st)
+	if err != nil {
+		return fmt.Errorf("invalid stop_timeout %s: %s", st, err)
+	}
+
+	if d < 0 {
+		return fmt.Errorf("invalid stop_timeout %s, stop_timeout can not be negative", st)
+	}
+
+	return nil
+}
+
+// validateInit checks that init containers do not set options which
+// require the container to keep running
+func (c *Container) validateInit() error {
+	if !c.Init {
+		return nil
+	}
+
+	if c.RestartsOnExit() {
+		return fmt.Errorf("init containers can not be restarted, remove restart and max_restart_count")
+	}
+
+	if c.HealthCheck != nil {
+		return fmt.Errorf("init containers can not have a health_check")
+	}
+
+	if c.Startup != nil {
+		return fmt.Errorf("init containers can not have a startup block")
+	}
+
+	return nil
+}
+
+// restartPolicies are the Docker restart policies which can be set for a container
+var restartPolicies = []string{"no", "on-failure", "always", "unless-stopped"}
+
+// validateRestart checks the restart policy is supported by Docker, the maximum
+// restart count can only be used with the on-failure policy
+func (c *Container) validateRestart() error {
+	if c.Restart == "" {
+		return nil
+	}
+
+	valid := false
+	for _, p := range restartPolicies {
+		if c.Restart == p {
+			valid = true
+		}
+	}
+
+	if !valid {
+		return fmt.Errorf("invalid restart policy %s, valid options are %s", c.Restart, strings.Join(restartPolicies, ", "))
+	}
+
+	if c.MaxRestartCount > 0 && c.Restart != "on-failure" {
+		return fmt.Errorf("max_restart_count can only be used with the on-failure restart policy")
+	}
+
+	return nil
+}
+
+// RestartsOnExit returns true when Docker restarts the container after it exits
+func (c *Container) RestartsOnExit() bool {
+	if c.Restart == "" {
+		return c.MaxRestartCount > 0
+	}
+
+	return c.Restart != "no"
+}
+
+// logDrivers are the logging drivers supported by Docker and the options
+// each driver requires
+var logDrivers = map[string][]string{
+	"json-file":  nil,
+	"local":      nil,
+	"journald":   nil,
+	"syslog":     nil,
+	"fluentd":    nil,
+	"gelf":       []string{"gelf-address"},
+	"awslogs":    []string{"awslogs-group"},
+	"splunk":     []string{"splunk-token", "splunk-url"},
+	"etwlogs":    nil,
+	"gcplogs":    nil,
+	"logentries": []string{"logentries-token"},
+	"none":       nil,
+}
+
+func (c *Container) validateLogDriver() error {
+	if c.LogDriver == "" {
+		if len(c.LogOpts) > 0 {
+			return fmt.Errorf("log_opts can only be used when log_driver is set")
+		}
+
+		return nil
+	}
+
+	required, ok := logDrivers[c.LogDriver]
+	if !ok {
+		return fmt.Errorf("invalid log_driver %s, valid options are json-file, local, journald, syslog, fluentd, gelf, awslogs, splunk, etwlogs, gcplogs, logentries, or none", c.LogDriver)
+	}
+
+	for _, o := range required {
+		if c.LogOpts[o] == "" {
+			return fmt.Errorf("log_driver %s requires the log_opts value %s", c.LogDriver, o)
+		}
+	}
+
+	return nil
+}
+
+// validateIPAddresses checks that any static ip addresses for the container
+// are valid and within the subnet of the network they are assigned on
+func (c *Container) validateIPAddresses(cfg *Config) error {
+	for _, n := range c.Networks {
+		if n.IPAddress == "" {
+			continue
+		}
+
+		ip := net.ParseIP(n.IPAddress)
+		if ip == nil {
+			return fmt.Errorf("ip_address %s for network %s is not a valid IP address", n.IPAddress, n.Name)
+		}
+
+		r, err := cfg.FindResource(n.Name)
+		if err != nil {
+			// missing networks are reported when the dependency graph is built
+			continue
+		}
+
+		nw, ok := r.(*Network)
+		if !ok || nw.Subnet == "" {
+			continue
+		}
+
+		_, subnet, err := net.ParseCIDR(nw.Subnet)
+		if err != nil {
+			continue
+		}
+
+		if !subnet.Contains(ip) {
+			return fmt.Errorf("ip_address %s is not within the subnet %s of network %s", n.IPAddress, nw.Subnet, n.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateUniqueMACAddresses checks that the MAC addresses for the container
+// are not used by any other container attached to the same network
+func (c *Container) validateUniqueMACAddresses(cfg *Config) error {
+	for _, n := range c.Networks {
+		if n.MacAddress == "" {
+			continue
+		}
+
+		for _, r := range cfg.FindResourcesByType(string(TypeContainer)) {
+			other := r.(*Container)
+			if other == c {
+				continue
+			}
+
+			for _, on := range other.Networks {
+				if on.Name == n.Name && strings.EqualFold(on.MacAddress, n.MacAddress) {
+					return fmt.Errorf("mac_address %s for network %s is already used by container %s", n.MacAddress, n.Name, other.Name)
+				}
+			}
+		}
+	}
+
 	return nil
 }
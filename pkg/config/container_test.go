@@ -1,6 +1,9 @@
 package config
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -36,6 +39,306 @@ func TestContainerSetsDisabled(t *testing.T) {
 	assert.Equal(t, Disabled, co.Info().Status)
 }
 
+func TestContainerSetsNetworkMode(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerNetworkMode)
+	defer cleanup()
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "host", co.(*Container).NetworkMode)
+}
+
+func TestContainerWithNetworkModeAndNetworkReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t, containerNetworkModeInvalid)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.Error(t, err)
+}
+
+func TestContainerValidateReturnsErrorForUnknownNetworkMode(t *testing.T) {
+	c := NewContainer("abc")
+	c.NetworkMode = "bridge"
+
+	assert.Error(t, c.Validate())
+}
+
+func TestContainerValidateReturnsErrorForContainerNetworkModeWithPorts(t *testing.T) {
+	c := NewContainer("abc")
+	c.NetworkMode = "container:abc"
+	c.Ports = []Port{Port{Local: "80"}}
+
+	assert.Error(t, c.Validate())
+}
+
+func TestContainerSetsMacAddress(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerMacAddress)
+	defer cleanup()
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "02:42:ac:11:00:02", co.(*Container).Networks[0].MacAddress)
+}
+
+func TestContainerValidateReturnsErrorForInvalidMacAddress(t *testing.T) {
+	c := NewContainer("abc")
+	c.Networks = []NetworkAttachment{NetworkAttachment{Name: "network.test", MacAddress: "02:42:ac"}}
+
+	assert.Error(t, c.Validate())
+}
+
+func TestContainerWithDuplicateMacAddressReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t, containerMacAddressDuplicate)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.Error(t, err)
+}
+
+func TestContainerWithIPAddressInSubnetIsValid(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerIPAddress)
+	defer cleanup()
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.200", co.(*Container).Networks[0].IPAddress)
+}
+
+func TestContainerWithIPAddressOutsideSubnetReturnsError(t *testing.T) {
+	// the network is defined after the container to check the order does not matter
+	dir, cleanup := createTestFiles(t, containerIPAddressOutsideSubnet)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.NoError(t, err)
+
+	err = ParseReferences(c)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ip_address 10.1.0.200 is not within the subnet 10.0.0.0/24 of network network.test")
+}
+
+func TestContainerWithInvalidIPAddressReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t, containerIPAddressInvalid)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.NoError(t, err)
+
+	err = ParseReferences(c)
+	assert.Error(t, err)
+}
+
+func TestVolumeValidatesPropagationAndSELinuxLabel(t *testing.T) {
+	assert.NoError(t, Volume{Source: "/tmp", Destination: "/data", Propagation: "rshared", SELinuxLabel: "z"}.Validate())
+
+	err := Volume{Source: "/tmp", Destination: "/data", Propagation: "sideways"}.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid propagation sideways")
+
+	err = Volume{Source: "/tmp", Destination: "/data", SELinuxLabel: "x"}.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid selinux_label x")
+
+	err = Volume{Source: "data", Destination: "/data", Type: "volume", SELinuxLabel: "z"}.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "selinux_label can only be set for bind volumes")
+
+	err = Volume{Source: "data", Destination: "/data", Type: "volume", Propagation: "shared"}.Validate()
+	assert.Error(t, err)
+}
+
+func TestContainerWithInvalidVolumeReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t, containerVolumeInvalidLabel)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid volume /data")
+}
+
+func TestPortRangeValidatesHostRangeSize(t *testing.T) {
+	assert.NoError(t, PortRange{Range: "8000-8002"}.Validate())
+	assert.NoError(t, PortRange{Range: "8000-8002", HostRange: "18000-18002"}.Validate())
+
+	err := PortRange{Range: "8000-8002", HostRange: "18000-18005"}.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "must be the same size")
+
+	assert.Error(t, PortRange{Range: "8002-8000"}.Validate())
+	assert.Error(t, PortRange{Range: "8000"}.Validate())
+}
+
+func TestContainerWithMismatchedPortRangeReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t, containerPortRangeMismatch)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "host_range 18000-18001 must be the same size as range 8000-8002")
+}
+
+func TestContainerSetsDNS(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerDNS)
+	defer cleanup()
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"10.5.0.2"}, co.(*Container).DNS)
+}
+
+func TestContainerValidateReturnsErrorForInvalidDNS(t *testing.T) {
+	c := NewContainer("abc")
+	c.DNS = []string{"dns.local"}
+
+	assert.Error(t, c.Validate())
+}
+
+func TestContainerSetsLogDriver(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerLogDriver)
+	defer cleanup()
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "journald", co.(*Container).LogDriver)
+	assert.Equal(t, "consul", co.(*Container).LogOpts["tag"])
+}
+
+func TestContainerValidateReturnsErrorForUnknownLogDriver(t *testing.T) {
+	c := NewContainer("abc")
+	c.LogDriver = "printer"
+
+	assert.Error(t, c.Validate())
+}
+
+func TestContainerValidateReturnsErrorForUnknownRestartPolicy(t *testing.T) {
+	c := NewContainer("abc")
+	c.Restart = "sometimes"
+
+	assert.Error(t, c.Validate())
+}
+
+func TestContainerValidateReturnsErrorForMaxRestartCountWithoutOnFailure(t *testing.T) {
+	c := NewContainer("abc")
+	c.Restart = "always"
+	c.MaxRestartCount = 3
+
+	assert.Error(t, c.Validate())
+}
+
+func TestContainerValidateReturnsErrorForInvalidStopTimeout(t *testing.T) {
+	c := NewContainer("abc")
+	c.StopTimeout = "soon"
+
+	assert.Error(t, c.Validate())
+}
+
+func TestSidecarValidateReturnsErrorForInvalidStopTimeout(t *testing.T) {
+	s := NewSidecar("abc")
+	s.Image = Image{Name: "envoy"}
+	s.StopTimeout = "-5s"
+
+	assert.Error(t, s.Validate())
+}
+
+func TestContainerValidateReturnsErrorForInitWithRestart(t *testing.T) {
+	c := NewContainer("abc")
+	c.Init = true
+	c.Restart = "always"
+
+	assert.Error(t, c.Validate())
+}
+
+func TestContainerValidateReturnsErrorForInitWithHealthCheck(t *testing.T) {
+	c := NewContainer("abc")
+	c.Init = true
+	c.HealthCheck = &HealthCheck{Timeout: "30s"}
+
+	assert.Error(t, c.Validate())
+}
+
+func TestContainerRestartsOnExit(t *testing.T) {
+	c := NewContainer("abc")
+	assert.False(t, c.RestartsOnExit())
+
+	c.MaxRestartCount = 3
+	assert.True(t, c.RestartsOnExit())
+
+	c.MaxRestartCount = 0
+	c.Restart = "unless-stopped"
+	assert.True(t, c.RestartsOnExit())
+
+	c.Restart = "no"
+	assert.False(t, c.RestartsOnExit())
+}
+
+func TestContainerValidateReturnsErrorForMissingLogOption(t *testing.T) {
+	c := NewContainer("abc")
+	c.LogDriver = "splunk"
+	c.LogOpts = map[string]string{"splunk-token": "abc"}
+
+	assert.Error(t, c.Validate())
+}
+
+func TestContainerValidateReturnsErrorForLogOptsWithoutDriver(t *testing.T) {
+	c := NewContainer("abc")
+	c.LogOpts = map[string]string{"tag": "abc"}
+
+	assert.Error(t, c.Validate())
+}
+
+func TestContainerSetsStartup(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerStartup)
+	defer cleanup()
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "30s", co.(*Container).Startup.GracePeriod)
+	assert.Equal(t, 1, co.(*Container).Startup.MaxRestarts)
+}
+
+func TestContainerSetsRetry(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerRetry)
+	defer cleanup()
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, co.Info().Retry.Attempts)
+	assert.Equal(t, "5s", co.Info().Retry.Backoff)
+	assert.Equal(t, "30s", co.Info().Retry.MaxWait)
+}
+
+func TestContainerSetsTimeout(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerTimeout)
+	defer cleanup()
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "30s", co.Info().Timeout)
+}
+
+func TestContainerSetsPriority(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerPriority)
+	defer cleanup()
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 10, co.Info().Priority)
+}
+
 const containerDefault = `
 network "test" {
 	subnet = "10.0.0.0/24"
@@ -67,3 +370,394 @@ container "testing" {
 	}
 }
 `
+
+const containerNetworkMode = `
+container "testing" {
+	network_mode = "host"
+
+	image {
+		name = "consul"
+	}
+}
+`
+
+const containerNetworkModeInvalid = `
+network "test" {
+	subnet = "10.0.0.0/24"
+}
+
+container "testing" {
+	network_mode = "host"
+
+	network {
+		name = "network.test"
+	}
+	image {
+		name = "consul"
+	}
+}
+`
+
+const containerMacAddress = `
+network "test" {
+	subnet = "10.0.0.0/24"
+}
+
+container "testing" {
+	network {
+		name        = "network.test"
+		mac_address = "02:42:ac:11:00:02"
+	}
+	image {
+		name = "consul"
+	}
+}
+`
+
+const containerMacAddressDuplicate = `
+network "test" {
+	subnet = "10.0.0.0/24"
+}
+
+container "testing" {
+	network {
+		name        = "network.test"
+		mac_address = "02:42:ac:11:00:02"
+	}
+	image {
+		name = "consul"
+	}
+}
+
+container "testing2" {
+	network {
+		name        = "network.test"
+		mac_address = "02:42:AC:11:00:02"
+	}
+	image {
+		name = "consul"
+	}
+}
+`
+
+const containerStartup = `
+container "testing" {
+	max_restart_count = 3
+
+	startup {
+		grace_period = "30s"
+		max_restarts = 1
+	}
+
+	image {
+		name = "consul"
+	}
+}
+`
+
+const containerRetry = `
+container "testing" {
+	retry {
+		attempts = 3
+		backoff  = "5s"
+		max_wait = "30s"
+	}
+
+	image {
+		name = "consul"
+	}
+}
+`
+
+const containerTimeout = `
+container "testing" {
+	timeout = "30s"
+
+	image {
+		name = "consul"
+	}
+}
+`
+
+const containerLogDriver = `
+container "testing" {
+	log_driver = "journald"
+	log_opts = {
+		tag = "consul"
+	}
+
+	image {
+		name = "consul"
+	}
+}
+`
+
+const containerDNS = `
+container "testing" {
+	dns = ["10.5.0.2"]
+
+	image {
+		name = "consul"
+	}
+}
+`
+
+func TestContainerParsesHealthCheck(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerHealthCheck)
+	defer cleanup()
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+
+	hc := co.(*Container).HealthCheck
+	assert.Equal(t, "30s", hc.Timeout)
+	assert.Equal(t, "2s", hc.Interval)
+	assert.Equal(t, "localhost:8500", hc.TCP)
+	assert.Equal(t, []string{"consul", "members"}, hc.Exec)
+}
+
+const containerHealthCheck = `
+container "consul" {
+  image {
+    name = "consul"
+  }
+
+  health_check {
+    timeout  = "30s"
+    interval = "2s"
+    tcp      = "localhost:8500"
+    exec     = ["consul", "members"]
+  }
+}
+`
+
+func TestContainerWithInvalidPlatformReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t, containerInvalidPlatform)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid platform linux/amd46")
+}
+
+func TestImageValidateAllowsKnownPlatform(t *testing.T) {
+	i := &Image{Name: "consul", Platform: "linux/arm64"}
+	assert.NoError(t, i.Validate())
+}
+
+const containerInvalidPlatform = `
+container "consul" {
+  image {
+    name     = "consul"
+    platform = "linux/amd46"
+  }
+}
+`
+
+const containerPriority = `
+container "testing" {
+	priority = 10
+
+	image {
+		name = "consul"
+	}
+}
+`
+
+func TestContainerBuildSetsArgsFromVariables(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerBuildArgs)
+	defer cleanup()
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"VERSION": "1.2.3"}, co.(*Container).Build.Args)
+}
+
+func TestBuildChecksumChangesWhenContextChanges(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	f := createNamedFile(t, dir, "Dockerfile*", "FROM alpine")
+	b := &Build{Context: dir}
+
+	first, err := b.Checksum()
+	assert.NoError(t, err)
+
+	same, err := b.Checksum()
+	assert.NoError(t, err)
+	assert.Equal(t, first, same)
+
+	err = ioutil.WriteFile(f, []byte("FROM ubuntu"), 0644)
+	assert.NoError(t, err)
+
+	changed, err := b.Checksum()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, changed)
+}
+
+func TestBuildChecksumChangesWhenArgsChange(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	b := &Build{Context: dir, Args: map[string]string{"VERSION": "1"}}
+	first, err := b.Checksum()
+	assert.NoError(t, err)
+
+	b.Args["VERSION"] = "2"
+	changed, err := b.Checksum()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, changed)
+}
+
+func TestBuildChecksumIgnoresFilesInDockerignore(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	err := ioutil.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("*.log\ntmp\n"), 0644)
+	assert.NoError(t, err)
+
+	err = os.MkdirAll(filepath.Join(dir, "tmp"), os.ModePerm)
+	assert.NoError(t, err)
+
+	b := &Build{Context: dir}
+	first, err := b.Checksum()
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "build.log"), []byte("log"), 0644)
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "tmp", "cache"), []byte("cache"), 0644)
+	assert.NoError(t, err)
+
+	ignored, err := b.Checksum()
+	assert.NoError(t, err)
+	assert.Equal(t, first, ignored)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644)
+	assert.NoError(t, err)
+
+	changed, err := b.Checksum()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, changed)
+}
+
+func TestBuildChecksumIncludesDockerignoreExceptions(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	err := ioutil.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("tmp\n!tmp/keep\n"), 0644)
+	assert.NoError(t, err)
+
+	err = os.MkdirAll(filepath.Join(dir, "tmp"), os.ModePerm)
+	assert.NoError(t, err)
+
+	b := &Build{Context: dir}
+	first, err := b.Checksum()
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "tmp", "keep"), []byte("keep"), 0644)
+	assert.NoError(t, err)
+
+	changed, err := b.Checksum()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, changed)
+}
+
+func TestBuildChecksumErrorsWhenContextMissing(t *testing.T) {
+	b := &Build{Context: "/not/a/real/folder"}
+
+	_, err := b.Checksum()
+	assert.Error(t, err)
+}
+
+const containerBuildArgs = `
+variable "version" {
+	default = "1.2.3"
+}
+
+container "testing" {
+	build {
+		context = "./"
+		args = {
+			VERSION = var.version
+		}
+	}
+}
+`
+
+const containerIPAddress = `
+network "test" {
+	subnet = "10.0.0.0/24"
+}
+
+container "testing" {
+	network {
+		name       = "network.test"
+		ip_address = "10.0.0.200"
+	}
+	image {
+		name = "consul"
+	}
+}
+`
+
+const containerIPAddressOutsideSubnet = `
+container "testing" {
+	network {
+		name       = "network.test"
+		ip_address = "10.1.0.200"
+	}
+	image {
+		name = "consul"
+	}
+}
+
+network "test" {
+	subnet = "10.0.0.0/24"
+}
+`
+
+const containerIPAddressInvalid = `
+network "test" {
+	subnet = "10.0.0.0/24"
+}
+
+container "testing" {
+	network {
+		name       = "network.test"
+		ip_address = "10.0.0.300"
+	}
+	image {
+		name = "consul"
+	}
+}
+`
+
+const containerVolumeInvalidLabel = `
+container "testing" {
+	image {
+		name = "consul"
+	}
+
+	volume {
+		source        = "./data"
+		destination   = "/data"
+		selinux_label = "shared"
+	}
+}
+`
+
+const containerPortRangeMismatch = `
+container "testing" {
+	image {
+		name = "consul"
+	}
+
+	port_range {
+		range      = "8000-8002"
+		host_range = "18000-18001"
+	}
+}
+`
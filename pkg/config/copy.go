@@ -0,0 +1,23 @@
+package config
+
+// TypeCopy is the resource string for a Copy resource
+const TypeCopy ResourceType = "copy"
+
+// Copy copies a file from the local machine into a running container
+type Copy struct {
+	ResourceInfo `hcl:",remain" mapstructure:",squash"`
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Container is the container or sidecar to copy the file to e.g. container.consul
+	Container string `hcl:"container" json:"container"`
+	// Source is the path of the file on the local machine
+	Source string `hcl:"source" json:"source"`
+	// Destination is the folder in the container the file is copied to, the folder must exist
+	Destination string `hcl:"destination" json:"destination"`
+}
+
+// NewCopy creates a Copy resource with the default values
+func NewCopy(name string) *Copy {
+	return &Copy{ResourceInfo: ResourceInfo{Name: name, Type: TypeCopy, Status: PendingCreation}}
+}
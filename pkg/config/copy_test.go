@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCreatesCopy(t *testing.T) {
+	c := NewCopy("abc")
+
+	assert.Equal(t, "abc", c.Name)
+	assert.Equal(t, TypeCopy, c.Type)
+}
+
+func TestCopyCreatesCorrectly(t *testing.T) {
+	c, base, cleanup := setupTestConfig(t, copyDefault)
+	defer cleanup()
+
+	cl, err := c.FindResource("copy.test")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "test", cl.Info().Name)
+	assert.Equal(t, TypeCopy, cl.Info().Type)
+	assert.Equal(t, PendingCreation, cl.Info().Status)
+
+	// source is made absolute relative to the file
+	assert.Contains(t, cl.(*Copy).Source, base)
+	assert.Equal(t, "/config", cl.(*Copy).Destination)
+	assert.Contains(t, cl.Info().DependsOn, "container.consul")
+}
+
+func TestCopySetsDisabled(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, copyDisabled)
+	defer cleanup()
+
+	cl, err := c.FindResource("copy.test")
+	assert.NoError(t, err)
+
+	assert.Equal(t, Disabled, cl.Info().Status)
+}
+
+const copyDefault = `
+container "consul" {
+	image {
+		name = "consul"
+	}
+}
+
+copy "test" {
+	container   = "container.consul"
+	source      = "./consul.hcl"
+	destination = "/config"
+}
+`
+
+const copyDisabled = `
+container "consul" {
+	image {
+		name = "consul"
+	}
+}
+
+copy "test" {
+	disabled    = true
+	container   = "container.consul"
+	source      = "./consul.hcl"
+	destination = "/config"
+}
+`
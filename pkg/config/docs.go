@@ -3,6 +3,9 @@ package config
 // TypeDocs is the resource string for a Docs resource
 const TypeDocs ResourceType = "docs"
 
+// DefaultLiveReloadPort is the host port used for live reload when LiveReloadPort is not set
+const DefaultLiveReloadPort = 37950
+
 // Docs allows the running of a Docusaurus container which can be used for
 // online tutorials or documentation
 type Docs struct {
@@ -1,5 +1,19 @@
 package config
 
+import "fmt"
+
+// ExecWhenCreate, ExecWhenDestroy, and ExecWhenBoth control when the command of an
+// exec resource is run
+const (
+	ExecWhenCreate  = "create"
+	ExecWhenDestroy = "destroy"
+	ExecWhenBoth    = "both"
+)
+
+// DefaultExecOutputLimit is the maximum number of bytes of command output stored
+// for an exec resource when output_limit is not set
+const DefaultExecOutputLimit = 4096
+
 // TypeExecLocal is the resource string for a LocalExec resource
 const TypeExecLocal ResourceType = "exec_local"
 
@@ -10,6 +24,10 @@ type ExecLocal struct {
 	// Id stores the ID of the created connector service
 	Pid int `json:"pid,omitempty" state:"true"`
 
+	// Output stores the output of the command, truncated to OutputLimit bytes, it can be
+	// referenced by other resources e.g. resource.exec_local.name.output
+	Output string `json:"output,omitempty" state:"true" sensitive:"true"`
+
 	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
 
 	Command          string   `hcl:"cmd,optional" json:"cmd,omitempty" mapstructure:"cmd"`                                           // Command to execute
@@ -18,11 +36,50 @@ type ExecLocal struct {
 	Daemon           bool     `hcl:"daemon,optional" json:"daemon,omitempty"`                                                        // Should the process run as a daemon
 	Timeout          string   `hcl:"timeout,optional" json:"timeout,omitempty"`                                                      // Set the timeout for the command
 
-	Environment []KV              `hcl:"env,block" json:"env" mapstructure:"env"`                          // environment variables to set
-	EnvVar      map[string]string `hcl:"env_var,optional" json:"env_var,omitempty" mapstructure:"env_var"` // environment variables to set
+	// OutputLimit is the maximum number of bytes of output which are stored, defaults to 4096
+	OutputLimit int `hcl:"output_limit,optional" json:"output_limit,omitempty" mapstructure:"output_limit"`
+
+	// When sets if the command runs when the resource is created, destroyed, or both, defaults to create
+	When string `hcl:"when,optional" json:"when,omitempty"`
+
+	Environment []KV              `hcl:"env,block" json:"env" mapstructure:"env"`                                           // environment variables to set
+	EnvVar      map[string]string `hcl:"env_var,optional" json:"env_var,omitempty" mapstructure:"env_var" sensitive:"true"` // environment variables to set
 }
 
 // NewExecLocal creates a LocalExec resource with the default values
 func NewExecLocal(name string) *ExecLocal {
 	return &ExecLocal{ResourceInfo: ResourceInfo{Name: name, Type: TypeExecLocal, Status: PendingCreation}}
 }
+
+// Validate the config
+func (e *ExecLocal) Validate() error {
+	err := validateExecWhen(e.When)
+	if err != nil {
+		return err
+	}
+
+	if e.Daemon && e.When != "" && e.When != ExecWhenCreate {
+		return fmt.Errorf("daemon can only be used when the command is run on create")
+	}
+
+	return nil
+}
+
+// RunsOnCreate returns true when the command should be run when the resource is created
+func (e *ExecLocal) RunsOnCreate() bool {
+	return e.When == "" || e.When == ExecWhenCreate || e.When == ExecWhenBoth
+}
+
+// RunsOnDestroy returns true when the command should be run when the resource is destroyed
+func (e *ExecLocal) RunsOnDestroy() bool {
+	return e.When == ExecWhenDestroy || e.When == ExecWhenBoth
+}
+
+func validateExecWhen(w string) error {
+	switch w {
+	case "", ExecWhenCreate, ExecWhenDestroy, ExecWhenBoth:
+		return nil
+	}
+
+	return fmt.Errorf("invalid when %s, must be one of [%s, %s, %s]", w, ExecWhenCreate, ExecWhenDestroy, ExecWhenBoth)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,7 +15,7 @@ func TestNewCreatesExecLocal(t *testing.T) {
 }
 
 func TestExecLocalCreatesCorrectly(t *testing.T) {
-	c, _, cleanup := setupTestConfig(t, execLocalRelative)
+	c, dir, cleanup := setupTestConfig(t, execLocalRelative)
 	defer cleanup()
 
 	ex, err := c.FindResource("exec_local.setup_vault")
@@ -23,7 +24,7 @@ func TestExecLocalCreatesCorrectly(t *testing.T) {
 	assert.Equal(t, "setup_vault", ex.Info().Name)
 	assert.Equal(t, TypeExecLocal, ex.Info().Type)
 	assert.Equal(t, PendingCreation, ex.Info().Status)
-	assert.Equal(t, "./", ex.(*ExecLocal).WorkingDirectory)
+	assert.Equal(t, dir, ex.(*ExecLocal).WorkingDirectory)
 	assert.True(t, ex.(*ExecLocal).Daemon)
 }
 
@@ -37,6 +38,16 @@ func TestExecLocalSetsDisabled(t *testing.T) {
 	assert.Equal(t, Disabled, ex.Info().Status)
 }
 
+func TestExecLocalSetsResourceTimeout(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, execLocalTimeout)
+	defer cleanup()
+
+	ex, err := c.FindResource("exec_local.setup_vault")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "60s", ex.(*ExecLocal).Timeout)
+}
+
 var execLocalRelative = `
 exec_local "setup_vault" {
   cmd = "./scripts/setup_vault.sh"
@@ -55,3 +66,66 @@ exec_local "setup_vault" {
   daemon = true
 }
 `
+
+var execLocalTimeout = `
+exec_local "setup_vault" {
+  cmd = "./scripts/setup_vault.sh"
+  timeout = "60s"
+}
+`
+
+func TestExecLocalWithInvalidWhenReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t, execLocalInvalidWhen)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid when sometimes")
+}
+
+func TestExecLocalValidateRejectsDaemonOnDestroy(t *testing.T) {
+	e := NewExecLocal("test")
+	e.Daemon = true
+	e.When = ExecWhenDestroy
+
+	assert.Error(t, e.Validate())
+}
+
+func TestExecLocalRunsOnCreateAndDestroy(t *testing.T) {
+	e := NewExecLocal("test")
+	assert.True(t, e.RunsOnCreate())
+	assert.False(t, e.RunsOnDestroy())
+
+	e.When = ExecWhenDestroy
+	assert.False(t, e.RunsOnCreate())
+	assert.True(t, e.RunsOnDestroy())
+
+	e.When = ExecWhenBoth
+	assert.True(t, e.RunsOnCreate())
+	assert.True(t, e.RunsOnDestroy())
+}
+
+const execLocalInvalidWhen = `
+exec_local "setup_vault" {
+  cmd = "./scripts/setup_vault.sh"
+  when = "sometimes"
+}
+`
+
+func TestExecLocalMakesWorkingDirectoryAbsolute(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, execLocalWorkingDirectory)
+	defer cleanup()
+
+	ex, err := c.FindResource("exec_local.setup_vault")
+	assert.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(dir, "scripts"), ex.(*ExecLocal).WorkingDirectory)
+}
+
+const execLocalWorkingDirectory = `
+exec_local "setup_vault" {
+  cmd = "./setup_vault.sh"
+  working_directory = "./scripts"
+}
+`
@@ -9,6 +9,16 @@ type ExecRemote struct {
 
 	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
 
+	// Output stores the output of the command, truncated to OutputLimit bytes, it can be
+	// referenced by other resources e.g. resource.exec_remote.name.output
+	Output string `json:"output,omitempty" state:"true" sensitive:"true"`
+
+	// OutputLimit is the maximum number of bytes of output which are stored, defaults to 4096
+	OutputLimit int `hcl:"output_limit,optional" json:"output_limit,omitempty" mapstructure:"output_limit"`
+
+	// When sets if the command runs when the resource is created, destroyed, or both, defaults to create
+	When string `hcl:"when,optional" json:"when,omitempty"`
+
 	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // Attach to the correct network // only when Image is specified
 
 	// Either Image or Target must be specified
@@ -21,9 +31,9 @@ type ExecRemote struct {
 	Arguments        []string `hcl:"args,optional" json:"args,omitempty" mapstructure:"args"`                                        // only used when combined with Command
 	WorkingDirectory string   `hcl:"working_directory,optional" json:"working_directory,omitempty" mapstructure:"working_directory"` // Working directory to execute commands
 
-	Volumes     []Volume          `hcl:"volume,block" json:"volumes,omitempty"`                            // Volumes to mount to container
-	Environment []KV              `hcl:"env,block" json:"env,omitempty" mapstructure:"env"`                // Environment varialbes to set
-	EnvVar      map[string]string `hcl:"env_var,optional" json:"env_var,omitempty" mapstructure:"env_var"` // environment variables to set when starting the container
+	Volumes     []Volume          `hcl:"volume,block" json:"volumes,omitempty"`                                             // Volumes to mount to container
+	Environment []KV              `hcl:"env,block" json:"env,omitempty" mapstructure:"env"`                                 // Environment varialbes to set
+	EnvVar      map[string]string `hcl:"env_var,optional" json:"env_var,omitempty" mapstructure:"env_var" sensitive:"true"` // environment variables to set when starting the container
 
 	// User block for mapping the user id and group id inside the container
 	RunAs *User `hcl:"run_as,block" json:"run_as,omitempty" mapstructure:"run_as"`
@@ -33,3 +43,23 @@ type ExecRemote struct {
 func NewExecRemote(name string) *ExecRemote {
 	return &ExecRemote{ResourceInfo: ResourceInfo{Name: name, Type: TypeExecRemote, Status: PendingCreation}}
 }
+
+// Validate the config
+func (e *ExecRemote) Validate() error {
+	err := validateVolumes(e.Volumes)
+	if err != nil {
+		return err
+	}
+
+	return validateExecWhen(e.When)
+}
+
+// RunsOnCreate returns true when the command should be run when the resource is created
+func (e *ExecRemote) RunsOnCreate() bool {
+	return e.When == "" || e.When == ExecWhenCreate || e.When == ExecWhenBoth
+}
+
+// RunsOnDestroy returns true when the command should be run when the resource is destroyed
+func (e *ExecRemote) RunsOnDestroy() bool {
+	return e.When == ExecWhenDestroy || e.When == ExecWhenBoth
+}
@@ -0,0 +1,23 @@
+package config
+
+// TypeExternal is the resource string for an External resource
+const TypeExternal ResourceType = "external"
+
+// External defines a service which is not managed by Shipyard such as a
+// cloud database or SaaS endpoint. External resources are never created or
+// destroyed, creation blocks until the health check passes allowing other
+// resources to depend on the service being available.
+type External struct {
+	// embedded type holding name, etc
+	ResourceInfo `hcl:",remain" mapstructure:",squash"`
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// HealthCheck defines the readiness check for the service, only http and tcp checks are supported
+	HealthCheck *HealthCheck `hcl:"health_check,block" json:"health_check,omitempty" mapstructure:"health_check"`
+}
+
+// NewExternal returns a new External resource with the correct default options
+func NewExternal(name string) *External {
+	return &External{ResourceInfo: ResourceInfo{Name: name, Type: TypeExternal, Status: PendingCreation}}
+}
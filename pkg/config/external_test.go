@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCreatesExternal(t *testing.T) {
+	c := NewExternal("abc")
+
+	assert.Equal(t, "abc", c.Name)
+	assert.Equal(t, TypeExternal, c.Type)
+}
+
+func TestExternalCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, externalDefault)
+	defer cleanup()
+
+	e, err := c.FindResource("external.api")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "api", e.Info().Name)
+	assert.Equal(t, TypeExternal, e.Info().Type)
+	assert.Equal(t, PendingCreation, e.Info().Status)
+
+	assert.Equal(t, "https://staging.example.com/health", e.(*External).HealthCheck.HTTP)
+	assert.Equal(t, "staging.example.com:5432", e.(*External).HealthCheck.TCP)
+}
+
+func TestExternalCanBeADependency(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, externalDefault)
+	defer cleanup()
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+
+	assert.Contains(t, co.Info().DependsOn, "external.api")
+}
+
+const externalDefault = `
+external "api" {
+	health_check {
+		timeout = "60s"
+		http    = "https://staging.example.com/health"
+		tcp     = "staging.example.com:5432"
+	}
+}
+
+container "testing" {
+	depends_on = ["external.api"]
+
+	image {
+		name = "consul"
+	}
+}
+`
@@ -1,5 +1,7 @@
 package config
 
+import "fmt"
+
 // HealthCheck is an internal block for configuration which
 // allows the user to define the criteria for successful creation
 // example config:
@@ -9,12 +11,67 @@ package config
 //    services 		        = ["consul-consul"]                                              // does service exist and there are endpoints
 //    pods     		        = ["component=server,app=consul", "component=client,app=consul"] // is the pod running and healthy
 //    nomad_jobs          = ["redis"] 																										   // are the Nomad jobs running and healthy
+//    exec                = ["pg_isready"]                                                 // does the command exit with status 0 inside the container
+//    interval            = "2s"                                                           // time between checks
+//    condition {                                                                          // does the Kubernetes object report the condition
+//      kind      = "Deployment"
+//      name      = "consul"
+//      condition = "Available"
+//    }
 type HealthCheck struct {
 	Timeout          string   `hcl:"timeout" json:"timeout"`
+	Interval         string   `hcl:"interval,optional" json:"interval,omitempty"`
 	HTTP             string   `hcl:"http,optional" json:"http,omitempty"`
 	HTTPSuccessCodes []int    `hcl:"http_success_codes,optional" json:"http_success_codes,omitempty"`
 	TCP              string   `hcl:"tcp,optional" json:"tcp,omitempty"`
 	Services         []string `hcl:"services,optional" json:"services,omitempty"`
 	Pods             []string `hcl:"pods,optional" json:"pods,omitempty"`
 	NomadJobs        []string `hcl:"nomad_jobs,optional" json:"nomad_jobs,omitempty" mapstructure:"nomad_jobs"`
+	Exec             []string `hcl:"exec,optional" json:"exec,omitempty"`
+
+	Conditions []HealthCheckCondition `hcl:"condition,block" json:"conditions,omitempty"`
+}
+
+// HealthCheckCondition is a Kubernetes object status condition which must be True
+// for the resource to be healthy
+type HealthCheckCondition struct {
+	// APIVersion of the object e.g. apps/v1, can be omitted for core and apps kinds
+	APIVersion string `hcl:"api_version,optional" json:"api_version,omitempty" mapstructure:"api_version"`
+	// Kind of the object e.g. Deployment
+	Kind string `hcl:"kind" json:"kind"`
+	// Name of the object
+	Name string `hcl:"name" json:"name"`
+	// Namespace of the object, defaults to the namespace of the resource
+	Namespace string `hcl:"namespace,optional" json:"namespace,omitempty"`
+	// Condition is the status condition type which must be True e.g. Available, Complete
+	Condition string `hcl:"condition" json:"condition"`
+	// Timeout is the maximum duration to wait for the condition, defaults to the health check timeout
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"`
+}
+
+// defaultAPIVersions are the API versions used for built in kinds when no api_version is set
+var defaultAPIVersions = map[string]string{
+	"Pod":                   "v1",
+	"Service":               "v1",
+	"PersistentVolumeClaim": "v1",
+	"Node":                  "v1",
+	"Deployment":            "apps/v1",
+	"StatefulSet":           "apps/v1",
+	"DaemonSet":             "apps/v1",
+	"ReplicaSet":            "apps/v1",
+	"Job":                   "batch/v1",
+}
+
+// GroupVersion returns the API version of the object, when not set the default API version for
+// built in kinds is returned
+func (h HealthCheckCondition) GroupVersion() (string, error) {
+	if h.APIVersion != "" {
+		return h.APIVersion, nil
+	}
+
+	if v, ok := defaultAPIVersions[h.Kind]; ok {
+		return v, nil
+	}
+
+	return "", fmt.Errorf("api_version must be set for condition on %s %s", h.Kind, h.Name)
 }
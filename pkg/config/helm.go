@@ -1,5 +1,12 @@
 package config
 
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"sort"
+)
+
 // TypeHelm is the string representation of the ResourceType
 const TypeHelm ResourceType = "helm"
 
@@ -12,7 +19,14 @@ type Helm struct {
 	Cluster      string            `hcl:"cluster" json:"cluster"`
 	Chart        string            `hcl:"chart" json:"chart"`
 	Values       string            `hcl:"values,optional" json:"values"`
-	ValuesString map[string]string `hcl:"values_string,optional" json:"values_string" mapstructure:"values_string"`
+	ValuesString map[string]string `hcl:"values_string,optional" json:"values_string" mapstructure:"values_string" sensitive:"true"`
+
+	// ValuesFiles are merged in order after Values, values in later files override earlier ones.
+	// ValuesString is merged on top of all the files
+	ValuesFiles []string `hcl:"values_files,optional" json:"values_files,omitempty" mapstructure:"values_files"`
+
+	// ValuesChecksum is the checksum of the merged values when the chart was installed
+	ValuesChecksum string `json:"values_checksum,omitempty" mapstructure:"values_checksum" state:"true"`
 
 	// ChartName is the name of the chart, if not present
 	// uses the name of the resource block
@@ -25,9 +39,51 @@ type Helm struct {
 	CreateNamespace bool `hcl:"create_namespace,optional" json:"create_namespace,omitempty" mapstructure:"create_namespace"`
 
 	HealthCheck *HealthCheck `hcl:"health_check,block" json:"health_check,omitempty" mapstructure:"health_check"`
+
+	// Atomic uninstalls the chart when the install fails or does not complete within the
+	// timeout for the resource, when set Helm waits for the resources in the chart to be ready
+	Atomic bool `hcl:"atomic,optional" json:"atomic,omitempty"`
 }
 
 // NewHelm creates a new Helm resource with the correct detaults
 func NewHelm(name string) *Helm {
 	return &Helm{ResourceInfo: ResourceInfo{Name: name, Type: TypeHelm, Status: PendingCreation}}
 }
+
+// ValuesPaths returns the values files in the order they are merged
+func (h *Helm) ValuesPaths() []string {
+	paths := []string{}
+	if h.Values != "" {
+		paths = append(paths, h.Values)
+	}
+
+	return append(paths, h.ValuesFiles...)
+}
+
+// Checksum returns a checksum of the contents of the values files and the
+// string values, when any of these change the chart needs to be reinstalled
+func (h *Helm) Checksum() (string, error) {
+	sum := sha256.New()
+
+	for _, p := range h.ValuesPaths() {
+		d, err := ioutil.ReadFile(p)
+		if err != nil {
+			return "", fmt.Errorf("unable to read values file %s: %s", p, err)
+		}
+
+		fmt.Fprintf(sum, "%s\n", p)
+		sum.Write(d)
+	}
+
+	keys := []string{}
+	for k := range h.ValuesString {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(sum, "%s=%s\n", k, h.ValuesString[k])
+	}
+
+	return fmt.Sprintf("%x", sum.Sum(nil)), nil
+}
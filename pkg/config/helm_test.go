@@ -1,6 +1,8 @@
 package config
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -54,3 +56,65 @@ helm "testing" {
 	values = "test"
 }
 `
+
+func TestHelmMakesValuesFilesAbsolute(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, helmValuesFiles)
+	defer cleanup()
+
+	h, err := c.FindResource("helm.testing")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "base.yaml"),
+		filepath.Join(dir, "base.yaml"),
+		filepath.Join(dir, "dev.yaml"),
+	}, h.(*Helm).ValuesPaths())
+}
+
+func TestHelmChecksumChangesWhenValuesChange(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	f := createNamedFile(t, dir, "*.yaml", "replicas: 1")
+
+	h := NewHelm("test")
+	h.ValuesFiles = []string{f}
+	h.ValuesString = map[string]string{"image": "consul"}
+
+	first, err := h.Checksum()
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(f, []byte("replicas: 3"), 0644)
+	assert.NoError(t, err)
+
+	file, err := h.Checksum()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, file)
+
+	h.ValuesString["image"] = "vault"
+
+	str, err := h.Checksum()
+	assert.NoError(t, err)
+	assert.NotEqual(t, file, str)
+}
+
+func TestHelmChecksumErrorsWhenValuesFileMissing(t *testing.T) {
+	h := NewHelm("test")
+	h.ValuesFiles = []string{"/not/a/real/values.yaml"}
+
+	_, err := h.Checksum()
+	assert.Error(t, err)
+}
+
+const helmValuesFiles = `
+k8s_cluster "k3s" {
+	driver = "k3s"
+}
+
+helm "testing" {
+	cluster      = "k8s_cluster.k3s"
+	chart        = "./helm/chart"
+	values       = "./base.yaml"
+	values_files = ["./base.yaml", "./dev.yaml"]
+}
+`
@@ -1,5 +1,10 @@
 package config
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Image defines a docker image which will be pushed to the clusters Docker
 // registry
 type Image struct {
@@ -7,5 +12,36 @@ type Image struct {
 	// Username is the Docker registry user to use for private repositories
 	Username string `hcl:"username,optional" json:"username,omitempty"`
 	// Password is the Docker registry password to use for private repositories
-	Password string `hcl:"password,optional" json:"password,omitempty"`
+	Password string `hcl:"password,optional" json:"password,omitempty" sensitive:"true"`
+	// Platform is the os and architecture of the image to pull e.g. linux/arm64,
+	// when not set the platform of the host is used
+	Platform string `hcl:"platform,optional" json:"platform,omitempty"`
+}
+
+// imagePlatforms are the platforms which can be set for an image
+var imagePlatforms = []string{
+	"linux/amd64",
+	"linux/arm64",
+	"linux/arm64/v8",
+	"linux/arm/v7",
+	"linux/arm/v6",
+	"linux/386",
+	"linux/ppc64le",
+	"linux/s390x",
+	"windows/amd64",
+}
+
+// Validate the image config
+func (i *Image) Validate() error {
+	if i.Platform == "" {
+		return nil
+	}
+
+	for _, p := range imagePlatforms {
+		if i.Platform == p {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid platform %s for image %s, valid options are %s", i.Platform, i.Name, strings.Join(imagePlatforms, ", "))
 }
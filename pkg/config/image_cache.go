@@ -9,6 +9,12 @@ type ImageCache struct {
 	ResourceInfo `mapstructure:",squash"`
 
 	Networks []string `json:"networks" state:"true"` // Attach to the correct network // only when Image is specified
+
+	// Registries are the private registries the cache container was created with
+	Registries []Registry `json:"registries,omitempty" state:"true"`
+
+	// Refresh removes the cached images so that they are pulled again from the registry
+	Refresh bool `json:"-"`
 }
 
 func NewImageCache(name string) *ImageCache {
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestImageCacheTypeMatchesUtils(t *testing.T) {
+	assert.Equal(t, string(TypeImageCache), utils.CacheResourceType)
+}
+
+func TestImageCacheFQDNInWorkspaceMatchesProxyAddress(t *testing.T) {
+	os.Setenv(utils.WorkspaceEnvName, "dev")
+	defer os.Unsetenv(utils.WorkspaceEnvName)
+
+	fqdn := utils.FQDN(utils.CacheResourceName, string(TypeImageCache))
+
+	assert.Equal(t, "docker-cache.image-cache.shipyard.run", fqdn)
+	assert.True(t, strings.Contains(utils.ProxyAddress, "//"+fqdn+":"))
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TypeImport is the resource string for an Import resource
+const TypeImport ResourceType = "import"
+
+// Import adds the resources from another blueprint to the current blueprint.
+// Unlike a Module an Import is not parameterized, it allows existing
+// standalone blueprints to be composed into a larger environment.
+//
+// The names of imported resources are prefixed with the name of the import
+// to avoid collisions, e.g. container "server" imported with the import
+// "vault" becomes container.vault-server. References between the imported
+// resources are updated to use the new names.
+type Import struct {
+	ResourceInfo `hcl:",remain" mapstructure:",squash"`
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Source is a local folder or a remote location e.g. github.com/shipyard-run/blueprints//vault
+	Source string `hcl:"source" json:"source"`
+}
+
+// NewImport creates a new Import config resource
+func NewImport(name string) *Import {
+	return &Import{ResourceInfo: ResourceInfo{Name: name, Type: TypeImport, Status: PendingCreation}}
+}
+
+// ImportCycleError is returned when blueprints import each other
+type ImportCycleError struct {
+	Sources []string
+}
+
+func (e ImportCycleError) Error() string {
+	return fmt.Sprintf("Import cycle detected: %s", strings.Join(e.Sources, " -> "))
+}
+
+// importStack holds the sources of the imports which are currently being parsed
+// and is used to detect cycles
+var importStack = []string{}
+
+// parseImport parses the blueprint referenced by the import and adds the
+// namespaced resources to the config c
+func parseImport(i *Import, c *Config, moduleName string, disabled bool, dependsOn []string) error {
+	for n, s := range importStack {
+		if s == i.Source {
+			cycle := append([]string{}, importStack[n:]...)
+			return ImportCycleError{append(cycle, i.Source)}
+		}
+	}
+
+	importStack = append(importStack, i.Source)
+	defer func() {
+		importStack = importStack[:len(importStack)-1]
+	}()
+
+	// parse into a separate config so the resources can be renamed before
+	// they are added, any imports in the imported blueprint are resolved
+	// recursively
+	ic := New()
+	err := parseFolder(i.Source, ic, true, "", disabled, nil, nil, "")
+	if err != nil {
+		return err
+	}
+
+	namespaceResources(ic, i.Name)
+
+	for _, r := range ic.Resources {
+		if r.Info().Module == "" {
+			r.Info().Module = moduleName
+		}
+
+		r.Info().DependsOn = append(r.Info().DependsOn, dependsOn...)
+		r.Info().DependsOn = append(r.Info().DependsOn, i.Depends...)
+
+		err := c.AddResource(r)
+		if err != nil {
+			return fmt.Errorf("Unable to add resource %s.%s from import %s: %s", r.Info().Type, r.Info().Name, i.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// namespaceResources prefixes the names of all resources in the config with
+// the namespace and updates any references to the renamed resources
+func namespaceResources(c *Config, namespace string) {
+	names := map[string]string{}
+
+	for _, r := range c.Resources {
+		n := fmt.Sprintf("%s-%s", namespace, r.Info().Name)
+		names[fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name)] = fmt.Sprintf("%s.%s", r.Info().Type, n)
+
+		if m := r.Info().Module; m != "" {
+			names[fmt.Sprintf("module.%s", m)] = fmt.Sprintf("module.%s-%s", namespace, m)
+			r.Info().Module = fmt.Sprintf("%s-%s", namespace, m)
+		}
+
+		r.Info().Name = n
+	}
+
+	for _, r := range c.Resources {
+		replaceReferences(reflect.ValueOf(r), names)
+	}
+}
+
+var configType = reflect.TypeOf(&Config{})
+
+// replaceReferences walks the fields of a resource replacing any string which
+// is a reference to a renamed resource
+func replaceReferences(v reflect.Value, names map[string]string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		// do not walk back up to the parent config
+		if v.IsNil() || v.Type() == configType {
+			return
+		}
+
+		replaceReferences(v.Elem(), names)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+
+			replaceReferences(v.Field(i), names)
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			replaceReferences(v.Index(i), names)
+		}
+
+	case reflect.String:
+		if n, ok := names[v.String()]; ok && v.CanSet() {
+			v.SetString(n)
+		}
+	}
+}
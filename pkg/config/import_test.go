@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportAddsNamespacedResources(t *testing.T) {
+	importDir, cleanupImport := createTestFiles(t, importedBlueprint)
+	defer cleanupImport()
+
+	c, _, cleanup := setupTestConfig(t, fmt.Sprintf(importValid, importDir))
+	defer cleanup()
+
+	// resources with the same name in the importing blueprint do not collide
+	_, err := c.FindResource("container.server")
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("network.vault-cloud")
+	assert.NoError(t, err)
+
+	r, err := c.FindResource("container.vault-server")
+	assert.NoError(t, err)
+
+	co := r.(*Container)
+	assert.Equal(t, "network.vault-cloud", co.Networks[0].Name)
+	assert.Contains(t, co.DependsOn, "network.vault-cloud")
+	assert.Contains(t, co.DependsOn, "container.server")
+}
+
+func TestImportCycleReturnsError(t *testing.T) {
+	dirA, cleanupA := createTestFiles(t)
+	defer cleanupA()
+
+	dirB, cleanupB := createTestFiles(t)
+	defer cleanupB()
+
+	createNamedFile(t, dirA, "*.hcl", fmt.Sprintf(`import "b" { source = "%s" }`, dirB))
+	createNamedFile(t, dirB, "*.hcl", fmt.Sprintf(`import "a" { source = "%s" }`, dirA))
+
+	c := New()
+	err := ParseFolder(dirA, c, false, "", false, []string{}, nil, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Import cycle detected")
+}
+
+const importedBlueprint = `
+network "cloud" {
+  subnet = "10.5.0.0/16"
+}
+
+container "server" {
+  image {
+    name = "vault:1.6.1"
+  }
+
+  network {
+    name = "network.cloud"
+  }
+}
+`
+
+const importValid = `
+container "server" {
+  image {
+    name = "consul:1.8.1"
+  }
+}
+
+import "vault" {
+  source     = "%s"
+  depends_on = ["container.server"]
+}
+`
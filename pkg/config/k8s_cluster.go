@@ -1,5 +1,14 @@
 package config
 
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver"
+)
+
+// DefaultK8sVersion is the version of Kubernetes used when a cluster does not set a version
+const DefaultK8sVersion = "v1.18.16"
+
 // TypeK8sCluster is the resource string for a Cluster resource
 const TypeK8sCluster ResourceType = "k8s_cluster"
 
@@ -12,19 +21,79 @@ type K8sCluster struct {
 
 	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // Attach to the correct network // only when Image is specified
 
-	Driver  string   `hcl:"driver" json:"driver,omitempty"`
+	Driver  string   `hcl:"driver,optional" json:"driver,omitempty"`
 	Version string   `hcl:"version,optional" json:"version,omitempty"`
-	Nodes   int      `hcl:"nodes,optional" json:"nodes,omitempty"`
+	Nodes   int      `hcl:"nodes,optional" json:"nodes,omitempty"` // number of nodes including the server, defaults to 1
 	Images  []Image  `hcl:"image,block" json:"images,omitempty"`
 	Volumes []Volume `hcl:"volume,block" json:"volumes,omitempty"` // volumes to attach to the cluster
 
 	Ports      []Port      `hcl:"port,block" json:"ports,omitempty"`                                       // ports to expose
 	PortRanges []PortRange `hcl:"port_range,block" json:"port_ranges,omitempty" mapstructure:"port_range"` // range of ports to expose
 
-	EnvVar map[string]string `hcl:"env_var,optional" json:"env_var,omitempty" mapstructure:"env_var"` // environment variables to set when starting the container
+	EnvVar map[string]string `hcl:"env_var,optional" json:"env_var,omitempty" mapstructure:"env_var" sensitive:"true"` // environment variables to set when starting the container
+
+	// External uses an existing cluster rather than creating one, the cluster is
+	// accessed using the KubeConfig
+	External bool `hcl:"external,optional" json:"external,omitempty"`
+
+	// KubeConfig is the path to the Kubernetes config file for an external cluster
+	KubeConfig string `hcl:"kubeconfig,optional" json:"kubeconfig,omitempty"`
+
+	// ResolvedVersion is the version of Kubernetes the cluster was created with
+	ResolvedVersion string `json:"resolved_version,omitempty" mapstructure:"resolved_version" state:"true"`
 }
 
 // NewK8sCluster creates new Cluster config with the correct defaults
 func NewK8sCluster(name string) *K8sCluster {
 	return &K8sCluster{ResourceInfo: ResourceInfo{Name: name, Type: TypeK8sCluster, Status: PendingCreation}}
 }
+
+// Validate the config
+func (k *K8sCluster) Validate() error {
+	if k.External {
+		if k.KubeConfig == "" {
+			return fmt.Errorf("kubeconfig must be set for an external cluster")
+		}
+
+		return nil
+	}
+
+	if k.Driver == "" {
+		return fmt.Errorf("driver must be set")
+	}
+
+	if k.Nodes < 0 {
+		return fmt.Errorf("invalid nodes %d, must be 1 or greater", k.Nodes)
+	}
+
+	err := validateVolumes(k.Volumes)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range k.PortRanges {
+		err := p.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if k.Version == "" {
+		return nil
+	}
+
+	if _, err := semver.NewVersion(k.Version); err != nil {
+		return fmt.Errorf("invalid version %s, must be a semantic version e.g. %s", k.Version, DefaultK8sVersion)
+	}
+
+	return nil
+}
+
+// RequestedVersion returns the version of Kubernetes the cluster should be created with
+func (k *K8sCluster) RequestedVersion() string {
+	if k.Version == "" {
+		return DefaultK8sVersion
+	}
+
+	return k.Version
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -53,3 +54,66 @@ k8s_cluster "testing" {
 	driver = "k3s"
 }
 `
+
+func TestK8sClusterWithInvalidVersionReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t, clusterInvalidVersion)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid version latest")
+}
+
+func TestK8sClusterRequestedVersionDefaults(t *testing.T) {
+	k := NewK8sCluster("test")
+	assert.Equal(t, DefaultK8sVersion, k.RequestedVersion())
+
+	k.Version = "v1.20.0"
+	assert.Equal(t, "v1.20.0", k.RequestedVersion())
+}
+
+const clusterInvalidVersion = `
+k8s_cluster "testing" {
+	driver  = "k3s"
+	version = "latest"
+}
+`
+
+func TestK8sClusterValidateRejectsNegativeNodes(t *testing.T) {
+	k := NewK8sCluster("test")
+	k.Nodes = -1
+
+	assert.Error(t, k.Validate())
+}
+
+func TestK8sClusterExternalMakesKubeConfigAbsolute(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, clusterExternal)
+	defer cleanup()
+
+	cl, err := c.FindResource("k8s_cluster.testing")
+	assert.NoError(t, err)
+
+	assert.True(t, cl.(*K8sCluster).External)
+	assert.Equal(t, filepath.Join(dir, "kubeconfig.yaml"), cl.(*K8sCluster).KubeConfig)
+}
+
+func TestK8sClusterValidateRequiresKubeConfigWhenExternal(t *testing.T) {
+	k := NewK8sCluster("test")
+	k.External = true
+
+	assert.Error(t, k.Validate())
+}
+
+func TestK8sClusterValidateRequiresDriver(t *testing.T) {
+	k := NewK8sCluster("test")
+
+	assert.Error(t, k.Validate())
+}
+
+const clusterExternal = `
+k8s_cluster "testing" {
+	external   = true
+	kubeconfig = "./kubeconfig.yaml"
+}
+`
@@ -1,5 +1,15 @@
 package config
 
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
 // TypeK8sConfig defines the string type for the Kubernetes config resource
 const TypeK8sConfig ResourceType = "k8s_config"
 
@@ -16,8 +26,36 @@ type K8sConfig struct {
 	// WaitUntilReady when set to true waits until all resources have been created and are in a "Running" state
 	WaitUntilReady bool `hcl:"wait_until_ready" json:"wait_until_ready" mapstructure:"wait_until_ready"`
 
+	// Vars are injected into the manifests before they are applied, manifests reference
+	// the values using the template syntax #{{ .Vars.name }}
+	Vars map[string]string `hcl:"vars,optional" json:"vars,omitempty"`
+
+	// Prune when set to true deletes objects which were previously applied but
+	// have since been removed from the manifests
+	Prune bool `hcl:"prune,optional" json:"prune,omitempty"`
+
 	// HealthCheck defines a health check for the resource
 	HealthCheck *HealthCheck `hcl:"health_check,block" json:"health_check,omitempty" mapstructure:"health_check"`
+
+	// ManifestChecksum is the checksum of the manifests and Vars when they were last applied
+	ManifestChecksum string `json:"manifest_checksum,omitempty" mapstructure:"manifest_checksum" state:"true"`
+
+	// AppliedObjects are the Kubernetes objects created when the manifests were last applied,
+	// only set when Prune is true
+	AppliedObjects []K8sObject `json:"applied_objects,omitempty" mapstructure:"applied_objects" state:"true"`
+}
+
+// K8sObject is a reference to a Kubernetes object
+type K8sObject struct {
+	APIVersion string `json:"api_version" mapstructure:"api_version"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// String returns the object reference in the form apiVersion/Kind/namespace/name
+func (o K8sObject) String() string {
+	return fmt.Sprintf("%s/%s/%s/%s", o.APIVersion, o.Kind, o.Namespace, o.Name)
 }
 
 // NewK8sConfig creates a kubernetes config resource with the correct defaults
@@ -29,3 +67,56 @@ func NewK8sConfig(name string) *K8sConfig {
 func (b *K8sConfig) Validate() []error {
 	return nil
 }
+
+// Checksum returns a checksum of the manifest files in Paths and the Vars
+func (b *K8sConfig) Checksum() (string, error) {
+	sum := sha256.New()
+
+	for _, p := range b.Paths {
+		err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() || !IsK8sManifest(path) {
+				return nil
+			}
+
+			d, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(sum, "%s\n", path)
+			sum.Write(d)
+
+			return nil
+		})
+
+		if err != nil {
+			return "", fmt.Errorf("unable to read Kubernetes config %s: %s", p, err)
+		}
+	}
+
+	keys := []string{}
+	for k := range b.Vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(sum, "%s=%s\n", k, b.Vars[k])
+	}
+
+	return fmt.Sprintf("%x", sum.Sum(nil)), nil
+}
+
+// IsK8sManifest returns true when the file at path is a YAML or JSON Kubernetes manifest
+func IsK8sManifest(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+
+	return false
+}
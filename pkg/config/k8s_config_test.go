@@ -1,6 +1,8 @@
 package config
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,6 +28,7 @@ func TestK8sConfigCreatesCorrectly(t *testing.T) {
 
 	assert.Equal(t, "/tmp/files", cc.(*K8sConfig).Paths[0])
 	assert.True(t, cc.(*K8sConfig).WaitUntilReady)
+	assert.Equal(t, "consul:1.8.1", cc.(*K8sConfig).Vars["image"])
 }
 
 func TestK8sConfigSetsDisabled(t *testing.T) {
@@ -48,6 +51,43 @@ func TestMakesPathAbsolute(t *testing.T) {
 	assert.Contains(t, kc.(*K8sConfig).Paths[1], base)
 }
 
+func TestK8sConfigParsesPrune(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, k8sConfigPrune)
+	defer cleanup()
+
+	kc, err := c.FindResource("k8s_config.test")
+	assert.NoError(t, err)
+
+	assert.True(t, kc.(*K8sConfig).Prune)
+}
+
+func TestK8sConfigChecksumChangesWithManifestsAndVars(t *testing.T) {
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, "app.yaml"), []byte("kind: Service"), 0644)
+
+	c := NewK8sConfig("test")
+	c.Paths = []string{dir}
+
+	first, err := c.Checksum()
+	assert.NoError(t, err)
+
+	// non manifest files are ignored
+	ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("docs"), 0644)
+	second, err := c.Checksum()
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	c.Vars = map[string]string{"image": "consul:1.8.1"}
+	third, err := c.Checksum()
+	assert.NoError(t, err)
+	assert.NotEqual(t, second, third)
+
+	ioutil.WriteFile(filepath.Join(dir, "app.yaml"), []byte("kind: Deployment"), 0644)
+	fourth, err := c.Checksum()
+	assert.NoError(t, err)
+	assert.NotEqual(t, third, fourth)
+}
+
 var k8sConfigValid = `
 k8s_cluster "cloud" {
   driver  = "k3s" // default
@@ -65,6 +105,10 @@ k8s_config "test" {
 	paths = ["/tmp/files","./myfiles"]
 	wait_until_ready = true
 
+	vars = {
+		image = "consul:1.8.1"
+	}
+
 	health_check {
 		timeout = "30s"
 		http = "http://www.google.com"
@@ -96,3 +140,72 @@ k8s_config "test" {
 	}
 }
 `
+
+func TestK8sConfigParsesHealthCheckConditions(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, k8sConfigConditions)
+	defer cleanup()
+
+	kc, err := c.FindResource("k8s_config.test")
+	assert.NoError(t, err)
+
+	conds := kc.(*K8sConfig).HealthCheck.Conditions
+	assert.Len(t, conds, 2)
+	assert.Equal(t, "Deployment", conds[0].Kind)
+	assert.Equal(t, "Available", conds[0].Condition)
+	assert.Equal(t, "1m", conds[1].Timeout)
+}
+
+func TestHealthCheckConditionGroupVersion(t *testing.T) {
+	v, err := HealthCheckCondition{Kind: "Deployment"}.GroupVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "apps/v1", v)
+
+	v, err = HealthCheckCondition{Kind: "Certificate", APIVersion: "cert-manager.io/v1"}.GroupVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "cert-manager.io/v1", v)
+
+	_, err = HealthCheckCondition{Kind: "Certificate"}.GroupVersion()
+	assert.Error(t, err)
+}
+
+var k8sConfigConditions = `
+k8s_cluster "cloud" {
+  driver  = "k3s"
+}
+
+k8s_config "test" {
+	cluster = "k8s_cluster.cloud"
+	paths = ["./myfiles"]
+	wait_until_ready = true
+
+	health_check {
+		timeout = "30s"
+
+		condition {
+			kind      = "Deployment"
+			name      = "web"
+			condition = "Available"
+		}
+
+		condition {
+			kind      = "Job"
+			name      = "migrate"
+			condition = "Complete"
+			timeout   = "1m"
+		}
+	}
+}
+`
+
+var k8sConfigPrune = `
+k8s_cluster "cloud" {
+  driver  = "k3s"
+}
+
+k8s_config "test" {
+	cluster = "k8s_cluster.cloud"
+	paths = ["./myfiles"]
+	wait_until_ready = true
+	prune = true
+}
+`
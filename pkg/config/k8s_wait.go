@@ -0,0 +1,37 @@
+package config
+
+// TypeK8sWait defines the string type for the Kubernetes wait resource
+const TypeK8sWait ResourceType = "k8s_wait"
+
+// K8sWait blocks until a Kubernetes object reports the given status condition
+// example config:
+//    api_version = "cert-manager.io/v1"
+//    kind        = "Certificate"
+//    object_name = "consul-server"
+//    namespace   = "default"
+//    condition   = "Ready"
+type K8sWait struct {
+	ResourceInfo `hcl:",remain" mapstructure:",squash"`
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Cluster is the name of the cluster containing the object
+	Cluster string `hcl:"cluster" json:"cluster"`
+	// APIVersion of the object e.g. apps/v1
+	APIVersion string `hcl:"api_version" json:"api_version" mapstructure:"api_version"`
+	// Kind of the object e.g. Deployment
+	Kind string `hcl:"kind" json:"kind"`
+	// ObjectName is the name of the Kubernetes object to check
+	ObjectName string `hcl:"object_name" json:"object_name" mapstructure:"object_name"`
+	// Namespace of the object, defaults to default, ignored for cluster scoped objects
+	Namespace string `hcl:"namespace,optional" json:"namespace,omitempty"`
+	// Condition is the status condition type which must be True e.g. Available
+	Condition string `hcl:"condition" json:"condition"`
+	// Timeout is the maximum duration to wait for the condition, defaults to 60s
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"`
+}
+
+// NewK8sWait creates a kubernetes wait resource with the correct defaults
+func NewK8sWait(name string) *K8sWait {
+	return &K8sWait{ResourceInfo: ResourceInfo{Name: name, Type: TypeK8sWait, Status: PendingCreation}}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCreatesK8sWait(t *testing.T) {
+	c := NewK8sWait("abc")
+
+	assert.Equal(t, "abc", c.Name)
+	assert.Equal(t, TypeK8sWait, c.Type)
+}
+
+func TestK8sWaitCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, k8sWaitValid)
+	defer cleanup()
+
+	cc, err := c.FindResource("k8s_wait.test")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "test", cc.Info().Name)
+	assert.Equal(t, TypeK8sWait, cc.Info().Type)
+	assert.Equal(t, PendingCreation, cc.Info().Status)
+
+	kw := cc.(*K8sWait)
+	assert.Equal(t, "cert-manager.io/v1", kw.APIVersion)
+	assert.Equal(t, "Certificate", kw.Kind)
+	assert.Equal(t, "consul", kw.ObjectName)
+	assert.Equal(t, "Ready", kw.Condition)
+	assert.Contains(t, kw.DependsOn, "k8s_cluster.cloud")
+}
+
+func TestK8sWaitSetsDisabled(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, k8sWaitDisabled)
+	defer cleanup()
+
+	cc, err := c.FindResource("k8s_wait.test")
+	assert.NoError(t, err)
+
+	assert.Equal(t, Disabled, cc.Info().Status)
+}
+
+var k8sWaitValid = `
+k8s_cluster "cloud" {
+  driver  = "k3s" // default
+  version = "1.16.0"
+
+  nodes = 1 // default
+
+  network {
+	  name = "network.k8s"
+  }
+}
+
+k8s_wait "test" {
+	cluster = "k8s_cluster.cloud"
+
+	api_version = "cert-manager.io/v1"
+	kind        = "Certificate"
+	object_name = "consul"
+	condition   = "Ready"
+	timeout     = "120s"
+}
+`
+
+var k8sWaitDisabled = `
+k8s_cluster "cloud" {
+  driver  = "k3s" // default
+  version = "1.16.0"
+
+  nodes = 1 // default
+
+  network {
+	  name = "network.k8s"
+  }
+}
+
+k8s_wait "test" {
+	disabled = true
+
+	cluster = "k8s_cluster.cloud"
+
+	api_version = "apps/v1"
+	kind        = "Deployment"
+	object_name = "consul"
+	condition   = "Available"
+}
+`
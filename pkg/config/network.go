@@ -1,5 +1,10 @@
 package config
 
+import (
+	"fmt"
+	"net"
+)
+
 // TypeNetwork is the string resource type for Network resources
 const TypeNetwork ResourceType = "network"
 
@@ -7,10 +12,59 @@ const TypeNetwork ResourceType = "network"
 type Network struct {
 	ResourceInfo `hcl:",remain" mapstructure:",squash"`
 
-	Subnet string `hcl:"subnet" json:"subnet"`
+	Subnet string `hcl:"subnet,optional" json:"subnet,omitempty"`
+
+	// External when set to true attaches to an existing Docker network with the same
+	// name, the network is not created or destroyed
+	External bool `hcl:"external,optional" json:"external,omitempty"`
+
+	// ID is the Docker id of the network, only set for external networks
+	ID string `json:"id,omitempty" state:"true"`
 }
 
 // NewNetwork creates a new Network resource with the correct defaults
 func NewNetwork(name string) *Network {
 	return &Network{ResourceInfo: ResourceInfo{Name: name, Type: TypeNetwork, Status: PendingCreation}}
 }
+
+// Validate the Network and return errors
+func (n *Network) Validate() error {
+	if n.Subnet == "" && !n.External {
+		return fmt.Errorf("subnet is required unless the network is external")
+	}
+
+	return nil
+}
+
+// validateSubnetOverlap checks that the subnet for the network does not overlap
+// with the subnet of any other enabled network. Subnets which are not valid CIDR
+// ranges are not checked.
+func (n *Network) validateSubnetOverlap(cfg *Config) error {
+	if n.Status == Disabled {
+		return nil
+	}
+
+	_, subnet, err := net.ParseCIDR(n.Subnet)
+	if err != nil {
+		return nil
+	}
+
+	for _, r := range cfg.FindResourcesByType(string(TypeNetwork)) {
+		other := r.(*Network)
+		if other == n || other.Status == Disabled {
+			continue
+		}
+
+		_, otherSubnet, err := net.ParseCIDR(other.Subnet)
+		if err != nil {
+			continue
+		}
+
+		// two CIDR ranges overlap when either contains the base address of the other
+		if subnet.Contains(otherSubnet.IP) || otherSubnet.Contains(subnet.IP) {
+			return fmt.Errorf("subnet %s overlaps with subnet %s of network.%s", n.Subnet, other.Subnet, other.Name)
+		}
+	}
+
+	return nil
+}
@@ -35,6 +35,55 @@ func TestNetworkSetsDisabled(t *testing.T) {
 	assert.Equal(t, Disabled, cl.Info().Status)
 }
 
+func TestNetworkOverlappingSubnetReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t, networkOverlapping)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "resource 'network.other' is invalid")
+	assert.Contains(t, err.Error(), "subnet 10.0.5.0/24 overlaps with subnet 10.0.0.0/16 of network.test")
+}
+
+func TestNetworkOverlappingDisabledSubnetIsValid(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, networkOverlappingDisabled)
+	defer cleanup()
+
+	_, err := c.FindResource("network.test")
+	assert.NoError(t, err)
+}
+
+func TestNetworkExternalDoesNotRequireSubnet(t *testing.T) {
+	dir, cleanup := createTestFiles(t, networkExternal)
+	defer cleanup()
+
+	c := New()
+	ic := NewImageCache("docker-cache")
+	c.AddResource(ic)
+
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.NoError(t, err)
+
+	n, err := c.FindResource("network.test")
+	assert.NoError(t, err)
+	assert.True(t, n.(*Network).External)
+
+	// external networks are not attached to the image cache
+	assert.NotContains(t, ic.DependsOn, "network.test")
+	assert.Contains(t, ic.DependsOn, "network.internal")
+}
+
+func TestNetworkWithoutSubnetReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t, networkNoSubnet)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "subnet is required")
+}
+
 const networkDefault = `
 network "test" {
 	subnet = "10.0.0.0/24"
@@ -47,3 +96,39 @@ network "test" {
 	subnet = "10.0.0.0/24"
 }
 `
+
+const networkOverlapping = `
+network "test" {
+	subnet = "10.0.0.0/16"
+}
+
+network "other" {
+	subnet = "10.0.5.0/24"
+}
+`
+
+const networkOverlappingDisabled = `
+network "test" {
+	subnet = "10.0.0.0/16"
+}
+
+network "other" {
+	disabled = true
+	subnet = "10.0.5.0/24"
+}
+`
+
+const networkExternal = `
+network "test" {
+	external = true
+}
+
+network "internal" {
+	subnet = "10.0.0.0/24"
+}
+`
+
+const networkNoSubnet = `
+network "test" {
+}
+`
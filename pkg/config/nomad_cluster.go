@@ -28,6 +28,11 @@ func NewNomadCluster(name string) *NomadCluster {
 	return &NomadCluster{ResourceInfo: ResourceInfo{Name: name, Type: TypeNomadCluster, Status: PendingCreation}}
 }
 
+// Validate the NomadCluster and return errors
+func (n *NomadCluster) Validate() error {
+	return validateVolumes(n.Volumes)
+}
+
 // ClusterConfig defines arbitary config to set for the cluster
 type ClusterConfig struct {
 	ConsulHTTPAddr string `hcl:"consul_http_addr,optional" json:"consul_http_addr,omitempty" mapstructure:"consul_http_addr"`
@@ -207,6 +207,38 @@ func TestVariablesSetFromDefault(t *testing.T) {
 	assert.Equal(t, "onprem", con.Networks[0].Name)
 }
 
+func TestParseRecordsVariablesReferencedByResource(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, `
+variable "tag" {
+  default = "1.6.1"
+}
+
+container "consul" {
+  image {
+    name = "consul:${var.tag}"
+  }
+
+  env {
+    key   = "TAG"
+    value = var.tag
+  }
+}
+
+network "cloud" {
+  subnet = "10.7.0.0/16"
+}
+`)
+	defer cleanup()
+
+	r, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tag"}, r.Info().Variables)
+
+	r, err = c.FindResource("network.cloud")
+	assert.NoError(t, err)
+	assert.Empty(t, r.Info().Variables)
+}
+
 func TestOverridesVariableDefaultsWithEnv(t *testing.T) {
 	absoluteFolderPath, err := filepath.Abs("../../examples/variables/simple/")
 	if err != nil {
@@ -496,6 +528,54 @@ func TestParseProcessesShipyardFunctions(t *testing.T) {
 	assert.Equal(t, clusterIP, cc.EnvVar["cluster_api"])
 }
 
+func TestParseRecordsResourceFileAndLine(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, `
+network "cloud" {
+  subnet = "10.0.0.0/16"
+}
+
+container "consul" {
+  image {
+    name = "consul:1.8.1"
+  }
+}
+`)
+	defer cleanup()
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, dir, filepath.Dir(co.Info().File))
+	assert.Equal(t, 6, co.Info().Line)
+
+	n, err := c.FindResource("network.cloud")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n.Info().Line)
+}
+
+func TestParseRecordsFileAndLineForModuleResources(t *testing.T) {
+	mod := t.TempDir()
+	mf := createNamedFile(t, mod, "*.hcl", `
+container "consul" {
+  image {
+    name = "consul:1.8.1"
+  }
+}
+`)
+
+	c, _, cleanup := setupTestConfig(t, fmt.Sprintf(`
+module "consul" {
+  source = "%s"
+}
+`, mod))
+	defer cleanup()
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul", co.Info().Module)
+	assert.Equal(t, mf, co.Info().File)
+	assert.Equal(t, 2, co.Info().Line)
+}
+
 /*
 func TestSingleKubernetesCluster(t *testing.T) {
 	absoluteFolderPath, err := filepath.Abs("./examples/single-cluster-k8s")
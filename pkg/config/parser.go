@@ -70,7 +70,8 @@ func ParseFolder(
 }
 
 func parseFile(file string, c *Config, variables map[string]string, variablesFile string) error {
-	SetVariables(variables)
+	// values files are loaded first so that environment variables and
+	// variables set on the command line take precedence
 	if variablesFile != "" {
 		err := LoadValuesFile(variablesFile)
 		if err != nil {
@@ -78,6 +79,8 @@ func parseFile(file string, c *Config, variables map[string]string, variablesFil
 		}
 	}
 
+	SetVariables(variables)
+
 	err := parseVariableFile(file, c)
 	if err != nil {
 		return err
@@ -207,13 +210,18 @@ func LoadValuesFile(path string) error {
 }
 
 // SetVariables allow variables to be set from a collection or environment variables
-// Precedence should be file, env, vars
+// prefixed with VariableEnvPrefix, values set by SetVariables replace any values
+// loaded from files.
+//
+// Variables are merged in the following order, later values take precedence:
+// default values in variable blocks, *.vars files in the blueprint folder, the
+// variables file, environment variables, and finally the vars collection.
 func SetVariables(vars map[string]string) {
 	// first any vars defined as environment variables
 	for _, e := range os.Environ() {
-		if strings.HasPrefix(e, "SY_VAR_") {
-			parts := strings.Split(e, "=")
-			setContextVariable(strings.Replace(parts[0], "SY_VAR_", "", -1), parts[1])
+		if strings.HasPrefix(e, VariableEnvPrefix) {
+			parts := strings.SplitN(e, "=", 2)
+			setContextVariable(strings.TrimPrefix(parts[0], VariableEnvPrefix), parts[1])
 		}
 	}
 
@@ -229,9 +237,9 @@ func parseVariableFile(file string, c *Config) error {
 	ctx.Functions["file_path"] = getFilePathFunc(file)
 	ctx.Functions["file_dir"] = getFileDirFunc(file)
 
-	f, diag := parser.ParseHCLFile(file)
-	if diag.HasErrors() {
-		return errors.New(diag.Error())
+	f, err := parseConfigFile(parser, file)
+	if err != nil {
+		return err
 	}
 
 	body, ok := f.Body.(*hclsyntax.Body)
@@ -249,8 +257,11 @@ func parseVariableFile(file string, c *Config) error {
 				return err
 			}
 
-			val, _ := v.Default.(*hcl.Attribute).Expr.Value(ctx)
-			setContextVariableIfMissing(v.Name, val)
+			// variables without a default are required and must be set
+			if a, ok := v.Default.(*hcl.Attribute); ok && a != nil {
+				val, _ := a.Expr.Value(ctx)
+				setContextVariableIfMissing(v.Name, val)
+			}
 		}
 	}
 
@@ -263,9 +274,9 @@ func parseHCLFile(file string, c *Config, moduleName string, disabled bool, depe
 	ctx.Functions["file_path"] = getFilePathFunc(file)
 	ctx.Functions["file_dir"] = getFileDirFunc(file)
 
-	f, diag := parser.ParseHCLFile(file)
-	if diag.HasErrors() {
-		return errors.New(diag.Error())
+	f, err := parseConfigFile(parser, file)
+	if err != nil {
+		return err
 	}
 
 	body, ok := f.Body.(*hclsyntax.Body)
@@ -302,10 +313,21 @@ func parseHCLFile(file string, c *Config, moduleName string, disabled bool, depe
 				return err
 			}
 
-			// Process volumes
-			// make sure mount paths are absolute
+			// process volumes
 			for i, v := range cl.Volumes {
-				cl.Volumes[i].Source = ensureAbsolute(v.Source, file)
+				// make sure mount paths are absolute when type is bind
+				if v.Type == "" || v.Type == "bind" {
+					cl.Volumes[i].Source = ensureAbsolute(v.Source, file)
+				}
+			}
+
+			if cl.KubeConfig != "" {
+				cl.KubeConfig = ensureAbsolute(cl.KubeConfig, file)
+			}
+
+			err = cl.Validate()
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': resource '%s.%s' is invalid: %s", file, b.Type, name, err)
 			}
 
 			setDisabled(cl, disabled)
@@ -374,6 +396,10 @@ func parseHCLFile(file string, c *Config, moduleName string, disabled bool, depe
 				h.Values = ensureAbsolute(h.Values, file)
 			}
 
+			for i, v := range h.ValuesFiles {
+				h.ValuesFiles[i] = ensureAbsolute(v, file)
+			}
+
 			setDisabled(h, disabled)
 
 			err = c.AddResource(h)
@@ -387,6 +413,29 @@ func parseHCLFile(file string, c *Config, moduleName string, disabled bool, depe
 				)
 			}
 
+		case string(TypeK8sWait):
+			w := NewK8sWait(name)
+			w.Info().Module = moduleName
+			w.Info().DependsOn = dependsOn
+
+			err := decodeBody(file, b, w)
+			if err != nil {
+				return err
+			}
+
+			setDisabled(w, disabled)
+
+			err = c.AddResource(w)
+			if err != nil {
+				return fmt.Errorf(
+					"Unable to add resource %s.%s in file %s: %s",
+					b.Type,
+					b.Labels[0],
+					file,
+					err,
+				)
+			}
+
 		case string(TypeK8sIngress):
 			i := NewK8sIngress(name)
 			i.Info().Module = moduleName
@@ -432,10 +481,17 @@ func parseHCLFile(file string, c *Config, moduleName string, disabled bool, depe
 				cl.ConsulConfig = ensureAbsolute(cl.ConsulConfig, file)
 			}
 
-			// Process volumes
-			// make sure mount paths are absolute
+			// process volumes
 			for i, v := range cl.Volumes {
-				cl.Volumes[i].Source = ensureAbsolute(v.Source, file)
+				// make sure mount paths are absolute when type is bind
+				if v.Type == "" || v.Type == "bind" {
+					cl.Volumes[i].Source = ensureAbsolute(v.Source, file)
+				}
+			}
+
+			err = cl.Validate()
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': resource '%s.%s' is invalid: %s", file, b.Type, name, err)
 			}
 
 			setDisabled(cl, disabled)
@@ -512,8 +568,18 @@ func parseHCLFile(file string, c *Config, moduleName string, disabled bool, depe
 				return err
 			}
 
+			err = n.Validate()
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': resource '%s.%s' is invalid: %s", file, b.Type, name, err)
+			}
+
 			setDisabled(n, disabled)
 
+			err = n.validateSubnetOverlap(c)
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': resource '%s.%s' is invalid: %s", file, b.Type, name, err)
+			}
+
 			err = c.AddResource(n)
 			if err != nil {
 				return fmt.Errorf(
@@ -525,11 +591,12 @@ func parseHCLFile(file string, c *Config, moduleName string, disabled bool, depe
 				)
 			}
 
-			// always add this network as a dependency of the image cache
+			// always add this network as a dependency of the image cache, external
+			// networks are not managed by shipyard so the cache is not attached
 			ics := c.FindResourcesByType(string(TypeImageCache))
-			if ics != nil && len(ics) == 1 {
+			if ics != nil && len(ics) == 1 && !n.External {
 				ic := ics[0].(*ImageCache)
-				ic.DependsOn = append(ic.DependsOn, "network."+n.Name)
+				ic.DependsOn = appendUnique(ic.DependsOn, "network."+n.Name)
 			}
 
 		case string(TypeIngress):
@@ -578,6 +645,16 @@ func parseHCLFile(file string, c *Config, moduleName string, disabled bool, depe
 				co.Build.Context = ensureAbsolute(co.Build.Context, file)
 			}
 
+			err = co.Validate()
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': resource '%s.%s' is invalid: %s", file, b.Type, name, err)
+			}
+
+			err = co.validateUniqueMACAddresses(c)
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': resource '%s.%s' is invalid: %s", file, b.Type, name, err)
+			}
+
 			setDisabled(co, disabled)
 
 			err = c.AddResource(co)
@@ -624,8 +701,17 @@ func parseHCLFile(file string, c *Config, moduleName string, disabled bool, depe
 				return err
 			}
 
+			// process volumes
 			for i, v := range s.Volumes {
-				s.Volumes[i].Source = ensureAbsolute(v.Source, file)
+				// make sure mount paths are absolute when type is bind
+				if v.Type == "" || v.Type == "bind" {
+					s.Volumes[i].Source = ensureAbsolute(v.Source, file)
+				}
+			}
+
+			err = s.Validate()
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': resource '%s.%s' is invalid: %s", file, b.Type, name, err)
 			}
 
 			setDisabled(s, disabled)
@@ -676,6 +762,16 @@ func parseHCLFile(file string, c *Config, moduleName string, disabled bool, depe
 				return err
 			}
 
+			// make sure the working directory is absolute
+			if h.WorkingDirectory != "" {
+				h.WorkingDirectory = ensureAbsolute(h.WorkingDirectory, file)
+			}
+
+			err = h.Validate()
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': resource '%s.%s' is invalid: %s", file, b.Type, name, err)
+			}
+
 			setDisabled(h, disabled)
 
 			err = c.AddResource(h)
@@ -700,9 +796,16 @@ func parseHCLFile(file string, c *Config, moduleName string, disabled bool, depe
 			}
 
 			// process volumes
-			// make sure mount paths are absolute
 			for i, v := range h.Volumes {
-				h.Volumes[i].Source = ensureAbsolute(v.Source, file)
+				// make sure mount paths are absolute when type is bind
+				if v.Type == "" || v.Type == "bind" {
+					h.Volumes[i].Source = ensureAbsolute(v.Source, file)
+				}
+			}
+
+			err = h.Validate()
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': resource '%s.%s' is invalid: %s", file, b.Type, name, err)
 			}
 
 			setDisabled(h, disabled)
@@ -718,6 +821,208 @@ func parseHCLFile(file string, c *Config, moduleName string, disabled bool, depe
 				)
 			}
 
+		case string(TypeExternal):
+			e := NewExternal(name)
+			e.Info().Module = moduleName
+			e.Info().DependsOn = dependsOn
+
+			err := decodeBody(file, b, e)
+			if err != nil {
+				return err
+			}
+
+			setDisabled(e, disabled)
+
+			err = c.AddResource(e)
+			if err != nil {
+				return fmt.Errorf(
+					"Unable to add resource %s.%s in file %s: %s",
+					b.Type,
+					b.Labels[0],
+					file,
+					err,
+				)
+			}
+
+		case string(TypeAssert):
+			a := NewAssert(name)
+			a.Info().Module = moduleName
+			a.Info().DependsOn = dependsOn
+
+			err := decodeBody(file, b, a)
+			if err != nil {
+				return err
+			}
+
+			err = a.Validate()
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': resource '%s.%s' is invalid: %s", file, b.Type, name, err)
+			}
+
+			setDisabled(a, disabled)
+
+			err = c.AddResource(a)
+			if err != nil {
+				return fmt.Errorf(
+					"Unable to add resource %s.%s in file %s: %s",
+					b.Type,
+					b.Labels[0],
+					file,
+					err,
+				)
+			}
+
+		case string(TypeSecret):
+			se := NewSecret(name)
+			se.Info().Module = moduleName
+			se.Info().DependsOn = dependsOn
+
+			err := decodeBody(file, b, se)
+			if err != nil {
+				return err
+			}
+
+			err = se.Validate()
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': resource '%s.%s' is invalid: %s", file, b.Type, name, err)
+			}
+
+			if se.Source != "" {
+				se.Source = ensureAbsolute(se.Source, file)
+			}
+
+			setDisabled(se, disabled)
+
+			err = c.AddResource(se)
+			if err != nil {
+				return fmt.Errorf(
+					"Unable to add resource %s.%s in file %s: %s",
+					b.Type,
+					b.Labels[0],
+					file,
+					err,
+				)
+			}
+
+		case string(TypeRegistry):
+			r := NewRegistry(name)
+			r.Info().Module = moduleName
+			r.Info().DependsOn = dependsOn
+
+			err := decodeBody(file, b, r)
+			if err != nil {
+				return err
+			}
+
+			err = r.Validate()
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': resource '%s.%s' is invalid: %s", file, b.Type, name, err)
+			}
+
+			setDisabled(r, disabled)
+
+			err = c.AddResource(r)
+			if err != nil {
+				return fmt.Errorf(
+					"Unable to add resource %s.%s in file %s: %s",
+					b.Type,
+					b.Labels[0],
+					file,
+					err,
+				)
+			}
+
+			// the image cache is configured with the registry credentials
+			ics := c.FindResourcesByType(string(TypeImageCache))
+			if ics != nil && len(ics) == 1 {
+				ic := ics[0].(*ImageCache)
+				ic.DependsOn = appendUnique(ic.DependsOn, "registry."+r.Name)
+			}
+
+		case string(TypeDockerConfig):
+			dc := NewDockerConfig(name)
+			dc.Info().Module = moduleName
+			dc.Info().DependsOn = dependsOn
+
+			err := decodeBody(file, b, dc)
+			if err != nil {
+				return err
+			}
+
+			err = dc.Validate()
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': resource '%s.%s' is invalid: %s", file, b.Type, name, err)
+			}
+
+			if dc.Source != "" {
+				dc.Source = ensureAbsolute(dc.Source, file)
+			}
+
+			setDisabled(dc, disabled)
+
+			err = c.AddResource(dc)
+			if err != nil {
+				return fmt.Errorf(
+					"Unable to add resource %s.%s in file %s: %s",
+					b.Type,
+					b.Labels[0],
+					file,
+					err,
+				)
+			}
+
+		case string(TypeSSHKey):
+			k := NewSSHKey(name)
+			k.Info().Module = moduleName
+			k.Info().DependsOn = dependsOn
+
+			err := decodeBody(file, b, k)
+			if err != nil {
+				return err
+			}
+
+			if k.Output != "" {
+				k.Output = ensureAbsolute(k.Output, file)
+			}
+
+			setDisabled(k, disabled)
+
+			err = c.AddResource(k)
+			if err != nil {
+				return fmt.Errorf(
+					"Unable to add resource %s.%s in file %s: %s",
+					b.Type,
+					b.Labels[0],
+					file,
+					err,
+				)
+			}
+
+		case string(TypeCopy):
+			cp := NewCopy(name)
+			cp.Info().Module = moduleName
+			cp.Info().DependsOn = dependsOn
+
+			err := decodeBody(file, b, cp)
+			if err != nil {
+				return err
+			}
+
+			cp.Source = ensureAbsolute(cp.Source, file)
+
+			setDisabled(cp, disabled)
+
+			err = c.AddResource(cp)
+			if err != nil {
+				return fmt.Errorf(
+					"Unable to add resource %s.%s in file %s: %s",
+					b.Type,
+					b.Labels[0],
+					file,
+					err,
+				)
+			}
+
 		case string(TypeTemplate):
 			i := NewTemplate(name)
 			i.Info().Module = moduleName
@@ -786,6 +1091,38 @@ func parseHCLFile(file string, c *Config, moduleName string, disabled bool, depe
 			ctx.Functions["file_path"] = getFilePathFunc(file)
 			ctx.Functions["file_dir"] = getFileDirFunc(file)
 
+		case string(TypeImport):
+			i := NewImport(name)
+
+			err := decodeBody(file, b, i)
+			if err != nil {
+				return err
+			}
+
+			// fetch remote blueprints to the local cache
+			if !utils.IsLocalFolder(ensureAbsolute(i.Source, file)) {
+				dst := utils.GetBlueprintLocalFolder(i.Source)
+				err := getFiles(i.Source, dst)
+				if err != nil {
+					return err
+				}
+
+				i.Source = dst
+			}
+
+			i.Source = ensureAbsolute(i.Source, file)
+
+			setDisabled(i, disabled)
+
+			err = parseImport(i, c, moduleName, i.Disabled, dependsOn)
+			if err != nil {
+				return fmt.Errorf("Error in file '%s': unable to import '%s': %s", file, name, err)
+			}
+
+			// reset the file path after parsing the imported files
+			ctx.Functions["file_path"] = getFilePathFunc(file)
+			ctx.Functions["file_dir"] = getFileDirFunc(file)
+
 		default:
 			return ResourceTypeNotExistError{string(b.Type), file}
 		}
@@ -803,6 +1140,12 @@ func ParseReferences(c *Config) error {
 			for _, n := range c.Networks {
 				c.DependsOn = append(c.DependsOn, n.Name)
 			}
+			for _, s := range c.Secrets {
+				c.DependsOn = append(c.DependsOn, s.Name)
+			}
+			for _, cf := range c.Configs {
+				c.DependsOn = append(c.DependsOn, cf.Name)
+			}
 			c.DependsOn = append(c.DependsOn, c.Depends...)
 
 		case TypeContainerIngress:
@@ -842,10 +1185,41 @@ func ParseReferences(c *Config) error {
 			c := r.(*ExecLocal)
 			c.DependsOn = append(c.DependsOn, c.Depends...)
 
+		case TypeCopy:
+			c := r.(*Copy)
+			c.DependsOn = append(c.DependsOn, c.Container)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
 		case TypeTemplate:
 			c := r.(*Template)
 			c.DependsOn = append(c.DependsOn, c.Depends...)
 
+		case TypeAssert:
+			c := r.(*Assert)
+			c.DependsOn = append(c.DependsOn, c.Resource)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeSecret:
+			c := r.(*Secret)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeDockerConfig:
+			c := r.(*DockerConfig)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeRegistry:
+			c := r.(*Registry)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeSSHKey:
+			c := r.(*SSHKey)
+			c.DependsOn = append(c.DependsOn, c.Containers...)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeExternal:
+			c := r.(*External)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
 		case TypeIngress:
 			c := r.(*Ingress)
 			if c.Source.Config.Cluster != "" {
@@ -879,6 +1253,11 @@ func ParseReferences(c *Config) error {
 			c.DependsOn = append(c.DependsOn, c.Cluster)
 			c.DependsOn = append(c.DependsOn, c.Depends...)
 
+		case TypeK8sWait:
+			c := r.(*K8sWait)
+			c.DependsOn = append(c.DependsOn, c.Cluster)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
 		case TypeK8sIngress:
 			c := r.(*K8sIngress)
 			for _, n := range c.Networks {
@@ -911,11 +1290,111 @@ func ParseReferences(c *Config) error {
 		}
 	}
 
-	return nil
+	// static ip addresses can only be validated once all the networks have been parsed
+	for _, r := range c.FindResourcesByType(string(TypeContainer)) {
+		co := r.(*Container)
+
+		err := co.validateIPAddresses(c)
+		if err != nil {
+			return fmt.Errorf("resource '%s.%s' is invalid: %s", co.Type, co.Name, err)
+		}
+	}
+
+	// docker fails part way through an apply when a host port is already
+	// in use, check all the resources before anything is created
+	return validateHostPorts(c)
 }
 
-func parseVariables(abs string, c *Config) error {
+// configFiles returns the HCL and YAML resource files in the folder abs
+func configFiles(abs string) ([]string, error) {
 	files, err := filepath.Glob(path.Join(abs, "*.hcl"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ext := range []string{"*.yaml", "*.yml"} {
+		yamlFiles, err := filepath.Glob(path.Join(abs, ext))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range yamlFiles {
+			if isYAMLBlueprint(f) {
+				files = append(files, f)
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// parseConfigFile parses a HCL or YAML file, YAML files are converted to HCL
+// before parsing
+func parseConfigFile(parser *hclparse.Parser, file string) (*hcl.File, error) {
+	f, diag, err := parseConfigFileDiagnostics(parser, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if diag.HasErrors() {
+		return nil, errors.New(diag.Error())
+	}
+
+	return f, nil
+}
+
+// parseConfigFileDiagnostics parses a HCL or YAML file returning the diagnostics,
+// an error is only returned when a YAML file can not be converted to HCL
+func parseConfigFileDiagnostics(parser *hclparse.Parser, file string) (*hcl.File, hcl.Diagnostics, error) {
+	if !isYAMLFile(file) {
+		f, diag := parser.ParseHCLFile(file)
+		return f, diag, nil
+	}
+
+	src, err := yamlToHCL(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, diag := parser.ParseHCL(src, file)
+	return f, diag, nil
+}
+
+// ParseDiagnostics parses every config file in the file or folder at path and returns
+// the diagnostics for all of them, unlike ParseFolder parsing does not stop at the
+// first file which contains errors
+func ParseDiagnostics(path string) (hcl.Diagnostics, error) {
+	files := []string{path}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if fi.IsDir() {
+		files, err = configFiles(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	diags := hcl.Diagnostics{}
+	parser := hclparse.NewParser()
+
+	for _, f := range files {
+		_, diag, err := parseConfigFileDiagnostics(parser, f)
+		if err != nil {
+			return nil, err
+		}
+
+		diags = append(diags, diag...)
+	}
+
+	return diags, nil
+}
+
+func parseVariables(abs string, c *Config) error {
+	files, err := configFiles(abs)
 	if err != nil {
 		return err
 	}
@@ -931,7 +1410,7 @@ func parseVariables(abs string, c *Config) error {
 }
 
 func parseOutputs(abs string, disabled bool, c *Config) error {
-	files, err := filepath.Glob(path.Join(abs, "*.hcl"))
+	files, err := configFiles(abs)
 	if err != nil {
 		return err
 	}
@@ -951,9 +1430,9 @@ func parseOutputFile(file string, disabled bool, c *Config) error {
 	ctx.Functions["file_path"] = getFilePathFunc(file)
 	ctx.Functions["file_dir"] = getFileDirFunc(file)
 
-	f, diag := parser.ParseHCLFile(file)
-	if diag.HasErrors() {
-		return errors.New(diag.Error())
+	f, err := parseConfigFile(parser, file)
+	if err != nil {
+		return err
 	}
 
 	body, ok := f.Body.(*hclsyntax.Body)
@@ -981,7 +1460,7 @@ func parseOutputFile(file string, disabled bool, c *Config) error {
 }
 
 func parseResources(abs string, c *Config, moduleName string, disabled bool, dependsOn []string) error {
-	files, err := filepath.Glob(path.Join(abs, "*.hcl"))
+	files, err := configFiles(abs)
 	if err != nil {
 		return err
 	}
@@ -1038,9 +1517,9 @@ func setContextVariableIfMissing(key string, value interface{}) {
 func parseYardHCL(file string, c *Config) error {
 	parser := hclparse.NewParser()
 
-	f, diag := parser.ParseHCLFile(file)
-	if diag.HasErrors() {
-		return errors.New(diag.Error())
+	f, err := parseConfigFile(parser, file)
+	if err != nil {
+		return err
 	}
 
 	body, ok := f.Body.(*hclsyntax.Body)
@@ -1106,6 +1585,15 @@ func parseYardMarkdown(file string, c *Config) error {
 		bp.ShipyardVersion = a
 	}
 
+	if mp, ok := fr["max_parallel"].(map[interface{}]interface{}); ok {
+		bp.MaxParallel = map[string]int{}
+		for k, v := range mp {
+			if n, ok := v.(int); ok {
+				bp.MaxParallel[fmt.Sprintf("%v", k)] = n
+			}
+		}
+	}
+
 	if envs, ok := fr["env"].([]interface{}); ok {
 		bp.Environment = []KV{}
 		for _, e := range envs {
@@ -1308,14 +1796,64 @@ func decodeBody(path string, b *hclsyntax.Block, p interface{}) error {
 	// build them from relative paths.
 	ctx.Variables["path"] = cty.StringVal(path)
 
+	// references to other resources are decoded as placeholders which
+	// are replaced by the engine once the referenced resource is created
+	refs, err := setResourceReferences(b.Body)
+	if err != nil {
+		return err
+	}
+
 	diag := gohcl.DecodeBody(b.Body, ctx, p)
 	if diag.HasErrors() {
 		return errors.New(diag.Error())
 	}
 
+	if r, ok := p.(Resource); ok {
+		r.Info().Variables = referencedVariables(b.Body)
+
+		// record where the resource is defined so errors can refer to it
+		r.Info().File = path
+		r.Info().Line = b.DefRange().Start.Line
+
+		// copy the dependencies as the slice can be shared by the resources in a module
+		r.Info().DependsOn = append(append([]string{}, r.Info().DependsOn...), refs...)
+	}
+
 	return nil
 }
 
+// referencedVariables returns the names of the variables used by
+// expressions in the body e.g. var.image_tag returns image_tag
+func referencedVariables(body *hclsyntax.Body) []string {
+	found := map[string]bool{}
+	vars := []string{}
+
+	hclsyntax.VisitAll(body, func(n hclsyntax.Node) hcl.Diagnostics {
+		ex, ok := n.(hclsyntax.Expression)
+		if !ok {
+			return nil
+		}
+
+		for _, t := range ex.Variables() {
+			if t.RootName() != "var" || len(t) < 2 {
+				continue
+			}
+
+			a, ok := t[1].(hcl.TraverseAttr)
+			if !ok || found[a.Name] {
+				continue
+			}
+
+			found[a.Name] = true
+			vars = append(vars, a.Name)
+		}
+
+		return nil
+	})
+
+	return vars
+}
+
 // ensureAbsolute ensure that the given path is either absolute or
 // if relative is converted to abasolute based on the path of the config
 func ensureAbsolute(path, file string) string {
@@ -1372,3 +1910,15 @@ func setDisabled(r Resource, parentDisabled bool) {
 		r.Info().Status = "disabled"
 	}
 }
+
+// appendUnique adds s to the list when it is not already in the list, the
+// image cache loaded from the state already depends on the existing resources
+func appendUnique(list []string, s string) []string {
+	for _, l := range list {
+		if l == s {
+			return list
+		}
+	}
+
+	return append(list, s)
+}
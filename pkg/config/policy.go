@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/hcl2/gohcl"
+	"github.com/hashicorp/hcl2/hclparse"
+)
+
+// Policy checks which can be used in a policy rule
+const (
+	// PolicyNoPrivileged denies containers which run in privileged mode
+	PolicyNoPrivileged = "no_privileged"
+	// PolicyMemoryLimit requires containers to set a memory limit
+	PolicyMemoryLimit = "memory_limit"
+	// PolicyAllowedRegistries requires container images to come from one of the registries in values
+	PolicyAllowedRegistries = "allowed_registries"
+)
+
+// Policy defines a set of rules which the resources in a blueprint
+// must satisfy e.g.
+//
+//   rule "approved_images" {
+//     check  = "allowed_registries"
+//     values = ["docker.io", "ghcr.io"]
+//   }
+type Policy struct {
+	Rules []PolicyRule `hcl:"rule,block"`
+}
+
+// PolicyRule is a single rule in a policy
+type PolicyRule struct {
+	Name string `hcl:"name,label"`
+	// Check is the type of check to perform [no_privileged, memory_limit, allowed_registries]
+	Check string `hcl:"check"`
+	// Values are the parameters for the check such as the list of registries
+	Values []string `hcl:"values,optional"`
+}
+
+// ParsePolicyFile parses a HCL policy file
+func ParsePolicyFile(file string) (*Policy, error) {
+	parser := hclparse.NewParser()
+
+	f, diag := parser.ParseHCLFile(file)
+	if diag.HasErrors() {
+		return nil, errors.New(diag.Error())
+	}
+
+	p := &Policy{}
+
+	diag = gohcl.DecodeBody(f.Body, nil, p)
+	if diag.HasErrors() {
+		return nil, errors.New(diag.Error())
+	}
+
+	for _, r := range p.Rules {
+		switch r.Check {
+		case PolicyNoPrivileged, PolicyMemoryLimit:
+		case PolicyAllowedRegistries:
+			if len(r.Values) == 0 {
+				return nil, fmt.Errorf("Error in file '%s': rule '%s' must define the allowed registries in values", file, r.Name)
+			}
+		default:
+			return nil, fmt.Errorf("Error in file '%s': rule '%s' has unknown check '%s'", file, r.Name, r.Check)
+		}
+	}
+
+	return p, nil
+}
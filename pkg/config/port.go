@@ -1,5 +1,11 @@
 package config
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // Port is a port mapping
 type Port struct {
 	Local         string `hcl:"local" json:"local"`                                                             // Local port in the container
@@ -14,4 +20,172 @@ type PortRange struct {
 	Range      string `hcl:"range" json:"local" mapstructure:"local"`                                      // Local port in the container
 	EnableHost bool   `hcl:"enable_host,optional" json:"enable_host,omitempty" mapstructure:"enable_host"` // Host port
 	Protocol   string `hcl:"protocol,optional" json:"protocol,omitempty"`                                  // Protocol tcp, udp
+	// HostRange maps the ports in Range to a different range of ports on the host, the
+	// ranges must be the same size. When set the ports are always published to the host.
+	HostRange string `hcl:"host_range,optional" json:"host_range,omitempty" mapstructure:"host_range"`
+}
+
+// Validate the PortRange and return errors
+func (p PortRange) Validate() error {
+	start, end, err := ParsePortRange(p.Range)
+	if err != nil {
+		return err
+	}
+
+	if p.HostRange == "" {
+		return nil
+	}
+
+	hostStart, hostEnd, err := ParsePortRange(p.HostRange)
+	if err != nil {
+		return err
+	}
+
+	if end-start != hostEnd-hostStart {
+		return fmt.Errorf("Invalid port range, host_range %s must be the same size as range %s", p.HostRange, p.Range)
+	}
+
+	return nil
+}
+
+// ParsePortRange returns the first and last port of a range written start-end, e.g 80-82
+func ParsePortRange(r string) (int, int, error) {
+	parts := strings.Split(r, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("Invalid port range, range should be written start-end, e.g 80-82")
+	}
+
+	// ensure the start is less than the end
+	start, serr := strconv.Atoi(parts[0])
+	end, eerr := strconv.Atoi(parts[1])
+
+	if serr != nil || eerr != nil {
+		return 0, 0, fmt.Errorf(
+			"Invalid port range, range should be numbers and written start-end, e.g 80-82",
+		)
+	}
+
+	if start > end {
+		return 0, 0, fmt.Errorf(
+			"Invalid port range, start and end ports should be numeric and written start-end, e.g 80-82",
+		)
+	}
+
+	return start, end, nil
+}
+
+// PortConflictError is returned when more than one resource publishes the same port on the host
+type PortConflictError struct {
+	Conflicts []string
+}
+
+func (p PortConflictError) Error() string {
+	return fmt.Sprintf("Host ports are published by more than one resource: %s", strings.Join(p.Conflicts, ", "))
+}
+
+// validateHostPorts checks that each port published on the host is only used by
+// a single enabled resource, all conflicts are returned as a PortConflictError
+func validateHostPorts(c *Config) error {
+	users := map[string][]string{}
+	ports := []string{}
+
+	for _, r := range c.Resources {
+		if r.Info().Status == Disabled {
+			continue
+		}
+
+		id := fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name)
+
+		for _, p := range hostPorts(r) {
+			if len(users[p]) == 0 {
+				ports = append(ports, p)
+			}
+
+			// a resource which publishes the same port twice is not a conflict between resources
+			if n := len(users[p]); n > 0 && users[p][n-1] == id {
+				continue
+			}
+
+			users[p] = append(users[p], id)
+		}
+	}
+
+	conflicts := []string{}
+	for _, p := range ports {
+		if len(users[p]) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("%s (%s)", p, strings.Join(users[p], ", ")))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return PortConflictError{Conflicts: conflicts}
+	}
+
+	return nil
+}
+
+// hostPorts returns the ports the resource publishes on the host in the form port/protocol
+func hostPorts(r Resource) []string {
+	var ps []Port
+	var prs []PortRange
+
+	switch v := r.(type) {
+	case *Container:
+		ps, prs = v.Ports, v.PortRanges
+	case *K8sCluster:
+		ps, prs = v.Ports, v.PortRanges
+	case *ContainerIngress:
+		ps = v.Ports
+	case *K8sIngress:
+		ps = v.Ports
+	case *NomadIngress:
+		ps = v.Ports
+	case *LegacyIngress:
+		ps = v.Ports
+	case *Docs:
+		lr := v.LiveReloadPort
+		if lr == 0 {
+			lr = DefaultLiveReloadPort
+		}
+
+		ps = []Port{{Host: strconv.Itoa(v.Port)}, {Host: strconv.Itoa(lr)}}
+	}
+
+	out := []string{}
+	for _, p := range ps {
+		// ports without a host port are not published
+		if p.Host != "" {
+			out = append(out, hostPort(p.Host, p.Protocol))
+		}
+	}
+
+	for _, pr := range prs {
+		if !pr.EnableHost && pr.HostRange == "" {
+			continue
+		}
+
+		r := pr.Range
+		if pr.HostRange != "" {
+			r = pr.HostRange
+		}
+
+		start, end, err := ParsePortRange(r)
+		if err != nil {
+			continue
+		}
+
+		for i := start; i <= end; i++ {
+			out = append(out, hostPort(strconv.Itoa(i), pr.Protocol))
+		}
+	}
+
+	return out
+}
+
+func hostPort(port, protocol string) string {
+	if protocol == "" {
+		protocol = "tcp"
+	}
+
+	return fmt.Sprintf("%s/%s", port, protocol)
 }
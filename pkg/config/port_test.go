@@ -0,0 +1,152 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateHostPortsReturnsConflicts(t *testing.T) {
+	dir, cleanup := createTestFiles(t, portsConflicting)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.NoError(t, err)
+
+	err = ParseReferences(c)
+	assert.Error(t, err)
+
+	pe, ok := err.(PortConflictError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"8500/tcp (container.one, container.two)",
+		"9001/tcp (container.two, k8s_cluster.k3s)",
+	}, pe.Conflicts)
+}
+
+func TestValidateHostPortsIgnoresDisabledResources(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, portsConflictingDisabled)
+	defer cleanup()
+
+	_, err := c.FindResource("container.two")
+	assert.NoError(t, err)
+}
+
+func TestValidateHostPortsReturnsConflictsWithDocs(t *testing.T) {
+	dir, cleanup := createTestFiles(t, portsConflictingDocs)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.NoError(t, err)
+
+	err = ParseReferences(c)
+	assert.Error(t, err)
+
+	pe, ok := err.(PortConflictError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"8080/tcp (container.one, docs.docs)",
+		"37950/tcp (container.one, docs.docs)",
+	}, pe.Conflicts)
+}
+
+const portsConflicting = `
+container "one" {
+	image {
+		name = "consul"
+	}
+
+	port {
+		local = 8500
+		host  = 8500
+	}
+
+	// udp does not conflict with tcp
+	port {
+		local    = 8600
+		host     = 8600
+		protocol = "udp"
+	}
+}
+
+container "two" {
+	image {
+		name = "consul"
+	}
+
+	port {
+		local = 8500
+		host  = 8500
+	}
+
+	port {
+		local = 8600
+		host  = 8600
+	}
+
+	port_range {
+		range      = "9000-9002"
+		host_range = "9001-9003"
+	}
+}
+
+k8s_cluster "k3s" {
+	driver = "k3s"
+
+	port_range {
+		range       = "8999-9001"
+		enable_host = true
+	}
+}
+`
+
+const portsConflictingDisabled = `
+container "one" {
+	image {
+		name = "consul"
+	}
+
+	port {
+		local = 8500
+		host  = 8500
+	}
+}
+
+container "two" {
+	disabled = true
+
+	image {
+		name = "consul"
+	}
+
+	port {
+		local = 8500
+		host  = 8500
+	}
+}
+`
+
+const portsConflictingDocs = `
+container "one" {
+	image {
+		name = "consul"
+	}
+
+	port {
+		local = 8080
+		host  = 8080
+	}
+
+	port {
+		local = 37950
+		host  = 37950
+	}
+}
+
+docs "docs" {
+	path = "./docs"
+	port = 8080
+}
+`
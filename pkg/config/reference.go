@@ -0,0 +1,228 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/xerrors"
+)
+
+// referenceAttributes are the attributes of a resource which are set when the resource is
+// created and can be referenced by other resources e.g. resource.exec_local.setup.output
+var referenceAttributes = map[ResourceType][]string{
+	TypeExecLocal:  {"output"},
+	TypeExecRemote: {"output"},
+}
+
+// referenceRegex matches the placeholders for references which are set when the
+// config is parsed, the placeholders are replaced once the referenced resource exists
+var referenceRegex = regexp.MustCompile(`\$\{resource\.([a-z0-9_]+)\.([^.}]+)\.([a-z0-9_]+)\}`)
+
+func referencePlaceholder(t, name, attribute string) string {
+	return fmt.Sprintf("${resource.%s.%s.%s}", t, name, attribute)
+}
+
+// setResourceReferences adds a placeholder to the context for every resource attribute
+// referenced by expressions in the body and returns the ids of the referenced resources
+func setResourceReferences(body *hclsyntax.Body) ([]string, error) {
+	values := map[string]map[string]map[string]cty.Value{}
+	ids := []string{}
+	var err error
+
+	hclsyntax.VisitAll(body, func(n hclsyntax.Node) hcl.Diagnostics {
+		ex, ok := n.(hclsyntax.Expression)
+		if !ok || err != nil {
+			return nil
+		}
+
+		for _, t := range ex.Variables() {
+			if t.RootName() != "resource" {
+				continue
+			}
+
+			parts := []string{}
+			for _, tr := range t[1:] {
+				if a, ok := tr.(hcl.TraverseAttr); ok {
+					parts = append(parts, a.Name)
+				}
+			}
+
+			if len(parts) != 3 || !isReferenceAttribute(ResourceType(parts[0]), parts[2]) {
+				err = fmt.Errorf("%s is not a valid reference, only the output of exec_local and exec_remote resources can be referenced e.g. resource.exec_local.name.output", traversalString(t))
+				return nil
+			}
+
+			if values[parts[0]] == nil {
+				values[parts[0]] = map[string]map[string]cty.Value{}
+			}
+
+			if values[parts[0]][parts[1]] == nil {
+				values[parts[0]][parts[1]] = map[string]cty.Value{}
+			}
+
+			values[parts[0]][parts[1]][parts[2]] = cty.StringVal(referencePlaceholder(parts[0], parts[1], parts[2]))
+			ids = appendUnique(ids, fmt.Sprintf("%s.%s", parts[0], parts[1]))
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	types := map[string]cty.Value{}
+	for t, names := range values {
+		resources := map[string]cty.Value{}
+		for n, attributes := range names {
+			resources[n] = cty.ObjectVal(attributes)
+		}
+
+		types[t] = cty.ObjectVal(resources)
+	}
+
+	ctx.Variables["resource"] = cty.ObjectVal(types)
+
+	return ids, nil
+}
+
+func isReferenceAttribute(t ResourceType, attribute string) bool {
+	for _, a := range referenceAttributes[t] {
+		if a == attribute {
+			return true
+		}
+	}
+
+	return false
+}
+
+// traversalString returns the text of a traversal e.g. resource.exec_local.setup.output
+func traversalString(t hcl.Traversal) string {
+	parts := []string{t.RootName()}
+	for _, tr := range t[1:] {
+		if a, ok := tr.(hcl.TraverseAttr); ok {
+			parts = append(parts, a.Name)
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// ResolveReferences replaces the placeholders for references to the attributes of other
+// resources with their current values. Referenced resources are dependencies of r so
+// they must be created before references are resolved.
+func ResolveReferences(r Resource) error {
+	return walkStrings(reflect.ValueOf(r), func(s string) (string, error) {
+		if !strings.Contains(s, "${resource.") {
+			return s, nil
+		}
+
+		var err error
+		s = referenceRegex.ReplaceAllStringFunc(s, func(m string) string {
+			parts := referenceRegex.FindStringSubmatch(m)
+
+			v, rerr := referenceValue(r, parts[1], parts[2], parts[3])
+			if rerr != nil {
+				err = rerr
+				return m
+			}
+
+			return v
+		})
+
+		return s, err
+	})
+}
+
+// referenceValue returns the value of the field with the json name attribute
+// for the resource with the given type and name
+func referenceValue(r Resource, t, name, attribute string) (string, error) {
+	if r.Info().Config == nil {
+		return "", xerrors.Errorf("unable to resolve reference resource.%s.%s.%s, resource is not part of a config", t, name, attribute)
+	}
+
+	dr, err := r.Info().Config.FindResource(fmt.Sprintf("%s.%s", t, name))
+	if err != nil {
+		return "", xerrors.Errorf("unable to resolve reference resource.%s.%s.%s: %w", t, name, attribute, err)
+	}
+
+	v := reflect.ValueOf(dr).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		tag := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		if tag == attribute && v.Field(i).Kind() == reflect.String {
+			return v.Field(i).String(), nil
+		}
+	}
+
+	return "", xerrors.Errorf("unable to resolve reference resource.%s.%s.%s, resource does not have the attribute %s", t, name, attribute, attribute)
+}
+
+// walkStrings calls f for every string value in the exported fields of v replacing
+// the value with the result. Strings, and the values of string maps are supported.
+func walkStrings(v reflect.Value, f func(string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		// do not walk back up to the parent config
+		if v.IsNil() || v.Type() == reflect.TypeOf(&Config{}) {
+			return nil
+		}
+
+		return walkStrings(v.Elem(), f)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+
+			// the resource info contains the name and type which are never references
+			if field.PkgPath != "" || field.Type == reflect.TypeOf(ResourceInfo{}) {
+				continue
+			}
+
+			err := walkStrings(v.Field(i), f)
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			err := walkStrings(v.Index(i), f)
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+
+		for _, k := range v.MapKeys() {
+			s, err := f(v.MapIndex(k).String())
+			if err != nil {
+				return err
+			}
+
+			v.SetMapIndex(k, reflect.ValueOf(s).Convert(v.Type().Elem()))
+		}
+
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+
+		s, err := f(v.String())
+		if err != nil {
+			return err
+		}
+
+		v.SetString(s)
+	}
+
+	return nil
+}
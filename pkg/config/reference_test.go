@@ -0,0 +1,90 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenceToExecOutputAddsDependency(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, execOutputReference)
+	defer cleanup()
+
+	co, err := c.FindResource("container.consul")
+	require.NoError(t, err)
+
+	assert.Contains(t, co.Info().DependsOn, "exec_local.token")
+	assert.Equal(t, "${resource.exec_local.token.output}", co.(*Container).EnvVar["TOKEN"])
+	assert.Equal(t, "token=${resource.exec_local.token.output}", co.(*Container).Command[0])
+}
+
+func TestReferenceToUnsupportedAttributeReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t, execOutputInvalidReference)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "resource.container.consul.image is not a valid reference")
+}
+
+func TestResolveReferencesReplacesExecOutput(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, execOutputReference)
+	defer cleanup()
+
+	ex, err := c.FindResource("exec_local.token")
+	require.NoError(t, err)
+	ex.(*ExecLocal).Output = "abc123"
+
+	co, err := c.FindResource("container.consul")
+	require.NoError(t, err)
+
+	err = ResolveReferences(co)
+	require.NoError(t, err)
+
+	assert.Equal(t, "abc123", co.(*Container).EnvVar["TOKEN"])
+	assert.Equal(t, "token=abc123", co.(*Container).Command[0])
+}
+
+func TestResolveReferencesReturnsErrorWhenResourceMissing(t *testing.T) {
+	co := NewContainer("consul")
+	co.Command = []string{"${resource.exec_local.missing.output}"}
+
+	c := New()
+	c.AddResource(co)
+
+	err := ResolveReferences(co)
+	assert.Error(t, err)
+}
+
+const execOutputReference = `
+exec_local "token" {
+  cmd = "./scripts/token.sh"
+}
+
+container "consul" {
+  image {
+    name = "consul:1.6.1"
+  }
+
+  command = ["token=${resource.exec_local.token.output}"]
+
+  env_var = {
+    TOKEN = resource.exec_local.token.output
+  }
+}
+`
+
+const execOutputInvalidReference = `
+container "consul" {
+  image {
+    name = "consul:1.6.1"
+  }
+}
+
+exec_local "token" {
+  cmd = "./scripts/token.sh"
+  args = [resource.container.consul.image]
+}
+`
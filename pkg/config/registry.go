@@ -0,0 +1,78 @@
+package config
+
+import "fmt"
+
+// TypeRegistry is the resource string for a Registry resource
+const TypeRegistry ResourceType = "registry"
+
+// Registry adds a private container registry to the image cache, images
+// pulled from the registry through the cache use the given credentials
+type Registry struct {
+	ResourceInfo `hcl:",remain" mapstructure:",squash"`
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Hostname of the registry e.g. registry.example.com
+	Hostname string `hcl:"hostname" json:"hostname"`
+
+	// Auth defines the credentials used to pull images from the registry
+	Auth *RegistryAuth `hcl:"auth,block" json:"auth,omitempty"`
+}
+
+// RegistryAuth defines the credentials for a private registry, either a
+// password or a token can be set
+type RegistryAuth struct {
+	Username string `hcl:"username,optional" json:"username,omitempty"`
+	Password string `hcl:"password,optional" json:"password,omitempty" sensitive:"true"`
+	// Token is an access token used in place of the password
+	Token string `hcl:"token,optional" json:"token,omitempty" sensitive:"true"`
+}
+
+// NewRegistry creates a new Registry resource with the correct defaults
+func NewRegistry(name string) *Registry {
+	return &Registry{ResourceInfo: ResourceInfo{Name: name, Type: TypeRegistry, Status: PendingCreation}}
+}
+
+// Validate the config
+func (r *Registry) Validate() error {
+	if r.Hostname == "" {
+		return fmt.Errorf("hostname must be set")
+	}
+
+	if r.Auth == nil {
+		return nil
+	}
+
+	if r.Auth.Password != "" && r.Auth.Token != "" {
+		return fmt.Errorf("only one of password or token can be set for auth")
+	}
+
+	if r.Auth.Password != "" && r.Auth.Username == "" {
+		return fmt.Errorf("username must be set when using password auth")
+	}
+
+	if r.Auth.Password == "" && r.Auth.Token == "" {
+		return fmt.Errorf("auth requires either password or token to be set")
+	}
+
+	return nil
+}
+
+// Credentials returns the username and password used to authenticate with the
+// registry, tokens are passed as the password
+func (r *Registry) Credentials() (string, string) {
+	if r.Auth == nil {
+		return "", ""
+	}
+
+	if r.Auth.Token != "" {
+		user := r.Auth.Username
+		if user == "" {
+			user = "token"
+		}
+
+		return user, r.Auth.Token
+	}
+
+	return r.Auth.Username, r.Auth.Password
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCreatesRegistry(t *testing.T) {
+	r := NewRegistry("abc")
+
+	assert.Equal(t, "abc", r.Name)
+	assert.Equal(t, TypeRegistry, r.Type)
+}
+
+func TestRegistryCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, registryValid)
+	defer cleanup()
+
+	r, err := c.FindResource("registry.private")
+	assert.NoError(t, err)
+
+	reg := r.(*Registry)
+	assert.Equal(t, "registry.example.com", reg.Hostname)
+	assert.Equal(t, "nic", reg.Auth.Username)
+	assert.Equal(t, "s3cr3t", reg.Auth.Password)
+}
+
+func TestRegistryIsAddedToImageCacheOnce(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.hcl", registryValid)
+
+	// the image cache from the state already depends on the registry
+	c := New()
+	ic := NewImageCache("docker-cache")
+	ic.DependsOn = []string{"registry.private"}
+	c.AddResource(ic)
+
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"registry.private"}, ic.DependsOn)
+}
+
+func TestRegistryValidateReturnsErrorWithPasswordAndToken(t *testing.T) {
+	r := NewRegistry("abc")
+	r.Hostname = "registry.example.com"
+	r.Auth = &RegistryAuth{Username: "nic", Password: "abc", Token: "123"}
+
+	assert.Error(t, r.Validate())
+}
+
+func TestRegistryValidateReturnsErrorWithPasswordAndNoUsername(t *testing.T) {
+	r := NewRegistry("abc")
+	r.Hostname = "registry.example.com"
+	r.Auth = &RegistryAuth{Password: "abc"}
+
+	assert.Error(t, r.Validate())
+}
+
+func TestRegistryCredentialsReturnsToken(t *testing.T) {
+	r := NewRegistry("abc")
+	r.Auth = &RegistryAuth{Token: "123"}
+
+	u, p := r.Credentials()
+	assert.Equal(t, "token", u)
+	assert.Equal(t, "123", p)
+}
+
+func TestRegistryCredentialsAreRedacted(t *testing.T) {
+	c := New()
+
+	r := NewRegistry("abc")
+	r.Auth = &RegistryAuth{Username: "nic", Password: "s3cr3t"}
+	c.AddResource(r)
+
+	rc, err := c.Redacted()
+	assert.NoError(t, err)
+
+	rr, err := rc.FindResource("registry.abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "nic", rr.(*Registry).Auth.Username)
+	assert.Equal(t, redactedValue, rr.(*Registry).Auth.Password)
+
+	// the original is not modified
+	assert.Equal(t, "s3cr3t", r.Auth.Password)
+}
+
+func TestRegistryCredentialsAreRedactedForResource(t *testing.T) {
+	r := NewRegistry("abc")
+	r.Auth = &RegistryAuth{Username: "nic", Password: "s3cr3t"}
+
+	rr, err := RedactedResource(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "nic", rr.(*Registry).Auth.Username)
+	assert.Equal(t, redactedValue, rr.(*Registry).Auth.Password)
+
+	// the original is not modified
+	assert.Equal(t, "s3cr3t", r.Auth.Password)
+}
+
+const registryValid = `
+registry "private" {
+  hostname = "registry.example.com"
+
+  auth {
+    username = "nic"
+    password = "s3cr3t"
+  }
+}
+`
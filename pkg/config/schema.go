@@ -0,0 +1,154 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// schemaTypes defines the resource types which can be written in a blueprint
+// and are included in the generated JSON Schema.
+// New resource types should be added to this collection.
+var schemaTypes = map[ResourceType]interface{}{
+	TypeAssert:           Assert{},
+	TypeContainer:        Container{},
+	TypeContainerIngress: ContainerIngress{},
+	TypeCopy:             Copy{},
+	TypeDockerConfig:     DockerConfig{},
+	TypeDocs:             Docs{},
+	TypeExecLocal:        ExecLocal{},
+	TypeExecRemote:       ExecRemote{},
+	TypeExternal:         External{},
+	TypeHelm:             Helm{},
+	TypeImport:           Import{},
+	TypeIngress:          Ingress{},
+	TypeK8sCluster:       K8sCluster{},
+	TypeK8sConfig:        K8sConfig{},
+	TypeK8sIngress:       K8sIngress{},
+	TypeK8sWait:          K8sWait{},
+	TypeModule:           Module{},
+	TypeNetwork:          Network{},
+	TypeNomadCluster:     NomadCluster{},
+	TypeNomadIngress:     NomadIngress{},
+	TypeNomadJob:         NomadJob{},
+	TypeOutput:           Output{},
+	TypeRegistry:         Registry{},
+	TypeSecret:           Secret{},
+	TypeSidecar:          Sidecar{},
+	TypeSSHKey:           SSHKey{},
+	TypeTemplate:         Template{},
+	TypeVariable:         Variable{},
+}
+
+// GenerateSchema returns a JSON Schema describing all the resource types
+// and their fields which can be used in a blueprint.
+// The schema is generated from the hcl tags on the resource types and
+// follows the JSON representation of HCL where a block is an object keyed
+// by the block type then the block label, e.g. {"container": {"consul": {...}}}
+func GenerateSchema() ([]byte, error) {
+	types := []string{}
+	for t := range schemaTypes {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+
+	props := map[string]interface{}{}
+	for _, t := range types {
+		props[t] = map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": schemaForStruct(reflect.TypeOf(schemaTypes[ResourceType(t)])),
+		}
+	}
+
+	schema := map[string]interface{}{
+		"$schema":              "http://json-schema.org/draft-07/schema#",
+		"title":                "Shipyard Blueprint",
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaForStruct returns the schema for a struct using the hcl field tags
+func schemaForStruct(t reflect.Type) map[string]interface{} {
+	props := map[string]interface{}{}
+	required := []string{}
+
+	addStructFields(t, props, &required)
+
+	s := map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+
+	return s
+}
+
+func addStructFields(t reflect.Type, props map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag, ok := f.Tag.Lookup("hcl")
+		if !ok {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		kind := ""
+		if len(parts) > 1 {
+			kind = parts[1]
+		}
+
+		// embedded types such as ResourceInfo add their fields to the parent
+		if kind == "remain" {
+			if f.Type.Kind() == reflect.Struct {
+				addStructFields(f.Type, props, required)
+			}
+
+			continue
+		}
+
+		props[name] = schemaForType(f.Type)
+
+		// optional attributes, repeated blocks, and pointer blocks are not required
+		if kind == "" || (kind == "block" && f.Type.Kind() == reflect.Struct) {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaForType returns the schema for a go type
+func schemaForType(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaForType(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaForType(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaForType(t.Elem())}
+	case reflect.Struct:
+		return schemaForStruct(t)
+	}
+
+	// interface types can hold any value
+	return map[string]interface{}{}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateSchemaReturnsValidJSON(t *testing.T) {
+	s, err := GenerateSchema()
+	assert.NoError(t, err)
+
+	out := map[string]interface{}{}
+	err = json.Unmarshal(s, &out)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "object", out["type"])
+}
+
+func TestGenerateSchemaContainsResourceTypes(t *testing.T) {
+	s, err := GenerateSchema()
+	assert.NoError(t, err)
+
+	out := map[string]interface{}{}
+	json.Unmarshal(s, &out)
+
+	props := out["properties"].(map[string]interface{})
+	for k := range schemaTypes {
+		assert.Contains(t, props, string(k))
+	}
+}
+
+func TestGenerateSchemaContainsResourceFields(t *testing.T) {
+	s, err := GenerateSchema()
+	assert.NoError(t, err)
+
+	out := map[string]interface{}{}
+	json.Unmarshal(s, &out)
+
+	props := out["properties"].(map[string]interface{})
+	co := props["container"].(map[string]interface{})["additionalProperties"].(map[string]interface{})
+	fields := co["properties"].(map[string]interface{})
+
+	assert.Equal(t, "array", fields["network"].(map[string]interface{})["type"])
+	assert.Equal(t, "string", fields["network_mode"].(map[string]interface{})["type"])
+	assert.Equal(t, "boolean", fields["disabled"].(map[string]interface{})["type"])
+	assert.Equal(t, "object", fields["image"].(map[string]interface{})["type"])
+}
+
+func TestGenerateSchemaSetsRequiredFields(t *testing.T) {
+	s, err := GenerateSchema()
+	assert.NoError(t, err)
+
+	out := map[string]interface{}{}
+	json.Unmarshal(s, &out)
+
+	props := out["properties"].(map[string]interface{})
+	ne := props["network"].(map[string]interface{})["additionalProperties"].(map[string]interface{})
+
+	assert.Contains(t, ne["required"], "subnet")
+}
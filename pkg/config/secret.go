@@ -0,0 +1,84 @@
+package config
+
+import "fmt"
+
+// TypeSecret is the resource string for a Secret resource
+const TypeSecret ResourceType = "secret"
+
+// TypeDockerConfig is the resource string for a DockerConfig resource
+const TypeDockerConfig ResourceType = "config"
+
+// Secret writes sensitive data to a file which can be mounted into containers,
+// the file is only readable by its owner and the value is never written to the state
+type Secret struct {
+	ResourceInfo `hcl:",remain" mapstructure:",squash"`
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Value is the secret data, either value or source must be set
+	Value string `hcl:"value,optional" json:"-"`
+
+	// Source is a file containing the secret data
+	Source string `hcl:"source,optional" json:"source,omitempty"`
+
+	// Path is the location of the file written by the provider
+	Path string `json:"path,omitempty" state:"true"`
+}
+
+// NewSecret creates a new Secret resource with the correct defaults
+func NewSecret(name string) *Secret {
+	return &Secret{ResourceInfo: ResourceInfo{Name: name, Type: TypeSecret, Status: PendingCreation}}
+}
+
+// Validate the config
+func (s *Secret) Validate() error {
+	return validateFileData(s.Value, s.Source)
+}
+
+// DockerConfig writes non sensitive configuration to a file which can be mounted
+// into containers
+type DockerConfig struct {
+	ResourceInfo `hcl:",remain" mapstructure:",squash"`
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Value is the configuration data, either value or source must be set
+	Value string `hcl:"value,optional" json:"value,omitempty"`
+
+	// Source is a file containing the configuration data
+	Source string `hcl:"source,optional" json:"source,omitempty"`
+
+	// Path is the location of the file written by the provider
+	Path string `json:"path,omitempty" state:"true"`
+}
+
+// NewDockerConfig creates a new DockerConfig resource with the correct defaults
+func NewDockerConfig(name string) *DockerConfig {
+	return &DockerConfig{ResourceInfo: ResourceInfo{Name: name, Type: TypeDockerConfig, Status: PendingCreation}}
+}
+
+// Validate the config
+func (d *DockerConfig) Validate() error {
+	return validateFileData(d.Value, d.Source)
+}
+
+func validateFileData(value, source string) error {
+	if value == "" && source == "" {
+		return fmt.Errorf("either value or source must be specified")
+	}
+
+	if value != "" && source != "" {
+		return fmt.Errorf("only one of value or source can be specified")
+	}
+
+	return nil
+}
+
+// FileMount mounts the file for a Secret or DockerConfig resource into a container
+type FileMount struct {
+	// Name of the resource to mount e.g. secret.db_password
+	Name string `hcl:"name" json:"name"`
+
+	// Target is the path of the file inside the container e.g. /run/secrets/db_password
+	Target string `hcl:"target" json:"target"`
+}
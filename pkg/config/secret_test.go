@@ -0,0 +1,88 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCreatesSecret(t *testing.T) {
+	c := NewSecret("abc")
+
+	assert.Equal(t, "abc", c.Name)
+	assert.Equal(t, TypeSecret, c.Type)
+}
+
+func TestNewCreatesDockerConfig(t *testing.T) {
+	c := NewDockerConfig("abc")
+
+	assert.Equal(t, "abc", c.Name)
+	assert.Equal(t, TypeDockerConfig, c.Type)
+}
+
+func TestSecretCreatesCorrectly(t *testing.T) {
+	c, base, cleanup := setupTestConfig(t, secretValid)
+	defer cleanup()
+
+	r, err := c.FindResource("secret.db")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", r.(*Secret).Value)
+
+	r, err = c.FindResource("config.app")
+	assert.NoError(t, err)
+	assert.Contains(t, r.(*DockerConfig).Source, base)
+
+	r, err = c.FindResource("container.app")
+	assert.NoError(t, err)
+
+	co := r.(*Container)
+	assert.Equal(t, "/run/secrets/db", co.Secrets[0].Target)
+	assert.Equal(t, "/etc/app/config.json", co.Configs[0].Target)
+	assert.Contains(t, co.DependsOn, "secret.db")
+	assert.Contains(t, co.DependsOn, "config.app")
+}
+
+func TestSecretValueIsNotSerialized(t *testing.T) {
+	s := NewSecret("db")
+	s.Value = "s3cr3t"
+
+	d, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(d), "s3cr3t")
+}
+
+func TestSecretWithoutValueOrSourceIsInvalid(t *testing.T) {
+	s := NewSecret("db")
+	assert.Error(t, s.Validate())
+
+	s.Value = "abc"
+	s.Source = "./file"
+	assert.Error(t, s.Validate())
+}
+
+var secretValid = `
+secret "db" {
+  value = "s3cr3t"
+}
+
+config "app" {
+  source = "./config.json"
+}
+
+container "app" {
+  image {
+    name = "nicholasjackson/fake-service:v0.9.0"
+  }
+
+  secret {
+    name   = "secret.db"
+    target = "/run/secrets/db"
+  }
+
+  config {
+    name   = "config.app"
+    target = "/etc/app/config.json"
+  }
+}
+`
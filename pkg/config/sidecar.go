@@ -12,12 +12,12 @@ type Sidecar struct {
 
 	Target string `hcl:"target" json:"target"`
 
-	Image       Image             `hcl:"image,block" json:"image"`                                         // image to use for the container
-	Entrypoint  []string          `hcl:"entrypoint,optional" json:"entrypoint,omitempty"`                  // entrypoint to use when starting the container
-	Command     []string          `hcl:"command,optional" json:"command,omitempty"`                        // command to use when starting the container
-	Environment []KV              `hcl:"env,block" json:"environment,omitempty" mapstructure:"env"`        // environment variables to set when starting the container
-	EnvVar      map[string]string `hcl:"env_var,optional" json:"env_var,omitempty" mapstructure:"env_var"` // environment variables to set when starting the container
-	Volumes     []Volume          `hcl:"volume,block" json:"volumes,omitempty"`                            // volumes to attach to the container
+	Image       Image             `hcl:"image,block" json:"image"`                                                          // image to use for the container
+	Entrypoint  []string          `hcl:"entrypoint,optional" json:"entrypoint,omitempty"`                                   // entrypoint to use when starting the container
+	Command     []string          `hcl:"command,optional" json:"command,omitempty"`                                         // command to use when starting the container
+	Environment []KV              `hcl:"env,block" json:"environment,omitempty" mapstructure:"env"`                         // environment variables to set when starting the container
+	EnvVar      map[string]string `hcl:"env_var,optional" json:"env_var,omitempty" mapstructure:"env_var" sensitive:"true"` // environment variables to set when starting the container
+	Volumes     []Volume          `hcl:"volume,block" json:"volumes,omitempty"`                                             // volumes to attach to the container
 
 	Privileged bool `hcl:"privileged,optional" json:"privileged,omitempty"` // run the container in privileged mode?
 
@@ -28,9 +28,28 @@ type Sidecar struct {
 	HealthCheck *HealthCheck `hcl:"health_check,block" json:"health_check,omitempty" mapstructure:"health_check"`
 
 	MaxRestartCount int `hcl:"max_restart_count,optional" json:"max_restart_count,omitempty" mapstructure:"max_restart_count"`
+
+	// StopTimeout is the time the container is given to stop gracefully when it is destroyed
+	// before it is killed e.g. 60s, defaults to 30s
+	StopTimeout string `hcl:"stop_timeout,optional" json:"stop_timeout,omitempty" mapstructure:"stop_timeout"`
 }
 
 // NewSidecar returns a new Container resource with the correct default options
 func NewSidecar(name string) *Sidecar {
 	return &Sidecar{ResourceInfo: ResourceInfo{Name: name, Type: TypeSidecar, Status: PendingCreation}}
 }
+
+// Validate the sidecar configuration
+func (s *Sidecar) Validate() error {
+	err := s.Image.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = validateVolumes(s.Volumes)
+	if err != nil {
+		return err
+	}
+
+	return validateStopTimeout(s.StopTimeout)
+}
@@ -35,6 +35,21 @@ func TestSidecarSetsDisabled(t *testing.T) {
 	assert.Equal(t, Disabled, cl.Info().Status)
 }
 
+func TestSidecarMakesBindVolumesAbsolute(t *testing.T) {
+	c, base, cleanup := setupTestConfig(t, sidecarVolumes)
+	defer cleanup()
+
+	cl, err := c.FindResource("sidecar.test")
+	assert.NoError(t, err)
+
+	vols := cl.(*Sidecar).Volumes
+	assert.Contains(t, vols[0].Source, base)
+	assert.True(t, vols[0].ReadOnly)
+
+	// named volumes are not paths and are left unchanged
+	assert.Equal(t, "data", vols[1].Source)
+}
+
 const sidecarDefault = `
 sidecar "test" {
 	target = "container.test"
@@ -53,3 +68,24 @@ sidecar "test" {
 	}
 }
 `
+
+const sidecarVolumes = `
+sidecar "test" {
+	target = "container.test"
+	image {
+		name = "consul"
+	}
+
+	volume {
+		source      = "./config"
+		destination = "/config"
+		read_only   = true
+	}
+
+	volume {
+		source      = "data"
+		destination = "/data"
+		type        = "volume"
+	}
+}
+`
@@ -0,0 +1,34 @@
+package config
+
+// TypeSSHKey is the resource string for a SSHKey resource
+const TypeSSHKey ResourceType = "ssh_key"
+
+// SSHKey generates a SSH keypair, the public key can be added to the
+// authorized_keys file in containers to allow SSH access
+type SSHKey struct {
+	ResourceInfo `hcl:",remain" mapstructure:",squash"`
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Output is the folder to write the keys to, the private key is written to
+	// id_[name] and the public key to id_[name].pub. Defaults to the Shipyard data folder
+	Output string `hcl:"output,optional" json:"output,omitempty"`
+
+	// Containers to add the public key to e.g. container.bastion
+	Containers []string `hcl:"containers,optional" json:"containers,omitempty"`
+
+	// AuthorizedKeys is the path of the authorized_keys file in the containers,
+	// defaults to /root/.ssh/authorized_keys
+	AuthorizedKeys string `hcl:"authorized_keys,optional" json:"authorized_keys,omitempty" mapstructure:"authorized_keys"`
+
+	// PrivateKeyPath is the location of the generated private key
+	PrivateKeyPath string `json:"private_key_path,omitempty" mapstructure:"private_key_path" state:"true"`
+
+	// PublicKeyPath is the location of the generated public key
+	PublicKeyPath string `json:"public_key_path,omitempty" mapstructure:"public_key_path" state:"true"`
+}
+
+// NewSSHKey creates a SSHKey resource with the default values
+func NewSSHKey(name string) *SSHKey {
+	return &SSHKey{ResourceInfo: ResourceInfo{Name: name, Type: TypeSSHKey, Status: PendingCreation}}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCreatesSSHKey(t *testing.T) {
+	c := NewSSHKey("abc")
+
+	assert.Equal(t, "abc", c.Name)
+	assert.Equal(t, TypeSSHKey, c.Type)
+}
+
+func TestSSHKeyCreatesCorrectly(t *testing.T) {
+	c, base, cleanup := setupTestConfig(t, sshKeyValid)
+	defer cleanup()
+
+	cc, err := c.FindResource("ssh_key.demo")
+	assert.NoError(t, err)
+
+	assert.Equal(t, PendingCreation, cc.Info().Status)
+
+	k := cc.(*SSHKey)
+	assert.Equal(t, []string{"container.bastion"}, k.Containers)
+	assert.Contains(t, k.Output, base)
+	assert.Contains(t, k.DependsOn, "container.bastion")
+}
+
+var sshKeyValid = `
+container "bastion" {
+  image {
+    name = "linuxserver/openssh-server"
+  }
+}
+
+ssh_key "demo" {
+  output     = "./keys"
+  containers = ["container.bastion"]
+}
+`
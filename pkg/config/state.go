@@ -4,19 +4,31 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 
 	"github.com/mitchellh/mapstructure"
-	"github.com/shipyard-run/shipyard/pkg/utils"
 )
 
 var StateNotFoundError = fmt.Errorf("State file not found")
 
 // ToJSON saves the config in JSON format to the specified path
 // returns an error if the config can not be saved.
+// When SHIPYARD_STATE_KEY is set the values of fields tagged sensitive are
+// encrypted.
 func (c *Config) ToJSON(path string) error {
-	sd := utils.StateDir()
-	sp := utils.StatePath()
+	sd := filepath.Dir(path)
+	sp := path
+
+	out := c
+	if key := stateKey(); key != nil {
+		ec, err := c.encryptedCopy(key)
+		if err != nil {
+			return err
+		}
+
+		out = ec
+	}
 
 	// if it does not exist create the state folder
 	_, err := os.Stat(sd)
@@ -24,25 +36,29 @@ func (c *Config) ToJSON(path string) error {
 		os.MkdirAll(sd, os.ModePerm)
 	}
 
-	// if the statefile exists overwrite it
-	_, err = os.Stat(sp)
-	if err == nil {
-		// delete the old state
-		os.Remove(sp)
+	// serialize the state to json and write to a temporary file, the
+	// temporary file replaces the statefile once it has been written so
+	// that an interrupted write never leaves a partial state
+	tmp := sp + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
 	}
 
-	// serialize the state to json and write to a file
-	f, err := os.Create(sp)
+	ne := json.NewEncoder(f)
+	err = ne.Encode(out)
+	f.Close()
+
 	if err != nil {
+		os.Remove(tmp)
 		return err
 	}
-	defer f.Close()
 
-	ne := json.NewEncoder(f)
-	return ne.Encode(c)
+	return os.Rename(tmp, sp)
 }
 
-// FromJSON attempts to rehydrate the config from a JSON formatted statefile
+// FromJSON attempts to rehydrate the config from a JSON formatted statefile,
+// encrypted values are decrypted using the key in SHIPYARD_STATE_KEY
 func (c *Config) FromJSON(path string) error {
 	// it is fine that the state might not exist
 	f, err := os.Open(path)
@@ -52,7 +68,61 @@ func (c *Config) FromJSON(path string) error {
 	defer f.Close()
 
 	jd := json.NewDecoder(f)
-	return jd.Decode(c)
+	err = jd.Decode(c)
+	if err != nil {
+		return err
+	}
+
+	return c.decryptSensitive(stateKey())
+}
+
+// TypedState is a statefile where the resources have been grouped by type
+type TypedState struct {
+	*Config
+
+	types map[ResourceType][]Resource
+}
+
+// LoadTyped reads the statefile at the given path and returns the
+// resources grouped by their type
+func LoadTyped(path string) (*TypedState, error) {
+	c := New()
+	err := c.FromJSON(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := &TypedState{Config: c, types: map[ResourceType][]Resource{}}
+	for _, r := range c.Resources {
+		ts.types[r.Info().Type] = append(ts.types[r.Info().Type], r)
+	}
+
+	return ts, nil
+}
+
+// ByType returns the resources in the state with the given type
+func (t *TypedState) ByType(rt ResourceType) []Resource {
+	return t.types[rt]
+}
+
+// Helm returns the helm resources in the state
+func (t *TypedState) Helm() []*Helm {
+	h := []*Helm{}
+	for _, r := range t.types[TypeHelm] {
+		h = append(h, r.(*Helm))
+	}
+
+	return h
+}
+
+// K8sConfig returns the k8s_config resources in the state
+func (t *TypedState) K8sConfig() []*K8sConfig {
+	k := []*K8sConfig{}
+	for _, r := range t.types[TypeK8sConfig] {
+		k = append(k, r.(*K8sConfig))
+	}
+
+	return k
 }
 
 // UnmarshalJSON is a cusom Unmarshaler to deal with
@@ -89,20 +159,30 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 
 		var out interface{}
 		switch rt := ResourceType(mm["type"].(string)); rt {
+		case TypeAssert:
+			out = &Assert{}
 		case TypeContainerIngress:
 			out = &ContainerIngress{}
 		case TypeContainer:
 			out = &Container{}
+		case TypeCopy:
+			out = &Copy{}
+		case TypeDockerConfig:
+			out = &DockerConfig{}
 		case TypeDocs:
 			out = &Docs{}
 		case TypeExecLocal:
 			out = &ExecLocal{}
 		case TypeExecRemote:
 			out = &ExecRemote{}
+		case TypeExternal:
+			out = &External{}
 		case TypeHelm:
 			out = &Helm{}
 		case TypeImageCache:
 			out = &ImageCache{}
+		case TypeImport:
+			out = &Import{}
 		case TypeIngress:
 			out = &Ingress{}
 		case TypeK8sCluster:
@@ -111,6 +191,8 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 			out = &K8sConfig{}
 		case TypeK8sIngress:
 			out = &K8sIngress{}
+		case TypeK8sWait:
+			out = &K8sWait{}
 		case TypeModule:
 			out = &Module{}
 		case TypeNetwork:
@@ -123,8 +205,14 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 			out = &NomadJob{}
 		case TypeOutput:
 			out = &Output{}
+		case TypeRegistry:
+			out = &Registry{}
+		case TypeSecret:
+			out = &Secret{}
 		case TypeSidecar:
 			out = &Sidecar{}
+		case TypeSSHKey:
+			out = &SSHKey{}
 		case TypeTemplate:
 			out = &Template{}
 		case TypeVariable:
@@ -162,6 +250,23 @@ func (c *Config) decodeAndAdd(in map[string]interface{}, out interface{}) error
 	return c.AddResource(out.(Resource))
 }
 
+// CopyResource returns a deep copy of the resource r, the copy does not
+// belong to a config
+func CopyResource(r Resource) (Resource, error) {
+	cc, err := (&Config{Resources: []Resource{r}}).copyConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cc.Resources) != 1 {
+		return nil, fmt.Errorf("Unable to copy resource %s.%s", r.Info().Type, r.Info().Name)
+	}
+
+	cc.Resources[0].Info().Config = nil
+
+	return cc.Resources[0], nil
+}
+
 // Merge config merges two config items
 func (c *Config) Merge(c2 *Config) {
 	for _, cc2 := range c2.Resources {
@@ -181,6 +286,11 @@ func (c *Config) Merge(c2 *Config) {
 						// force recreation to attach any new networks
 						status = PendingCreation
 					}
+
+					if cc2.Info().Type == TypeAssert {
+						// assertions are checked on every apply
+						status = PendingCreation
+					}
 				}
 
 				c.Resources[i] = cc2
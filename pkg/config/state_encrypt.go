@@ -0,0 +1,279 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// StateKeyEnvName is the environment variable containing the key used to
+// encrypt sensitive values in the state
+const StateKeyEnvName = "SHIPYARD_STATE_KEY"
+
+// encryptedPrefix marks a value in the state which has been encrypted
+const encryptedPrefix = "enc:v1:"
+
+// StateEncryptionEnabled returns true when a key for encrypting the state
+// has been set
+func StateEncryptionEnabled() bool {
+	return os.Getenv(StateKeyEnvName) != ""
+}
+
+// stateKey returns the AES-256 key derived from the environment variable,
+// nil when the variable is not set
+func stateKey() []byte {
+	k := os.Getenv(StateKeyEnvName)
+	if k == "" {
+		return nil
+	}
+
+	h := sha256.Sum256([]byte(k))
+
+	return h[:]
+}
+
+// HasSensitiveValues returns true when any resource has a value set for a
+// field tagged as sensitive
+func (c *Config) HasSensitiveValues() bool {
+	found := false
+
+	for _, r := range c.Resources {
+		walkSensitive(reflect.ValueOf(r), false, func(s string) (string, error) {
+			if s != "" {
+				found = true
+			}
+
+			return s, nil
+		})
+	}
+
+	return found
+}
+
+// redactedValue replaces sensitive values when the config is displayed
+const redactedValue = "(sensitive)"
+
+// Redacted returns a copy of the config where the values of fields tagged
+// sensitive have been replaced so the config can be safely displayed
+func (c *Config) Redacted() (*Config, error) {
+	cc, err := c.copyConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range cc.Resources {
+		redact(r)
+	}
+
+	return cc, nil
+}
+
+// RedactedResource returns a copy of the resource where the values of fields
+// tagged sensitive have been replaced so the resource can be safely displayed
+func RedactedResource(r Resource) (Resource, error) {
+	rc, err := CopyResource(r)
+	if err != nil {
+		return nil, err
+	}
+
+	redact(rc)
+
+	return rc, nil
+}
+
+// redact replaces the values of the fields tagged sensitive in r
+func redact(r Resource) {
+	walkSensitive(reflect.ValueOf(r), false, func(s string) (string, error) {
+		if s == "" {
+			return s, nil
+		}
+
+		return redactedValue, nil
+	})
+}
+
+// copyConfig returns a deep copy of the config
+func (c *Config) copyConfig() (*Config, error) {
+	d, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	cc := New()
+	err = json.Unmarshal(d, cc)
+	if err != nil {
+		return nil, err
+	}
+
+	return cc, nil
+}
+
+// encryptedCopy returns a copy of the config where the values of fields tagged
+// sensitive have been encrypted, the original config is not modified as
+// resources may be in use while the state is saved
+func (c *Config) encryptedCopy(key []byte) (*Config, error) {
+	cc, err := c.copyConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range cc.Resources {
+		err := walkSensitive(reflect.ValueOf(r), false, func(s string) (string, error) {
+			return encryptValue(key, s)
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("Unable to encrypt %s.%s: %s", r.Info().Type, r.Info().Name, err)
+		}
+	}
+
+	return cc, nil
+}
+
+// decryptSensitive decrypts any encrypted values in the config
+func (c *Config) decryptSensitive(key []byte) error {
+	for _, r := range c.Resources {
+		err := walkSensitive(reflect.ValueOf(r), false, func(s string) (string, error) {
+			if !strings.HasPrefix(s, encryptedPrefix) {
+				return s, nil
+			}
+
+			if key == nil {
+				return "", fmt.Errorf("state contains encrypted values, set %s to the key used to save the state", StateKeyEnvName)
+			}
+
+			return decryptValue(key, s)
+		})
+
+		if err != nil {
+			return fmt.Errorf("Unable to decrypt %s.%s: %s", r.Info().Type, r.Info().Name, err)
+		}
+	}
+
+	return nil
+}
+
+// walkSensitive calls f for every string value in a field tagged
+// `sensitive:"true"` replacing the value with the result. Strings, and the values
+// of string maps are supported.
+func walkSensitive(v reflect.Value, sensitive bool, f func(string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		// do not walk back up to the parent config
+		if v.IsNil() || v.Type() == reflect.TypeOf(&Config{}) {
+			return nil
+		}
+
+		return walkSensitive(v.Elem(), sensitive, f)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			err := walkSensitive(v.Field(i), field.Tag.Get("sensitive") == "true", f)
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			err := walkSensitive(v.Index(i), sensitive, f)
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if !sensitive || v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+
+		for _, k := range v.MapKeys() {
+			s, err := f(v.MapIndex(k).String())
+			if err != nil {
+				return err
+			}
+
+			v.SetMapIndex(k, reflect.ValueOf(s).Convert(v.Type().Elem()))
+		}
+
+	case reflect.String:
+		if !sensitive || !v.CanSet() {
+			return nil
+		}
+
+		s, err := f(v.String())
+		if err != nil {
+			return err
+		}
+
+		v.SetString(s)
+	}
+
+	return nil
+}
+
+func encryptValue(key []byte, s string) (string, error) {
+	if s == "" || strings.HasPrefix(s, encryptedPrefix) {
+		return s, nil
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return "", err
+	}
+
+	out := gcm.Seal(nonce, nonce, []byte(s), nil)
+
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(out), nil
+}
+
+func decryptValue(key []byte, s string) (string, error) {
+	d, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, encryptedPrefix))
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	if len(d) < gcm.NonceSize() {
+		return "", fmt.Errorf("encrypted value is too short")
+	}
+
+	out, err := gcm.Open(nil, d[:gcm.NonceSize()], d[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to decrypt value, check %s is correct", StateKeyEnvName)
+	}
+
+	return string(out), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(b)
+}
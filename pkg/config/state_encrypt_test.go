@@ -0,0 +1,103 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupEncryptedState(t *testing.T, key string) (*Config, func()) {
+	c, cleanup := setupConfigTests(t)
+
+	old := os.Getenv(StateKeyEnvName)
+	os.Setenv(StateKeyEnvName, key)
+
+	co, _ := c.FindResource("container.config")
+	co.(*Container).Image = &Image{Name: "consul", Password: "secret"}
+	co.(*Container).EnvVar = map[string]string{"TOKEN": "abc123"}
+
+	return c, func() {
+		os.Setenv(StateKeyEnvName, old)
+		cleanup()
+	}
+}
+
+func TestToJSONEncryptsSensitiveValues(t *testing.T) {
+	c, cleanup := setupEncryptedState(t, "mykey")
+	defer cleanup()
+
+	err := c.ToJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	d, err := ioutil.ReadFile(utils.StatePath())
+	assert.NoError(t, err)
+
+	assert.NotContains(t, string(d), "secret")
+	assert.NotContains(t, string(d), "abc123")
+	assert.Contains(t, string(d), `"TOKEN":"enc:v1:`)
+	assert.Contains(t, string(d), `"name":"consul"`)
+
+	// the in memory config is not modified
+	co, _ := c.FindResource("container.config")
+	assert.Equal(t, "secret", co.(*Container).Image.Password)
+}
+
+func TestFromJSONDecryptsSensitiveValues(t *testing.T) {
+	c, cleanup := setupEncryptedState(t, "mykey")
+	defer cleanup()
+
+	err := c.ToJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	c2 := New()
+	err = c2.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	co, err := c2.FindResource("container.config")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", co.(*Container).Image.Password)
+	assert.Equal(t, "abc123", co.(*Container).EnvVar["TOKEN"])
+}
+
+func TestFromJSONReturnsErrorWhenKeyMissing(t *testing.T) {
+	c, cleanup := setupEncryptedState(t, "mykey")
+	defer cleanup()
+
+	err := c.ToJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	os.Setenv(StateKeyEnvName, "")
+
+	err = New().FromJSON(utils.StatePath())
+	assert.Error(t, err)
+}
+
+func TestFromJSONReturnsErrorWhenKeyIncorrect(t *testing.T) {
+	c, cleanup := setupEncryptedState(t, "mykey")
+	defer cleanup()
+
+	err := c.ToJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	os.Setenv(StateKeyEnvName, "otherkey")
+
+	err = New().FromJSON(utils.StatePath())
+	assert.Error(t, err)
+}
+
+func TestToJSONWritesPlainTextWithoutKey(t *testing.T) {
+	c, cleanup := setupEncryptedState(t, "")
+	defer cleanup()
+
+	assert.True(t, c.HasSensitiveValues())
+
+	err := c.ToJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	d, err := ioutil.ReadFile(utils.StatePath())
+	assert.NoError(t, err)
+	assert.Contains(t, string(d), "abc123")
+}
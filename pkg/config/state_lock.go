@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// StateLockedError is returned when the state is locked by another process
+type StateLockedError struct {
+	PID int
+}
+
+func (s StateLockedError) Error() string {
+	return fmt.Sprintf("state is locked by PID %d, if the process is no longer running remove the lock with 'shipyard force-unlock'", s.PID)
+}
+
+// LockState creates a lock file next to the state containing the id of the
+// current process. An error is returned when the state is already locked.
+func LockState() error {
+	return LockStateFile(utils.StateLockPath())
+}
+
+// LockStateFile creates the lock file at path containing the id of the current
+// process. An error is returned when the lock file already exists.
+func LockStateFile(path string) error {
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("Unable to create state folder: %s", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return StateLockedError{PID: lockPID(path)}
+		}
+
+		return fmt.Errorf("Unable to lock state: %s", err)
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(strconv.Itoa(os.Getpid()))
+	if err != nil {
+		return fmt.Errorf("Unable to lock state: %s", err)
+	}
+
+	return nil
+}
+
+// UnlockState removes the state lock, it is not an error to unlock state
+// which is not locked
+func UnlockState() error {
+	return UnlockStateFile(utils.StateLockPath())
+}
+
+// UnlockStateFile removes the lock file at path
+func UnlockStateFile(path string) error {
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Unable to unlock state: %s", err)
+	}
+
+	return nil
+}
+
+// lockPID returns the id of the process holding the lock, 0 if it can not
+// be determined
+func lockPID(path string) int {
+	d, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(d)))
+
+	return pid
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestLockStateCreatesLockWithPID(t *testing.T) {
+	_, cleanup := setupConfigTests(t)
+	defer cleanup()
+
+	err := LockState()
+	assert.NoError(t, err)
+	defer UnlockState()
+
+	assert.Equal(t, os.Getpid(), lockPID(utils.StateLockPath()))
+}
+
+func TestLockStateReturnsErrorWhenLocked(t *testing.T) {
+	_, cleanup := setupConfigTests(t)
+	defer cleanup()
+
+	err := ioutil.WriteFile(utils.StateLockPath(), []byte("1234"), 0644)
+	assert.NoError(t, err)
+
+	err = LockState()
+	assert.Equal(t, StateLockedError{PID: 1234}, err)
+	assert.Contains(t, err.Error(), "state is locked by PID 1234")
+}
+
+func TestUnlockStateRemovesLock(t *testing.T) {
+	_, cleanup := setupConfigTests(t)
+	defer cleanup()
+
+	err := LockState()
+	assert.NoError(t, err)
+
+	err = UnlockState()
+	assert.NoError(t, err)
+	assert.NoFileExists(t, utils.StateLockPath())
+
+	// unlocking again is not an error
+	err = UnlockState()
+	assert.NoError(t, err)
+}
@@ -87,6 +87,30 @@ func TestConfigDeSerializesFromJSON(t *testing.T) {
 	assert.NotNil(t, r)
 }
 
+func TestLoadTypedGroupsResourcesByType(t *testing.T) {
+	_, cleanup := setupConfigTests(t)
+	defer cleanup()
+
+	err := ioutil.WriteFile(utils.StatePath(), []byte(complexState), os.ModePerm)
+	assert.NoError(t, err)
+
+	ts, err := LoadTyped(utils.StatePath())
+	assert.NoError(t, err)
+
+	assert.Len(t, ts.Resources, 16)
+	assert.Len(t, ts.Helm(), 2)
+	assert.Len(t, ts.ByType(TypeContainer), 1)
+	assert.Len(t, ts.K8sConfig(), 0)
+}
+
+func TestLoadTypedReturnsErrorWhenNoState(t *testing.T) {
+	_, cleanup := setupConfigTests(t)
+	defer cleanup()
+
+	_, err := LoadTyped(utils.StatePath())
+	assert.Equal(t, StateNotFoundError, err)
+}
+
 func TestConfigMergesAddingItems(t *testing.T) {
 	c, cleanup := setupConfigTests(t)
 	defer cleanup()
@@ -184,6 +208,37 @@ func TestConfigMergesWithExistingItemAppendsDependencyOnCache(t *testing.T) {
 	assert.Len(t, cache.Info().DependsOn, 2)
 }
 
+func TestConfigMergesWithExistingAssertSetsPendingCreation(t *testing.T) {
+	c := New()
+	a := NewAssert("consul")
+	a.Status = Applied
+	c.AddResource(a)
+
+	c2 := New()
+	c2.AddResource(NewAssert("consul"))
+
+	c.Merge(c2)
+
+	assert.Len(t, c.Resources, 1)
+	assert.Equal(t, PendingCreation, c.Resources[0].Info().Status)
+}
+
+func TestCopyResourceReturnsDeepCopy(t *testing.T) {
+	c := NewContainer("consul")
+	c.Image = &Image{Name: "consul:1.8.1"}
+	c.EnvVar = map[string]string{"TOKEN": "abc"}
+
+	cc, err := CopyResource(c)
+	assert.NoError(t, err)
+
+	c.Image.Name = "consul:1.9.0"
+	c.EnvVar["TOKEN"] = "123"
+
+	assert.Equal(t, "consul", cc.Info().Name)
+	assert.Equal(t, "consul:1.8.1", cc.(*Container).Image.Name)
+	assert.Equal(t, "abc", cc.(*Container).EnvVar["TOKEN"])
+}
+
 var complexState = `
 {
   "blueprint": null,
@@ -1,11 +1,28 @@
 package config
 
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/hcl2/hclparse"
+)
+
 const TypeVariable ResourceType = "variable"
 
+// VariableEnvPrefix is the prefix for environment variables which set
+// the value of a variable e.g. SY_VAR_version sets the variable version
+const VariableEnvPrefix = "SY_VAR_"
+
 // Output defines an output variable which can be set by a module
 type Variable struct {
 	ResourceInfo `mapstructure:",squash"`
-	Default      interface{} `hcl:"default" json:"default"`                            // default value for a variable
+	Default      interface{} `hcl:"default,optional" json:"default"`                   // default value for a variable, variables without a default are required
 	Description  string      `hcl:"description,optional" json:"description,omitempty"` // description of the variable
 }
 
@@ -13,3 +30,130 @@ type Variable struct {
 func NewVariable(name string) *Variable {
 	return &Variable{ResourceInfo: ResourceInfo{Name: name, Type: TypeVariable, Status: PendingCreation}}
 }
+
+// MissingVariablesError is returned when values have not been set
+// for variables which do not have a default
+type MissingVariablesError struct {
+	Names []string
+}
+
+func (m MissingVariablesError) Error() string {
+	return fmt.Sprintf("Values must be set for the required variables: %s, set them with --var, a variables file, or SY_VAR_ environment variables", strings.Join(m.Names, ", "))
+}
+
+// RequiredVariables returns the names of the variables defined in the file or
+// folder at path which do not have a default value
+func RequiredVariables(path string) ([]string, error) {
+	files := []string{path}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if fi.IsDir() {
+		files, err = configFiles(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	required := []string{}
+
+	for _, f := range files {
+		parser := hclparse.NewParser()
+
+		hf, err := parseConfigFile(parser, f)
+		if err != nil {
+			return nil, err
+		}
+
+		body, ok := hf.Body.(*hclsyntax.Body)
+		if !ok {
+			return nil, errors.New("Error getting body")
+		}
+
+		for _, b := range body.Blocks {
+			if b.Type != string(TypeVariable) || len(b.Labels) == 0 {
+				continue
+			}
+
+			if _, ok := b.Body.Attributes["default"]; !ok {
+				required = append(required, b.Labels[0])
+			}
+		}
+	}
+
+	sort.Strings(required)
+
+	return required, nil
+}
+
+// CheckRequiredVariables returns a MissingVariablesError listing every variable
+// without a default value defined in the file or folder at path which has no
+// value in variables, variablesFile, the *.vars files in the folder, or
+// the SY_VAR_ environment variables
+func CheckRequiredVariables(p string, variables map[string]string, variablesFile string) error {
+	required, err := RequiredVariables(p)
+	if err != nil {
+		return err
+	}
+
+	if len(required) == 0 {
+		return nil
+	}
+
+	set := map[string]bool{}
+
+	for k := range variables {
+		set[k] = true
+	}
+
+	for _, e := range os.Environ() {
+		if strings.HasPrefix(e, VariableEnvPrefix) {
+			parts := strings.SplitN(e, "=", 2)
+			set[strings.TrimPrefix(parts[0], VariableEnvPrefix)] = true
+		}
+	}
+
+	valueFiles := []string{}
+	if variablesFile != "" {
+		valueFiles = append(valueFiles, variablesFile)
+	}
+
+	if fi, err := os.Stat(p); err == nil && fi.IsDir() {
+		vf, err := filepath.Glob(path.Join(p, "*.vars"))
+		if err != nil {
+			return err
+		}
+
+		valueFiles = append(valueFiles, vf...)
+	}
+
+	for _, f := range valueFiles {
+		parser := hclparse.NewParser()
+
+		hf, diag := parser.ParseHCLFile(f)
+		if diag.HasErrors() {
+			return errors.New(diag.Error())
+		}
+
+		attrs, _ := hf.Body.JustAttributes()
+		for name := range attrs {
+			set[name] = true
+		}
+	}
+
+	missing := []string{}
+	for _, r := range required {
+		if !set[r] {
+			missing = append(missing, r)
+		}
+	}
+
+	if len(missing) > 0 {
+		return MissingVariablesError{Names: missing}
+	}
+
+	return nil
+}
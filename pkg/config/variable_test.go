@@ -0,0 +1,153 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testRequiredVariables = `
+variable "version" {
+  default = "1.8.1"
+}
+
+variable "token" {
+  description = "API token"
+}
+
+variable "region" {}
+`
+
+func TestRequiredVariablesReturnsVariablesWithoutDefault(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.hcl", testRequiredVariables)
+
+	req, err := RequiredVariables(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"region", "token"}, req)
+}
+
+func TestCheckRequiredVariablesReturnsAllMissing(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.hcl", testRequiredVariables)
+
+	err := CheckRequiredVariables(dir, nil, "")
+	assert.Error(t, err)
+
+	mv, ok := err.(MissingVariablesError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"region", "token"}, mv.Names)
+}
+
+func TestCheckRequiredVariablesUsesVariablesAndFiles(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.hcl", testRequiredVariables)
+	createNamedFile(t, dir, "*.vars", `token = "abc"`)
+
+	err := CheckRequiredVariables(dir, map[string]string{"region": "eu"}, "")
+	assert.NoError(t, err)
+}
+
+func TestCheckRequiredVariablesUsesEnvironment(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.hcl", testRequiredVariables)
+
+	os.Setenv("SY_VAR_token", "abc")
+	defer os.Unsetenv("SY_VAR_token")
+
+	err := CheckRequiredVariables(dir, nil, "")
+	assert.Error(t, err)
+
+	mv := err.(MissingVariablesError)
+	assert.Equal(t, []string{"region"}, mv.Names)
+}
+
+func TestParseVariableWithoutDefaultUsesValue(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.hcl", `
+variable "subnet" {}
+
+network "cloud" {
+  subnet = var.subnet
+}
+`)
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, map[string]string{"subnet": "10.4.0.0/16"}, "")
+	assert.NoError(t, err)
+
+	r, err := c.FindResource("network.cloud")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.4.0.0/16", r.(*Network).Subnet)
+}
+
+const testVariablePrecedence = `
+variable "subnet" {
+  default = "10.1.0.0/16"
+}
+
+network "cloud" {
+  subnet = var.subnet
+}
+`
+
+func parseVariablePrecedence(t *testing.T, single bool, vars map[string]string, env string) string {
+	dir, cleanup := createTestFiles(t)
+	t.Cleanup(cleanup)
+
+	f := createNamedFile(t, dir, "*.hcl", testVariablePrecedence)
+	vf := createNamedFile(t, t.TempDir(), "*.vars", `subnet = "10.2.0.0/16"`)
+
+	if env != "" {
+		os.Setenv(VariableEnvPrefix+"subnet", env)
+		t.Cleanup(func() {
+			os.Unsetenv(VariableEnvPrefix + "subnet")
+		})
+	}
+
+	c := New()
+
+	var err error
+	if single {
+		err = ParseSingleFile(f, c, vars, vf)
+	} else {
+		err = ParseFolder(dir, c, false, "", false, []string{}, vars, vf)
+	}
+	assert.NoError(t, err)
+
+	r, err := c.FindResource("network.cloud")
+	assert.NoError(t, err)
+
+	return r.(*Network).Subnet
+}
+
+func TestVariablePrecedence(t *testing.T) {
+	for name, single := range map[string]bool{"file": true, "folder": false} {
+		single := single
+
+		t.Run(name, func(t *testing.T) {
+			// variables file replaces the default
+			assert.Equal(t, "10.2.0.0/16", parseVariablePrecedence(t, single, nil, ""))
+
+			// environment replaces the variables file
+			assert.Equal(t, "10.3.0.0/16", parseVariablePrecedence(t, single, nil, "10.3.0.0/16"))
+
+			// vars replace the environment
+			assert.Equal(
+				t,
+				"10.4.0.0/16",
+				parseVariablePrecedence(t, single, map[string]string{"subnet": "10.4.0.0/16"}, "10.3.0.0/16"),
+			)
+		})
+	}
+}
+
+func TestEnvironmentVariableValueCanContainEquals(t *testing.T) {
+	assert.Equal(t, "a=b", parseVariablePrecedence(t, false, nil, "a=b"))
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// isYAMLFile returns true when the file has a YAML extension
+func isYAMLFile(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return true
+	}
+
+	return false
+}
+
+// isYAMLBlueprint returns true when the YAML file only contains resource
+// definitions. Blueprint folders often contain other YAML files such as
+// Kubernetes manifests, these are ignored by the parser.
+func isYAMLBlueprint(file string) bool {
+	d, err := ioutil.ReadFile(file)
+	if err != nil {
+		return false
+	}
+
+	doc := yaml.MapSlice{}
+	err = yaml.Unmarshal(d, &doc)
+	if err != nil || len(doc) == 0 {
+		return false
+	}
+
+	for _, i := range doc {
+		if _, ok := schemaTypes[ResourceType(fmt.Sprintf("%v", i.Key))]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// yamlToHCL converts a YAML blueprint file into HCL so that it can be
+// processed by the HCL parser.
+// Resources are defined as a map keyed by the resource type then the name e.g.
+//
+//   container:
+//     consul:
+//       image:
+//         name: consul:1.8.1
+//
+// Strings are converted to HCL templates so interpolation such as ${var.name}
+// works the same as in HCL files.
+func yamlToHCL(file string) ([]byte, error) {
+	d, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := yaml.MapSlice{}
+	err = yaml.Unmarshal(d, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse YAML file %s: %s", file, err)
+	}
+
+	out := bytes.NewBufferString("")
+
+	for _, t := range doc {
+		typeName := fmt.Sprintf("%v", t.Key)
+
+		rt, ok := schemaTypes[ResourceType(typeName)]
+		if !ok {
+			return nil, ResourceTypeNotExistError{typeName, file}
+		}
+
+		resources, ok := t.Value.(yaml.MapSlice)
+		if !ok {
+			return nil, fmt.Errorf("Error in file '%s': resources of type '%s' must be a map keyed by the resource name", file, typeName)
+		}
+
+		for _, r := range resources {
+			body, ok := r.Value.(yaml.MapSlice)
+			if !ok && r.Value != nil {
+				return nil, fmt.Errorf("Error in file '%s': resource '%s.%v' must be a map", file, typeName, r.Key)
+			}
+
+			fmt.Fprintf(out, "%s %s {\n", typeName, strconv.Quote(fmt.Sprintf("%v", r.Key)))
+
+			err := writeHCLBody(out, reflect.TypeOf(rt), body, 1)
+			if err != nil {
+				return nil, fmt.Errorf("Error in file '%s': resource '%s.%v': %s", file, typeName, r.Key, err)
+			}
+
+			out.WriteString("}\n\n")
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+// writeHCLBody writes the attributes and blocks for the struct type t
+func writeHCLBody(out *bytes.Buffer, t reflect.Type, body yaml.MapSlice, indent int) error {
+	blocks := map[string]reflect.Type{}
+	findBlocks(t, blocks)
+
+	pad := strings.Repeat("  ", indent)
+
+	for _, i := range body {
+		name := fmt.Sprintf("%v", i.Key)
+
+		bt, isBlock := blocks[name]
+		if !isBlock {
+			fmt.Fprintf(out, "%s%s = %s\n", pad, name, hclValue(i.Value))
+			continue
+		}
+
+		// a block can be defined as a single map or a list of maps
+		items := []interface{}{i.Value}
+		if l, ok := i.Value.([]interface{}); ok {
+			items = l
+		}
+
+		for _, item := range items {
+			bb, ok := item.(yaml.MapSlice)
+			if !ok && item != nil {
+				return fmt.Errorf("block '%s' must be a map or a list of maps", name)
+			}
+
+			fmt.Fprintf(out, "%s%s {\n", pad, name)
+
+			err := writeHCLBody(out, bt, bb, indent+1)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "%s}\n", pad)
+		}
+	}
+
+	return nil
+}
+
+// findBlocks returns the struct types for the hcl blocks defined on t
+func findBlocks(t reflect.Type, blocks map[string]reflect.Type) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		parts := strings.Split(f.Tag.Get("hcl"), ",")
+		if len(parts) < 2 {
+			continue
+		}
+
+		switch parts[1] {
+		case "remain":
+			findBlocks(f.Type, blocks)
+		case "block":
+			blocks[parts[0]] = f.Type
+		}
+	}
+}
+
+// hclValue returns the HCL expression for a YAML value
+func hclValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return hclString(val)
+	case bool:
+		return strconv.FormatBool(val)
+	case int, int64, uint64, float64:
+		return fmt.Sprintf("%v", val)
+	case []interface{}:
+		items := []string{}
+		for _, i := range val {
+			items = append(items, hclValue(i))
+		}
+
+		return fmt.Sprintf("[%s]", strings.Join(items, ", "))
+	case yaml.MapSlice:
+		items := []string{}
+		for _, i := range val {
+			items = append(items, fmt.Sprintf("%s = %s", strconv.Quote(fmt.Sprintf("%v", i.Key)), hclValue(i.Value)))
+		}
+
+		return fmt.Sprintf("{ %s }", strings.Join(items, ", "))
+	case map[interface{}]interface{}:
+		keys := []string{}
+		for k := range val {
+			keys = append(keys, fmt.Sprintf("%v", k))
+		}
+		sort.Strings(keys)
+
+		items := []string{}
+		for _, k := range keys {
+			items = append(items, fmt.Sprintf("%s = %s", strconv.Quote(k), hclValue(val[k])))
+		}
+
+		return fmt.Sprintf("{ %s }", strings.Join(items, ", "))
+	}
+
+	return hclString(fmt.Sprintf("%v", v))
+}
+
+// hclString returns a quoted HCL template string, template sequences
+// such as ${var.name} are preserved so they are evaluated by the parser
+func hclString(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+
+	return `"` + r.Replace(s) + `"`
+}
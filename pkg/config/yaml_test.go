@@ -0,0 +1,100 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYAMLBlueprintCreatesResources(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.yaml", yamlBlueprint)
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+
+	cc := co.(*Container)
+	assert.Equal(t, "consul:1.8.1", cc.Image.Name)
+	assert.Equal(t, []string{"consul", "agent", "-dev"}, cc.Command)
+	assert.Equal(t, "value", cc.EnvVar["key"])
+	assert.Len(t, cc.Networks, 1)
+	assert.Equal(t, "network.onprem", cc.Networks[0].Name)
+	assert.Len(t, cc.Ports, 2)
+	assert.Equal(t, "8500", cc.Ports[0].Local)
+
+	n, err := c.FindResource("network.onprem")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.6.0.0/16", n.(*Network).Subnet)
+}
+
+func TestYAMLBlueprintIgnoresOtherYAMLFiles(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.yaml", yamlBlueprint)
+	createNamedFile(t, dir, "*.yaml", yamlKubernetesManifest)
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, nil, "")
+	assert.NoError(t, err)
+}
+
+func TestYAMLBlueprintSetsVariablesFromCommandLine(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.yaml", yamlBlueprint)
+
+	c := New()
+	err := ParseFolder(dir, c, false, "", false, []string{}, map[string]string{"version": "consul:1.9.0"}, "")
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.9.0", co.(*Container).Image.Name)
+}
+
+func TestYAMLSingleFileWithUnknownTypeReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	f := createNamedFile(t, dir, "*.yaml", yamlKubernetesManifest)
+
+	c := New()
+	err := ParseSingleFile(f, c, nil, "")
+	assert.Error(t, err)
+}
+
+const yamlBlueprint = `
+variable:
+  version:
+    default: consul:1.8.1
+
+network:
+  onprem:
+    subnet: 10.6.0.0/16
+
+container:
+  consul:
+    image:
+      name: ${var.version}
+    command: ["consul", "agent", "-dev"]
+    env_var:
+      key: value
+    network:
+      name: network.onprem
+    port:
+      - local: 8500
+        host: 8500
+      - local: 8501
+        host: 8501
+`
+
+const yamlKubernetesManifest = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: consul
+`
@@ -0,0 +1,128 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// Assert provider checks the attributes of other resources once they have been created
+type Assert struct {
+	config *config.Assert
+	log    hclog.Logger
+}
+
+// NewAssert creates a new Assert provider
+func NewAssert(c *config.Assert, l hclog.Logger) *Assert {
+	return &Assert{c, l}
+}
+
+// Create checks the assertion and returns an error when it does not hold
+func (a *Assert) Create(ctx context.Context) error {
+	a.log.Info("Checking assertion", "ref", a.config.Name, "resource", a.config.Resource, "attribute", a.config.Attribute)
+
+	r, err := a.config.FindDependentResource(a.config.Resource)
+	if err != nil {
+		return xerrors.Errorf("Unable to find resource %s: %w", a.config.Resource, err)
+	}
+
+	val, err := attributeValue(r, a.config.Attribute)
+	if err != nil {
+		return xerrors.Errorf("Unable to read attribute %s of resource %s: %w", a.config.Attribute, a.config.Resource, err)
+	}
+
+	ok, err := checkCondition(a.config.Condition, val, a.config.Value)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		msg := a.config.Message
+		if msg == "" {
+			msg = fmt.Sprintf("expected %s.%s %s %q, got %q", a.config.Resource, a.config.Attribute, a.config.Condition, a.config.Value, val)
+		}
+
+		return fmt.Errorf("Assertion %s failed: %s", a.config.Name, msg)
+	}
+
+	return nil
+}
+
+// Destroy is a noop, assertions do not create anything
+func (a *Assert) Destroy(ctx context.Context) error {
+	return nil
+}
+
+// Lookup is a noop, assertions have no ids
+func (a *Assert) Lookup() ([]string, error) {
+	return []string{}, nil
+}
+
+// attributeValue returns the string value of the attribute at the given path,
+// attributes use the same names as the resource in the state file
+func attributeValue(r config.Resource, path string) (string, error) {
+	d, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+
+	var v interface{}
+	err = json.Unmarshal(d, &v)
+	if err != nil {
+		return "", err
+	}
+
+	for _, p := range strings.Split(path, ".") {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			v = t[p]
+		case []interface{}:
+			i, err := strconv.Atoi(p)
+			if err != nil || i < 0 || i >= len(t) {
+				return "", fmt.Errorf("invalid index %s", p)
+			}
+
+			v = t[i]
+		default:
+			// attribute does not exist or is not set
+			return "", nil
+		}
+	}
+
+	switch t := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return t, nil
+	case float64, bool:
+		return fmt.Sprintf("%v", t), nil
+	}
+
+	// return complex values as json so they can be compared
+	d, err = json.Marshal(v)
+	return string(d), err
+}
+
+func checkCondition(condition, actual, expected string) (bool, error) {
+	switch condition {
+	case config.AssertNotEmpty:
+		return actual != "", nil
+	case config.AssertEquals:
+		return actual == expected, nil
+	case config.AssertNotEquals:
+		return actual != expected, nil
+	case config.AssertContains:
+		return strings.Contains(actual, expected), nil
+	case config.AssertMatches:
+		return regexp.MatchString(expected, actual)
+	}
+
+	return false, fmt.Errorf("invalid condition %s", condition)
+}
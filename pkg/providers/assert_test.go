@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAssert(condition, attribute, value string) *Assert {
+	co := config.NewContainer("api")
+	co.Ports = []config.Port{{Local: "9090", Remote: "9090", Host: "8080"}}
+
+	a := config.NewAssert("test")
+	a.Resource = "container.api"
+	a.Attribute = attribute
+	a.Condition = condition
+	a.Value = value
+
+	c := config.New()
+	c.AddResource(co)
+	c.AddResource(a)
+
+	return NewAssert(a, hclog.NewNullLogger())
+}
+
+func TestAssertEqualsPasses(t *testing.T) {
+	p := setupAssert(config.AssertEquals, "ports.0.host", "8080")
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestAssertEqualsFailsWhenValueDiffers(t *testing.T) {
+	p := setupAssert(config.AssertEquals, "ports.0.host", "9000")
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `got "8080"`)
+}
+
+func TestAssertNotEmptyFailsWhenAttributeMissing(t *testing.T) {
+	p := setupAssert(config.AssertNotEmpty, "ip_address", "")
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestAssertMatchesPasses(t *testing.T) {
+	p := setupAssert(config.AssertMatches, "ports.0.local", "^90[0-9]+$")
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestAssertReturnsMessageOnFailure(t *testing.T) {
+	p := setupAssert(config.AssertContains, "name", "web")
+	p.config.Message = "container name should contain web"
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "container name should contain web")
+}
+
+func TestAssertReturnsErrorWhenResourceNotFound(t *testing.T) {
+	p := setupAssert(config.AssertNotEmpty, "name", "")
+	p.config.Resource = "container.missing"
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients"
 	"github.com/shipyard-run/shipyard/pkg/config"
@@ -17,12 +18,12 @@ type DOKSCluster struct {
 }
 
 // Create a new DOKS cluster in Digial Ocean
-func (d *DOKSCluster) Create() error {
+func (d *DOKSCluster) Create(ctx context.Context) error {
 	return nil
 }
 
 // Destroy the cluster
-func (d *DOKSCluster) Destroy() error {
+func (d *DOKSCluster) Destroy(ctx context.Context) error {
 	return nil
 }
 
@@ -2,6 +2,7 @@ package providers
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -24,7 +25,6 @@ import (
 // https://github.com/rancher/k3d/blob/master/cli/commands.go
 
 const k3sBaseImage = "shipyardrun/k3s"
-const k3sBaseVersion = "v1.18.16"
 
 var startTimeout = (300 * time.Second)
 
@@ -44,17 +44,25 @@ func NewK8sCluster(c *config.K8sCluster, cc clients.ContainerTasks, kc clients.K
 }
 
 // Create implements interface method to create a cluster of the specified type
-func (c *K8sCluster) Create() error {
+func (c *K8sCluster) Create(ctx context.Context) error {
+	if c.config.External {
+		return c.createExternal()
+	}
+
 	switch c.config.Driver {
 	case "k3s":
-		return c.createK3s()
+		return c.createK3s(ctx)
 	default:
 		return ErrorClusterDriverNotImplemented
 	}
 }
 
 // Destroy implements interface method to destroy a cluster
-func (c *K8sCluster) Destroy() error {
+func (c *K8sCluster) Destroy(ctx context.Context) error {
+	if c.config.External {
+		return c.destroyExternal()
+	}
+
 	switch c.config.Driver {
 	case "k3s":
 		return c.destroyK3s()
@@ -65,10 +73,56 @@ func (c *K8sCluster) Destroy() error {
 
 // Lookup the a clusters current state
 func (c *K8sCluster) Lookup() ([]string, error) {
+	// external clusters are not managed by Shipyard
+	if c.config.External {
+		return []string{c.config.KubeConfig}, nil
+	}
+
 	return c.client.FindContainerIDs(fmt.Sprintf("server.%s", c.config.Name), c.config.Type)
 }
 
-func (c *K8sCluster) createK3s() error {
+// createExternal copies the Kubernetes config for an existing cluster to the location
+// used by the resources which deploy to the cluster and checks the cluster can be reached
+func (c *K8sCluster) createExternal() error {
+	c.log.Info("Using external Cluster", "ref", c.config.Name, "kubeconfig", c.config.KubeConfig)
+
+	kc, err := ioutil.ReadFile(c.config.KubeConfig)
+	if err != nil {
+		return xerrors.Errorf("Unable to read Kubernetes config: %w", err)
+	}
+
+	_, kubePath, dockerPath := utils.CreateKubeConfigPath(c.config.Name)
+	for _, p := range []string{kubePath, dockerPath} {
+		err := ioutil.WriteFile(p, kc, 0600)
+		if err != nil {
+			return xerrors.Errorf("Unable to write Kubernetes config: %w", err)
+		}
+	}
+
+	c.kubeClient, err = c.kubeClient.SetConfig(kubePath)
+	if err != nil {
+		return xerrors.Errorf("Unable to create Kubernetes client: %w", err)
+	}
+
+	_, err = c.kubeClient.GetPods("")
+	if err != nil {
+		return xerrors.Errorf("Unable to connect to external cluster: %w", err)
+	}
+
+	return nil
+}
+
+// destroyExternal removes the copied Kubernetes config, the cluster is left unchanged
+func (c *K8sCluster) destroyExternal() error {
+	c.log.Info("Removing external Cluster", "ref", c.config.Name)
+
+	dir, _, _ := utils.CreateKubeConfigPath(c.config.Name)
+	os.RemoveAll(dir)
+
+	return nil
+}
+
+func (c *K8sCluster) createK3s(ctx context.Context) error {
 	// create a named log
 	c.log = c.log.Named(c.config.Name)
 
@@ -84,12 +138,10 @@ func (c *K8sCluster) createK3s() error {
 		return ErrorClusterExists
 	}
 
-	if c.config.Version == "" {
-		c.config.Version = k3sBaseVersion
-	}
+	version := c.config.RequestedVersion()
 
 	// set the image
-	image := fmt.Sprintf("%s:%s", k3sBaseImage, c.config.Version)
+	image := fmt.Sprintf("%s:%s", k3sBaseImage, version)
 
 	// pull the container image
 	err = c.client.PullImage(config.Image{Name: image}, false)
@@ -140,7 +192,7 @@ func (c *K8sCluster) createK3s() error {
 		return err
 	}
 
-	v, err := semver.NewVersion(c.config.Version)
+	v, err := semver.NewVersion(version)
 	if err != nil {
 		return fmt.Errorf("Kubernetes version is not valid semantic version: %s", err)
 	}
@@ -205,12 +257,26 @@ func (c *K8sCluster) createK3s() error {
 		return err
 	}
 
+	// record the version so a change to the requested version recreates the cluster
+	c.config.ResolvedVersion = version
+
 	// wait for the server to start
-	err = c.waitForStart(id)
+	err = c.waitForStart(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	// create the agent nodes, the server is the first node
+	agentIDs := []string{}
+	for i := 1; i < c.config.Nodes; i++ {
+		aid, err := c.createAgentNode(i, cc, clusterConfig.APIPort)
+		if err != nil {
+			return xerrors.Errorf("Unable to create agent node: %w", err)
+		}
+
+		agentIDs = append(agentIDs, aid)
+	}
+
 	// get the Kubernetes config file and drop it in a temp folder
 	kc, err := c.copyKubeConfig(id)
 	if err != nil {
@@ -259,9 +325,11 @@ func (c *K8sCluster) createK3s() error {
 	// import the images to the servers container d instance
 	// importing images means that k3s does not need to pull from a remote docker hub
 	if c.config.Images != nil && len(c.config.Images) > 0 {
-		err := c.ImportLocalDockerImages(utils.ImageVolumeName, id, c.config.Images, false)
-		if err != nil {
-			return xerrors.Errorf("Error importing Docker images: %w", err)
+		for _, nid := range append([]string{id}, agentIDs...) {
+			err := c.ImportLocalDockerImages(utils.ImageVolumeName, nid, c.config.Images, false)
+			if err != nil {
+				return xerrors.Errorf("Error importing Docker images: %w", err)
+			}
 		}
 	}
 
@@ -270,7 +338,34 @@ func (c *K8sCluster) createK3s() error {
 	return c.deployConnector(clusterConfig.ConnectorPort, clusterConfig.ConnectorPort+1)
 }
 
-func (c *K8sCluster) waitForStart(id string) error {
+// createAgentNode creates a k3s agent which joins the cluster created by the server
+func (c *K8sCluster) createAgentNode(index int, server *config.Container, apiPort int) (string, error) {
+	cc := config.NewContainer(fmt.Sprintf("%d.agent.%s", index, c.config.Name))
+	c.config.ResourceInfo.AddChild(cc)
+
+	cc.Image = server.Image
+	cc.Networks = server.Networks
+	cc.Privileged = true
+	cc.Volumes = server.Volumes
+
+	cc.EnvVar = map[string]string{}
+	for k, v := range server.EnvVar {
+		cc.EnvVar[k] = v
+	}
+
+	delete(cc.EnvVar, "K3S_KUBECONFIG_OUTPUT")
+	cc.EnvVar["K3S_URL"] = fmt.Sprintf("https://%s:%d", utils.WorkspaceFQDN(fmt.Sprintf("server.%s", c.config.Name), string(config.TypeK8sCluster), c.client.Workspace()), apiPort)
+	cc.EnvVar["K3S_TOKEN"] = cc.EnvVar["K3S_CLUSTER_SECRET"]
+
+	cc.Command = []string{
+		"agent",
+		"--kube-proxy-arg=conntrack-max-per-core=0",
+	}
+
+	return c.client.CreateContainer(cc)
+}
+
+func (c *K8sCluster) waitForStart(ctx context.Context, id string) error {
 	start := time.Now()
 
 	for {
@@ -297,7 +392,10 @@ func (c *K8sCluster) waitForStart(id string) error {
 		}
 
 		// wait and try again
-		time.Sleep(1 * time.Second)
+		err = sleepContext(ctx, 1*time.Second)
+		if err != nil {
+			return xerrors.Errorf("Cancelled waiting for cluster %s to start: %w", c.config.Name, err)
+		}
 	}
 
 	return nil
@@ -336,7 +434,7 @@ func (c *K8sCluster) createDockerKubeConfig(kubeconfig string) error {
 	_, _, dockerPath := utils.CreateKubeConfigPath(c.config.Name)
 
 	return c.changeServerAddressInK8sConfig(
-		fmt.Sprintf("https://server.%s", utils.FQDN(c.config.Name, string(c.config.Type))),
+		fmt.Sprintf("https://server.%s", utils.WorkspaceFQDN(c.config.Name, string(c.config.Type), c.client.Workspace())),
 		kubeconfig,
 		dockerPath,
 	)
@@ -494,21 +592,19 @@ func (c *K8sCluster) ImportLocalDockerImages(name string, id string, images []co
 func (c *K8sCluster) destroyK3s() error {
 	c.log.Info("Destroy Cluster", "ref", c.config.Name)
 
-	ids, err := c.client.FindContainerIDs(fmt.Sprintf("server.%s", c.config.Name), c.config.Type)
+	serverIDs, err := c.client.FindContainerIDs(fmt.Sprintf("server.%s", c.config.Name), c.config.Type)
 	if err != nil {
 		return err
 	}
 
-	for _, i := range ids {
-		// remove from the networks
-		for _, n := range c.config.Networks {
-			err := c.client.DetachNetwork(n.Name, i)
-			if err != nil {
-				return err
-			}
-		}
+	agentIDs, err := c.agentIDs(serverIDs)
+	if err != nil {
+		return err
+	}
 
-		err := c.client.RemoveContainer(i, false)
+	// destroy the agents before the server
+	for _, id := range append(agentIDs, serverIDs...) {
+		err := c.removeNode(id)
 		if err != nil {
 			return err
 		}
@@ -520,6 +616,55 @@ func (c *K8sCluster) destroyK3s() error {
 	return nil
 }
 
+// agentIDs returns the IDs of the agent containers for the cluster. Agents are
+// found using the resource label so that agents are removed when the number of
+// nodes has been reduced, agents created before containers were labelled are
+// found by name.
+func (c *K8sCluster) agentIDs(serverIDs []string) ([]string, error) {
+	ids, err := c.client.FindResourceContainerIDs(fmt.Sprintf("%s.%s", c.config.Type, c.config.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < c.config.Nodes; i++ {
+		aids, err := c.client.FindContainerIDs(fmt.Sprintf("%d.agent.%s", i, c.config.Name), c.config.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, aids...)
+	}
+
+	skip := map[string]bool{}
+	for _, id := range serverIDs {
+		skip[id] = true
+	}
+
+	agents := []string{}
+	for _, id := range ids {
+		if skip[id] {
+			continue
+		}
+
+		skip[id] = true
+		agents = append(agents, id)
+	}
+
+	return agents, nil
+}
+
+// removeNode detaches the node container from the networks and removes it
+func (c *K8sCluster) removeNode(id string) error {
+	for _, n := range c.config.Networks {
+		err := c.client.DetachNetwork(dockerNetworkName(&c.config.ResourceInfo, n.Name, c.client.Workspace()), id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.client.RemoveContainer(id, false)
+}
+
 func writeConnectorNamespace(path string) error {
 	return ioutil.WriteFile(path, []byte(connectorNamespace), os.ModePerm)
 }
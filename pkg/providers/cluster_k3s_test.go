@@ -18,6 +18,7 @@ import (
 	"github.com/shipyard-run/shipyard/pkg/utils"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
 )
 
 // setupClusterMocks sets up a happy path for mocks
@@ -26,6 +27,7 @@ func setupClusterMocks(t *testing.T) (
 
 	md := &mocks.MockContainerTasks{}
 	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
+	md.On("FindResourceContainerIDs", mock.Anything).Return([]string{}, nil)
 	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
 	md.On("CreateVolume", mock.Anything, mock.Anything).Return("123", nil)
 	md.On("CreateContainer", mock.Anything).Return("containerid", nil)
@@ -102,7 +104,7 @@ func TestClusterK3ErrorsWhenUnableToLookupIDs(t *testing.T) {
 	mk := &clients.MockKubernetes{}
 	p := NewK8sCluster(clusterConfig, md, mk, nil, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -112,7 +114,7 @@ func TestClusterK3SetsEnvironment(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -132,7 +134,7 @@ func TestClusterK3DoesNotSetProxyEnvironmentWithWrongVersion(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -147,7 +149,7 @@ func TestClusterK3ErrorsWhenClusterExists(t *testing.T) {
 	mk := &clients.MockKubernetes{}
 	p := NewK8sCluster(clusterConfig, md, mk, nil, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -157,17 +159,29 @@ func TestClusterK3PullsImageUsingBase(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", config.Image{Name: "shipyardrun/k3s:v1.18.16"}, false)
 }
 
+func TestClusterK3SetsResolvedVersion(t *testing.T) {
+	cc, md, mk, mc := setupClusterMocks(t)
+	cc.Version = ""
+
+	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, config.DefaultK8sVersion, cc.ResolvedVersion)
+	assert.Equal(t, "", cc.Version)
+}
+
 func TestClusterK3PullsImageUsingCustom(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", config.Image{Name: "shipyardrun/k3s:v1.0.0"}, false)
 }
@@ -177,7 +191,7 @@ func TestClusterK3CreatesANewVolume(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CreateVolume", utils.ImageVolumeName)
 }
@@ -190,7 +204,7 @@ func TestClusterK3FailsWhenUnableToCreatesANewVolume(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 	md.AssertCalled(t, "CreateVolume", utils.ImageVolumeName)
 }
@@ -200,7 +214,7 @@ func TestClusterK3CreatesAServer(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -250,7 +264,7 @@ func TestClusterK3CreatesAServerWithAdditionalPorts(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -276,7 +290,7 @@ func TestClusterK3sErrorsIfServerNOTStart(t *testing.T) {
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 	startTimeout = 10 * time.Millisecond // reset the startTimeout, do not want to wait 120s
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -286,7 +300,7 @@ func TestClusterK3sDownloadsConfig(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CopyFromContainer")[0].Arguments
@@ -303,7 +317,7 @@ func TestClusterK3sRaisesErrorWhenUnableToDownloadConfig(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -319,7 +333,7 @@ func TestClusterK3sSetsServerInConfig(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	// check the kubeconfig file for docker uses a network ip not localhost
@@ -341,7 +355,7 @@ func TestClusterK3sCreatesDockerConfig(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	// check the kubeconfig file for docker uses a network ip not localhost
@@ -363,7 +377,7 @@ func TestClusterK3sCreatesKubeClient(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	mk.AssertCalled(t, "SetConfig", mock.Anything)
 }
@@ -376,7 +390,7 @@ func TestClusterK3sErrorsWhenFailedToCreateKubeClient(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -385,7 +399,7 @@ func TestClusterK3sWaitsForPods(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	mk.AssertCalled(t, "HealthCheckPods", []string{""}, startTimeout)
 }
@@ -398,7 +412,7 @@ func TestClusterK3sErrorsWhenWaitsForPodsFail(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -408,7 +422,7 @@ func TestClusterK3sStreamsLogsWhenRunning(t *testing.T) {
 	mk.On("GetPodLogs", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	logReader, err := mk.GetPodLogs(context.TODO(), mock.Anything, mock.Anything)
@@ -422,7 +436,7 @@ func TestClusterK3sImportDockerImagesPullsImages(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", clusterConfig.Images[0], false)
 	md.AssertCalled(t, "PullImage", clusterConfig.Images[1], false)
@@ -433,7 +447,7 @@ func TestClusterK3sImportDockerCopiesImages(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CopyLocalDockerImagesToVolume", []string{"consul:1.6.1", "vault:1.6.1"}, utils.FQDNVolumeName(utils.ImageVolumeName), false)
 }
@@ -445,7 +459,7 @@ func TestClusterK3sImportDockerCopyImageFailReturnsError(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -453,7 +467,7 @@ func TestClusterK3sImportDockerRunsExecCommand(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
-	err := p.Create()
+	err := p.Create(context.Background())
 
 	assert.NoError(t, err)
 	md.AssertCalled(t, "ExecuteCommand", "containerid", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
@@ -466,7 +480,7 @@ func TestClusterK3sImportDockerExecFailReturnsError(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -475,7 +489,7 @@ func TestClusterK3sGeneratesCertsForConnector(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mc.AssertCalled(t, "GetLocalCertBundle", mock.Anything)
@@ -494,7 +508,7 @@ func TestClusterK3sGeneratesCertsForDeployment(t *testing.T) {
 
 	cp := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := cp.Create()
+	err := cp.Create(context.Background())
 	assert.NoError(t, err)
 
 	//args := getCalls(&mk.Mock, "Apply")[0]
@@ -508,7 +522,7 @@ func TestClusterK3sDeploysConnector(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	files := []string{
@@ -533,23 +547,143 @@ func TestClusterK3sWaitsForConnectorStart(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mk.AssertCalled(t, "HealthCheckPods", []string{"app=connector"}, 60*time.Second)
 }
 
+func TestClusterK3CreatesAgentNodes(t *testing.T) {
+	cc, md, mk, mc := setupClusterMocks(t)
+	cc.Nodes = 3
+
+	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	calls := getCalls(&md.Mock, "CreateContainer")
+	assert.Len(t, calls, 3)
+
+	agent := calls[2].Arguments[0].(*config.Container)
+	assert.Equal(t, "2.agent.test", agent.Name)
+	assert.Equal(t, "agent", agent.Command[0])
+	assert.Equal(t, cc.Networks, agent.Networks)
+	assert.Contains(t, agent.EnvVar["K3S_URL"], "server.test.k8s-cluster.shipyard.run")
+	assert.Equal(t, "mysupersecret", agent.EnvVar["K3S_TOKEN"])
+	assert.NotContains(t, agent.EnvVar, "K3S_KUBECONFIG_OUTPUT")
+}
+
+func TestClusterK3DoesNotCreateAgentNodesByDefault(t *testing.T) {
+	cc, md, mk, mc := setupClusterMocks(t)
+
+	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "CreateContainer", 1)
+}
+
+func setupExternalCluster(t *testing.T) (*config.K8sCluster, *mocks.MockContainerTasks, *clients.MockKubernetes) {
+	cc, md, mk, _ := setupClusterMocks(t)
+	cc.External = true
+	cc.KubeConfig = filepath.Join(t.TempDir(), "kubeconfig.yaml")
+
+	err := ioutil.WriteFile(cc.KubeConfig, []byte(kubeconfig), 0600)
+	assert.NoError(t, err)
+
+	mk.On("GetPods", "").Return(&v1.PodList{}, nil)
+
+	return cc, md, mk
+}
+
+func TestClusterExternalCopiesKubeConfigAndChecksConnection(t *testing.T) {
+	cc, md, mk := setupExternalCluster(t)
+
+	p := NewK8sCluster(cc, md, mk, nil, nil, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	_, kubePath, dockerPath := utils.CreateKubeConfigPath(cc.Name)
+	assert.FileExists(t, kubePath)
+	assert.FileExists(t, dockerPath)
+
+	mk.AssertCalled(t, "SetConfig", kubePath)
+	mk.AssertCalled(t, "GetPods", "")
+	md.AssertNotCalled(t, "CreateContainer", mock.Anything)
+}
+
+func TestClusterExternalReturnsErrorWhenUnreachable(t *testing.T) {
+	cc, md, mk := setupExternalCluster(t)
+	removeOn(&mk.Mock, "GetPods")
+	mk.On("GetPods", "").Return(nil, fmt.Errorf("boom"))
+
+	p := NewK8sCluster(cc, md, mk, nil, nil, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestClusterExternalDestroyDoesNotRemoveContainers(t *testing.T) {
+	cc, md, mk := setupExternalCluster(t)
+
+	p := NewK8sCluster(cc, md, mk, nil, nil, hclog.NewNullLogger())
+
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "FindContainerIDs", mock.Anything, mock.Anything)
+	md.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything)
+}
+
 // Destroy Tests
 func TestClusterK3sDestroyGetsIDr(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "FindContainerIDs", "server."+clusterConfig.Name, clusterConfig.Type)
+}
+
+func TestClusterK3sDestroyRemovesAgentNodes(t *testing.T) {
+	cc, md, mk, mc := setupClusterMocks(t)
+	cc.Nodes = 2
+
+	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
+
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
+	md.AssertCalled(t, "FindContainerIDs", "1.agent."+clusterConfig.Name, clusterConfig.Type)
 	md.AssertCalled(t, "FindContainerIDs", "server."+clusterConfig.Name, clusterConfig.Type)
 }
 
+func TestClusterK3sDestroyRemovesLabelledAgentsBeforeServer(t *testing.T) {
+	cc, md, mk, mc := setupClusterMocks(t)
+	cc.Nodes = 1
+
+	removeOn(&md.Mock, "FindContainerIDs")
+	md.On("FindContainerIDs", "server."+clusterConfig.Name, clusterConfig.Type).Return([]string{"server"}, nil)
+
+	// agents left from a previous apply with more nodes are found by label
+	removeOn(&md.Mock, "FindResourceContainerIDs")
+	md.On("FindResourceContainerIDs", "k8s_cluster."+clusterConfig.Name).Return([]string{"server", "agent1", "agent2"}, nil)
+
+	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
+
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+
+	calls := getCalls(&md.Mock, "RemoveContainer")
+	assert.Len(t, calls, 3)
+	assert.Equal(t, "agent1", calls[0].Arguments[0])
+	assert.Equal(t, "agent2", calls[1].Arguments[0])
+	assert.Equal(t, "server", calls[2].Arguments[0])
+}
+
 func TestClusterK3sDestroyWithFindIDErrorReturnsError(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 	removeOn(&md.Mock, "FindContainerIDs")
@@ -557,7 +691,7 @@ func TestClusterK3sDestroyWithFindIDErrorReturnsError(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.Error(t, err)
 }
 
@@ -568,7 +702,7 @@ func TestClusterK3sDestroyWithNoIDReturns(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	md.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything)
 }
@@ -580,7 +714,7 @@ func TestClusterK3sDestroyRemovesContainer(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "RemoveContainer", mock.Anything, false)
 }
@@ -594,7 +728,7 @@ func TestClusterK3sDestroyRemovesConfig(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "RemoveContainer", mock.Anything, false)
 
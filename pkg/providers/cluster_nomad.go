@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -60,12 +61,12 @@ func NewNomadCluster(c *config.NomadCluster, cc clients.ContainerTasks, hc clien
 }
 
 // Create implements interface method to create a cluster of the specified type
-func (c *NomadCluster) Create() error {
+func (c *NomadCluster) Create(ctx context.Context) error {
 	return c.createNomad()
 }
 
 // Destroy implements interface method to destroy a cluster
-func (c *NomadCluster) Destroy() error {
+func (c *NomadCluster) Destroy(ctx context.Context) error {
 	return c.destroyNomad()
 }
 
@@ -148,7 +149,7 @@ func (c *NomadCluster) createNomad() error {
 			cMutex.Unlock()
 
 			clWait.Done()
-		}(i+1, image, volID, configPath, utils.FQDN(fmt.Sprintf("server.%s", c.config.Name), string(config.TypeNomadCluster)))
+		}(i+1, image, volID, configPath, utils.WorkspaceFQDN(fmt.Sprintf("server.%s", c.config.Name), string(config.TypeNomadCluster), c.client.Workspace()))
 	}
 
 	clWait.Wait()
@@ -500,7 +501,7 @@ func (c *NomadCluster) destroyNode(id string) error {
 		// remove from the networks
 		for _, n := range c.config.Networks {
 			c.log.Debug("Detaching container from network", "ref", c.config.Name, "id", i, "network", n.Name)
-			err := c.client.DetachNetwork(n.Name, i)
+			err := c.client.DetachNetwork(dockerNetworkName(&c.config.ResourceInfo, n.Name, c.client.Workspace()), i)
 			if err != nil {
 				c.log.Error("Unable to detach network", "ref", c.config.Name, "network", n.Name, "error", err)
 			}
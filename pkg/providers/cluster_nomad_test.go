@@ -2,6 +2,7 @@ package providers
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -70,7 +71,7 @@ func TestClusterNomadErrorsWhenUnableToLookupIDs(t *testing.T) {
 
 	p := NewNomadCluster(clusterNomadConfig, md, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -80,7 +81,7 @@ func TestClusterNomadErrorsWhenClusterExists(t *testing.T) {
 
 	p := NewNomadCluster(clusterNomadConfig, md, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -92,7 +93,7 @@ func TestClusterNomadErrorsWhenWorkerNodesExist(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -101,7 +102,7 @@ func TestClusterNomadPullsImage(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", config.Image{Name: "shipyardrun/nomad:v1.0.0"}, false)
 }
@@ -112,7 +113,7 @@ func TestClusterNomadPullsImageWithDefault(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", config.Image{Name: "shipyardrun/nomad:" + nomadBaseVersion}, false)
 }
@@ -122,7 +123,7 @@ func TestClusterNomadCreatesANewVolume(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CreateVolume", utils.ImageVolumeName)
 }
@@ -135,7 +136,7 @@ func TestClusterNomadFailsWhenUnableToCreatesANewVolume(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 	md.AssertCalled(t, "CreateVolume", utils.ImageVolumeName)
 }
@@ -147,7 +148,7 @@ func TestClusterNomadCreatesAServer(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -210,7 +211,7 @@ func TestClusterNomadCreatesClientNodes(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertNumberOfCalls(t, "CreateContainer", 4)
@@ -224,7 +225,7 @@ func TestClusterNomadCreatesClientNodesWithCorrectDetails(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertNumberOfCalls(t, "CreateContainer", 2)
@@ -271,7 +272,7 @@ func TestClusterNomadSetsNodeCountInConfig(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	conf, _ := utils.GetClusterConfig(string(config.TypeNomadCluster) + "." + cc.Name)
@@ -284,7 +285,7 @@ func TestClusterNomadHealthChecksAPI(t *testing.T) {
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 	startTimeout = 10 * time.Millisecond // reset the startTimeout, do not want to wait 120s
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mh.AssertCalled(t, "HealthCheckAPI", mock.Anything)
@@ -299,7 +300,7 @@ func TestClusterNomadErrorsIfHealthFails(t *testing.T) {
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 	startTimeout = 10 * time.Millisecond // reset the startTimeout, do not want to wait 120s
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -308,7 +309,7 @@ func TestClusterNomadImportDockerImagesPullsImages(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", clusterConfig.Images[0], false)
 	md.AssertCalled(t, "PullImage", clusterConfig.Images[1], false)
@@ -319,7 +320,7 @@ func TestClusterNomadImportDockerCopiesImages(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CopyLocalDockerImagesToVolume", []string{"consul:1.6.1", "vault:1.6.1"}, "images.volume.shipyard.run", false)
 }
@@ -331,7 +332,7 @@ func TestClusterNomadImportDockerCopyImageFailReturnsError(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -341,7 +342,7 @@ func TestClusterNomadImportDockerRunsExecCommand(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	importCommand := []string{"docker", "load", "-i", "file.tar.gz"}
@@ -356,7 +357,7 @@ func TestClusterNomadImportDockerExecFailReturnsError(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -367,7 +368,7 @@ func TestClusterNomadSetsEnvironmentOnServer(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -392,7 +393,7 @@ func TestClusterNomadDoesNotSetProxyEnvironmentWithWrongVersion(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -409,7 +410,7 @@ func TestClusterNomadDestroyGetsIDs(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "FindContainerIDs", "server."+clusterNomadConfig.Name, clusterNomadConfig.Type)
 	md.AssertCalled(t, "FindContainerIDs", "1.client."+clusterNomadConfig.Name, clusterNomadConfig.Type)
@@ -424,7 +425,7 @@ func TestClusterNomadDestroyWithFindIDErrorReturnsError(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.Error(t, err)
 }
 
@@ -437,7 +438,7 @@ func TestClusterNomadDestroyWithFindIDClientNodeErrorReturnsError(t *testing.T)
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.Error(t, err)
 }
 
@@ -448,7 +449,7 @@ func TestClusterNomadDestroyWithNoIDReturns(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	md.AssertNotCalled(t, "RemoveContainer", mock.Anything)
 }
@@ -461,7 +462,7 @@ func TestClusterNomadDestroyRemovesContainer(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	md.AssertNumberOfCalls(t, "RemoveContainer", 4)
 }
@@ -475,7 +476,7 @@ func TestClusterNomadDestroyRemovesConfig(t *testing.T) {
 
 	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "RemoveContainer", mock.Anything, mock.Anything)
 
@@ -1,11 +1,19 @@
 package providers
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients"
 	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
 	"golang.org/x/xerrors"
 )
 
@@ -38,27 +46,38 @@ func NewContainerSidecar(cs *config.Sidecar, cl clients.ContainerTasks, hc clien
 	co.Type = cs.Type
 	co.Config = cs.Config
 	co.MaxRestartCount = cs.MaxRestartCount
+	co.StopTimeout = cs.StopTimeout
 
 	return &Container{co, cl, hc, l}
 }
 
 // Create implements provider method and creates a Docker container with the given config
-func (c *Container) Create() error {
+func (c *Container) Create(ctx context.Context) error {
 	c.log.Info("Creating Container", "ref", c.config.Name)
 
-	return c.internalCreate()
+	return c.internalCreate(ctx)
 }
 
-func (c *Container) internalCreate() error {
+func (c *Container) internalCreate(ctx context.Context) error {
 	// do we need to build an image
 	if c.config.Build != nil {
 		c.log.Debug("Building image", "context", c.config.Build.Context, "dockerfile", c.config.Build.File)
 
-		name, err := c.client.BuildContainer(c.config, false)
+		checksum, err := c.config.Build.Checksum()
 		if err != nil {
 			return xerrors.Errorf("Unable to build image: %w", err)
 		}
 
+		// rebuild the image when the context or arguments have changed since the last build
+		force := checksum != c.config.BuildChecksum
+
+		name, err := c.client.BuildContainer(c.config, force)
+		if err != nil {
+			return xerrors.Errorf("Unable to build image: %w", err)
+		}
+
+		c.config.BuildChecksum = checksum
+
 		// set the image to be loaded and continue with the container creation
 		c.config.Image = &config.Image{Name: name}
 	} else {
@@ -71,33 +90,301 @@ func (c *Container) internalCreate() error {
 		}
 	}
 
-	_, err := c.client.CreateContainer(c.config)
+	cc, err := c.withFileMounts()
+	if err != nil {
+		return err
+	}
+
+	id, err := c.client.CreateContainer(cc)
+	if err != nil {
+		return err
+	}
+
+	// init containers must exit successfully before the container is created
+	if c.config.Init {
+		err := c.waitForExit(ctx, id)
+		if err != nil {
+			return c.withLogs(id, err)
+		}
+
+		return nil
+	}
+
+	// wait for the container to stabilize
+	if c.config.Startup != nil {
+		err := c.checkStartup(ctx, id)
+		if err != nil {
+			return c.withLogs(id, err)
+		}
+	}
 
 	if c.config.HealthCheck == nil {
+		return nil
+	}
+
+	err = c.checkHealth(ctx, id)
+	if err != nil {
+		return c.withLogs(id, err)
+	}
+
+	return nil
+}
+
+// containerLogLines is the number of lines of container output added to the
+// error when a container fails to start
+const containerLogLines = 20
+
+// withLogs writes the output of the container to the logs folder and appends
+// the last lines of the output to the error so failures can be debugged
+// without running docker logs
+func (c *Container) withLogs(id string, err error) error {
+	rc, lerr := c.client.ContainerLogs(id, true, true)
+	if lerr != nil {
+		c.log.Error("Unable to get logs from container", "ref", c.config.Name, "error", lerr)
 		return err
 	}
+	defer rc.Close()
 
-	// check the health of the container
-	if hc := c.config.HealthCheck.HTTP; hc != "" {
-		d, err := time.ParseDuration(c.config.HealthCheck.Timeout)
+	raw, lerr := ioutil.ReadAll(rc)
+	if lerr != nil {
+		c.log.Error("Unable to read logs from container", "ref", c.config.Name, "error", lerr)
+		return err
+	}
+
+	// logs for containers without a TTY are multiplexed, when the output
+	// can not be demultiplexed use the raw output
+	out := &bytes.Buffer{}
+	_, lerr = stdcopy.StdCopy(out, out, bytes.NewReader(raw))
+	if lerr != nil {
+		out = bytes.NewBuffer(raw)
+	}
+
+	logs := strings.TrimRight(out.String(), "\n")
+	if logs == "" {
+		return err
+	}
+
+	path := filepath.Join(utils.LogsDir(), fmt.Sprintf("%s.%s.log", c.config.Type, c.config.Name))
+	lerr = ioutil.WriteFile(path, out.Bytes(), 0644)
+	if lerr != nil {
+		c.log.Error("Unable to write container logs", "ref", c.config.Name, "path", path, "error", lerr)
+		path = ""
+	}
+
+	lines := strings.Split(logs, "\n")
+	if len(lines) > containerLogLines {
+		lines = lines[len(lines)-containerLogLines:]
+	}
+
+	msg := fmt.Sprintf("last %d lines of container output", len(lines))
+	if path != "" {
+		msg = fmt.Sprintf("%s, full output written to %s", msg, path)
+	}
+
+	return fmt.Errorf("%w\n\n%s:\n%s", err, msg, strings.Join(lines, "\n"))
+}
+
+// withFileMounts returns the container config with read only volumes for the
+// secrets and configs mounted into the container. The volumes are added to a copy
+// so they are not written to the state.
+func (c *Container) withFileMounts() (*config.Container, error) {
+	if len(c.config.Secrets) == 0 && len(c.config.Configs) == 0 {
+		return c.config, nil
+	}
+
+	cc := *c.config
+	cc.Volumes = append([]config.Volume{}, c.config.Volumes...)
+
+	for _, m := range append(append([]config.FileMount{}, c.config.Secrets...), c.config.Configs...) {
+		r, err := c.config.FindDependentResource(m.Name)
 		if err != nil {
-			return err
+			return nil, xerrors.Errorf("Unable to find %s to mount in container %s: %w", m.Name, c.config.Name, err)
+		}
+
+		var path string
+		switch v := r.(type) {
+		case *config.Secret:
+			path = v.Path
+		case *config.DockerConfig:
+			path = v.Path
+		default:
+			return nil, fmt.Errorf("Unable to mount %s in container %s, only secret and config resources can be mounted", m.Name, c.config.Name)
+		}
+
+		if path == "" {
+			return nil, fmt.Errorf("Unable to mount %s in container %s, the file has not been created", m.Name, c.config.Name)
+		}
+
+		cc.Volumes = append(cc.Volumes, config.Volume{Source: path, Destination: m.Target, Type: "bind", ReadOnly: true})
+	}
+
+	return &cc, nil
+}
+
+// initPollInterval is the time between checks that an init container has exited
+var initPollInterval = 1 * time.Second
+
+// waitForExit blocks until the init container has exited, an error is returned
+// when the container exits with a non zero exit code. The time waited is limited
+// by the timeout for the resource.
+func (c *Container) waitForExit(ctx context.Context, id string) error {
+	c.log.Debug("Waiting for init container to complete", "ref", c.config.Name)
+
+	for {
+		info, err := c.client.ContainerInfo(id)
+		if err != nil {
+			return xerrors.Errorf("Unable to check container status: %w", err)
+		}
+
+		if cj, ok := info.(types.ContainerJSON); ok && cj.ContainerJSONBase != nil && cj.State != nil {
+			if cj.State.Status == "exited" || cj.State.Status == "dead" {
+				if cj.State.ExitCode != 0 {
+					return fmt.Errorf("Init container %s exited with code %d", c.config.Name, cj.State.ExitCode)
+				}
+
+				return nil
+			}
+		}
+
+		err = sleepContext(ctx, initPollInterval)
+		if err != nil {
+			return xerrors.Errorf("Cancelled waiting for init container %s: %w", c.config.Name, err)
+		}
+	}
+}
+
+// checkStartup ensures the container is running at the end of the startup grace period
+// and has not restarted more than the allowed number of times
+func (c *Container) checkStartup(ctx context.Context, id string) error {
+	d, err := time.ParseDuration(c.config.Startup.GracePeriod)
+	if err != nil {
+		return xerrors.Errorf("unable to parse startup grace_period: %w", err)
+	}
+
+	c.log.Debug("Waiting for container to stabilize", "ref", c.config.Name, "grace_period", d)
+
+	st := time.Now()
+	for {
+		info, err := c.client.ContainerInfo(id)
+		if err != nil {
+			return xerrors.Errorf("Unable to check container status: %w", err)
+		}
+
+		running := false
+		if cj, ok := info.(types.ContainerJSON); ok && cj.ContainerJSONBase != nil && cj.State != nil {
+			if cj.RestartCount > c.config.Startup.MaxRestarts {
+				return fmt.Errorf("Container %s restarted %d times during the startup grace period, the maximum is %d", c.config.Name, cj.RestartCount, c.config.Startup.MaxRestarts)
+			}
+
+			// without a restart policy an exited container will not recover
+			if cj.State.Status == "exited" && !c.config.RestartsOnExit() {
+				return fmt.Errorf("Container %s exited during the startup grace period with exit code %d", c.config.Name, cj.State.ExitCode)
+			}
+
+			running = cj.State.Running && !cj.State.Restarting
+		}
+
+		elapsed := time.Since(st)
+		if elapsed >= d {
+			if !running {
+				return fmt.Errorf("Container %s is not running at the end of the startup grace period", c.config.Name)
+			}
+
+			return nil
+		}
+
+		wait := d - elapsed
+		if wait > time.Second {
+			wait = time.Second
 		}
 
+		err = sleepContext(ctx, wait)
+		if err != nil {
+			return xerrors.Errorf("Cancelled waiting for container %s to start: %w", c.config.Name, err)
+		}
+	}
+}
+
+// checkHealth runs the HTTP, TCP, and exec health checks for the container,
+// all checks must pass before the health check timeout elapses
+func (c *Container) checkHealth(ctx context.Context, id string) error {
+	hc := c.config.HealthCheck
+
+	d, err := time.ParseDuration(hc.Timeout)
+	if err != nil {
+		return xerrors.Errorf("unable to parse health_check timeout: %w", err)
+	}
+
+	interval := time.Second
+	if hc.Interval != "" {
+		interval, err = time.ParseDuration(hc.Interval)
+		if err != nil {
+			return xerrors.Errorf("unable to parse health_check interval: %w", err)
+		}
+	}
+
+	c.log.Debug("Checking health of container", "ref", c.config.Name, "timeout", d, "interval", interval)
+
+	// the checks are run in order, each check is retried at the interval
+	// until it passes before the next check is run
+	type healthCheck struct {
+		name  string
+		check func() error
+	}
+
+	checks := []healthCheck{}
+
+	if hc.HTTP != "" {
 		// do we have custom status codes, if not use 200
-		codes := c.config.HealthCheck.HTTPSuccessCodes
+		codes := hc.HTTPSuccessCodes
 		if codes == nil {
 			codes = []int{200}
 		}
 
-		return c.httpClient.HealthCheckHTTP(hc, codes, d)
+		checks = append(checks, healthCheck{"HTTP", func() error {
+			return c.httpClient.CheckHTTP(hc.HTTP, codes)
+		}})
+	}
+
+	if hc.TCP != "" {
+		checks = append(checks, healthCheck{"TCP", func() error {
+			return c.httpClient.CheckTCP(hc.TCP, interval)
+		}})
+	}
+
+	if len(hc.Exec) > 0 {
+		checks = append(checks, healthCheck{"exec", func() error {
+			return c.client.ExecuteCommand(id, hc.Exec, nil, "/", "", "", nil)
+		}})
+	}
+
+	st := time.Now()
+	next := 0
+
+	for next < len(checks) {
+		err := checks[next].check()
+		if err == nil {
+			next++
+			continue
+		}
+
+		if time.Since(st)+interval > d {
+			c.log.Error("Timeout waiting for health check", "ref", c.config.Name, "check", checks[next].name)
+
+			return xerrors.Errorf("Container %s failed %s health check: %w", c.config.Name, checks[next].name, err)
+		}
+
+		err = sleepContext(ctx, interval)
+		if err != nil {
+			return xerrors.Errorf("Cancelled waiting for %s health check for container %s: %w", checks[next].name, c.config.Name, err)
+		}
 	}
 
 	return nil
 }
 
 // Destroy stops and removes the container
-func (c *Container) Destroy() error {
+func (c *Container) Destroy(ctx context.Context) error {
 	c.log.Info("Destroy Container", "ref", c.config.Name)
 
 	return c.internalDestroy()
@@ -109,9 +396,17 @@ func (c *Container) internalDestroy() error {
 		return err
 	}
 
+	removeVolumes := c.config.RemoveVolumes == nil || *c.config.RemoveVolumes
+
+	// the stop timeout is validated when the config is parsed
+	var stopTimeout time.Duration
+	if c.config.StopTimeout != "" {
+		stopTimeout, _ = time.ParseDuration(c.config.StopTimeout)
+	}
+
 	if len(ids) > 0 {
 		for _, id := range ids {
-			err := c.client.RemoveContainer(id, false)
+			err := c.client.RemoveContainerWithOptions(id, false, removeVolumes, stopTimeout)
 
 			if err != nil {
 				return err
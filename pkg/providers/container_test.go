@@ -1,13 +1,21 @@
 package providers
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
 	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
 )
@@ -25,10 +33,43 @@ func TestContainerCreatesSuccessfully(t *testing.T) {
 	// check calls CreateContainer with the config
 	md.On("CreateContainer", cc).Once().Return("", nil)
 
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
-	hc.AssertNotCalled(t, "HealthCheckHTTP", mock.Anything, mock.Anything)
+	hc.AssertNotCalled(t, "CheckHTTP", mock.Anything, mock.Anything)
+}
+
+func TestContainerCreateMountsSecretsAndConfigs(t *testing.T) {
+	s := config.NewSecret("db")
+	s.Path = "/shipyard/data/secret/db"
+
+	dc := config.NewDockerConfig("app")
+	dc.Path = "/shipyard/data/config/app"
+
+	cc := config.NewContainer("tests")
+	cc.Image = &config.Image{}
+	cc.Secrets = []config.FileMount{{Name: "secret.db", Target: "/run/secrets/db"}}
+	cc.Configs = []config.FileMount{{Name: "config.app", Target: "/etc/app.json"}}
+
+	c := config.New()
+	c.AddResource(s)
+	c.AddResource(dc)
+	c.AddResource(cc)
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, false).Once().Return(nil)
+	md.On("CreateContainer", mock.Anything).Once().Return("", nil)
+
+	p := NewContainer(cc, md, &mocks.MockHTTP{}, hclog.NewNullLogger())
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	ac := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Contains(t, ac.Volumes, config.Volume{Source: "/shipyard/data/secret/db", Destination: "/run/secrets/db", Type: "bind", ReadOnly: true})
+	assert.Contains(t, ac.Volumes, config.Volume{Source: "/shipyard/data/config/app", Destination: "/etc/app.json", Type: "bind", ReadOnly: true})
+
+	// the volumes must not be added to the config saved in the state
+	assert.Len(t, cc.Volumes, 0)
 }
 
 func TestContainerSidecarCreatesContainerSuccessfully(t *testing.T) {
@@ -52,7 +93,7 @@ func TestContainerSidecarCreatesContainerSuccessfully(t *testing.T) {
 	md.On("CreateContainer", mock.Anything).Once().Return("", nil)
 
 	c := NewContainerSidecar(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	ac := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -88,12 +129,12 @@ func TestContainerRunsHTTPChecks(t *testing.T) {
 	md.On("PullImage", *cc.Image, false).Once().Return(nil)
 	md.On("CreateContainer", cc).Once().Return("", nil)
 
-	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	hc.On("CheckHTTP", mock.Anything, mock.Anything).Return(nil)
 
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
-	hc.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8500", []int{200}, 30*time.Second)
+	hc.AssertCalled(t, "CheckHTTP", "http://localhost:8500", []int{200})
 }
 
 func TestContainerRunsHTTPChecksWithCustomStatusCodes(t *testing.T) {
@@ -112,12 +153,167 @@ func TestContainerRunsHTTPChecksWithCustomStatusCodes(t *testing.T) {
 	md.On("PullImage", *cc.Image, false).Once().Return(nil)
 	md.On("CreateContainer", cc).Once().Return("", nil)
 
-	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	hc.On("CheckHTTP", mock.Anything, mock.Anything).Return(nil)
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	hc.AssertCalled(t, "CheckHTTP", "http://localhost:8500", []int{200, 429})
+}
+
+func TestContainerRetriesHTTPChecksAtInterval(t *testing.T) {
+	cc := config.NewContainer("tests")
+	cc.Image = &config.Image{}
+	cc.HealthCheck = &config.HealthCheck{
+		Timeout:  "30s",
+		Interval: "1ms",
+		HTTP:     "http://localhost:8500",
+	}
+
+	md := &mocks.MockContainerTasks{}
+	hc := &mocks.MockHTTP{}
+	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
+
+	md.On("PullImage", *cc.Image, false).Once().Return(nil)
+	md.On("CreateContainer", cc).Once().Return("", nil)
+
+	hc.On("CheckHTTP", mock.Anything, mock.Anything).Once().Return(fmt.Errorf("boom"))
+	hc.On("CheckHTTP", mock.Anything, mock.Anything).Once().Return(nil)
 
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
-	hc.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8500", []int{200, 429}, 30*time.Second)
+	hc.AssertNumberOfCalls(t, "CheckHTTP", 2)
+}
+
+func TestContainerRunsTCPChecks(t *testing.T) {
+	cc := config.NewContainer("tests")
+	cc.Image = &config.Image{}
+	cc.HealthCheck = &config.HealthCheck{
+		Timeout: "30s",
+		TCP:     "localhost:8500",
+	}
+
+	md := &mocks.MockContainerTasks{}
+	hc := &mocks.MockHTTP{}
+	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
+
+	md.On("PullImage", *cc.Image, false).Once().Return(nil)
+	md.On("CreateContainer", cc).Once().Return("", nil)
+
+	hc.On("CheckTCP", mock.Anything, mock.Anything).Return(nil)
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	hc.AssertCalled(t, "CheckTCP", "localhost:8500", mock.Anything)
+}
+
+func TestContainerReturnsErrorWhenTCPCheckFails(t *testing.T) {
+	cc := config.NewContainer("tests")
+	cc.Image = &config.Image{}
+	cc.HealthCheck = &config.HealthCheck{
+		Timeout:  "10ms",
+		Interval: "1ms",
+		TCP:      "localhost:8500",
+	}
+
+	md := &mocks.MockContainerTasks{}
+	hc := &mocks.MockHTTP{}
+	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
+
+	md.On("PullImage", *cc.Image, false).Once().Return(nil)
+	md.On("CreateContainer", cc).Once().Return("", nil)
+
+	md.On("ContainerLogs", mock.Anything, true, true).Return(nil, fmt.Errorf("boom"))
+	hc.On("CheckTCP", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := c.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestContainerRunsExecChecks(t *testing.T) {
+	cc := config.NewContainer("tests")
+	cc.Image = &config.Image{}
+	cc.HealthCheck = &config.HealthCheck{
+		Timeout:  "30s",
+		Interval: "1ms",
+		Exec:     []string{"pg_isready"},
+	}
+
+	md := &mocks.MockContainerTasks{}
+	hc := &mocks.MockHTTP{}
+	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
+
+	md.On("PullImage", *cc.Image, false).Once().Return(nil)
+	md.On("CreateContainer", cc).Once().Return("abc", nil)
+	md.On("ExecuteCommand", "abc", []string{"pg_isready"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once().Return(fmt.Errorf("boom"))
+	md.On("ExecuteCommand", "abc", []string{"pg_isready"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "ExecuteCommand", 2)
+}
+
+func TestContainerReturnsErrorWhenExecCheckTimesOut(t *testing.T) {
+	cc := config.NewContainer("tests")
+	cc.Image = &config.Image{}
+	cc.HealthCheck = &config.HealthCheck{
+		Timeout:  "10ms",
+		Interval: "1ms",
+		Exec:     []string{"pg_isready"},
+	}
+
+	md := &mocks.MockContainerTasks{}
+	hc := &mocks.MockHTTP{}
+	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
+
+	md.On("PullImage", *cc.Image, false).Once().Return(nil)
+	md.On("CreateContainer", cc).Once().Return("abc", nil)
+	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+	md.On("ContainerLogs", "abc", true, true).Return(ioutil.NopCloser(bytes.NewBufferString("")), nil)
+
+	err := c.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestContainerAddsLogsToErrorWhenHealthCheckFails(t *testing.T) {
+	home := os.Getenv(utils.HomeEnvName())
+	os.Setenv(utils.HomeEnvName(), t.TempDir())
+	defer os.Setenv(utils.HomeEnvName(), home)
+
+	cc := config.NewContainer("tests")
+	cc.Image = &config.Image{}
+	cc.HealthCheck = &config.HealthCheck{
+		Timeout:  "10ms",
+		Interval: "1ms",
+		TCP:      "localhost:8500",
+	}
+
+	logs := &bytes.Buffer{}
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(logs, "line %d\n", i)
+	}
+
+	md := &mocks.MockContainerTasks{}
+	hc := &mocks.MockHTTP{}
+	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
+
+	md.On("PullImage", *cc.Image, false).Once().Return(nil)
+	md.On("CreateContainer", cc).Once().Return("abc", nil)
+	md.On("ContainerLogs", "abc", true, true).Return(ioutil.NopCloser(bytes.NewBufferString(logs.String())), nil)
+	hc.On("CheckTCP", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := c.Create(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 29")
+	assert.Contains(t, err.Error(), "line 10")
+	assert.NotContains(t, err.Error(), "line 9\n")
+
+	d, err := ioutil.ReadFile(filepath.Join(utils.LogsDir(), "container.tests.log"))
+	assert.NoError(t, err)
+	assert.Equal(t, logs.String(), string(d))
 }
 
 func TestContainerDoesNOTCreateWhenPullImageFail(t *testing.T) {
@@ -134,7 +330,7 @@ func TestContainerDoesNOTCreateWhenPullImageFail(t *testing.T) {
 	// check does not call CreateContainer with the config
 	md.On("CreateContainer", cc).Times(0)
 
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.Equal(t, imageErr, err)
 }
 
@@ -146,13 +342,60 @@ func TestContainerDestroysCorrectlyWhenContainerExists(t *testing.T) {
 	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
 
 	md.On("FindContainerIDs", cc.Name, cc.Type).Return([]string{"abc"}, nil)
-	md.On("RemoveContainer", "abc", false).Return(nil)
+	md.On("RemoveContainerWithOptions", "abc", false, true, time.Duration(0)).Return(nil)
 	md.On("DetachNetwork", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	err := c.Destroy()
+	err := c.Destroy(context.Background())
 	assert.NoError(t, err)
 }
 
+func TestContainerDestroyPreservesVolumesWhenRemoveVolumesFalse(t *testing.T) {
+	removeVolumes := false
+
+	cc := config.NewContainer("tests")
+	cc.RemoveVolumes = &removeVolumes
+	md := &mocks.MockContainerTasks{}
+	hc := &mocks.MockHTTP{}
+	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
+
+	md.On("FindContainerIDs", cc.Name, cc.Type).Return([]string{"abc"}, nil)
+	md.On("RemoveContainerWithOptions", "abc", false, false, time.Duration(0)).Return(nil)
+
+	err := c.Destroy(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "RemoveContainerWithOptions", "abc", false, false, time.Duration(0))
+}
+
+func TestContainerDestroyUsesStopTimeout(t *testing.T) {
+	cc := config.NewContainer("tests")
+	cc.StopTimeout = "2m"
+	md := &mocks.MockContainerTasks{}
+	hc := &mocks.MockHTTP{}
+	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
+
+	md.On("FindContainerIDs", cc.Name, cc.Type).Return([]string{"abc"}, nil)
+	md.On("RemoveContainerWithOptions", "abc", false, true, 2*time.Minute).Return(nil)
+
+	err := c.Destroy(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "RemoveContainerWithOptions", "abc", false, true, 2*time.Minute)
+}
+
+func TestSidecarDestroyUsesStopTimeout(t *testing.T) {
+	sc := config.NewSidecar("tests")
+	sc.StopTimeout = "45s"
+	md := &mocks.MockContainerTasks{}
+	hc := &mocks.MockHTTP{}
+	c := NewContainerSidecar(sc, md, hc, hclog.NewNullLogger())
+
+	md.On("FindContainerIDs", sc.Name, sc.Type).Return([]string{"abc"}, nil)
+	md.On("RemoveContainerWithOptions", "abc", false, true, 45*time.Second).Return(nil)
+
+	err := c.Destroy(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "RemoveContainerWithOptions", "abc", false, true, 45*time.Second)
+}
+
 func TestContainerDoesNotDestroysWhenNotExists(t *testing.T) {
 	cc := config.NewContainer("tests")
 	cc.Networks = []config.NetworkAttachment{config.NetworkAttachment{Name: "cloud"}}
@@ -162,9 +405,9 @@ func TestContainerDoesNotDestroysWhenNotExists(t *testing.T) {
 
 	md.On("FindContainerIDs", cc.Name, cc.Type).Return(nil, nil)
 
-	err := c.Destroy()
+	err := c.Destroy(context.Background())
 	assert.NoError(t, err)
-	md.AssertNotCalled(t, "RemoveContainer")
+	md.AssertNotCalled(t, "RemoveContainerWithOptions")
 }
 
 func TestContainerDoesNotDestroysWhenLookupError(t *testing.T) {
@@ -176,9 +419,9 @@ func TestContainerDoesNotDestroysWhenLookupError(t *testing.T) {
 
 	md.On("FindContainerIDs", cc.Name, cc.Type).Return(nil, fmt.Errorf("boom"))
 
-	err := c.Destroy()
+	err := c.Destroy(context.Background())
 	assert.Error(t, err)
-	md.AssertNotCalled(t, "RemoveContainer")
+	md.AssertNotCalled(t, "RemoveContainerWithOptions")
 }
 
 func TestContainerLooksupIDs(t *testing.T) {
@@ -197,7 +440,7 @@ func TestContainerLooksupIDs(t *testing.T) {
 
 func TestContainerBuildsContainer(t *testing.T) {
 	cc := config.NewContainer("tests")
-	cc.Build = &config.Build{Context: "./", File: "./"}
+	cc.Build = &config.Build{Context: t.TempDir(), File: "./"}
 
 	md := &mocks.MockContainerTasks{}
 	md.On("BuildContainer", mock.Anything, mock.Anything).Return("testimage", nil)
@@ -206,9 +449,145 @@ func TestContainerBuildsContainer(t *testing.T) {
 	hc := &mocks.MockHTTP{}
 	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
 
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	conf := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
 	assert.Equal(t, "testimage", conf.Image.Name)
+	assert.NotEmpty(t, conf.BuildChecksum)
+}
+
+func TestContainerDoesNotForceBuildWhenChecksumUnchanged(t *testing.T) {
+	cc := config.NewContainer("tests")
+	cc.Build = &config.Build{Context: t.TempDir()}
+
+	checksum, err := cc.Build.Checksum()
+	assert.NoError(t, err)
+	cc.BuildChecksum = checksum
+
+	md := &mocks.MockContainerTasks{}
+	md.On("BuildContainer", mock.Anything, mock.Anything).Return("testimage", nil)
+	md.On("CreateContainer", cc).Once().Return("", nil)
+
+	c := NewContainer(cc, md, &mocks.MockHTTP{}, hclog.NewNullLogger())
+
+	err = c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "BuildContainer", cc, false)
+}
+
+func TestContainerForcesBuildWhenChecksumChanged(t *testing.T) {
+	cc := config.NewContainer("tests")
+	cc.Build = &config.Build{Context: t.TempDir()}
+	cc.BuildChecksum = "old"
+
+	md := &mocks.MockContainerTasks{}
+	md.On("BuildContainer", mock.Anything, mock.Anything).Return("testimage", nil)
+	md.On("CreateContainer", cc).Once().Return("", nil)
+
+	c := NewContainer(cc, md, &mocks.MockHTTP{}, hclog.NewNullLogger())
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "BuildContainer", cc, true)
+	assert.NotEqual(t, "old", cc.BuildChecksum)
+}
+
+func setupContainerStartup(state *types.ContainerState, restarts int) (*Container, *mocks.MockContainerTasks) {
+	cc := config.NewContainer("tests")
+	cc.Image = &config.Image{}
+	cc.Startup = &config.Startup{GracePeriod: "10ms", MaxRestarts: 1}
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, false).Return(nil)
+	md.On("CreateContainer", cc).Return("abc", nil)
+	md.On("ContainerInfo", "abc").Return(
+		types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: state, RestartCount: restarts}},
+		nil,
+	)
+	md.On("ContainerLogs", "abc", true, true).Return(ioutil.NopCloser(bytes.NewBufferString("")), nil)
+
+	hc := &mocks.MockHTTP{}
+
+	return NewContainer(cc, md, hc, hclog.NewNullLogger()), md
+}
+
+func TestContainerStartupPassesWhenRunning(t *testing.T) {
+	c, md := setupContainerStartup(&types.ContainerState{Status: "running", Running: true}, 1)
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "ContainerInfo", "abc")
+}
+
+func TestContainerStartupFailsWhenRestartsExceeded(t *testing.T) {
+	c, _ := setupContainerStartup(&types.ContainerState{Status: "running", Running: true}, 2)
+
+	err := c.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func setupInitContainer(exitCode int) (*Container, *mocks.MockContainerTasks) {
+	initPollInterval = 1 * time.Millisecond
+
+	cc := config.NewContainer("tests")
+	cc.Image = &config.Image{}
+	cc.Init = true
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, false).Return(nil)
+	md.On("CreateContainer", cc).Return("abc", nil)
+	md.On("ContainerInfo", "abc").Once().Return(
+		types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Status: "running", Running: true}}},
+		nil,
+	)
+	md.On("ContainerInfo", "abc").Return(
+		types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Status: "exited", ExitCode: exitCode}}},
+		nil,
+	)
+	md.On("ContainerLogs", "abc", true, true).Return(ioutil.NopCloser(bytes.NewBufferString("")), nil)
+
+	hc := &mocks.MockHTTP{}
+
+	return NewContainer(cc, md, hc, hclog.NewNullLogger()), md
+}
+
+func TestInitContainerWaitsForExit(t *testing.T) {
+	c, md := setupInitContainer(0)
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "ContainerInfo", 2)
+}
+
+func TestInitContainerReturnsErrorWhenExitCodeNotZero(t *testing.T) {
+	c, _ := setupInitContainer(2)
+
+	err := c.Create(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exited with code 2")
+}
+
+func TestInitContainerStopsWaitingWhenContextCancelled(t *testing.T) {
+	c, md := setupInitContainer(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := c.Create(ctx)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	md.AssertNumberOfCalls(t, "ContainerInfo", 1)
+}
+
+func TestContainerStartupFailsWhenExited(t *testing.T) {
+	c, _ := setupContainerStartup(&types.ContainerState{Status: "exited", ExitCode: 1}, 0)
+
+	err := c.Create(context.Background())
+	assert.Error(t, err)
 }
@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// Copy provider copies files from the local machine into containers
+type Copy struct {
+	config *config.Copy
+	client clients.ContainerTasks
+	log    hclog.Logger
+}
+
+// NewCopy creates a new Copy provider
+func NewCopy(c *config.Copy, cc clients.ContainerTasks, l hclog.Logger) *Copy {
+	return &Copy{c, cc, l}
+}
+
+// Create copies the source file into the container
+func (c *Copy) Create(ctx context.Context) error {
+	c.log.Info("Copying file to container", "ref", c.config.Name, "source", c.config.Source, "container", c.config.Container, "destination", c.config.Destination)
+
+	fi, err := os.Stat(c.config.Source)
+	if err != nil {
+		return xerrors.Errorf("Unable to read source %s: %w", c.config.Source, err)
+	}
+
+	if fi.IsDir() {
+		return fmt.Errorf("Unable to copy source %s, source must be a file", c.config.Source)
+	}
+
+	res, err := c.config.FindDependentResource(c.config.Container)
+	if err != nil {
+		return xerrors.Errorf("Unable to find container %s: %w", c.config.Container, err)
+	}
+
+	ids, err := c.client.FindContainerIDs(res.Info().Name, res.Info().Type)
+	if err != nil {
+		return xerrors.Errorf("Unable to find container %s: %w", c.config.Container, err)
+	}
+
+	if len(ids) == 0 {
+		return fmt.Errorf("Unable to find container %s", c.config.Container)
+	}
+
+	for _, id := range ids {
+		err := c.client.CopyFileToContainer(id, c.config.Source, c.config.Destination)
+		if err != nil {
+			return xerrors.Errorf("Unable to copy file to container %s: %w", c.config.Container, err)
+		}
+	}
+
+	return nil
+}
+
+// Destroy is a no-op, the copied file is removed with the container
+func (c *Copy) Destroy(ctx context.Context) error {
+	c.log.Info("Destroy Copy", "ref", c.config.Name)
+
+	return nil
+}
+
+// Lookup is not implemented for Copy resources
+func (c *Copy) Lookup() ([]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,80 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupCopy(t *testing.T) (*config.Copy, *Copy, *mocks.MockContainerTasks) {
+	md := &mocks.MockContainerTasks{}
+	md.On("FindContainerIDs", "consul", config.TypeContainer).Return([]string{"1234"}, nil)
+	md.On("CopyFileToContainer", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	src := filepath.Join(t.TempDir(), "consul.hcl")
+	ioutil.WriteFile(src, []byte("data_dir = \"/tmp\""), 0644)
+
+	cp := config.NewCopy("config")
+	cp.Container = "container.consul"
+	cp.Source = src
+	cp.Destination = "/config"
+
+	c := config.New()
+	c.AddResource(config.NewContainer("consul"))
+	c.AddResource(cp)
+
+	return cp, NewCopy(cp, md, hclog.NewNullLogger()), md
+}
+
+func TestCopyCreateCopiesFileToContainer(t *testing.T) {
+	cp, p, md := setupCopy(t)
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "CopyFileToContainer", "1234", cp.Source, "/config")
+}
+
+func TestCopyCreateWithMissingSourceReturnsError(t *testing.T) {
+	cp, p, md := setupCopy(t)
+	cp.Source = filepath.Join(t.TempDir(), "missing.hcl")
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+
+	md.AssertNotCalled(t, "CopyFileToContainer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCopyCreateWithDirectorySourceReturnsError(t *testing.T) {
+	cp, p, _ := setupCopy(t)
+	cp.Source = t.TempDir()
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestCopyCreateWithMissingContainerReturnsError(t *testing.T) {
+	_, p, md := setupCopy(t)
+	removeOn(&md.Mock, "FindContainerIDs")
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, nil)
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestCopyCreateCopyFailReturnsError(t *testing.T) {
+	_, p, md := setupCopy(t)
+	removeOn(&md.Mock, "CopyFileToContainer")
+	md.On("CopyFileToContainer", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"io/ioutil"
@@ -31,12 +32,12 @@ func NewDocs(c *config.Docs, cc clients.ContainerTasks, l hclog.Logger) *Docs {
 }
 
 // Create a new documentation container
-func (i *Docs) Create() error {
+func (i *Docs) Create(ctx context.Context) error {
 	i.log.Info("Creating Documentation", "ref", i.config.Name)
 
 	// set the default live reload port
 	if i.config.LiveReloadPort == 0 {
-		i.config.LiveReloadPort = 37950
+		i.config.LiveReloadPort = config.DefaultLiveReloadPort
 	}
 
 	// create the documentation container
@@ -129,7 +130,7 @@ func (i *Docs) createDocsContainer() error {
 }
 
 // Destroy the documentation container
-func (i *Docs) Destroy() error {
+func (i *Docs) Destroy(ctx context.Context) error {
 	i.log.Info("Destroy Documentation", "ref", i.config.Name)
 
 	// remove the docs
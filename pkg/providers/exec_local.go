@@ -1,7 +1,10 @@
 package providers
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -25,7 +28,16 @@ func NewExecLocal(c *config.ExecLocal, ex clients.Command, l hclog.Logger) *Exec
 }
 
 // Create a new exec
-func (c *ExecLocal) Create() error {
+func (c *ExecLocal) Create(ctx context.Context) error {
+	if !c.config.RunsOnCreate() {
+		c.log.Debug("Command runs on destroy, skipping", "ref", c.config.Name)
+		return nil
+	}
+
+	return c.run(ctx)
+}
+
+func (c *ExecLocal) run(ctx context.Context) error {
 	c.log.Info("Locally executing script", "ref", c.config.Name, "script", c.config.Command, "args", c.config.Arguments)
 
 	// build the environment variables
@@ -38,6 +50,13 @@ func (c *ExecLocal) Create() error {
 		envs = append(envs, fmt.Sprintf("%s=%s", k, v))
 	}
 
+	if c.config.WorkingDirectory != "" {
+		fi, err := os.Stat(c.config.WorkingDirectory)
+		if err != nil || !fi.IsDir() {
+			return fmt.Errorf("Working directory %s does not exist", c.config.WorkingDirectory)
+		}
+	}
+
 	// create the folders for logs and pids
 	logPath := filepath.Join(utils.LogsDir(), fmt.Sprintf("exec_%s.log", c.config.Name))
 
@@ -55,6 +74,11 @@ func (c *ExecLocal) Create() error {
 		}
 	}
 
+	// stop the command when the deadline for creating the resource is reached
+	if dl, ok := ctx.Deadline(); ok && (d == 0 || time.Until(dl) < d) {
+		d = time.Until(dl)
+	}
+
 	// create the config
 	cc := clients.CommandConfig{
 		Command:          c.config.Command,
@@ -66,6 +90,12 @@ func (c *ExecLocal) Create() error {
 		Timeout:          d,
 	}
 
+	// the log file is appended to, only read the output written by this command
+	var offset int64
+	if fi, err := os.Stat(logPath); err == nil {
+		offset = fi.Size()
+	}
+
 	// set the env vars
 	p, err := c.client.Execute(cc)
 	c.config.Pid = p
@@ -76,11 +106,38 @@ func (c *ExecLocal) Create() error {
 		return err
 	}
 
+	// daemons are still running so there is no output to capture
+	if !c.config.Daemon {
+		c.config.Output = c.readOutput(logPath, offset)
+	}
+
 	return nil
 }
 
-// Destroy statisfies the interface method but is not implemented by LocalExec
-func (c *ExecLocal) Destroy() error {
+// readOutput returns the output written to the log file after offset
+func (c *ExecLocal) readOutput(path string, offset int64) string {
+	f, err := os.Open(path)
+	if err != nil {
+		c.log.Debug("Unable to read command output", "ref", c.config.Name, "error", err)
+		return ""
+	}
+	defer f.Close()
+
+	_, err = f.Seek(offset, io.SeekStart)
+	if err != nil {
+		c.log.Debug("Unable to read command output", "ref", c.config.Name, "error", err)
+		return ""
+	}
+
+	out := newOutputBuffer(c.config.OutputLimit)
+	io.Copy(out, f)
+
+	return out.String()
+}
+
+// Destroy stops the process when running as a daemon and runs the
+// command when it is set to run on destroy
+func (c *ExecLocal) Destroy(ctx context.Context) error {
 	if c.config.Daemon {
 		// attempt to destroy the process
 		c.log.Info("Stopping locally executing script", "ref", c.config.Name, "pid", c.config.Pid)
@@ -96,6 +153,10 @@ func (c *ExecLocal) Destroy() error {
 		}
 	}
 
+	if c.config.RunsOnDestroy() {
+		return c.run(ctx)
+	}
+
 	return nil
 }
 
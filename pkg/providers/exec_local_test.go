@@ -1,9 +1,13 @@
 package providers
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients"
@@ -27,7 +31,7 @@ func TestExecLocalExecutesCommandSuccessfully(t *testing.T) {
 
 	p := NewExecLocal(c, mc, hclog.Default())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mc.AssertCalled(t, "Execute", mock.Anything)
@@ -43,12 +47,29 @@ func TestExecLocalExecutesCommandSuccessfully(t *testing.T) {
 	assert.Equal(t, filepath.Join(utils.LogsDir(), "exec_test.log"), params.LogFilePath)
 }
 
+func TestExecLocalStopsCommandAtContextDeadline(t *testing.T) {
+	c, mc := testLocalExecSetupMocks()
+	c.Timeout = ""
+
+	p := NewExecLocal(c, mc, hclog.NewNullLogger())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	err := p.Create(ctx)
+	assert.NoError(t, err)
+
+	params := mc.Calls[0].Arguments[0].(clients.CommandConfig)
+	assert.Greater(t, int64(params.Timeout), int64(0))
+	assert.LessOrEqual(t, int64(params.Timeout), int64(time.Minute))
+}
+
 func TestExecLocalExecutesCommandAndSetsPid(t *testing.T) {
 	c, mc := testLocalExecSetupMocks()
 
 	p := NewExecLocal(c, mc, hclog.Default())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	assert.Equal(t, 123, c.Pid)
@@ -62,17 +83,29 @@ func TestExecLocalExecuteFailsReturnsError(t *testing.T) {
 
 	p := NewExecLocal(c, mc, hclog.Default())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
+func TestExecLocalReturnsErrorWhenWorkingDirectoryMissing(t *testing.T) {
+	c, mc := testLocalExecSetupMocks()
+	c.WorkingDirectory = filepath.Join(t.TempDir(), "missing")
+
+	p := NewExecLocal(c, mc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+
+	mc.AssertNotCalled(t, "Execute", mock.Anything)
+}
+
 func TestExecLocalDestroyCallsStopWhenDaemon(t *testing.T) {
 	c, mc := testLocalExecSetupMocks()
 	c.Pid = 123
 
 	p := NewExecLocal(c, mc, hclog.Default())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 
 	mc.AssertCalled(t, "Kill", 123)
@@ -85,12 +118,97 @@ func TestExecLocalDestroyNotCallsStopWhenNotDaemon(t *testing.T) {
 
 	p := NewExecLocal(c, mc, hclog.Default())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+
+	mc.AssertNotCalled(t, "Kill", mock.Anything)
+}
+
+func TestExecLocalCapturesOutputFromThisRun(t *testing.T) {
+	setupSecretHome(t)
+
+	c, mc := testLocalExecSetupMocks()
+	c.Daemon = false
+	c.OutputLimit = 5
+
+	logPath := filepath.Join(utils.LogsDir(), "exec_test.log")
+	os.MkdirAll(filepath.Dir(logPath), os.ModePerm)
+	err := ioutil.WriteFile(logPath, []byte("previous run\n"), 0644)
+	assert.NoError(t, err)
+
+	removeOn(&mc.Mock, "Execute")
+	mc.On("Execute", mock.Anything).Run(func(args mock.Arguments) {
+		f, _ := os.OpenFile(args.Get(0).(clients.CommandConfig).LogFilePath, os.O_APPEND|os.O_WRONLY, 0644)
+		defer f.Close()
+		f.WriteString("hello world")
+	}).Return(123, nil)
+
+	p := NewExecLocal(c, mc, hclog.NewNullLogger())
+
+	err = p.Create(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "hello", c.Output)
+}
+
+func TestExecLocalDoesNotCaptureOutputWhenDaemon(t *testing.T) {
+	setupSecretHome(t)
+
+	c, mc := testLocalExecSetupMocks()
+
+	p := NewExecLocal(c, mc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
+	assert.Empty(t, c.Output)
+}
+
+func TestExecLocalDoesNotRunOnCreateWhenDestroyOnly(t *testing.T) {
+	c, mc := testLocalExecSetupMocks()
+	c.Daemon = false
+	c.When = config.ExecWhenDestroy
+
+	p := NewExecLocal(c, mc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	mc.AssertNotCalled(t, "Execute", mock.Anything)
+}
+
+func TestExecLocalDestroyRunsCommandWhenDestroy(t *testing.T) {
+	setupSecretHome(t)
+
+	c, mc := testLocalExecSetupMocks()
+	c.Daemon = false
+	c.When = config.ExecWhenBoth
+
+	p := NewExecLocal(c, mc, hclog.NewNullLogger())
+
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+
+	mc.AssertCalled(t, "Execute", mock.Anything)
 	mc.AssertNotCalled(t, "Kill", mock.Anything)
 }
 
+func TestExecLocalDestroyReturnsErrorWhenCommandFails(t *testing.T) {
+	setupSecretHome(t)
+
+	c, mc := testLocalExecSetupMocks()
+	c.Daemon = false
+	c.When = config.ExecWhenDestroy
+
+	removeOn(&mc.Mock, "Execute")
+	mc.On("Execute", mock.Anything).Return(0, fmt.Errorf("boom"))
+
+	p := NewExecLocal(c, mc, hclog.NewNullLogger())
+
+	err := p.Destroy(context.Background())
+	assert.Error(t, err)
+}
+
 var execLocalConfig = &config.ExecLocal{
 	ResourceInfo:     config.ResourceInfo{Name: "test", Type: config.TypeExecLocal},
 	Command:          "mycommand",
@@ -0,0 +1,41 @@
+package providers
+
+import (
+	"bytes"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+)
+
+// outputBuffer captures command output up to a limit, any output
+// after the limit has been reached is discarded
+type outputBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func newOutputBuffer(limit int) *outputBuffer {
+	if limit <= 0 {
+		limit = config.DefaultExecOutputLimit
+	}
+
+	return &outputBuffer{limit: limit}
+}
+
+// Write implements io.Writer, it always reports the full length as written
+// so that writers sharing a MultiWriter are not interrupted
+func (o *outputBuffer) Write(p []byte) (int, error) {
+	remaining := o.limit - o.buf.Len()
+	if remaining > len(p) {
+		remaining = len(p)
+	}
+
+	if remaining > 0 {
+		o.buf.Write(p[:remaining])
+	}
+
+	return len(p), nil
+}
+
+func (o *outputBuffer) String() string {
+	return o.buf.String()
+}
@@ -0,0 +1,28 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestOutputBufferTruncatesAtLimit(t *testing.T) {
+	o := newOutputBuffer(8)
+
+	n, err := o.Write([]byte("hello "))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, n)
+
+	n, err = o.Write([]byte("world"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	assert.Equal(t, "hello wo", o.String())
+}
+
+func TestOutputBufferUsesDefaultLimit(t *testing.T) {
+	o := newOutputBuffer(0)
+
+	assert.Equal(t, config.DefaultExecOutputLimit, o.limit)
+}
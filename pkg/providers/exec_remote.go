@@ -1,7 +1,9 @@
 package providers
 
 import (
+	"context"
 	"fmt"
+	"io"
 
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients"
@@ -23,7 +25,16 @@ func NewRemoteExec(c *config.ExecRemote, ex clients.ContainerTasks, l hclog.Logg
 }
 
 // Create a new execution instance
-func (c *ExecRemote) Create() error {
+func (c *ExecRemote) Create(ctx context.Context) error {
+	if !c.config.RunsOnCreate() {
+		c.log.Debug("Command runs on destroy, skipping", "ref", c.config.Name)
+		return nil
+	}
+
+	return c.run()
+}
+
+func (c *ExecRemote) run() error {
 	c.log.Info("Remote executing command", "ref", c.config.Name, "command", c.config.Command, "args", c.config.Arguments, "image", c.config.Image)
 
 	/*
@@ -94,11 +105,17 @@ func (c *ExecRemote) Create() error {
 		group = c.config.RunAs.Group
 	}
 
-	err := c.client.ExecuteCommand(targetID, command, envs, c.config.WorkingDirectory, user, group, c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
+	// capture the output so that it is stored in the state
+	out := newOutputBuffer(c.config.OutputLimit)
+	w := io.MultiWriter(c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}), out)
+
+	err := c.client.ExecuteCommand(targetID, command, envs, c.config.WorkingDirectory, user, group, w)
 	if err != nil {
 		err = xerrors.Errorf("Unable to execute command in remote container: %w", err)
 	}
 
+	c.config.Output = out.String()
+
 	// destroy the container if we created one
 	if c.config.Target == "" {
 		c.client.RemoveContainer(targetID, true)
@@ -129,8 +146,12 @@ func (c *ExecRemote) createRemoteExecContainer() (string, error) {
 	return c.client.CreateContainer(cc)
 }
 
-// Destroy statisfies the interface requirements but is not used
-func (c *ExecRemote) Destroy() error {
+// Destroy runs the command when it is set to run on destroy
+func (c *ExecRemote) Destroy(ctx context.Context) error {
+	if c.config.RunsOnDestroy() {
+		return c.run()
+	}
+
 	return nil
 }
 
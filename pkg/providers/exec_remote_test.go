@@ -1,7 +1,9 @@
 package providers
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/hashicorp/go-hclog"
@@ -47,7 +49,7 @@ func TestRemoteExecThrowsErrorIfScript(t *testing.T) {
 	trex.Script = "./script.sh"
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 */
@@ -56,7 +58,7 @@ func TestRemoteExecPullsImageWhenNoTarget(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", mock.Anything, mock.Anything)
 }
@@ -68,7 +70,7 @@ func TestRemoteExecPullsImageReturnsErrorWhenError(t *testing.T) {
 
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -76,7 +78,7 @@ func TestRemoteExecCreatesContainerWhenNoTarget(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CreateContainer", mock.Anything)
 }
@@ -88,7 +90,7 @@ func TestRemoteExecCreatesContainerFailsReturnError(t *testing.T) {
 
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -97,7 +99,7 @@ func TestRemoteExecWithTargetLooksupID(t *testing.T) {
 	trex.Target = "container.test"
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "FindContainerIDs", "test", config.TypeContainer)
 }
@@ -109,7 +111,7 @@ func TestRemoteExecWithTargetLooksupIDNotFoundReturnsError(t *testing.T) {
 	md.On("FindContainerIDs", "test", config.TypeContainer).Return([]string{}, nil)
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -117,7 +119,7 @@ func TestRemoteExecExecutesCommand(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 
@@ -140,7 +142,7 @@ func TestRemoteExecRunsAsUserWhenSpecified(t *testing.T) {
 	}
 
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	user := getCalls(&md.Mock, "ExecuteCommand")[0].Arguments[4].(string)
@@ -157,7 +159,7 @@ func TestRemoteExecExecutesCommandFailReturnsError(t *testing.T) {
 
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -165,29 +167,78 @@ func TestRemoteExecRemovesContainer(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "RemoveContainer", "1234", true)
 }
 
 /*
-func TestRemoteExecRemoveContainerFailReturnsError(t *testing.T) {
-	trex, _, md := testRemoteExecSetupMocks()
-	removeOn(&md.Mock, "RemoveContainer")
-	md.On("RemoveContainer", "1234").Return(fmt.Errorf("boom"))
+	func TestRemoteExecRemoveContainerFailReturnsError(t *testing.T) {
+		trex, _, md := testRemoteExecSetupMocks()
+		removeOn(&md.Mock, "RemoveContainer")
+		md.On("RemoveContainer", "1234").Return(fmt.Errorf("boom"))
 
-	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
+		p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
-	assert.Error(t, err)
-}
+		err := p.Create(context.Background())
+		assert.Error(t, err)
+	}
 */
 func TestRemoteExecDoesNOTRemovesContainerWhenTarget(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
 	trex.Target = "container.test"
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertNotCalled(t, "RemoveContainer", mock.Anything)
 }
+
+func TestRemoteExecCapturesOutput(t *testing.T) {
+	trex, _, md := testRemoteExecSetupMocks()
+	trex.OutputLimit = 5
+
+	removeOn(&md.Mock, "ExecuteCommand")
+	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(6).(io.Writer).Write([]byte("hello world"))
+	}).Return(nil)
+
+	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", trex.Output)
+}
+
+func TestRemoteExecDoesNotRunOnCreateWhenDestroyOnly(t *testing.T) {
+	trex, _, md := testRemoteExecSetupMocks()
+	trex.When = config.ExecWhenDestroy
+
+	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+	md.AssertNotCalled(t, "ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRemoteExecDestroyRunsCommandWhenDestroy(t *testing.T) {
+	trex, _, md := testRemoteExecSetupMocks()
+	trex.When = config.ExecWhenDestroy
+
+	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
+
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	md.AssertCalled(t, "RemoveContainer", "1234", true)
+}
+
+func TestRemoteExecDestroyDoesNothingByDefault(t *testing.T) {
+	trex, _, md := testRemoteExecSetupMocks()
+
+	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
+
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+	md.AssertNotCalled(t, "ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
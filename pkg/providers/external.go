@@ -0,0 +1,72 @@
+package providers
+
+import (
+	"context"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// External is a provider for services which are not managed by Shipyard,
+// Create blocks until the service passes its health check
+type External struct {
+	config     *config.External
+	httpClient clients.HTTP
+	log        hclog.Logger
+}
+
+// NewExternal creates a new External provider
+func NewExternal(c *config.External, hc clients.HTTP, l hclog.Logger) *External {
+	return &External{c, hc, l}
+}
+
+// Create waits until the external service is reachable
+func (e *External) Create(ctx context.Context) error {
+	e.log.Info("Checking External service", "ref", e.config.Name)
+
+	if e.config.HealthCheck == nil {
+		return nil
+	}
+
+	d, err := time.ParseDuration(e.config.HealthCheck.Timeout)
+	if err != nil {
+		return xerrors.Errorf("unable to parse healthcheck duration: %w", err)
+	}
+
+	if hc := e.config.HealthCheck.HTTP; hc != "" {
+		// do we have custom status codes, if not use 200
+		codes := e.config.HealthCheck.HTTPSuccessCodes
+		if codes == nil {
+			codes = []int{200}
+		}
+
+		err := e.httpClient.HealthCheckHTTP(hc, codes, d)
+		if err != nil {
+			return xerrors.Errorf("external service %s is not available: %w", e.config.Name, err)
+		}
+	}
+
+	if hc := e.config.HealthCheck.TCP; hc != "" {
+		err := e.httpClient.HealthCheckTCP(hc, d)
+		if err != nil {
+			return xerrors.Errorf("external service %s is not available: %w", e.config.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Destroy is a noop, external services are never destroyed
+func (e *External) Destroy(ctx context.Context) error {
+	e.log.Info("Destroy External service", "ref", e.config.Name)
+
+	return nil
+}
+
+// Lookup is a noop, external services have no local ids
+func (e *External) Lookup() ([]string, error) {
+	return []string{}, nil
+}
@@ -0,0 +1,57 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupExternal() (*config.External, *mocks.MockHTTP, *External) {
+	ec := config.NewExternal("api")
+	ec.HealthCheck = &config.HealthCheck{
+		Timeout: "30s",
+		HTTP:    "http://localhost:8500",
+		TCP:     "localhost:5432",
+	}
+
+	hc := &mocks.MockHTTP{}
+	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	hc.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(nil)
+
+	return ec, hc, NewExternal(ec, hc, hclog.NewNullLogger())
+}
+
+func TestExternalRunsHealthChecks(t *testing.T) {
+	_, hc, p := setupExternal()
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	hc.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8500", []int{200}, 30*time.Second)
+	hc.AssertCalled(t, "HealthCheckTCP", "localhost:5432", 30*time.Second)
+}
+
+func TestExternalReturnsErrorWhenHealthCheckFails(t *testing.T) {
+	_, hc, p := setupExternal()
+	removeOn(&hc.Mock, "HealthCheckTCP")
+	hc.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestExternalDestroyDoesNothing(t *testing.T) {
+	_, hc, p := setupExternal()
+
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+
+	hc.AssertNotCalled(t, "HealthCheckHTTP", mock.Anything, mock.Anything, mock.Anything)
+}
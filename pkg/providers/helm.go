@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
@@ -10,6 +11,10 @@ import (
 	"golang.org/x/xerrors"
 )
 
+// defaultHelmTimeout is the time Helm waits for an atomic install when the
+// resource does not set a timeout, it matches the default resource timeout
+var defaultHelmTimeout = 10 * time.Minute
+
 type Helm struct {
 	config       *config.Helm
 	kubeClient   clients.Kubernetes
@@ -24,7 +29,7 @@ func NewHelm(c *config.Helm, kc clients.Kubernetes, hc clients.Helm, g clients.G
 }
 
 // Create implements the provider Create method
-func (h *Helm) Create() error {
+func (h *Helm) Create(ctx context.Context) error {
 	h.log.Info("Creating Helm chart", "ref", h.config.Name)
 
 	// get the target cluster
@@ -61,33 +66,48 @@ func (h *Helm) Create() error {
 		return xerrors.Errorf("unable to create Kubernetes client: %w", err)
 	}
 
+	checksum, err := h.config.Checksum()
+	if err != nil {
+		return xerrors.Errorf("Unable to read Helm values: %w", err)
+	}
+
+	// the install is bounded by the timeout for the resource, Helm fails an
+	// atomic install immediately when the timeout is zero
+	timeout := defaultHelmTimeout
+	if d, ok := ctx.Deadline(); ok {
+		timeout = time.Until(d)
+	}
+
+	if h.config.Timeout != "" {
+		timeout, err = time.ParseDuration(h.config.Timeout)
+		if err != nil {
+			return xerrors.Errorf("Unable to parse timeout: %w", err)
+		}
+	}
+
 	err = h.helmClient.Create(
 		kcPath, h.config.ChartName,
 		h.config.Namespace, h.config.CreateNamespace,
-		h.config.Chart, h.config.Values, h.config.ValuesString)
+		h.config.Chart, h.config.ValuesPaths(), h.config.ValuesString,
+		timeout, h.config.Atomic)
 
 	if err != nil {
 		return err
 	}
 
-	// we can now health check the install
-	if h.config.HealthCheck != nil && len(h.config.HealthCheck.Pods) > 0 {
-		to, err := time.ParseDuration(h.config.HealthCheck.Timeout)
-		if err != nil {
-			return xerrors.Errorf("unable to parse healthcheck duration: %w", err)
-		}
+	h.config.ValuesChecksum = checksum
 
-		err = h.kubeClient.HealthCheckPods(h.config.HealthCheck.Pods, to)
-		if err != nil {
-			return xerrors.Errorf("healthcheck failed after helm chart setup: %w", err)
-		}
+	// we can now health check the install
+	err = h.kubeClient.HealthCheckResource(h.config, 0)
+	if err != nil {
+		return xerrors.Errorf("healthcheck failed after helm chart setup: %w", err)
 	}
 
 	return nil
 }
 
 // Destroy implements the provider Destroy method
-func (h *Helm) Destroy() error {
+func (h *Helm) Destroy(ctx context.Context) error {
 	h.log.Info("Destroy Helm chart", "ref", h.config.Name)
 	kcPath, err := h.getKubeConfigPath()
 	if err != nil {
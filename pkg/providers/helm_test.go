@@ -1,10 +1,13 @@
 package providers
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients"
@@ -17,12 +20,12 @@ import (
 
 func setupHelm() (*mocks.MockHelm, *clients.MockKubernetes, *mocks.Getter, *config.Config, *Helm) {
 	mh := &mocks.MockHelm{}
-	mh.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mh.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mh.On("Destroy", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	kc := &clients.MockKubernetes{}
 	kc.On("SetConfig", mock.Anything).Return(nil)
-	kc.On("HealthCheckPods", mock.Anything, mock.Anything).Return(nil)
+	kc.On("HealthCheckResource", mock.Anything, mock.Anything).Return(nil)
 
 	mg := &mocks.Getter{}
 	mg.On("Get", mock.Anything, mock.Anything).Return(nil)
@@ -45,7 +48,7 @@ func TestHelmCreateCantFindClusterReturnsError(t *testing.T) {
 	_, _, _, c, p := setupHelm()
 	c.RemoveResource(c.Resources[0])
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -55,17 +58,17 @@ func TestHelmCreateGetsRemoteRepo(t *testing.T) {
 	hc.(*config.Helm).Chart = "github.com/shipyard-run/blueprints//vault-k8s"
 	helmFolder := filepath.Join(utils.ShipyardHome(), "helm_charts", strings.Replace(hc.(*config.Helm).Chart, "//", "/", -1))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mg.AssertCalled(t, "Get", mock.Anything, helmFolder)
-	mh.AssertCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, helmFolder, mock.Anything, mock.Anything)
+	mh.AssertCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, helmFolder, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestHelmCreateSetsConfig(t *testing.T) {
 	_, kc, mg, _, p := setupHelm()
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	_, fp, _ := utils.CreateKubeConfigPath("tester")
@@ -78,14 +81,14 @@ func TestHelmCreateConfigSetFailReturnsError(t *testing.T) {
 	removeOn(&kc.Mock, "SetConfig")
 	kc.On("SetConfig", mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestHelmCreateCallsCreateWithDefaultNamespace(t *testing.T) {
 	hm, _, _, _, p := setupHelm()
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	hm.AssertCalled(
@@ -96,16 +99,103 @@ func TestHelmCreateCallsCreateWithDefaultNamespace(t *testing.T) {
 		"default",
 		false,
 		p.config.Chart,
-		p.config.Values,
+		[]string{},
 		p.config.ValuesString,
+		time.Duration(0),
+		false,
 	)
 }
 
+func TestHelmCreateCallsCreateWithValuesFilesInOrder(t *testing.T) {
+	hm, _, _, _, p := setupHelm()
+
+	dir := t.TempDir()
+	p.config.Values = filepath.Join(dir, "values.yaml")
+	p.config.ValuesFiles = []string{filepath.Join(dir, "base.yaml"), filepath.Join(dir, "dev.yaml")}
+
+	for _, f := range p.config.ValuesPaths() {
+		ioutil.WriteFile(f, []byte("replicas: 1"), 0644)
+	}
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	hm.AssertCalled(
+		t,
+		"Create",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		[]string{p.config.Values, p.config.ValuesFiles[0], p.config.ValuesFiles[1]},
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	)
+
+	assert.NotEmpty(t, p.config.ValuesChecksum)
+}
+
+func TestHelmCreateWithMissingValuesFileReturnsError(t *testing.T) {
+	hm, _, _, _, p := setupHelm()
+	p.config.ValuesFiles = []string{filepath.Join(t.TempDir(), "missing.yaml")}
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+
+	hm.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHelmCreateCallsCreateWithTimeoutAndAtomic(t *testing.T) {
+	hm, _, _, _, p := setupHelm()
+	p.config.Timeout = "2m"
+	p.config.Atomic = true
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	hm.AssertCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, 2*time.Minute, true)
+}
+
+func TestHelmCreateWithoutTimeoutUsesDefaultTimeout(t *testing.T) {
+	hm, _, _, _, p := setupHelm()
+	p.config.Atomic = true
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	hm.AssertCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, defaultHelmTimeout, true)
+}
+
+func TestHelmCreateWithoutTimeoutUsesContextDeadline(t *testing.T) {
+	hm, _, _, _, p := setupHelm()
+	p.config.Atomic = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	err := p.Create(ctx)
+	assert.NoError(t, err)
+
+	timeout := getCalls(&hm.Mock, "Create")[0].Arguments[7].(time.Duration)
+	assert.Greater(t, int64(timeout), int64(0))
+	assert.LessOrEqual(t, int64(timeout), int64(time.Minute))
+}
+
+func TestHelmCreateWithInvalidTimeoutReturnsError(t *testing.T) {
+	_, _, _, _, p := setupHelm()
+	p.config.Timeout = "soon"
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
 func TestHelmCreateCallsCreateWithCustomNamespace(t *testing.T) {
 	hm, _, _, _, p := setupHelm()
 	p.config.Namespace = "custom"
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	hm.AssertCalled(
@@ -116,60 +206,53 @@ func TestHelmCreateCallsCreateWithCustomNamespace(t *testing.T) {
 		"custom",
 		p.config.CreateNamespace,
 		p.config.Chart,
-		p.config.Values,
+		[]string{},
 		p.config.ValuesString,
+		time.Duration(0),
+		false,
 	)
 }
 
 func TestHelmCreateCallCreateFailReturnsError(t *testing.T) {
 	hm, _, _, _, p := setupHelm()
 	removeOn(&hm.Mock, "Create")
-	hm.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+	hm.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
-func TestHelmDoesNotHealthChecksPodswhenNotSet(t *testing.T) {
-	_, kc, _, _, p := setupHelm()
-
-	err := p.Create()
-	assert.NoError(t, err)
-
-	kc.AssertNotCalled(t, "HealthCheckPods", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
-}
-
-func TestHelmHealthChecksPodswhenSet(t *testing.T) {
+func TestHelmHealthChecksResource(t *testing.T) {
 	_, kc, _, _, p := setupHelm()
 	p.config.HealthCheck = &config.HealthCheck{Timeout: "1s", Pods: []string{"consul=release"}}
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
-	kc.AssertCalled(t, "HealthCheckPods", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	kc.AssertCalled(t, "HealthCheckResource", p.config, time.Duration(0))
 }
 
 func TestHelmCreateHealthCheckPodsFailReturnsError(t *testing.T) {
 	_, kc, _, _, p := setupHelm()
 	p.config.HealthCheck = &config.HealthCheck{Timeout: "1s", Pods: []string{"consul=release"}}
-	removeOn(&kc.Mock, "HealthCheckPods")
-	kc.On("HealthCheckPods", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+	removeOn(&kc.Mock, "HealthCheckResource")
+	kc.On("HealthCheckResource", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 func TestHelmDestroyCantFindClusterReturnsError(t *testing.T) {
 	_, _, _, c, p := setupHelm()
 	c.RemoveResource(c.Resources[0])
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.Error(t, err)
 }
 
 func TestHelmDestroyCallsDestroyWithDefaultNamespace(t *testing.T) {
 	hm, _, _, _, p := setupHelm()
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	hm.AssertCalled(t, "Destroy", mock.Anything, mock.Anything, "default")
 }
@@ -180,7 +263,7 @@ func TestHelmDestroyWithErrorSwallowsError(t *testing.T) {
 	removeOn(&hm.Mock, "Destroy")
 	hm.On("Destroy", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	hm.AssertCalled(t, "Destroy", mock.Anything, mock.Anything, "custom")
 }
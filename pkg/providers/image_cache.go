@@ -1,8 +1,10 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients"
@@ -25,7 +27,7 @@ func NewImageCache(co *config.ImageCache, cl clients.ContainerTasks, hc clients.
 	return &ImageCache{co, cl, hc, l}
 }
 
-func (c *ImageCache) Create() error {
+func (c *ImageCache) Create(ctx context.Context) error {
 	c.log.Info("Creating ImageCache", "ref", c.config.Name)
 
 	// check the cache does not already exist
@@ -34,17 +36,48 @@ func (c *ImageCache) Create() error {
 		return err
 	}
 
+	registries := c.registries()
+
+	// when the registries have changed try to reload the running cache, if
+	// this is not possible the container is recreated with the new config
+	if len(ids) > 0 && !sameRegistries(c.config.Registries, registries) {
+		err := c.AddRegistry(ids[0], registries)
+		if err == nil {
+			c.config.Registries = registries
+		} else {
+			c.log.Debug("Unable to reload ImageCache registries, recreating", "ref", c.config.Name, "error", err)
+
+			for _, id := range ids {
+				err := c.client.RemoveContainer(id, true)
+				if err != nil {
+					return fmt.Errorf("Unable to remove image cache: %s", err)
+				}
+			}
+
+			ids = nil
+		}
+	}
+
 	id := ""
 
 	if ids == nil || len(ids) == 0 {
 		var err error
-		id, err = c.createImageCache()
+		id, err = c.createImageCache(registries)
 		if err != nil {
 			return err
 		}
+
+		c.config.Registries = registries
 	} else {
 		c.log.Debug("ImageCache already exists, not recreating")
 		id = ids[0]
+
+		if c.config.Refresh {
+			err := c.clearCache(id)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// remove all networks first
@@ -61,7 +94,16 @@ func (c *ImageCache) Create() error {
 			continue
 		}
 
-		err = c.client.AttachNetwork(target.Info().Name, id, nil, "")
+		if target.Info().Type != config.TypeNetwork {
+			continue
+		}
+
+		// external networks are not managed by shipyard
+		if target.(*config.Network).External {
+			continue
+		}
+
+		err = c.client.AttachNetwork(utils.NetworkName(target.Info().Name, c.client.Workspace()), id, nil, "")
 		if err != nil {
 			return fmt.Errorf("Unable to attach cache to network: %s", err)
 		}
@@ -72,7 +114,62 @@ func (c *ImageCache) Create() error {
 	return nil
 }
 
-func (c *ImageCache) createImageCache() (string, error) {
+// defaultRegistries are the registries which are always cached
+const defaultRegistries = "k8s.gcr.io gcr.io asia.gcr.io eu.gcr.io us.gcr.io quay.io ghcr.io docker.pkg.github.com"
+
+// registries returns the private registries the cache depends on
+func (c *ImageCache) registries() []config.Registry {
+	regs := []config.Registry{}
+	seen := map[string]bool{}
+
+	for _, d := range c.config.DependsOn {
+		if seen[d] {
+			continue
+		}
+
+		seen[d] = true
+
+		r, err := c.config.FindDependentResource(d)
+		if err != nil || r.Info().Type != config.TypeRegistry {
+			continue
+		}
+
+		regs = append(regs, *r.(*config.Registry))
+	}
+
+	return regs
+}
+
+// sameRegistries returns true when both lists contain the same set of hostnames
+// with the same credentials, the order of the registries is ignored
+func sameRegistries(a, b []config.Registry) bool {
+	creds := func(regs []config.Registry) map[string]string {
+		m := map[string]string{}
+		for _, r := range regs {
+			u, p := r.Credentials()
+			m[r.Hostname] = u + ":" + p
+		}
+
+		return m
+	}
+
+	ca := creds(a)
+	cb := creds(b)
+
+	if len(ca) != len(cb) {
+		return false
+	}
+
+	for h, cr := range ca {
+		if c, ok := cb[h]; !ok || c != cr {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *ImageCache) createImageCache(registries []config.Registry) (string, error) {
 	// Create the volume to store the cache
 	// if this volume exists it will not be recreated
 	volID, err := c.client.CreateVolume("images")
@@ -113,14 +210,113 @@ func (c *ImageCache) createImageCache() (string, error) {
 		"CA_CRT_FILE":           "/cache/ca/root.cert",
 		"DOCKER_MIRROR_CACHE":   "/cache/docker",
 		"ENABLE_MANIFEST_CACHE": "true",
-		"REGISTRIES":            "k8s.gcr.io gcr.io asia.gcr.io eu.gcr.io us.gcr.io quay.io ghcr.io docker.pkg.github.com",
 		"ALLOW_PUSH":            "true",
 	}
 
+	for k, v := range registryEnv(registries) {
+		cc.EnvVar[k] = v
+	}
+
 	return c.client.CreateContainer(cc)
 }
 
-func (c *ImageCache) Destroy() error {
+// registryEnv returns the environment variables which configure the registries
+// the cache intercepts and the credentials for those registries
+func registryEnv(registries []config.Registry) map[string]string {
+	hosts := []string{defaultRegistries}
+	auth := []string{}
+
+	for _, r := range registries {
+		hosts = append(hosts, r.Hostname)
+
+		if u, p := r.Credentials(); p != "" {
+			// use a delimiter which is unlikely to be part of a password
+			auth = append(auth, strings.Join([]string{r.Hostname, u, p}, ":::"))
+		}
+	}
+
+	env := map[string]string{"REGISTRIES": strings.Join(hosts, " ")}
+
+	if len(auth) > 0 {
+		env["AUTH_REGISTRIES"] = strings.Join(auth, " ")
+		env["AUTH_REGISTRY_DELIMITER"] = ":::"
+	}
+
+	return env
+}
+
+// reloadScript rewrites the nginx maps the cache uses to intercept registries
+// and authenticate pulls, then reloads nginx. The TLS certificate for the cache
+// is generated for the intercepted hosts when the container starts, new hosts
+// which are not in the certificate can not be reloaded.
+const reloadScript = `set -e
+test -f /etc/nginx/docker.intercept.map
+test -f /etc/nginx/docker.auth.map
+for r in $REGISTRIES; do
+  openssl x509 -in /certs/web.crt -noout -text | grep -q "DNS:$r"
+done
+echo -n "" > /etc/nginx/docker.intercept.map
+for r in $REGISTRIES; do
+  echo "$r 127.0.0.1:443;" >> /etc/nginx/docker.intercept.map
+done
+echo -n "" > /etc/nginx/docker.auth.map
+for a in $AUTH_REGISTRIES; do
+  host=$(echo "$a" | awk -F "$AUTH_REGISTRY_DELIMITER" '{print $1}')
+  auth=$(echo "$a" | awk -F "$AUTH_REGISTRY_DELIMITER" '{printf "%s:%s", $2, $3}' | base64 | tr -d '\n')
+  echo "\"$host\" \"$auth\";" >> /etc/nginx/docker.auth.map
+done
+nginx -s reload`
+
+// AddRegistry hot reloads the registries for the running cache container
+// without recreating it so that the cached layers and open connections are kept.
+// An error is returned when the cache can not be reloaded, the container must
+// then be recreated with the new registries.
+func (c *ImageCache) AddRegistry(id string, registries []config.Registry) error {
+	c.log.Info("Reloading ImageCache registries", "ref", c.config.Name)
+
+	env := []string{}
+	for k, v := range registryEnv(registries) {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	err := c.client.ExecuteCommand(
+		id,
+		[]string{"sh", "-c", reloadScript},
+		env,
+		"/",
+		"",
+		"",
+		c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}),
+	)
+	if err != nil {
+		return fmt.Errorf("Unable to reload image cache registries: %s", err)
+	}
+
+	return nil
+}
+
+// clearCache removes the cached images and manifests, the cache treats
+// missing files as a miss and pulls the image from the registry
+func (c *ImageCache) clearCache(id string) error {
+	c.log.Info("Clearing ImageCache", "ref", c.config.Name)
+
+	err := c.client.ExecuteCommand(
+		id,
+		[]string{"sh", "-c", "rm -rf /cache/docker/*"},
+		nil,
+		"/",
+		"",
+		"",
+		c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}),
+	)
+	if err != nil {
+		return fmt.Errorf("Unable to clear image cache: %s", err)
+	}
+
+	return nil
+}
+
+func (c *ImageCache) Destroy(ctx context.Context) error {
 	c.log.Info("Destroy ImageCache", "ref", c.config.Name)
 
 	ids, err := c.client.FindContainerIDs(c.config.Name, c.config.Type)
@@ -149,7 +345,7 @@ func (c *ImageCache) detachFromNetworks(id string) {
 		if target.Info().Type == config.TypeNetwork {
 			c.log.Debug("Detaching container from network", "ref", c.config.Name, "id", id, "network", n)
 
-			err := c.client.DetachNetwork(target.Info().Name, id)
+			err := c.client.DetachNetwork(dockerNetworkName(&c.config.ResourceInfo, n, c.client.Workspace()), id)
 			if err != nil {
 				c.log.Error("Unable to detach network", "ref", c.config.Name, "network", target.Info().Name)
 			}
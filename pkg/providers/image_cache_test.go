@@ -1,6 +1,8 @@
 package providers
 
 import (
+	"context"
+	"fmt"
 	"path/filepath"
 	"testing"
 
@@ -36,7 +38,7 @@ func TestImageCacheCreateDoesNotCreateContainerWhenExists(t *testing.T) {
 	cc, md, hc := setupImageCacheTests(t)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	removeOn(&md.Mock, "FindContainerIDs")
@@ -45,11 +47,39 @@ func TestImageCacheCreateDoesNotCreateContainerWhenExists(t *testing.T) {
 	md.AssertNotCalled(t, "CreateContainer", "images")
 }
 
+func TestImageCacheCreateClearsCacheWhenRefresh(t *testing.T) {
+	cc, md, hc := setupImageCacheTests(t)
+	cc.Refresh = true
+
+	removeOn(&md.Mock, "FindContainerIDs")
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Once().Return([]string{"abc"}, nil)
+	md.On("ExecuteCommand", "abc", []string{"sh", "-c", "rm -rf /cache/docker/*"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "ExecuteCommand", 1)
+}
+
+func TestImageCacheCreateDoesNotClearCacheByDefault(t *testing.T) {
+	cc, md, hc := setupImageCacheTests(t)
+
+	removeOn(&md.Mock, "FindContainerIDs")
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Once().Return([]string{"abc"}, nil)
+
+	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestImageCacheCreateCreatesVolume(t *testing.T) {
 	cc, md, hc := setupImageCacheTests(t)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertCalled(t, "CreateVolume", "images")
@@ -59,7 +89,7 @@ func TestImageCachePullsImage(t *testing.T) {
 	cc, md, hc := setupImageCacheTests(t)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertCalled(t, "PullImage", config.Image{Name: cacheImage}, false)
@@ -69,7 +99,7 @@ func TestImageCacheCreateAddsVolumes(t *testing.T) {
 	cc, md, hc := setupImageCacheTests(t)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertCalled(t, "CreateContainer", mock.Anything)
@@ -87,7 +117,7 @@ func TestImageCacheCreateAddsEnvironmentVariables(t *testing.T) {
 	cc, md, hc := setupImageCacheTests(t)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertCalled(t, "CreateContainer", mock.Anything)
@@ -108,7 +138,7 @@ func TestImageCacheCreateCopiesCerts(t *testing.T) {
 	cc, md, hc := setupImageCacheTests(t)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertCalled(t, "CreateContainer", mock.Anything)
@@ -138,7 +168,7 @@ func TestImageCacheDetachesNetworks(t *testing.T) {
 	cc.Config.AddResource(net2)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertNumberOfCalls(t, "DetachNetwork", 2)
@@ -155,9 +185,150 @@ func TestImageCacheAttachesNetworks(t *testing.T) {
 	cc.Config.AddResource(net2)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertNumberOfCalls(t, "AttachNetwork", 2)
 	md.AssertNumberOfCalls(t, "DetachNetwork", 0)
 }
+
+func TestImageCacheDoesNotAttachExternalNetworks(t *testing.T) {
+	net1 := config.NewNetwork("one")
+	net2 := config.NewNetwork("two")
+	net2.External = true
+
+	cc, md, hc := setupImageCacheTests(t)
+	cc.DependsOn = []string{"network.one", "network.two"}
+
+	cc.Config.AddResource(net1)
+	cc.Config.AddResource(net2)
+
+	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "AttachNetwork", 1)
+	md.AssertCalled(t, "AttachNetwork", "one", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestImageCacheCreateAddsRegistryCredentials(t *testing.T) {
+	reg := config.NewRegistry("private")
+	reg.Hostname = "registry.example.com"
+	reg.Auth = &config.RegistryAuth{Username: "nic", Password: "s3cr3t"}
+
+	tok := config.NewRegistry("github")
+	tok.Hostname = "docker.example.com"
+	tok.Auth = &config.RegistryAuth{Token: "abc123"}
+
+	cc, md, hc := setupImageCacheTests(t)
+	cc.DependsOn = []string{"registry.private", "registry.github"}
+
+	cc.Config.AddResource(reg)
+	cc.Config.AddResource(tok)
+
+	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	conf := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+
+	assert.Equal(t, defaultRegistries+" registry.example.com docker.example.com", conf.EnvVar["REGISTRIES"])
+	assert.Equal(t, "registry.example.com:::nic:::s3cr3t docker.example.com:::token:::abc123", conf.EnvVar["AUTH_REGISTRIES"])
+	assert.Equal(t, ":::", conf.EnvVar["AUTH_REGISTRY_DELIMITER"])
+
+	// registries are not networks
+	md.AssertNumberOfCalls(t, "AttachNetwork", 0)
+	assert.Len(t, cc.Registries, 2)
+}
+
+func TestImageCacheRecreatesContainerWhenRegistriesChange(t *testing.T) {
+	reg := config.NewRegistry("private")
+	reg.Hostname = "registry.example.com"
+
+	cc, md, hc := setupImageCacheTests(t)
+	cc.DependsOn = []string{"registry.private"}
+	cc.Config.AddResource(reg)
+
+	removeOn(&md.Mock, "FindContainerIDs")
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Once().Return([]string{"abc"}, nil)
+	md.On("ExecuteCommand", "abc", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+	md.On("RemoveContainer", "abc", true).Once().Return(nil)
+
+	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "RemoveContainer", "abc", true)
+	md.AssertNumberOfCalls(t, "CreateContainer", 1)
+}
+
+func TestImageCacheReloadsRegistriesWhenChanged(t *testing.T) {
+	reg := config.NewRegistry("private")
+	reg.Hostname = "registry.example.com"
+	reg.Auth = &config.RegistryAuth{Username: "nic", Password: "s3cr3t"}
+
+	cc, md, hc := setupImageCacheTests(t)
+	cc.DependsOn = []string{"registry.private"}
+	cc.Config.AddResource(reg)
+
+	removeOn(&md.Mock, "FindContainerIDs")
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Once().Return([]string{"abc"}, nil)
+	md.On("ExecuteCommand", "abc", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	env := getCalls(&md.Mock, "ExecuteCommand")[0].Arguments[2].([]string)
+	assert.Contains(t, env, "REGISTRIES="+defaultRegistries+" registry.example.com")
+	assert.Contains(t, env, "AUTH_REGISTRIES=registry.example.com:::nic:::s3cr3t")
+
+	md.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything)
+	md.AssertNotCalled(t, "CreateContainer", mock.Anything)
+	assert.Len(t, cc.Registries, 1)
+}
+
+func TestImageCacheDoesNotRecreateContainerWhenRegistriesUnchanged(t *testing.T) {
+	reg := config.NewRegistry("private")
+	reg.Hostname = "registry.example.com"
+
+	cc, md, hc := setupImageCacheTests(t)
+	cc.DependsOn = []string{"registry.private"}
+	cc.Registries = []config.Registry{*reg}
+	cc.Config.AddResource(reg)
+
+	removeOn(&md.Mock, "FindContainerIDs")
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Once().Return([]string{"abc"}, nil)
+
+	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything)
+	md.AssertNotCalled(t, "CreateContainer", mock.Anything)
+}
+
+func TestImageCacheDoesNotRecreateContainerWhenDependsOnHasDuplicates(t *testing.T) {
+	reg := config.NewRegistry("private")
+	reg.Hostname = "registry.example.com"
+
+	other := config.NewRegistry("other")
+	other.Hostname = "other.example.com"
+
+	cc, md, hc := setupImageCacheTests(t)
+	cc.DependsOn = []string{"registry.private", "registry.other", "registry.private"}
+	cc.Registries = []config.Registry{*other, *reg}
+	cc.Config.AddResource(reg)
+	cc.Config.AddResource(other)
+
+	removeOn(&md.Mock, "FindContainerIDs")
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Once().Return([]string{"abc"}, nil)
+
+	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	md.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything)
+	md.AssertNotCalled(t, "CreateContainer", mock.Anything)
+}
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -29,7 +30,7 @@ func NewIngress(
 	return &Ingress{c, cc, co, l}
 }
 
-func (c *Ingress) Create() error {
+func (c *Ingress) Create(ctx context.Context) error {
 	c.log.Info("Create Ingress", "ref", c.config.Name)
 
 	if c.config.Destination.Driver == "local" {
@@ -44,7 +45,7 @@ func (c *Ingress) Create() error {
 }
 
 // Destroy satisfies the interface method but is not implemented by LocalExec
-func (c *Ingress) Destroy() error {
+func (c *Ingress) Destroy(ctx context.Context) error {
 	c.log.Info("Destroy Ingress", "ref", c.config.Name, "id", c.config.Id)
 
 	err := c.connector.RemoveService(c.config.Id)
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"os"
 	"strconv"
 	"testing"
@@ -42,7 +43,7 @@ func TestIngressExposeLocalErrorsWhenUnableToFindDependencies(t *testing.T) {
 
 	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -56,7 +57,7 @@ func TestIngressExposeLocalErrorsWhenInvalidName(t *testing.T) {
 
 	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -70,7 +71,7 @@ func TestIngressExposeLocalErrorsWhenInvalidPort(t *testing.T) {
 
 	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -84,14 +85,14 @@ func TestIngressExposeLocalErrorsWhenPortReserved(t *testing.T) {
 
 	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 
 	tc.Source.Config.Port = "60001"
 
 	p = NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err = p.Create()
+	err = p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -105,7 +106,7 @@ func TestIngressExposeLocalErrorsWhenInvalidAddress(t *testing.T) {
 
 	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -120,7 +121,7 @@ func TestIngressExposeLocalCallsExpose(t *testing.T) {
 
 	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	port, _ := strconv.Atoi(tc.Source.Config.Port)
@@ -145,7 +146,7 @@ func TestIngressExposeRemoteErrorsWhenUnableToFindDependencies(t *testing.T) {
 
 	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -159,7 +160,7 @@ func TestIngressExposeRemoteErrorsWhenNoDestinationAddress(t *testing.T) {
 
 	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -173,7 +174,7 @@ func TestIngressExposeRemoteErrorsWhenUnableToParsePort(t *testing.T) {
 
 	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -187,14 +188,14 @@ func TestIngressExposeRemoteErrorsWhenReservedPort(t *testing.T) {
 
 	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 
 	tc.Source.Config.Port = "30002"
 
 	p = NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err = p.Create()
+	err = p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -209,7 +210,7 @@ func TestIngressExposeRemoteCallsExpose(t *testing.T) {
 
 	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	port, _ := strconv.Atoi(tc.Source.Config.Port)
@@ -233,7 +234,7 @@ func TestIngressDestroyCallsRemove(t *testing.T) {
 
 	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 
 	mc.AssertCalled(t, "RemoveService", "12345")
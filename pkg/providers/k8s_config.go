@@ -1,13 +1,22 @@
 package providers
 
 import (
-	"time"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
 
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients"
 	"github.com/shipyard-run/shipyard/pkg/config"
 	"github.com/shipyard-run/shipyard/pkg/utils"
 	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
 )
 
 type K8sConfig struct {
@@ -22,7 +31,7 @@ func NewK8sConfig(c *config.K8sConfig, kc clients.Kubernetes, l hclog.Logger) *K
 }
 
 // Create the Kubernetes resources defined by the config
-func (c *K8sConfig) Create() error {
+func (c *K8sConfig) Create(ctx context.Context) error {
 	c.log.Info("Applying Kubernetes configuration", "ref", c.config.Name, "config", c.config.Paths)
 
 	err := c.setup()
@@ -30,22 +39,33 @@ func (c *K8sConfig) Create() error {
 		return err
 	}
 
-	err = c.client.Apply(c.config.Paths, c.config.WaitUntilReady)
+	paths, err := c.renderPaths()
+	if err != nil {
+		return err
+	}
+
+	err = c.client.Apply(paths, c.config.WaitUntilReady)
 	if err != nil {
 		return err
 	}
 
 	// run any health checks
-	if c.config.HealthCheck != nil && len(c.config.HealthCheck.Pods) > 0 {
-		to, err := time.ParseDuration(c.config.HealthCheck.Timeout)
-		if err != nil {
-			return xerrors.Errorf("unable to parse healthcheck duration: %w", err)
-		}
+	err = c.client.HealthCheckResource(c.config, 0)
+	if err != nil {
+		return xerrors.Errorf("healthcheck failed after applying Kubernetes configuration: %w", err)
+	}
+
+	// objects from the previous apply which have been removed from the
+	// manifests are deleted, unchanged objects are left in place
+	if c.config.Prune {
+		previous := c.config.AppliedObjects
 
-		err = c.client.HealthCheckPods(c.config.HealthCheck.Pods, to)
+		err = c.trackObjects(paths)
 		if err != nil {
-			return xerrors.Errorf("healthcheck failed after helm chart setup: %w", err)
+			return err
 		}
+
+		c.deleteObjects(previous, c.config.AppliedObjects)
 	}
 
 	// set the status
@@ -55,7 +75,7 @@ func (c *K8sConfig) Create() error {
 }
 
 // Destroy the Kubernetes resources defined by the config
-func (c *K8sConfig) Destroy() error {
+func (c *K8sConfig) Destroy(ctx context.Context) error {
 	c.log.Info("Destroy Kubernetes configuration", "ref", c.config.Name, "config", c.config.Paths)
 
 	err := c.setup()
@@ -63,10 +83,21 @@ func (c *K8sConfig) Destroy() error {
 		return err
 	}
 
-	err = c.client.Delete(c.config.Paths)
+	paths, err := c.renderPaths()
+	if err != nil {
+		return err
+	}
+
+	err = c.client.Delete(paths)
 	if err != nil {
 		c.log.Debug("There was a problem destroying Kuberntes config, logging message but ignoring error", "ref", c.config.Name, "error", err)
 	}
+
+	if c.config.Prune {
+		c.pruneObjects(paths)
+		c.config.AppliedObjects = nil
+	}
+
 	return nil
 }
 
@@ -89,3 +120,189 @@ func (c *K8sConfig) setup() error {
 
 	return nil
 }
+
+// renderPaths injects the Vars into the manifests and returns the paths of the rendered
+// files. When no Vars are set the original paths are returned unchanged.
+func (c *K8sConfig) renderPaths() ([]string, error) {
+	if len(c.config.Vars) == 0 {
+		return c.config.Paths, nil
+	}
+
+	dest := filepath.Join(utils.ShipyardTemp(), "k8s_config", c.config.Name)
+	os.RemoveAll(dest)
+
+	paths := []string{}
+
+	for i, p := range c.config.Paths {
+		root := filepath.Join(dest, fmt.Sprintf("%d", i))
+
+		err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() || !config.IsK8sManifest(path) {
+				return nil
+			}
+
+			rel, err := filepath.Rel(p, path)
+			if err != nil {
+				return err
+			}
+
+			// p is a single file
+			if rel == "." {
+				rel = filepath.Base(path)
+			}
+
+			out, err := c.renderFile(path)
+			if err != nil {
+				return err
+			}
+
+			outPath := filepath.Join(root, rel)
+			err = os.MkdirAll(filepath.Dir(outPath), os.ModePerm)
+			if err != nil {
+				return err
+			}
+
+			return ioutil.WriteFile(outPath, out, 0644)
+		})
+
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to render Kubernetes config %s: %w", p, err)
+		}
+
+		paths = append(paths, root)
+	}
+
+	return paths, nil
+}
+
+func (c *K8sConfig) renderFile(path string) ([]byte, error) {
+	d, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := template.New(filepath.Base(path)).Delims("#{{", "}}").Option("missingkey=error").Parse(string(d))
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to parse template: %w", err)
+	}
+
+	bs := bytes.NewBuffer(nil)
+	err = t.Execute(bs, struct{ Vars map[string]string }{Vars: c.config.Vars})
+	if err != nil {
+		return nil, xerrors.Errorf("Error processing template: %w", err)
+	}
+
+	return bs.Bytes(), nil
+}
+
+// trackObjects records the objects defined in the manifests at paths and the
+// checksum of the manifests so that removed objects can be pruned
+func (c *K8sConfig) trackObjects(paths []string) error {
+	objects, err := manifestObjects(paths)
+	if err != nil {
+		return xerrors.Errorf("Unable to read objects from Kubernetes config: %w", err)
+	}
+
+	checksum, err := c.config.Checksum()
+	if err != nil {
+		return xerrors.Errorf("Unable to checksum Kubernetes config: %w", err)
+	}
+
+	c.config.AppliedObjects = objects
+	c.config.ManifestChecksum = checksum
+
+	return nil
+}
+
+// pruneObjects deletes the previously applied objects which are no longer
+// defined in the manifests at paths, errors are logged but ignored
+func (c *K8sConfig) pruneObjects(paths []string) {
+	objects, err := manifestObjects(paths)
+	if err != nil {
+		c.log.Debug("Unable to read objects from Kubernetes config, pruning all applied objects", "ref", c.config.Name, "error", err)
+	}
+
+	c.deleteObjects(c.config.AppliedObjects, objects)
+}
+
+// deleteObjects deletes the objects in previous which are not in current,
+// errors are logged but ignored
+func (c *K8sConfig) deleteObjects(previous, current []config.K8sObject) {
+	keep := map[string]bool{}
+	for _, o := range current {
+		keep[o.String()] = true
+	}
+
+	for _, o := range previous {
+		if keep[o.String()] {
+			continue
+		}
+
+		c.log.Debug("Pruning Kubernetes object", "ref", c.config.Name, "object", o.String())
+
+		err := c.client.DeleteObject(o.APIVersion, o.Kind, o.Namespace, o.Name)
+		if err != nil {
+			c.log.Debug("There was a problem pruning Kubernetes object, logging message but ignoring error", "ref", c.config.Name, "object", o.String(), "error", err)
+		}
+	}
+}
+
+// manifestObjects returns references to the objects defined in the manifest files at paths
+func manifestObjects(paths []string) ([]config.K8sObject, error) {
+	objects := []config.K8sObject{}
+
+	for _, p := range paths {
+		err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() || !config.IsK8sManifest(path) {
+				return nil
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			dec := yaml.NewYAMLOrJSONDecoder(f, 4096)
+			for {
+				doc := map[string]interface{}{}
+
+				err := dec.Decode(&doc)
+				if err == io.EOF {
+					return nil
+				}
+
+				if err != nil {
+					return xerrors.Errorf("Unable to decode %s: %w", path, err)
+				}
+
+				// skip empty documents
+				if len(doc) == 0 {
+					continue
+				}
+
+				u := unstructured.Unstructured{Object: doc}
+				objects = append(objects, config.K8sObject{
+					APIVersion: u.GetAPIVersion(),
+					Kind:       u.GetKind(),
+					Namespace:  u.GetNamespace(),
+					Name:       u.GetName(),
+				})
+			}
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return objects, nil
+}
@@ -1,7 +1,11 @@
 package providers
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -18,6 +22,7 @@ func setupK8sConfig() (*clients.MockKubernetes, *K8sConfig) {
 	mk.On("SetConfig", mock.Anything).Return(nil)
 	mk.On("Apply", mock.Anything, mock.Anything).Return(nil)
 	mk.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	mk.On("HealthCheckResource", mock.Anything, mock.Anything).Return(nil)
 
 	c := config.NewK8sCluster("testcluster")
 	kc := config.NewK8sConfig("config")
@@ -36,7 +41,7 @@ func setupK8sConfig() (*clients.MockKubernetes, *K8sConfig) {
 func TestCreatesCorrectly(t *testing.T) {
 	mk, p := setupK8sConfig()
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	_, destPath, _ := utils.CreateKubeConfigPath("testcluster")
@@ -50,12 +55,10 @@ func TestRunsHealthChecks(t *testing.T) {
 		Pods:    []string{"app=mine"},
 		Timeout: "60s",
 	}
-	mk.On("HealthCheckPods", mock.Anything, mock.Anything).Return(nil)
-
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
-	mk.AssertCalled(t, "HealthCheckPods", []string{"app=mine"}, 60*time.Second)
+	mk.AssertCalled(t, "HealthCheckResource", p.config, time.Duration(0))
 }
 
 func TestHealthCheckFailReturnsError(t *testing.T) {
@@ -64,12 +67,11 @@ func TestHealthCheckFailReturnsError(t *testing.T) {
 		Pods:    []string{"app=mine"},
 		Timeout: "60s",
 	}
-	mk.On("HealthCheckPods", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+	removeOn(&mk.Mock, "HealthCheckResource")
+	mk.On("HealthCheckResource", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
-
-	mk.AssertCalled(t, "HealthCheckPods", []string{"app=mine"}, 60*time.Second)
 }
 
 func TestCreateSetupErrorReturnsError(t *testing.T) {
@@ -77,7 +79,7 @@ func TestCreateSetupErrorReturnsError(t *testing.T) {
 	removeOn(&mk.Mock, "SetConfig")
 	mk.On("SetConfig", mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -85,14 +87,14 @@ func TestCreateNoClusterErrorReturnsError(t *testing.T) {
 	_, p := setupK8sConfig()
 	p.config.Config.RemoveResource(p.config.Config.Resources[1])
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestDestroysCorrectly(t *testing.T) {
 	mk, p := setupK8sConfig()
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 
 	mk.AssertCalled(t, "Delete", p.config.Paths)
@@ -103,6 +105,139 @@ func TestDestroySetupErrorReturnsError(t *testing.T) {
 	removeOn(&mk.Mock, "SetConfig")
 	mk.On("SetConfig", mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
+	assert.Error(t, err)
+}
+
+func TestCreateWithVarsAppliesRenderedConfig(t *testing.T) {
+	mk, p := setupK8sConfig()
+
+	home := os.Getenv(utils.HomeEnvName())
+	os.Setenv(utils.HomeEnvName(), t.TempDir())
+	defer os.Setenv(utils.HomeEnvName(), home)
+
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, "deploy.yaml"), []byte("image: #{{ .Vars.image }}"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("#{{ .Vars.missing }}"), os.ModePerm)
+
+	p.config.Paths = []string{dir}
+	p.config.Vars = map[string]string{"image": "consul:1.8.1"}
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	paths := getCalls(&mk.Mock, "Apply")[0].Arguments[0].([]string)
+	assert.NotEqual(t, dir, paths[0])
+
+	d, err := ioutil.ReadFile(filepath.Join(paths[0], "deploy.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "image: consul:1.8.1", string(d))
+	assert.NoFileExists(t, filepath.Join(paths[0], "README.md"))
+}
+
+func TestCreateWithMissingVarReturnsError(t *testing.T) {
+	mk, p := setupK8sConfig()
+
+	home := os.Getenv(utils.HomeEnvName())
+	os.Setenv(utils.HomeEnvName(), t.TempDir())
+	defer os.Setenv(utils.HomeEnvName(), home)
+
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, "deploy.yaml"), []byte("image: #{{ .Vars.image }}"), os.ModePerm)
+
+	p.config.Paths = []string{dir}
+	p.config.Vars = map[string]string{"tag": "1.8.1"}
+
+	err := p.Create(context.Background())
 	assert.Error(t, err)
+
+	mk.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+}
+
+var pruneManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: apps
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+
+func TestCreateWithPruneTracksAppliedObjects(t *testing.T) {
+	_, p := setupK8sConfig()
+
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, "web.yaml"), []byte(pruneManifest), os.ModePerm)
+
+	p.config.Paths = []string{dir}
+	p.config.Prune = true
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, []config.K8sObject{
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "apps", Name: "web"},
+		{APIVersion: "v1", Kind: "Service", Name: "web"},
+	}, p.config.AppliedObjects)
+	assert.NotEmpty(t, p.config.ManifestChecksum)
+}
+
+func TestCreateWithPruneDeletesOnlyRemovedObjects(t *testing.T) {
+	mk, p := setupK8sConfig()
+	mk.On("DeleteObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, "web.yaml"), []byte(pruneManifest), os.ModePerm)
+
+	p.config.Paths = []string{dir}
+	p.config.Prune = true
+	p.config.AppliedObjects = []config.K8sObject{
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "apps", Name: "web"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "old"},
+	}
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	mk.AssertNotCalled(t, "Delete", mock.Anything)
+	mk.AssertNumberOfCalls(t, "DeleteObject", 1)
+	mk.AssertCalled(t, "DeleteObject", "v1", "ConfigMap", "", "old")
+	assert.Len(t, p.config.AppliedObjects, 2)
+}
+
+func TestCreateWithoutPruneDoesNotTrackObjects(t *testing.T) {
+	_, p := setupK8sConfig()
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	assert.Empty(t, p.config.AppliedObjects)
+	assert.Empty(t, p.config.ManifestChecksum)
+}
+
+func TestDestroyWithPruneDeletesRemovedObjects(t *testing.T) {
+	mk, p := setupK8sConfig()
+	mk.On("DeleteObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, "web.yaml"), []byte(pruneManifest), os.ModePerm)
+
+	p.config.Paths = []string{dir}
+	p.config.Prune = true
+	p.config.AppliedObjects = []config.K8sObject{
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "apps", Name: "web"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "old"},
+	}
+
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "Delete", p.config.Paths)
+	mk.AssertNumberOfCalls(t, "DeleteObject", 1)
+	mk.AssertCalled(t, "DeleteObject", "v1", "ConfigMap", "", "old")
+	assert.Empty(t, p.config.AppliedObjects)
 }
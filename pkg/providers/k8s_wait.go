@@ -0,0 +1,84 @@
+package providers
+
+import (
+	"context"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// K8sWait is a provider which blocks until a Kubernetes object
+// reports the required status condition
+type K8sWait struct {
+	config *config.K8sWait
+	client clients.Kubernetes
+	log    hclog.Logger
+}
+
+// NewK8sWait creates a provider which waits for Kubernetes object conditions
+func NewK8sWait(c *config.K8sWait, kc clients.Kubernetes, l hclog.Logger) *K8sWait {
+	return &K8sWait{c, kc, l}
+}
+
+// Create waits for the condition defined in the config
+func (c *K8sWait) Create(ctx context.Context) error {
+	c.log.Info("Waiting for Kubernetes condition", "ref", c.config.Name, "kind", c.config.Kind, "object", c.config.ObjectName, "condition", c.config.Condition)
+
+	err := c.setup()
+	if err != nil {
+		return err
+	}
+
+	timeout := "60s"
+	if c.config.Timeout != "" {
+		timeout = c.config.Timeout
+	}
+
+	to, err := time.ParseDuration(timeout)
+	if err != nil {
+		return xerrors.Errorf("unable to parse timeout duration: %w", err)
+	}
+
+	namespace := "default"
+	if c.config.Namespace != "" {
+		namespace = c.config.Namespace
+	}
+
+	err = c.client.WaitForCondition(c.config.APIVersion, c.config.Kind, namespace, c.config.ObjectName, c.config.Condition, to)
+	if err != nil {
+		return xerrors.Errorf("condition %s not met for %s %s: %w", c.config.Condition, c.config.Kind, c.config.ObjectName, err)
+	}
+
+	return nil
+}
+
+// Destroy is a noop as K8sWait does not create any resources
+func (c *K8sWait) Destroy(ctx context.Context) error {
+	c.log.Info("Destroy Kubernetes wait", "ref", c.config.Name)
+
+	return nil
+}
+
+// Lookup is a noop as K8sWait does not create any resources
+func (c *K8sWait) Lookup() ([]string, error) {
+	return []string{}, nil
+}
+
+func (c *K8sWait) setup() error {
+	cluster, err := c.config.FindDependentResource(c.config.Cluster)
+	if err != nil {
+		return xerrors.Errorf("Unable to find associated cluster: %w", err)
+	}
+
+	_, destPath, _ := utils.CreateKubeConfigPath(cluster.Info().Name)
+	c.client, err = c.client.SetConfig(destPath)
+	if err != nil {
+		return xerrors.Errorf("unable to create Kubernetes client: %w", err)
+	}
+
+	return nil
+}
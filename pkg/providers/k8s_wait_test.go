@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupK8sWait() (*clients.MockKubernetes, *K8sWait) {
+	mk := &clients.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("WaitForCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	c := config.NewK8sCluster("testcluster")
+	kw := config.NewK8sWait("wait")
+	kw.Cluster = "k8s_cluster.testcluster"
+	kw.APIVersion = "apps/v1"
+	kw.Kind = "Deployment"
+	kw.ObjectName = "consul"
+	kw.Condition = "Available"
+
+	cc := config.New()
+	cc.AddResource(kw)
+	cc.AddResource(c)
+
+	p := NewK8sWait(kw, mk, hclog.Default())
+
+	return mk, p
+}
+
+func TestK8sWaitCreatesWithDefaults(t *testing.T) {
+	mk, p := setupK8sWait()
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	_, destPath, _ := utils.CreateKubeConfigPath("testcluster")
+	mk.AssertCalled(t, "SetConfig", destPath)
+	mk.AssertCalled(t, "WaitForCondition", "apps/v1", "Deployment", "default", "consul", "Available", 60*time.Second)
+}
+
+func TestK8sWaitCreatesWithNamespaceAndTimeout(t *testing.T) {
+	mk, p := setupK8sWait()
+	p.config.Namespace = "consul"
+	p.config.Timeout = "120s"
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "WaitForCondition", "apps/v1", "Deployment", "consul", "consul", "Available", 120*time.Second)
+}
+
+func TestK8sWaitReturnsErrorWhenConditionFails(t *testing.T) {
+	mk, p := setupK8sWait()
+	removeOn(&mk.Mock, "WaitForCondition")
+	mk.On("WaitForCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestK8sWaitReturnsErrorWhenInvalidTimeout(t *testing.T) {
+	_, p := setupK8sWait()
+	p.config.Timeout = "abc"
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 
 	hclog "github.com/hashicorp/go-hclog"
@@ -87,7 +88,7 @@ func NewK8sIngress(kc *config.K8sIngress, cc clients.ContainerTasks, l hclog.Log
 }
 
 // Create the ingress
-func (i *LegacyIngress) Create() error {
+func (i *LegacyIngress) Create(ctx context.Context) error {
 	i.log.Info("Creating Legacy Ingress", "ref", i.config.Name)
 
 	// check the ingress does not already exist
@@ -121,7 +122,7 @@ func (i *LegacyIngress) Create() error {
 
 	switch target.Info().Type {
 	case config.TypeContainer:
-		serviceName = utils.FQDN(target.Info().Name, string(target.Info().Type))
+		serviceName = utils.WorkspaceFQDN(target.Info().Name, string(target.Info().Type), i.client.Workspace())
 	case config.TypeNomadCluster:
 		v := target.(*config.NomadCluster)
 		// if this is a nomad cluster we need to add the nomadconfig and
@@ -211,7 +212,7 @@ func (i *LegacyIngress) Create() error {
 }
 
 // Destroy the ingress
-func (i *LegacyIngress) Destroy() error {
+func (i *LegacyIngress) Destroy(ctx context.Context) error {
 	i.log.Info("Destroy Ingress", "ref", i.config.Name, "type", i.config.Type)
 
 	ids, err := i.client.FindContainerIDs(i.config.Name, i.config.Type)
@@ -222,7 +223,7 @@ func (i *LegacyIngress) Destroy() error {
 	for _, id := range ids {
 		for _, n := range i.config.Networks {
 			i.log.Debug("Detaching container from network", "ref", i.config.Name, "id", id, "network", n.Name)
-			err := i.client.DetachNetwork(n.Name, id)
+			err := i.client.DetachNetwork(dockerNetworkName(&i.config.ResourceInfo, n.Name, i.client.Workspace()), id)
 			if err != nil {
 				i.log.Error("Unable to detach network", "ref", i.config.Name, "network", n.Name, "error", err)
 			}
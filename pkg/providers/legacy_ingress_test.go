@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -56,7 +57,7 @@ func TestIngressK8sErrorsWhenUnableToLookupIDs(t *testing.T) {
 
 	p := NewK8sIngress(&testK8sIngressConfig, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -66,7 +67,7 @@ func TestIngressK8sErrorsWhenClusterExists(t *testing.T) {
 
 	p := NewK8sIngress(&testK8sIngressConfig, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -76,7 +77,7 @@ func TestIngressK8sTargetPullsImage(t *testing.T) {
 
 	p := NewK8sIngress(conf.(*config.K8sIngress), md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", config.Image{Name: ingressImage}, false)
 }
@@ -87,7 +88,7 @@ func TestIngressK8sTargetConfiguresCommand(t *testing.T) {
 
 	p := NewK8sIngress(conf.(*config.K8sIngress), md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -109,7 +110,7 @@ func TestIngressK8sTargetConfiguresKubeConfig(t *testing.T) {
 
 	p := NewK8sIngress(conf.(*config.K8sIngress), md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	container := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -132,7 +133,7 @@ func TestIngressK8sTargetWithNamespaceConfiguresCommand(t *testing.T) {
 	tc.(*config.K8sIngress).Namespace = "mine"
 	p := NewK8sIngress(tc.(*config.K8sIngress), md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -148,7 +149,7 @@ func TestIngressK8sTargetWithServiceConfiguresCommand(t *testing.T) {
 	tc.(*config.K8sIngress).Service = "myservice"
 	p := NewK8sIngress(tc.(*config.K8sIngress), md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -165,7 +166,7 @@ func TestIngressK8sTargetWithPodConfiguresCommand(t *testing.T) {
 	tc.(*config.K8sIngress).Pod = "mypod"
 	p := NewK8sIngress(tc.(*config.K8sIngress), md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -182,7 +183,7 @@ func TestIngressK8sTargetWithDeploymentConfiguresCommand(t *testing.T) {
 	tc.(*config.K8sIngress).Service = ""
 	p := NewK8sIngress(tc.(*config.K8sIngress), md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -197,7 +198,7 @@ func TestIngressContainerTargetConfiguresCommand(t *testing.T) {
 
 	p := NewContainerIngress(tc.(*config.ContainerIngress), md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -212,7 +213,7 @@ func TestIngressContainerAddsPorts(t *testing.T) {
 
 	p := NewContainerIngress(tc.(*config.ContainerIngress), md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -234,7 +235,7 @@ func TestIngressContainerFailReturnsError(t *testing.T) {
 	md.On("CreateContainer", mock.Anything).Return("", fmt.Errorf("boom"))
 	p := NewContainerIngress(tc.(*config.ContainerIngress), md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -246,7 +247,7 @@ func TestIngressK8sTargetDestroysContainer(t *testing.T) {
 	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"ingress"}, nil)
 	p := NewLegacyIngress(tc.(*config.LegacyIngress), md, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "RemoveContainer", "ingress", true)
 	md.AssertCalled(t, "DetachNetwork", mock.Anything, mock.Anything, mock.Anything)
@@ -258,7 +259,7 @@ func TestIngressNomadTargetConfiguresNomadConfig(t *testing.T) {
 
 	p := NewNomadIngress(tc.(*config.NomadIngress), md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -275,7 +276,7 @@ func TestIngressNomadTargetConfiguresCommand(t *testing.T) {
 
 	p := NewNomadIngress(tc.(*config.NomadIngress), md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -297,7 +298,7 @@ func TestIngressNomadAddsPorts(t *testing.T) {
 
 	p := NewNomadIngress(tc.(*config.NomadIngress), md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"github.com/shipyard-run/shipyard/pkg/config"
 	"github.com/stretchr/testify/mock"
 )
@@ -14,12 +15,12 @@ func New(c config.Resource) *MockProvider {
 	return &MockProvider{c: c}
 }
 
-func (m *MockProvider) Create() error {
+func (m *MockProvider) Create(ctx context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
 
-func (m *MockProvider) Destroy() error {
+func (m *MockProvider) Destroy(ctx context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
@@ -11,23 +12,49 @@ import (
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients"
 	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
 	"golang.org/x/xerrors"
 )
 
 // Network is a provider for creating docker networks
 type Network struct {
-	config *config.Network
-	client clients.Docker
-	log    hclog.Logger
+	config    *config.Network
+	client    clients.Docker
+	workspace string
+	log       hclog.Logger
 }
 
-// NewNetwork creates a new network with the given config and Docker client
-func NewNetwork(co *config.Network, cl clients.Docker, l hclog.Logger) *Network {
-	return &Network{co, cl, l}
+// NewNetwork creates a new network with the given config and Docker client,
+// networks are named and labelled using the given workspace
+func NewNetwork(co *config.Network, cl clients.Docker, workspace string, l hclog.Logger) *Network {
+	return &Network{co, cl, workspace, l}
+}
+
+// name returns the name of the Docker network, outside of the default workspace
+// the name includes the workspace
+func (n *Network) name() string {
+	return utils.NetworkName(n.config.Name, n.workspace)
+}
+
+// dockerNetworkName returns the name of the Docker network for the network
+// resource referenced by the given resource e.g. network.cloud, networks which
+// are not external include the workspace in their name
+func dockerNetworkName(ri *config.ResourceInfo, name, workspace string) string {
+	if r, err := ri.FindDependentResource(name); err == nil {
+		if nw, ok := r.(*config.Network); ok && nw.External {
+			return nw.Name
+		}
+	}
+
+	return utils.NetworkName(strings.TrimPrefix(name, "network."), workspace)
 }
 
 // Create implements the provider interface method for creating new networks
-func (n *Network) Create() error {
+func (n *Network) Create(ctx context.Context) error {
+	if n.config.External {
+		return n.attachExternal()
+	}
+
 	n.log.Info("Creating Network", "ref", n.config.Name)
 
 	// validate the subnet
@@ -49,7 +76,7 @@ func (n *Network) Create() error {
 			bridgeExists = true
 		}
 
-		if ne.Name == n.config.Name {
+		if ne.Name == n.name() {
 			for _, ci := range ne.IPAM.Config {
 				// check that the returned networks subnet matches the existing networks subnet
 				if ci.Subnet != n.config.Subnet {
@@ -93,9 +120,10 @@ func (n *Network) Create() error {
 			},
 		},
 		Attachable: true,
+		Labels:     clients.ResourceLabels(n.config.Info().OwnerID(), n.config.Type, n.workspace),
 	}
 
-	_, err = n.client.NetworkCreate(context.Background(), n.config.Name, opts)
+	_, err = n.client.NetworkCreate(ctx, n.name(), opts)
 	if err != nil {
 		return err
 	}
@@ -107,25 +135,67 @@ func (n *Network) Create() error {
 }
 
 // Destroy implements the provider interface method for destroying networks
-func (n *Network) Destroy() error {
+func (n *Network) Destroy(ctx context.Context) error {
+	if n.config.External {
+		n.log.Info("Network is external, skip destroy", "ref", n.config.Name)
+		return nil
+	}
+
 	n.log.Info("Destroy Network", "ref", n.config.Name)
 
-	// check network exists if so remove
-	ids, err := n.Lookup()
+	nets, err := n.getNetworks(n.name())
 	if err != nil {
 		return xerrors.Errorf("Unable to list networks: %w", err)
 	}
 
-	if len(ids) == 1 {
-		return n.client.NetworkRemove(context.Background(), n.config.Name)
+	// the name filter matches partial names so check for an exact match
+	for _, ne := range nets {
+		if ne.Name != n.name() {
+			continue
+		}
+
+		// do not remove a network which was created by another workspace
+		if ws, ok := ne.Labels[clients.LabelWorkspace]; ok && ws != n.workspace {
+			n.log.Info("Network was created by another workspace, skip destroy", "ref", n.config.Name, "workspace", ws)
+			return nil
+		}
+
+		return n.client.NetworkRemove(ctx, ne.ID)
 	}
 
 	return nil
 }
 
+// attachExternal checks that an existing network exists and records its ID
+func (n *Network) attachExternal() error {
+	n.log.Info("Using external Network", "ref", n.config.Name)
+
+	nets, err := n.getNetworks(n.config.Name)
+	if err != nil {
+		return xerrors.Errorf("Unable to list networks: %w", err)
+	}
+
+	// the name filter matches partial names so check for an exact match
+	for _, ne := range nets {
+		if ne.Name == n.config.Name {
+			n.config.ID = ne.ID
+			n.config.Status = config.Applied
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Unable to use external network %s, the network does not exist", n.config.Name)
+}
+
 // Lookup the ID for a network
 func (n *Network) Lookup() ([]string, error) {
-	nets, err := n.getNetworks(n.config.Name)
+	name := n.config.Name
+	if !n.config.External {
+		name = n.name()
+	}
+
+	nets, err := n.getNetworks(name)
 
 	if err != nil {
 		return nil, err
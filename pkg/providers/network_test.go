@@ -1,14 +1,17 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/network"
 	hclog "github.com/hashicorp/go-hclog"
+	sclients "github.com/shipyard-run/shipyard/pkg/clients"
 	clients "github.com/shipyard-run/shipyard/pkg/clients/mocks"
 	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
 )
@@ -26,7 +29,7 @@ func setupNetworkTests(c *config.Network) (*clients.MockDocker, *Network) {
 	md.On("NetworkCreate", mock.Anything, mock.Anything, mock.Anything).Return(types.NetworkCreateResponse{}, nil)
 	md.On("NetworkList", mock.Anything, mock.Anything).Return([]types.NetworkResource{bridgeNetwork}, nil)
 
-	return md, NewNetwork(c, md, hclog.Default())
+	return md, NewNetwork(c, md, utils.DefaultWorkspace, hclog.Default())
 }
 
 func TestLookupReturnsID(t *testing.T) {
@@ -66,7 +69,7 @@ func TestNetworkCreatesCorrectly(t *testing.T) {
 
 	md, p := setupNetworkTests(c)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 
 	assert.NoError(t, err)
 
@@ -91,7 +94,7 @@ func TestNetworkCreatesNatWhenNoBridge(t *testing.T) {
 	removeOn(&md.Mock, "NetworkList")
 	md.On("NetworkList", mock.Anything, mock.Anything).Return(nil, nil)
 
-	p.Create()
+	p.Create(context.Background())
 
 	md.AssertCalled(t, "NetworkCreate", mock.Anything, mock.Anything, mock.Anything)
 
@@ -116,7 +119,7 @@ func TestNetworkDoesNOTCreateWhenExists(t *testing.T) {
 		}, bridgeNetwork,
 	}, nil)
 
-	p.Create()
+	p.Create(context.Background())
 
 	md.AssertNotCalled(t, "NetworkCreate", mock.Anything, mock.Anything, mock.Anything)
 }
@@ -136,7 +139,7 @@ func TestCreateWithCorrectNameAndDifferentSubnetReturnsError(t *testing.T) {
 		}, bridgeNetwork,
 	}, nil)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -155,6 +158,110 @@ func TestCreateWithOverlappingSubnetReturnsError(t *testing.T) {
 		}, bridgeNetwork,
 	}, nil)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
+
+func TestCreateExternalRecordsIDAndDoesNotCreate(t *testing.T) {
+	c := config.NewNetwork("testnet")
+	c.External = true
+
+	md, p := setupNetworkTests(c)
+	removeOn(&md.Mock, "NetworkList")
+	md.On("NetworkList", mock.Anything, mock.Anything).Return([]types.NetworkResource{
+		types.NetworkResource{ID: "abc", Name: "testnet-other"},
+		types.NetworkResource{ID: "def", Name: "testnet"},
+	}, nil)
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "def", c.ID)
+	assert.Equal(t, config.Applied, c.Status)
+	md.AssertNotCalled(t, "NetworkCreate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateExternalReturnsErrorWhenNetworkDoesNotExist(t *testing.T) {
+	c := config.NewNetwork("testnet")
+	c.External = true
+
+	md, p := setupNetworkTests(c)
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+	md.AssertNotCalled(t, "NetworkCreate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDestroyExternalDoesNotRemoveNetwork(t *testing.T) {
+	c := config.NewNetwork("testnet")
+	c.External = true
+
+	md, p := setupNetworkTests(c)
+
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+	md.AssertNotCalled(t, "NetworkRemove", mock.Anything, mock.Anything)
+}
+
+func TestDestroyRemovesNetwork(t *testing.T) {
+	c := config.NewNetwork("testnet")
+
+	md, p := setupNetworkTests(c)
+	md.On("NetworkRemove", mock.Anything, mock.Anything).Return(nil)
+	removeOn(&md.Mock, "NetworkList")
+	md.On("NetworkList", mock.Anything, mock.Anything).Return([]types.NetworkResource{
+		{ID: "abc", Name: "testnet", Labels: map[string]string{sclients.LabelWorkspace: utils.DefaultWorkspace}},
+	}, nil)
+
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "NetworkRemove", mock.Anything, "abc")
+}
+
+func TestDestroyDoesNotRemoveNetworkFromOtherWorkspace(t *testing.T) {
+	c := config.NewNetwork("testnet")
+
+	md, p := setupNetworkTests(c)
+	removeOn(&md.Mock, "NetworkList")
+	md.On("NetworkList", mock.Anything, mock.Anything).Return([]types.NetworkResource{
+		{ID: "abc", Name: "testnet", Labels: map[string]string{sclients.LabelWorkspace: "dev"}},
+	}, nil)
+
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+	md.AssertNotCalled(t, "NetworkRemove", mock.Anything, mock.Anything)
+}
+
+func TestNetworkCreatesWithWorkspaceName(t *testing.T) {
+	c := config.NewNetwork("testnet")
+	c.Subnet = "10.1.2.0/24"
+
+	md, _ := setupNetworkTests(c)
+	p := NewNetwork(c, md, "dev", hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "NetworkCreate")[0].Arguments
+	assert.Equal(t, "testnet.dev", params[1].(string))
+	assert.Equal(t, "dev", params[2].(types.NetworkCreate).Labels[sclients.LabelWorkspace])
+}
+
+func TestDestroyRemovesWorkspaceNetwork(t *testing.T) {
+	c := config.NewNetwork("testnet")
+
+	md, _ := setupNetworkTests(c)
+	p := NewNetwork(c, md, "dev", hclog.NewNullLogger())
+
+	md.On("NetworkRemove", mock.Anything, mock.Anything).Return(nil)
+	removeOn(&md.Mock, "NetworkList")
+	md.On("NetworkList", mock.Anything, mock.Anything).Return([]types.NetworkResource{
+		{ID: "abc", Name: "testnet", Labels: map[string]string{sclients.LabelWorkspace: utils.DefaultWorkspace}},
+		{ID: "def", Name: "testnet.dev", Labels: map[string]string{sclients.LabelWorkspace: "dev"}},
+	}, nil)
+
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "NetworkRemove", mock.Anything, "def")
+	md.AssertNotCalled(t, "NetworkRemove", mock.Anything, "abc")
+}
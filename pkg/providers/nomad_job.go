@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -24,7 +25,7 @@ func NewNomadJob(c *config.NomadJob, hc clients.Nomad, l hclog.Logger) *NomadJob
 }
 
 // Create the Nomad jobs defined by the config
-func (n *NomadJob) Create() error {
+func (n *NomadJob) Create(ctx context.Context) error {
 	n.log.Info("Create Nomad Job", "ref", n.config.Name, "files", n.config.Paths)
 
 	// find the cluster
@@ -64,7 +65,10 @@ func (n *NomadJob) Create() error {
 					break
 				}
 
-				time.Sleep(1 * time.Second)
+				err = sleepContext(ctx, 1*time.Second)
+				if err != nil {
+					return xerrors.Errorf("Cancelled waiting for health checks: %w", err)
+				}
 			}
 		}
 
@@ -74,7 +78,7 @@ func (n *NomadJob) Create() error {
 }
 
 // Destroy the Nomad jobs defined by the config
-func (n *NomadJob) Destroy() error {
+func (n *NomadJob) Destroy(ctx context.Context) error {
 	n.log.Info("Destroy Nomad Job", "ref", n.config.Name)
 
 	// find the cluster
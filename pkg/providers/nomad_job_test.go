@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -41,7 +42,7 @@ func TestNomadJobWithNonExistentClusterReturnsError(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -54,7 +55,7 @@ func TestNomadJobUnableToLoadConfigReturnsError(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -65,7 +66,7 @@ func TestNomadJobCreateReturnsError(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -74,7 +75,7 @@ func TestNomadJobValidatesConfig(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 }
 
@@ -89,7 +90,7 @@ func TestNomadJobHealthCheckInvalidDurationReturnsError(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -103,7 +104,7 @@ func TestNomadJobHealthCheckReturnsErrorWhenNotHealthy(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 	mh.AssertNumberOfCalls(t, "JobRunning", 3)
 }
@@ -118,7 +119,7 @@ func TestNomadJobHealthCheckReturnsErrorWhenHealthError(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 	mh.AssertNumberOfCalls(t, "JobRunning", 3)
 }
@@ -133,7 +134,7 @@ func TestNomadJobHealthCheckReturnsOKHealthy(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	mh.AssertNumberOfCalls(t, "JobRunning", 1)
 }
@@ -144,7 +145,7 @@ func TestNomadJobDestroyReturnsErrorWhenNoCluster(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.Error(t, err)
 }
 
@@ -156,7 +157,7 @@ func TestNomadJobDestroyCallsStop(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 
 	mh.AssertCalled(t, "Stop", jc.Paths)
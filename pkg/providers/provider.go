@@ -1,12 +1,32 @@
 package providers
 
-// Provider defines an interface to be implemented by providers
+import (
+	"context"
+	"time"
+)
+
+// Provider defines an interface to be implemented by providers, the context
+// passed to Create and Destroy is cancelled when the resource timeout elapses
 type Provider interface {
-	Create() error
-	Destroy() error
+	Create(ctx context.Context) error
+	Destroy(ctx context.Context) error
 	Lookup() ([]string, error)
 }
 
+// sleepContext waits for the duration d, returning the context error when the
+// context is cancelled before the duration elapses
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // ConfigWrapper alows the provider config to be deserialized to a type
 type ConfigWrapper struct {
 	Type  string
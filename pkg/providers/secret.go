@@ -0,0 +1,86 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// Secret provider writes the data for a Secret or DockerConfig resource to a
+// file which can be mounted into containers
+type Secret struct {
+	info   *config.ResourceInfo
+	value  string
+	source string
+	path   *string
+	mode   os.FileMode
+	log    hclog.Logger
+}
+
+// NewSecret creates a provider for a Secret resource, the file is only readable by the owner
+func NewSecret(c *config.Secret, l hclog.Logger) *Secret {
+	return &Secret{c.Info(), c.Value, c.Source, &c.Path, 0400, l}
+}
+
+// NewDockerConfig creates a provider for a DockerConfig resource
+func NewDockerConfig(c *config.DockerConfig, l hclog.Logger) *Secret {
+	return &Secret{c.Info(), c.Value, c.Source, &c.Path, 0444, l}
+}
+
+// Create writes the data to the file
+func (s *Secret) Create(ctx context.Context) error {
+	s.log.Info("Creating file", "ref", s.info.Name, "type", s.info.Type)
+
+	data := []byte(s.value)
+	if s.source != "" {
+		var err error
+		data, err = ioutil.ReadFile(s.source)
+		if err != nil {
+			return xerrors.Errorf("Unable to read source file %s: %w", s.source, err)
+		}
+	}
+
+	p := filepath.Join(utils.GetDataFolder(string(s.info.Type)), s.info.Name)
+
+	// files are read only, remove any file from a previous apply before writing
+	err := os.Remove(p)
+	if err != nil && !os.IsNotExist(err) {
+		return xerrors.Errorf("Unable to remove existing file %s: %w", p, err)
+	}
+
+	err = ioutil.WriteFile(p, data, s.mode)
+	if err != nil {
+		return xerrors.Errorf("Unable to write file %s: %w", p, err)
+	}
+
+	*s.path = p
+
+	return nil
+}
+
+// Destroy removes the file
+func (s *Secret) Destroy(ctx context.Context) error {
+	s.log.Info("Destroy file", "ref", s.info.Name, "type", s.info.Type)
+
+	if *s.path == "" {
+		return nil
+	}
+
+	err := os.Remove(*s.path)
+	if err != nil && !os.IsNotExist(err) {
+		s.log.Warn("Unable to delete file", "ref", s.info.Name, "file", *s.path, "error", err)
+	}
+
+	return nil
+}
+
+// Lookup statisfies the interface method but is not implemented by Secret
+func (s *Secret) Lookup() ([]string, error) {
+	return []string{}, nil
+}
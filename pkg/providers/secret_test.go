@@ -0,0 +1,102 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupSecretHome(t *testing.T) {
+	home := os.Getenv(utils.HomeEnvName())
+	os.Setenv(utils.HomeEnvName(), t.TempDir())
+
+	t.Cleanup(func() {
+		os.Setenv(utils.HomeEnvName(), home)
+	})
+}
+
+func TestSecretCreateWritesFileOnlyReadableByOwner(t *testing.T) {
+	setupSecretHome(t)
+
+	s := config.NewSecret("db_password")
+	s.Value = "s3cr3t"
+
+	p := NewSecret(s, hclog.NewNullLogger())
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	d, err := ioutil.ReadFile(s.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", string(d))
+
+	fi, err := os.Stat(s.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0400), fi.Mode().Perm())
+}
+
+func TestSecretCreateOverwritesExistingFile(t *testing.T) {
+	setupSecretHome(t)
+
+	s := config.NewSecret("db_password")
+	s.Value = "s3cr3t"
+
+	p := NewSecret(s, hclog.NewNullLogger())
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	s2 := config.NewSecret("db_password")
+	s2.Value = "changed"
+
+	err = NewSecret(s2, hclog.NewNullLogger()).Create(context.Background())
+	assert.NoError(t, err)
+
+	d, err := ioutil.ReadFile(s2.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, "changed", string(d))
+}
+
+func TestDockerConfigCreateReadsSource(t *testing.T) {
+	setupSecretHome(t)
+
+	src := filepath.Join(t.TempDir(), "app.json")
+	err := ioutil.WriteFile(src, []byte(`{"debug": true}`), 0644)
+	assert.NoError(t, err)
+
+	c := config.NewDockerConfig("app")
+	c.Source = src
+
+	p := NewDockerConfig(c, hclog.NewNullLogger())
+	err = p.Create(context.Background())
+	assert.NoError(t, err)
+
+	d, err := ioutil.ReadFile(c.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"debug": true}`, string(d))
+
+	fi, err := os.Stat(c.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0444), fi.Mode().Perm())
+}
+
+func TestSecretDestroyRemovesFile(t *testing.T) {
+	setupSecretHome(t)
+
+	s := config.NewSecret("db_password")
+	s.Value = "s3cr3t"
+
+	p := NewSecret(s, hclog.NewNullLogger())
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	err = p.Destroy(context.Background())
+	assert.NoError(t, err)
+
+	assert.NoFileExists(t, s.Path)
+}
@@ -0,0 +1,155 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+)
+
+const sshKeyBits = 3072
+const defaultAuthorizedKeys = "/root/.ssh/authorized_keys"
+
+// SSHKey provider generates SSH keypairs and adds the public key to containers
+type SSHKey struct {
+	config *config.SSHKey
+	client clients.ContainerTasks
+	log    hclog.Logger
+}
+
+// NewSSHKey creates a new SSHKey provider
+func NewSSHKey(c *config.SSHKey, cc clients.ContainerTasks, l hclog.Logger) *SSHKey {
+	return &SSHKey{c, cc, l}
+}
+
+// Create generates the keypair and writes it to the output folder
+func (s *SSHKey) Create(ctx context.Context) error {
+	s.log.Info("Generating SSH key", "ref", s.config.Name)
+
+	out := s.config.Output
+	if out == "" {
+		out = utils.GetDataFolder("ssh_keys")
+	}
+
+	err := os.MkdirAll(out, os.ModePerm)
+	if err != nil {
+		return xerrors.Errorf("Unable to create output folder for SSH key: %w", err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, sshKeyBits)
+	if err != nil {
+		return xerrors.Errorf("Unable to generate SSH key: %w", err)
+	}
+
+	priv := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	pk, err := ssh.NewPublicKey(&key.PublicKey)
+	if err != nil {
+		return xerrors.Errorf("Unable to generate SSH public key: %w", err)
+	}
+
+	pub := ssh.MarshalAuthorizedKey(pk)
+
+	s.config.PrivateKeyPath = filepath.Join(out, fmt.Sprintf("id_%s", s.config.Name))
+	s.config.PublicKeyPath = s.config.PrivateKeyPath + ".pub"
+
+	// ssh refuses to use private keys which can be read by other users
+	err = ioutil.WriteFile(s.config.PrivateKeyPath, priv, 0600)
+	if err != nil {
+		return xerrors.Errorf("Unable to write private key: %w", err)
+	}
+
+	err = ioutil.WriteFile(s.config.PublicKeyPath, pub, 0644)
+	if err != nil {
+		return xerrors.Errorf("Unable to write public key: %w", err)
+	}
+
+	for _, c := range s.config.Containers {
+		err := s.authorizeKey(c, pub)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// authorizeKey appends the public key to the authorized_keys file in the container
+func (s *SSHKey) authorizeKey(container string, pub []byte) error {
+	res, err := s.config.FindDependentResource(container)
+	if err != nil {
+		return xerrors.Errorf("Unable to find container %s: %w", container, err)
+	}
+
+	ids, err := s.client.FindContainerIDs(res.Info().Name, res.Info().Type)
+	if err != nil {
+		return xerrors.Errorf("Unable to find container %s: %w", container, err)
+	}
+
+	if len(ids) == 0 {
+		return fmt.Errorf("Unable to find container %s", container)
+	}
+
+	ak := s.config.AuthorizedKeys
+	if ak == "" {
+		ak = defaultAuthorizedKeys
+	}
+
+	s.log.Debug("Adding SSH key to container", "ref", s.config.Name, "container", container, "authorized_keys", ak)
+
+	// pass the key and path as environment variables to avoid quoting issues
+	env := []string{
+		fmt.Sprintf("SSH_PUBLIC_KEY=%s", bytes.TrimSpace(pub)),
+		fmt.Sprintf("AUTHORIZED_KEYS=%s", ak),
+	}
+
+	cmd := []string{
+		"sh", "-c",
+		`mkdir -p "$(dirname "$AUTHORIZED_KEYS")" && echo "$SSH_PUBLIC_KEY" >> "$AUTHORIZED_KEYS" && chmod 600 "$AUTHORIZED_KEYS"`,
+	}
+
+	for _, id := range ids {
+		err := s.client.ExecuteCommand(id, cmd, env, "/", "", "", s.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
+		if err != nil {
+			return xerrors.Errorf("Unable to add SSH key to container %s: %w", container, err)
+		}
+	}
+
+	return nil
+}
+
+// Destroy removes the generated keys
+func (s *SSHKey) Destroy(ctx context.Context) error {
+	s.log.Info("Destroy SSH key", "ref", s.config.Name)
+
+	for _, f := range []string{s.config.PrivateKeyPath, s.config.PublicKeyPath} {
+		if f == "" {
+			continue
+		}
+
+		err := os.Remove(f)
+		if err != nil && !os.IsNotExist(err) {
+			s.log.Warn("Unable to delete SSH key", "ref", s.config.Name, "file", f, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// Lookup statisfies the interface method but is not implemented by SSHKey
+func (s *SSHKey) Lookup() ([]string, error) {
+	return []string{}, nil
+}
@@ -0,0 +1,87 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/ssh"
+)
+
+func setupSSHKey(t *testing.T) (*config.SSHKey, *SSHKey, *mocks.MockContainerTasks) {
+	md := &mocks.MockContainerTasks{}
+	md.On("FindContainerIDs", "bastion", config.TypeContainer).Return([]string{"1234"}, nil)
+	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	k := config.NewSSHKey("demo")
+	k.Output = t.TempDir()
+
+	c := config.New()
+	c.AddResource(config.NewContainer("bastion"))
+	c.AddResource(k)
+
+	return k, NewSSHKey(k, md, hclog.NewNullLogger()), md
+}
+
+func TestSSHKeyCreateWritesKeys(t *testing.T) {
+	k, p, _ := setupSSHKey(t)
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	priv, err := ioutil.ReadFile(k.PrivateKeyPath)
+	assert.NoError(t, err)
+
+	_, err = ssh.ParsePrivateKey(priv)
+	assert.NoError(t, err)
+
+	pub, err := ioutil.ReadFile(k.PublicKeyPath)
+	assert.NoError(t, err)
+
+	_, _, _, _, err = ssh.ParseAuthorizedKey(pub)
+	assert.NoError(t, err)
+
+	fi, err := os.Stat(k.PrivateKeyPath)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+}
+
+func TestSSHKeyCreateAddsKeyToContainers(t *testing.T) {
+	k, p, md := setupSSHKey(t)
+	k.Containers = []string{"container.bastion"}
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "ExecuteCommand", "1234", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	env := getCalls(&md.Mock, "ExecuteCommand")[0].Arguments[2].([]string)
+	assert.Contains(t, env, "AUTHORIZED_KEYS=/root/.ssh/authorized_keys")
+}
+
+func TestSSHKeyCreateReturnsErrorWhenContainerNotFound(t *testing.T) {
+	k, p, _ := setupSSHKey(t)
+	k.Containers = []string{"container.missing"}
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestSSHKeyDestroyRemovesKeys(t *testing.T) {
+	k, p, _ := setupSSHKey(t)
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	err = p.Destroy(context.Background())
+	assert.NoError(t, err)
+
+	assert.NoFileExists(t, k.PrivateKeyPath)
+	assert.NoFileExists(t, k.PublicKeyPath)
+}
@@ -2,6 +2,7 @@ package providers
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
 	"os"
@@ -23,7 +24,7 @@ func NewTemplate(c *config.Template, l hclog.Logger) *Template {
 }
 
 // Create a new template
-func (c *Template) Create() error {
+func (c *Template) Create(ctx context.Context) error {
 	c.log.Info("Generating template", "ref", c.config.Name, "output", c.config.Destination)
 	c.log.Debug("Template content", "ref", c.config.Name, "source", c.config.Source)
 
@@ -84,7 +85,7 @@ func (c *Template) Create() error {
 	return nil
 }
 
-func (c *Template) Destroy() error {
+func (c *Template) Destroy(ctx context.Context) error {
 	if _, err := os.Stat(c.config.Destination); !os.IsNotExist(err) {
 		err := os.RemoveAll(c.config.Destination)
 		if err != nil {
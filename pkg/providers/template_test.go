@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -22,7 +23,7 @@ func TestTemplateReturnsErrorWhenEmpty(t *testing.T) {
 	tmpl, provider := setupTemplate(t)
 	tmpl.Source = ""
 
-	err := provider.Create()
+	err := provider.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -30,14 +31,14 @@ func TestTemplateReturnsErrorWhenCantParse(t *testing.T) {
 	tmpl, provider := setupTemplate(t)
 	tmpl.Source = "template #{{ .Something"
 
-	err := provider.Create()
+	err := provider.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestTemplateProcessesCorrectly(t *testing.T) {
 	tmpl, provider := setupTemplate(t)
 
-	err := provider.Create()
+	err := provider.Create(context.Background())
 	assert.NoError(t, err)
 
 	d, err := ioutil.ReadFile(tmpl.Destination)
@@ -50,7 +51,7 @@ func TestTemplateWriteSourceWhenNoVars(t *testing.T) {
 	tmpl, provider := setupTemplate(t)
 	provider.config.Vars = nil
 
-	err := provider.Create()
+	err := provider.Create(context.Background())
 	assert.NoError(t, err)
 
 	d, err := ioutil.ReadFile(tmpl.Destination)
@@ -67,7 +68,7 @@ func TestTemplateOverwritesExistingFile(t *testing.T) {
 	f.WriteString("Some text in the file")
 	f.Close()
 
-	err = provider.Create()
+	err = provider.Create(context.Background())
 	assert.NoError(t, err)
 
 	d, err := ioutil.ReadFile(tmpl.Destination)
@@ -84,7 +85,7 @@ func TestTemplateDestroyRemovesDestination(t *testing.T) {
 	f.WriteString("test")
 	f.Close()
 
-	err = provider.Destroy()
+	err = provider.Destroy(context.Background())
 	assert.NoError(t, err)
 
 	assert.NoFileExists(t, tmpl.Destination)
@@ -0,0 +1,87 @@
+package shipyard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+)
+
+// CycleError is returned when the resources in a config depend on each other
+type CycleError struct {
+	// Cycle is the chain of resource ids forming the cycle, the first and
+	// last ids are the same e.g. container.a, container.b, container.a
+	Cycle []string
+}
+
+func (c CycleError) Error() string {
+	return fmt.Sprintf("Dependency cycle found: %s", strings.Join(c.Cycle, " -> "))
+}
+
+// findCycle returns the first chain of dependencies in the config which
+// forms a cycle, nil is returned when the config does not contain a cycle.
+// Dependencies which can not be found are ignored.
+func findCycle(c *config.Config) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := map[config.Resource]int{}
+	path := []config.Resource{}
+
+	var visit func(r config.Resource) []string
+	visit = func(r config.Resource) []string {
+		switch state[r] {
+		case visited:
+			return nil
+		case visiting:
+			// r is on the current path, the cycle starts at r
+			cycle := []string{}
+			for i := len(path) - 1; i >= 0; i-- {
+				if path[i] == r {
+					for _, p := range path[i:] {
+						cycle = append(cycle, resourceID(p))
+					}
+
+					break
+				}
+			}
+
+			return append(cycle, resourceID(r))
+		}
+
+		state[r] = visiting
+		path = append(path, r)
+
+		for _, d := range r.Info().DependsOn {
+			deps := []config.Resource{}
+
+			if strings.HasPrefix(d, "module.") {
+				deps, _ = c.FindModuleResources(d)
+			} else if dr, err := c.FindResource(d); err == nil {
+				deps = append(deps, dr)
+			}
+
+			for _, dr := range deps {
+				if cycle := visit(dr); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[r] = visited
+
+		return nil
+	}
+
+	for _, r := range c.Resources {
+		if cycle := visit(r); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
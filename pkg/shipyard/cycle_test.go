@@ -0,0 +1,87 @@
+package shipyard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	assert "github.com/stretchr/testify/require"
+)
+
+func addTestContainer(c *config.Config, name string, deps ...string) {
+	co := config.NewContainer(name)
+	co.DependsOn = deps
+	c.AddResource(co)
+}
+
+func TestFindCycleReturnsNilWhenNoCycle(t *testing.T) {
+	c := config.New()
+	c.AddResource(config.NewNetwork("cloud"))
+	addTestContainer(c, "a", "network.cloud")
+	addTestContainer(c, "b", "container.a", "network.cloud")
+
+	assert.Nil(t, findCycle(c))
+}
+
+func TestFindCycleReturnsChain(t *testing.T) {
+	c := config.New()
+	addTestContainer(c, "x", "container.a")
+	addTestContainer(c, "a", "container.b")
+	addTestContainer(c, "b", "container.c")
+	addTestContainer(c, "c", "container.a")
+
+	assert.Equal(
+		t,
+		[]string{"container.a", "container.b", "container.c", "container.a"},
+		findCycle(c),
+	)
+}
+
+func TestValidateReturnsCycleError(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	dir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "blueprint.hcl"), []byte(cycleBlueprint), os.ModePerm)
+	assert.NoError(t, err)
+
+	err = e.Validate(dir, nil, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "container.a -> container.b -> container.a")
+}
+
+func TestParseConfigReturnsCycleError(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	dir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "blueprint.hcl"), []byte(cycleBlueprint), os.ModePerm)
+	assert.NoError(t, err)
+
+	err = e.ParseConfig(dir)
+	assert.Error(t, err)
+
+	ce, ok := err.(CycleError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"container.a", "container.b", "container.a"}, ce.Cycle)
+}
+
+var cycleBlueprint = `
+container "a" {
+  depends_on = ["container.b"]
+
+  image {
+    name = "consul:1.8.1"
+  }
+}
+
+container "b" {
+  depends_on = ["container.a"]
+
+  image {
+    name = "consul:1.8.1"
+  }
+}
+`
@@ -0,0 +1,139 @@
+package shipyard
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// Dependencies are the things a blueprint needs from outside of the
+// blueprint in order to be created
+type Dependencies struct {
+	// Images are the container images which are pulled
+	Images []string `json:"images"`
+	// Sources are the remote modules and blueprints which are fetched
+	Sources []string `json:"sources"`
+	// HelmCharts are the remote Helm charts which are fetched
+	HelmCharts []string `json:"helm_charts"`
+	// HostPaths are the folders and files on the host which are mounted
+	HostPaths []string `json:"host_paths"`
+	// Ports are the host ports which must be free
+	Ports []string `json:"ports"`
+}
+
+var configPtrType = reflect.TypeOf(&config.Config{})
+
+// ExternalDependencies parses the configuration at path and returns the
+// images, remote sources, and host resources the configuration depends on
+func (e *EngineImpl) ExternalDependencies(path string, variables map[string]string, variablesFile string) (*Dependencies, error) {
+	cc, err := parseConfig(path, variables, variablesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	images := map[string]bool{}
+	sources := map[string]bool{}
+	charts := map[string]bool{}
+	paths := map[string]bool{}
+	ports := map[string]bool{}
+
+	blueprints := filepath.Join(utils.ShipyardHome(), "blueprints") + string(filepath.Separator)
+
+	for _, r := range cc.Resources {
+		switch v := r.(type) {
+		case *config.Module:
+			// remote modules are downloaded to the blueprints folder by the parser
+			if strings.HasPrefix(v.Source, blueprints) {
+				sources[strings.TrimPrefix(v.Source, blueprints)] = true
+			}
+		case *config.Helm:
+			if !utils.IsLocalFolder(v.Chart) {
+				charts[v.Chart] = true
+			}
+		}
+
+		collectDependencies(reflect.ValueOf(r), images, paths, ports)
+	}
+
+	return &Dependencies{
+		Images:     sortedKeys(images),
+		Sources:    sortedKeys(sources),
+		HelmCharts: sortedKeys(charts),
+		HostPaths:  sortedKeys(paths),
+		Ports:      sortedKeys(ports),
+	}, nil
+}
+
+// collectDependencies walks the resource adding any images, bind mounts, and
+// host ports
+func collectDependencies(v reflect.Value, images, paths, ports map[string]bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		// do not walk back up to the parent config
+		if v.IsNil() || v.Type() == configPtrType {
+			return
+		}
+
+		collectDependencies(v.Elem(), images, paths, ports)
+
+	case reflect.Struct:
+		switch i := v.Interface().(type) {
+		case config.Image:
+			if i.Name != "" {
+				images[i.Name] = true
+			}
+			return
+		case config.Volume:
+			if i.Type == "" || i.Type == "bind" {
+				paths[i.Source] = true
+			}
+			return
+		case config.Port:
+			if i.Host != "" {
+				ports[i.Host+"/"+protocol(i.Protocol)] = true
+			}
+			return
+		case config.PortRange:
+			if i.EnableHost {
+				ports[i.Range+"/"+protocol(i.Protocol)] = true
+			}
+			return
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+
+			collectDependencies(v.Field(i), images, paths, ports)
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectDependencies(v.Index(i), images, paths, ports)
+		}
+	}
+}
+
+func protocol(p string) string {
+	if p == "" {
+		return "tcp"
+	}
+
+	return p
+}
+
+func sortedKeys(m map[string]bool) []string {
+	k := []string{}
+	for s := range m {
+		k = append(k, s)
+	}
+
+	sort.Strings(k)
+
+	return k
+}
@@ -0,0 +1,34 @@
+package shipyard
+
+import (
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestExternalDependenciesReturnsImagesPathsAndPorts(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	d, err := e.ExternalDependencies("../../examples/container", nil, "")
+	assert.NoError(t, err)
+
+	abs, _ := filepath.Abs("../../examples/container/consul_config")
+
+	assert.Contains(t, d.Images, "envoyproxy/envoy-alpine:v1.14.3")
+	assert.Contains(t, d.HostPaths, abs)
+	assert.Contains(t, d.Ports, "28500/tcp")
+	assert.Contains(t, d.Ports, "8500-8502/tcp")
+	assert.Empty(t, d.HelmCharts)
+}
+
+func TestExternalDependenciesReturnsRemoteHelmCharts(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	d, err := e.ExternalDependencies("../../examples/single_k3s_cluster", nil, "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"github.com/hashicorp/vault-helm?ref=v0.11.0"}, d.HelmCharts)
+}
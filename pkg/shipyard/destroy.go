@@ -0,0 +1,208 @@
+package shipyard
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// DestroyOptions defines the options for DestroyWithOptions
+type DestroyOptions struct {
+	// AllResources destroys every resource in the state not only the
+	// resources defined by the config at path
+	AllResources bool
+	// ContinueOnError destroys the remaining resources when a resource fails
+	// to be destroyed, resources which fail are kept in the state
+	ContinueOnError bool
+}
+
+// DestroyError is returned by DestroyWithOptions when ContinueOnError is set
+// and one or more resources could not be destroyed
+type DestroyError struct {
+	// Errors contains the error for each resource which could not be destroyed
+	Errors []error
+}
+
+func (d *DestroyError) add(r config.Resource, err error) {
+	d.Errors = append(d.Errors, fmt.Errorf("%s.%s: %w", r.Info().Type, r.Info().Name, err))
+}
+
+func (d *DestroyError) Error() string {
+	msgs := []string{}
+	for _, e := range d.Errors {
+		msgs = append(msgs, e.Error())
+	}
+
+	return fmt.Sprintf("Unable to destroy %d resources: %s", len(d.Errors), strings.Join(msgs, ", "))
+}
+
+// DestroyResource destroys the resource in the state with the given id e.g.
+// k8s_cluster.k3s and removes it from the state, all other resources are left
+// unchanged. An error is returned if other resources depend on the resource
+// unless force is set. The image cache can not be destroyed individually.
+func (e *EngineImpl) DestroyResource(id string, force bool) error {
+	// prevent other processes changing the state while destroying
+	err := config.LockStateFile(e.stateLockPath())
+	if err != nil {
+		return err
+	}
+	defer config.UnlockStateFile(e.stateLockPath())
+
+	_, err = e.readConfig("", nil, "")
+	if err != nil {
+		return err
+	}
+
+	r, err := e.config.FindResource(strings.TrimPrefix(id, "resource."))
+	if err != nil {
+		return xerrors.Errorf("Unable to find resource %s: %w", id, err)
+	}
+
+	if r.Info().Type == config.TypeImageCache {
+		return fmt.Errorf("Resource %s is the image cache and can not be destroyed individually", id)
+	}
+
+	deps, err := dependents(e.config, r)
+	if err != nil {
+		return err
+	}
+
+	// sidecars share the lifecycle of the container they are attached to
+	// and are destroyed with it rather than blocking the destroy
+	sidecars := attachedSidecars(e.config, r)
+	for _, sc := range sidecars {
+		deps = removeString(deps, fmt.Sprintf("%s.%s", sc.Info().Type, sc.Info().Name))
+	}
+
+	if len(deps) > 0 {
+		if !force {
+			return fmt.Errorf("Unable to destroy resource %s, it is a dependency of: %s", id, strings.Join(deps, ", "))
+		}
+
+		e.log.Warn("Destroying resource which other resources depend on", "ref", r.Info().Name, "type", r.Info().Type, "dependents", deps)
+	}
+
+	for _, sc := range sidecars {
+		e.log.Debug("Destroying sidecar attached to resource", "ref", sc.Info().Name, "target", r.Info().Name)
+
+		err = e.destroySingle(sc)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = e.destroySingle(r)
+	if err != nil {
+		return err
+	}
+
+	// if no resources in the state delete
+	if len(e.config.Resources) == 0 {
+		os.RemoveAll(e.statePath())
+		return nil
+	}
+
+	return e.saveState()
+}
+
+// destroySingle destroys the resource r and removes it from the config, when the
+// resource can not be destroyed its status is set to Failed
+func (e *EngineImpl) destroySingle(r config.Resource) error {
+	if r.Info().Status != config.Disabled {
+		p := e.getProvider(r, e.clients)
+		if p == nil {
+			return fmt.Errorf("Unable to create provider for resource Name: %s, Type: %s", r.Info().Name, r.Info().Type)
+		}
+
+		err := e.withTimeout(r, "destroy", p.Destroy)
+		if err != nil {
+			e.updateStatus(r, config.Failed)
+			e.emit(EventResourceFailed, r, err)
+			return err
+		}
+	}
+
+	r.Info().Status = config.Destroyed
+	e.emit(EventResourceDestroyed, r, nil)
+
+	e.config.RemoveResource(r)
+
+	return nil
+}
+
+// attachedSidecars returns the sidecars in the config which target the resource r
+func attachedSidecars(c *config.Config, r config.Resource) []config.Resource {
+	sidecars := []config.Resource{}
+
+	for _, sr := range c.FindResourcesByType(string(config.TypeSidecar)) {
+		t, err := c.FindResource(sr.(*config.Sidecar).Target)
+		if err == nil && t == r {
+			sidecars = append(sidecars, sr)
+		}
+	}
+
+	return sidecars
+}
+
+// dependents returns the ids of the resources in the config which depend
+// directly on the resource r
+func dependents(c *config.Config, r config.Resource) ([]string, error) {
+	deps := []string{}
+
+	for _, dr := range c.Resources {
+		if dr == r || dr.Info().Status == config.Destroyed {
+			continue
+		}
+
+		for _, d := range dr.Info().DependsOn {
+			found := []config.Resource{}
+
+			if strings.HasPrefix(d, "module.") {
+				mr, err := c.FindModuleResources(d)
+				if err != nil {
+					return nil, xerrors.Errorf("Unable to find dependency %s for resource %s.%s: %w", d, dr.Info().Type, dr.Info().Name, err)
+				}
+
+				found = append(found, mr...)
+			} else {
+				fr, err := c.FindResource(d)
+				if err != nil {
+					return nil, xerrors.Errorf("Unable to find dependency %s for resource %s.%s: %w", d, dr.Info().Type, dr.Info().Name, err)
+				}
+
+				found = append(found, fr)
+			}
+
+			if containsResource(found, r) {
+				deps = append(deps, fmt.Sprintf("%s.%s", dr.Info().Type, dr.Info().Name))
+				break
+			}
+		}
+	}
+
+	return deps, nil
+}
+
+func containsResource(rs []config.Resource, r config.Resource) bool {
+	for _, i := range rs {
+		if i == r {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeString(s []string, v string) []string {
+	out := []string{}
+	for _, i := range s {
+		if i != v {
+			out = append(out, i)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,205 @@
+package shipyard
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestDestroyResourceDestroysAndRemovesFromState(t *testing.T) {
+	e, mp, cleanup := setupTestsWithState(nil, destroyResourceState)
+	defer cleanup()
+
+	err := e.DestroyResource("container.consul", false)
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+
+	c := config.New()
+	err = c.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+	assert.Len(t, c.Resources, 2)
+
+	_, err = c.FindResource("container.consul")
+	assert.Error(t, err)
+}
+
+func TestDestroyResourceReturnsErrorWhenDependentsExist(t *testing.T) {
+	e, mp, cleanup := setupTestsWithState(nil, destroyResourceState)
+	defer cleanup()
+
+	err := e.DestroyResource("network.cloud", false)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "container.consul")
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+}
+
+func TestDestroyResourceWithForceDestroysWhenDependentsExist(t *testing.T) {
+	e, mp, cleanup := setupTestsWithState(nil, destroyResourceState)
+	defer cleanup()
+
+	err := e.DestroyResource("network.cloud", true)
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+}
+
+func TestDestroyResourceReturnsErrorForImageCache(t *testing.T) {
+	e, mp, cleanup := setupTestsWithState(nil, destroyResourceState)
+	defer cleanup()
+
+	err := e.DestroyResource("image_cache.docker-cache", true)
+	assert.Error(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+}
+
+func TestDestroyResourceFailSetsStatus(t *testing.T) {
+	e, _, cleanup := setupTestsWithState(map[string]error{"consul": fmt.Errorf("boom")}, destroyResourceState)
+	defer cleanup()
+
+	err := e.DestroyResource("container.consul", false)
+	assert.Error(t, err)
+
+	c := config.New()
+	err = c.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	r, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, config.Failed, r.Info().Status)
+}
+
+func TestDestroyResourceDestroysAttachedSidecars(t *testing.T) {
+	e, mp, cleanup := setupTestsWithState(nil, destroyResourceSidecarState)
+	defer cleanup()
+
+	err := e.DestroyResource("container.consul", false)
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 2)
+
+	c := config.New()
+	err = c.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("sidecar.envoy")
+	assert.Error(t, err)
+	_, err = c.FindResource("container.consul")
+	assert.Error(t, err)
+}
+
+func TestDestroyResourceWithStatePathOnlyChangesThatState(t *testing.T) {
+	dev := filepath.Join(t.TempDir(), "dev.json")
+	test := filepath.Join(t.TempDir(), "test.json")
+
+	for _, p := range []string{dev, test} {
+		err := ioutil.WriteFile(p, []byte(destroyResourceState), 0644)
+		assert.NoError(t, err)
+	}
+
+	// two engines in the same process each with their own state
+	e1, _, cleanup1 := setupTests(nil)
+	defer cleanup1()
+	WithStatePath(dev)(e1.(*EngineImpl))
+
+	e2, mp2, cleanup2 := setupTests(nil)
+	defer cleanup2()
+	WithStatePath(test)(e2.(*EngineImpl))
+
+	// a lock on one state does not block the other
+	err := config.LockStateFile(e2.(*EngineImpl).stateLockPath())
+	assert.NoError(t, err)
+
+	err = e1.DestroyResource("container.consul", false)
+	assert.NoError(t, err)
+
+	c := config.New()
+	err = c.FromJSON(dev)
+	assert.NoError(t, err)
+	_, err = c.FindResource("container.consul")
+	assert.Error(t, err)
+
+	c = config.New()
+	err = c.FromJSON(test)
+	assert.NoError(t, err)
+	_, err = c.FindResource("container.consul")
+	assert.NoError(t, err)
+
+	err = config.UnlockStateFile(e2.(*EngineImpl).stateLockPath())
+	assert.NoError(t, err)
+
+	err = e2.DestroyResource("sidecar.envoy", false)
+	assert.NoError(t, err)
+	testAssertMethodCalled(t, mp2, "Destroy", 1)
+
+	c = config.New()
+	err = c.FromJSON(test)
+	assert.NoError(t, err)
+	_, err = c.FindResource("sidecar.envoy")
+	assert.Error(t, err)
+	_, err = c.FindResource("container.consul")
+	assert.NoError(t, err)
+}
+
+var destroyResourceState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "cloud",
+      "status": "applied",
+      "subnet": "10.15.0.0/16",
+      "type": "network"
+	},
+	{
+      "name": "docker-cache",
+      "status": "applied",
+      "type": "image_cache",
+      "depends_on": ["network.cloud"]
+	},
+	{
+      "name": "consul",
+      "status": "applied",
+      "type": "container",
+      "depends_on": ["network.cloud"],
+      "image": {"name": "consul:1.8.1"}
+	}
+  ]
+}
+`
+
+var destroyResourceSidecarState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "cloud",
+      "status": "applied",
+      "subnet": "10.15.0.0/16",
+      "type": "network"
+	},
+	{
+      "name": "consul",
+      "status": "applied",
+      "type": "container",
+      "depends_on": ["network.cloud"],
+      "image": {"name": "consul:1.8.1"}
+	},
+	{
+      "name": "envoy",
+      "status": "applied",
+      "type": "sidecar",
+      "target": "container.consul",
+      "depends_on": ["container.consul"],
+      "image": {"name": "envoyproxy/envoy:v1.14.3"}
+	}
+  ]
+}
+`
@@ -4,11 +4,15 @@ import (
 
 	// "fmt"
 
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
@@ -49,12 +53,110 @@ type Engine interface {
 	// configuraiton. Optionally the user can provide a map of variables which the configuraiton
 	// uses and / or a file containing variables.
 	ApplyWithVariables(path string, variables map[string]string, variablesFile string) ([]config.Resource, error)
+	// ApplyWithOverlays applies the configuration at multiple paths, resources in later
+	// paths replace resources with the same id in earlier paths.
+	ApplyWithOverlays(paths []string, variables map[string]string, variablesFile string) ([]config.Resource, error)
+	// ApplyWithOptions applies the configuration at multiple paths using the given options
+	ApplyWithOptions(paths []string, options ApplyOptions) ([]config.Resource, error)
+	// ApplyTarget applies the resources with the given ids and their dependencies,
+	// all other resources are left unchanged
+	ApplyTarget(path string, targets []string, variables map[string]string, variablesFile string) ([]config.Resource, error)
 	ParseConfig(string) error
 	ParseConfigWithVariables(string, map[string]string, string) error
+	ParseConfigWithOverlays([]string, map[string]string, string) error
+	// RequiredVariables returns the names of the variables in the configuration
+	// at path which do not have a default and must be set
+	RequiredVariables(path string) ([]string, error)
+	// Validate parses the configuration at path and checks it is valid without
+	// reading the state or changing the engine's config
+	Validate(path string, variables map[string]string, variablesFile string) error
 	Destroy(string, bool) error
+	// DestroyWithOptions destroys the resources defined by the config at path
+	// using the given options
+	DestroyWithOptions(path string, options DestroyOptions) error
+	// DestroyResource destroys the resource with the given id and removes it from
+	// the state, force destroys the resource even when other resources depend on it
+	DestroyResource(id string, force bool) error
+
+	// VerifyDestroy checks that the resources removed by the last call to Destroy
+	// no longer exist, returning the ids of any resources which still exist
+	VerifyDestroy() ([]string, error)
 	ResourceCount() int
 	ResourceCountForType(string) int
 	Blueprint() *config.Blueprint
+
+	// SnapshotData archives the volumes for the container resource with the given id
+	// e.g. container.consul to the folder dest
+	SnapshotData(resourceID, dest string) error
+	// RestoreData restores volume snapshots created with SnapshotData from the folder src
+	// to the container resource with the given id
+	RestoreData(resourceID, src string) error
+
+	// RunHealthChecks runs the health checks for the resources in the current state
+	// and returns the result for each check
+	RunHealthChecks() ([]HealthResult, error)
+
+	// RebuildState parses the configuration at path and reconstructs the state file
+	// by matching the resources to the running Docker objects
+	RebuildState(path string, variables map[string]string, variablesFile string) error
+
+	// Policy evaluates the configuration at path against the rules in the policy file
+	// and returns any violations
+	Policy(path, policyPath string, variables map[string]string, variablesFile string) ([]PolicyViolation, error)
+
+	// Plan compares the configuration at path to the current state and returns
+	// the changes Apply would make
+	Plan(path string, variables map[string]string, variablesFile string) (*Plan, error)
+
+	// ImportK8s generates resources for the objects deployed to the namespace in the
+	// given Kubernetes cluster resource
+	ImportK8s(clusterResource, namespace string) ([]config.Resource, error)
+
+	// VariableUsage returns the ids of the resources in the configuration at path
+	// which reference the given variable
+	VariableUsage(path, variableName string) ([]string, error)
+
+	// EstimateApplyDuration estimates how long it would take to apply the
+	// configuration at path using the durations recorded by previous applies
+	EstimateApplyDuration(path string, variables map[string]string, variablesFile string) (time.Duration, error)
+
+	// ExternalDependencies returns the images, remote sources, and host
+	// resources the configuration at path needs to be created
+	ExternalDependencies(path string, variables map[string]string, variablesFile string) (*Dependencies, error)
+
+	// Graph returns the dependency graph for the configuration at path
+	// in Graphviz DOT format
+	Graph(path string, variables map[string]string, variablesFile string) (string, error)
+
+	// Reconcile applies the configuration at path every interval recreating any
+	// resources which have drifted, it blocks until StopReconcile is called
+	Reconcile(path string, interval time.Duration, variables map[string]string, variablesFile string) error
+	// StopReconcile stops a running reconcile loop
+	StopReconcile()
+
+	// Pause stops the containers for the resources in the current state
+	// without removing them or changing the state
+	Pause() error
+	// Resume starts the containers stopped by Pause and returns the resources
+	// which were restarted
+	Resume() ([]config.Resource, error)
+
+	// ResourceStatus returns the status of the resource with the given id
+	// from the loaded config, StatusUnknown is returned for unknown resources
+	ResourceStatus(id string) (string, error)
+	// Statuses returns the status of every resource in the loaded config
+	// keyed by resource id
+	Statuses() map[string]string
+
+	// Workspace selects the workspace with the given name, each workspace has
+	// its own state and resource names
+	Workspace(name string) error
+	// ListWorkspaces returns the names of the workspaces which have been created
+	ListWorkspaces() ([]string, error)
+
+	// Prune returns the Docker containers created by Shipyard which are not in the
+	// state, when force is set the containers are removed
+	Prune(force bool) ([]string, error)
 }
 
 // EngineImpl is responsible for creating and destroying resources
@@ -64,6 +166,49 @@ type EngineImpl struct {
 	log         hclog.Logger
 	getProvider getProviderFunc
 	sync        sync.Mutex
+
+	// snapshots are copies of the resources which are written to the state while
+	// resources are being applied, providers modify the resources they are creating
+	// so the resources in the config can not be serialized until they have finished
+	snapshots map[config.Resource]config.Resource
+
+	// stopReconcile is closed to stop a running reconcile loop
+	stopReconcile chan struct{}
+
+	// concurrency is the maximum number of resources created at once, 0 is unlimited
+	concurrency int
+
+	// events receives the lifecycle events for resources
+	events EventSink
+
+	// workspace is the name of the selected workspace, the state and the
+	// names of containers and networks belong to the workspace
+	workspace string
+
+	// stateFile overrides the location of the state file, the lock and
+	// backup are kept alongside it
+	stateFile string
+}
+
+// Option sets optional configuration for the engine
+type Option func(*EngineImpl)
+
+// WithConcurrency limits the number of resources which are created or
+// destroyed at the same time, resources which do not depend on each other
+// are created in parallel up to this limit
+func WithConcurrency(n int) Option {
+	return func(e *EngineImpl) {
+		e.concurrency = n
+	}
+}
+
+// WithStatePath sets the location of the state file used by the engine, the
+// lock and backup files are kept alongside the state. This allows more than
+// one engine in the same process to manage independent environments.
+func WithStatePath(path string) Option {
+	return func(e *EngineImpl) {
+		e.stateFile = path
+	}
 }
 
 // defines a function which is used for generating providers
@@ -95,14 +240,15 @@ func GenerateClients(l hclog.Logger) (*Clients, error) {
 
 	tgz := &clients.TarGz{}
 
-	ct := clients.NewDockerTasks(dc, il, tgz, l)
+	// add a deadline to Docker API calls so a hung daemon does not block forever
+	ct := clients.NewDockerTasks(dc, il, tgz, l, clients.WithTimeout(clients.DockerTimeout()))
 
 	co := clients.DefaultConnectorOptions()
 	cc := clients.NewConnector(co)
 
 	return &Clients{
 		ContainerTasks: ct,
-		Docker:         dc,
+		Docker:         clients.NewTimeoutDocker(dc, clients.DockerTimeout()),
 		Kubernetes:     kc,
 		Helm:           hec,
 		Command:        ec,
@@ -118,11 +264,17 @@ func GenerateClients(l hclog.Logger) (*Clients, error) {
 }
 
 // New creates a new shipyard engine
-func New(l hclog.Logger) (Engine, error) {
+func New(l hclog.Logger, opts ...Option) (Engine, error) {
 	var err error
 	e := &EngineImpl{}
 	e.log = l
 	e.getProvider = generateProviderImpl
+	e.events = noopSink{}
+	e.workspace = utils.Workspace()
+
+	for _, o := range opts {
+		o(e)
+	}
 
 	// Set the standard writer to our logger as the DAG uses the standard library log.
 	log.SetOutput(l.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Trace}))
@@ -162,6 +314,29 @@ func (e *EngineImpl) ParseConfigWithVariables(path string, vars map[string]strin
 	return nil
 }
 
+// RequiredVariables returns the names of the variables defined in the
+// configuration at path which do not have a default value, values for
+// these variables must be set when parsing or applying the configuration
+func (e *EngineImpl) RequiredVariables(path string) ([]string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.RequiredVariables(path)
+}
+
+// ParseConfigWithOverlays parses the Shipyard files at paths merging them into a single
+// config, resources in later paths replace resources with the same id in earlier paths.
+func (e *EngineImpl) ParseConfigWithOverlays(paths []string, vars map[string]string, variablesFile string) error {
+	_, err := e.readConfigs(paths, vars, variablesFile)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // Apply the configuration and create or destroy the resources
 func (e *EngineImpl) Apply(path string) ([]config.Resource, error) {
 	return e.ApplyWithVariables(path, nil, "")
@@ -169,14 +344,40 @@ func (e *EngineImpl) Apply(path string) ([]config.Resource, error) {
 
 // ApplyWithVariables applies the current config creating the resources
 func (e *EngineImpl) ApplyWithVariables(path string, vars map[string]string, variablesFile string) ([]config.Resource, error) {
-	// abs paths
-	var err error
-	path, err = filepath.Abs(path)
+	return e.ApplyWithOverlays([]string{path}, vars, variablesFile)
+}
+
+// ApplyWithOverlays applies the configuration at paths, resources in later paths
+// replace resources with the same id in earlier paths
+func (e *EngineImpl) ApplyWithOverlays(paths []string, vars map[string]string, variablesFile string) ([]config.Resource, error) {
+	return e.ApplyWithOptions(paths, ApplyOptions{Variables: vars, VariablesFile: variablesFile})
+}
+
+// ApplyWithOptions applies the configuration at paths using the given options
+func (e *EngineImpl) ApplyWithOptions(paths []string, opts ApplyOptions) ([]config.Resource, error) {
+	vars := opts.Variables
+	variablesFile := opts.VariablesFile
+
+	// prevent other processes changing the state while applying
+	err := config.LockStateFile(e.stateLockPath())
 	if err != nil {
 		return nil, err
 	}
+	defer config.UnlockStateFile(e.stateLockPath())
 
-	e.log.Info("Creating resources from configuration", "path", path)
+	// abs paths
+	absPaths := []string{}
+	for _, p := range paths {
+		ap, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+
+		absPaths = append(absPaths, ap)
+	}
+	paths = absPaths
+
+	e.log.Info("Creating resources from configuration", "path", strings.Join(paths, ","))
 
 	if variablesFile != "" {
 		variablesFile, err = filepath.Abs(variablesFile)
@@ -185,13 +386,64 @@ func (e *EngineImpl) ApplyWithVariables(path string, vars map[string]string, var
 		}
 	}
 
-	d, err := e.readConfig(path, vars, variablesFile)
+	// take a copy of the state so the apply can be rolled back
+	var rt *rollbackTracker
+	if opts.Rollback {
+		rt, err = e.snapshotState()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	d, err := e.readConfigs(paths, vars, variablesFile)
 	if err != nil {
 		return nil, err
 	}
 
+	err = orderGraph(d, e.config, opts.Strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	orderPriority(d, e.config)
+
+	if !config.StateEncryptionEnabled() && e.config.HasSensitiveValues() {
+		e.log.Warn("State contains sensitive values which will be saved as plain text, set " + config.StateKeyEnvName + " to encrypt them")
+	}
+
+	// when targets are specified only the targets and their dependencies are applied
+	var targeted map[config.Resource]bool
+	if len(opts.Targets) > 0 {
+		targeted, err = targetResources(e.config, opts.Targets)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.RefreshImages {
+		e.refreshImages()
+	}
+
+	if opts.RecreateDrifted {
+		e.markDriftedResources()
+	}
+
+	e.markUnhealthyContainers()
+	e.markReplacedResources()
+	e.cascadeSidecars()
+
 	createdResource := []config.Resource{}
 
+	// record how long each resource takes to create so future applies can be estimated
+	history := loadApplyHistory(utils.ApplyHistoryPath())
+
+	// limit the number of concurrent operations for resource types
+	limiter := newTypeLimiter(e.config.Blueprint)
+	limiter.setConcurrency(e.concurrency)
+
+	// once a resource fails resources which have not started are not created
+	var cancelled int32
+
 	// walk the dag and apply the config
 	w := dag.Walker{}
 	w.Callback = func(v dag.Vertex) (diags tfdiags.Diagnostics) {
@@ -203,12 +455,44 @@ func (e *EngineImpl) ApplyWithVariables(path string, vars map[string]string, var
 			return nil
 		}
 
+		// resources which are not targeted are left unchanged
+		if targeted != nil && !targeted[r] {
+			return nil
+		}
+
 		// get the provider to create the resource
 		p := e.getProvider(r, e.clients)
 
 		if p == nil {
-			r.Info().Status = config.Failed
-			return diags.Append(fmt.Errorf("Unable to create provider for resource Name: %s, Type: %s", r.Info().Name, r.Info().Type))
+			err := fmt.Errorf("Unable to create provider for resource Name: %s, Type: %s", r.Info().Name, r.Info().Type)
+			e.updateStatus(r, config.Failed)
+			e.emit(EventResourceFailed, r, err)
+			return diags.Append(err)
+		}
+
+		release := limiter.acquire(r.Info().Type)
+		defer release()
+
+		if atomic.LoadInt32(&cancelled) == 1 {
+			e.log.Debug("Skipping resource, a previous resource failed", "ref", r.Info().Name, "type", r.Info().Type)
+			return nil
+		}
+
+		// references to the attributes of other resources can only be resolved once
+		// the referenced resources, which are dependencies, have been created
+		if r.Info().Status != config.Disabled {
+			err := config.ResolveReferences(r)
+			if err != nil {
+				atomic.StoreInt32(&cancelled, 1)
+				e.updateStatus(r, config.Failed)
+				e.emit(EventResourceFailed, r, err)
+				return diags.Append(resourceError("create", r, err))
+			}
+		}
+
+		switch r.Info().Status {
+		case config.PendingModification, config.Failed, config.PendingCreation:
+			e.emit(EventResourceStarted, r, nil)
 		}
 
 		switch r.Info().Status {
@@ -220,32 +504,67 @@ func (e *EngineImpl) ApplyWithVariables(path string, vars map[string]string, var
 
 			// Always attempt to destroy and re-create failed resources
 		case config.Failed:
-			err = p.Destroy()
+			err := e.withTimeout(r, "destroy", p.Destroy)
 			if err != nil {
-				r.Info().Status = config.Failed
-				return diags.Append(err)
+				atomic.StoreInt32(&cancelled, 1)
+				e.updateStatus(r, config.Failed)
+				e.emit(EventResourceFailed, r, err)
+				return diags.Append(resourceError("destroy", r, err))
 			}
 
 			fallthrough // failed resources should always attempt recreation
 
 		// Create new resources
 		case config.PendingCreation:
-			createErr := p.Create()
+			st := time.Now()
+
+			// only new resources are rolled back, they are recorded before
+			// creation so that partially created resources are removed
+			if rt != nil && r.Info().Status == config.PendingCreation {
+				rt.record(r)
+			}
+
+			createErr := e.createWithRetry(r, p)
 			if createErr != nil {
-				r.Info().Status = config.Failed
-				return diags.Append(createErr)
+				atomic.StoreInt32(&cancelled, 1)
+				e.updateStatus(r, config.Failed)
+				e.emit(EventResourceFailed, r, createErr)
+				return diags.Append(resourceError("create", r, createErr))
 			}
 
+			history.record(r, time.Since(st))
+
+			e.updateStatus(r, config.Applied)
+			e.emit(EventResourceCreated, r, nil)
+
 		case config.PendingUpdate:
-			// do nothing for pending updates
+			// unchanged resources are left as they are, resources which
+			// can be updated in place are applied again
+			reason := e.updateReason(r, r)
+			if reason == "" {
+				break
+			}
+
+			e.log.Info("Updating resource", "ref", r.Info().Name, "type", r.Info().Type, "reason", reason)
+			e.emit(EventResourceStarted, r, nil)
+
+			err := e.withTimeout(r, "update", p.Create)
+			if err != nil {
+				atomic.StoreInt32(&cancelled, 1)
+				e.updateStatus(r, config.Failed)
+				e.emit(EventResourceFailed, r, err)
+				return diags.Append(resourceError("update", r, err))
+			}
+
+			e.emit(EventResourceCreated, r, nil)
 
 		case config.Disabled:
 			// do nothing for disabled updates
 		}
 
 		// set the status only if not disabled
-		if r.Info().Status != config.Disabled {
-			r.Info().Status = config.Applied
+		if r.Info().Status != config.Disabled && r.Info().Status != config.Applied {
+			e.updateStatus(r, config.Applied)
 		}
 
 		appendResources(&createdResource, r)
@@ -253,15 +572,41 @@ func (e *EngineImpl) ApplyWithVariables(path string, vars map[string]string, var
 		return nil
 	}
 
+	err = e.startSnapshots()
+	if err != nil {
+		return nil, err
+	}
+
 	w.Update(d)
 	tf := w.Wait()
+	e.stopSnapshots()
+
 	if tf.Err() != nil {
 		err = tf.Err()
+		e.failInitDependents()
+	}
+
+	if targeted != nil {
+		e.restoreUntargeted(targeted)
+	}
+
+	herr := history.save(utils.ApplyHistoryPath())
+	if herr != nil {
+		e.log.Warn("Unable to save apply history", "error", herr)
+	}
+
+	if err != nil && rt != nil {
+		rerr := e.rollback(rt)
+		if rerr != nil {
+			return nil, xerrors.Errorf("Unable to roll back failed apply: %s, apply error: %w", rerr, err)
+		}
+
+		return nil, err
 	}
 
 	if len(e.config.Resources) > 0 {
 		// save the state regardless of error
-		jerr := e.config.ToJSON(utils.StatePath())
+		jerr := e.saveState()
 		if jerr != nil {
 			return createdResource, jerr
 		}
@@ -272,15 +617,278 @@ func (e *EngineImpl) ApplyWithVariables(path string, vars map[string]string, var
 	return nil, tf.Err()
 }
 
+// resourceError adds the id of the resource and, when it is known, the file
+// and line which define the resource to an error returned by a provider
+func resourceError(action string, r config.Resource, err error) error {
+	loc := ""
+	if r.Info().File != "" {
+		loc = fmt.Sprintf(" (defined in %s:%d)", r.Info().File, r.Info().Line)
+	}
+
+	return xerrors.Errorf("Unable to %s resource %s.%s%s: %w", action, r.Info().Type, r.Info().Name, loc, err)
+}
+
+// updateStatus sets the status of a resource and saves the state so that an
+// interrupted apply leaves an accurate record of the resources which exist.
+// updateStatus must only be called by the goroutine applying r, while resources
+// are being applied the state is written from the snapshots of the resources.
+func (e *EngineImpl) updateStatus(r config.Resource, s config.Status) {
+	e.sync.Lock()
+	defer e.sync.Unlock()
+
+	r.Info().Status = s
+
+	var err error
+	if e.snapshots != nil {
+		err = e.saveSnapshot(r)
+	} else {
+		err = e.config.ToJSON(e.statePath())
+	}
+
+	if err != nil {
+		e.log.Warn("Unable to save state", "ref", r.Info().Name, "type", r.Info().Type, "error", err)
+	}
+}
+
+// startSnapshots copies every resource so the state can be saved while
+// resources are being applied, no resources can be in use when it is called
+func (e *EngineImpl) startSnapshots() error {
+	e.sync.Lock()
+	defer e.sync.Unlock()
+
+	e.snapshots = map[config.Resource]config.Resource{}
+	for _, r := range e.config.Resources {
+		sr, err := config.CopyResource(r)
+		if err != nil {
+			e.snapshots = nil
+			return xerrors.Errorf("Unable to copy resource %s.%s: %w", r.Info().Type, r.Info().Name, err)
+		}
+
+		e.snapshots[r] = sr
+	}
+
+	return nil
+}
+
+// stopSnapshots removes the snapshots once no resources are in use
+func (e *EngineImpl) stopSnapshots() {
+	e.sync.Lock()
+	defer e.sync.Unlock()
+
+	e.snapshots = nil
+}
+
+// saveSnapshot replaces the snapshot of r, which has finished being applied, and writes
+// the snapshots to the state, the caller must hold the state lock
+func (e *EngineImpl) saveSnapshot(r config.Resource) error {
+	sr, err := config.CopyResource(r)
+	if err != nil {
+		return err
+	}
+
+	e.snapshots[r] = sr
+
+	sc := config.New()
+	sc.Blueprint = e.config.Blueprint
+
+	for _, r := range e.config.Resources {
+		if sr, ok := e.snapshots[r]; ok {
+			sc.AddResource(sr)
+		}
+	}
+
+	return sc.ToJSON(e.statePath())
+}
+
+// saveState writes the current config to the state file
+func (e *EngineImpl) saveState() error {
+	e.sync.Lock()
+	defer e.sync.Unlock()
+
+	return e.config.ToJSON(e.statePath())
+}
+
+// refreshImages forces images to be pulled and the image cache to be cleared
+func (e *EngineImpl) refreshImages() {
+	e.log.Info("Refreshing images")
+
+	if e.clients != nil && e.clients.ContainerTasks != nil {
+		e.clients.ContainerTasks.SetForcePull(true)
+	}
+
+	for _, r := range e.config.FindResourcesByType(string(config.TypeImageCache)) {
+		r.(*config.ImageCache).Refresh = true
+	}
+}
+
+// cascadeSidecars couples the lifecycle of sidecars to the container they are attached to.
+// When the target container is going to be created or re-created any existing sidecars are
+// also re-created, otherwise the sidecar would still reference the removed container.
+func (e *EngineImpl) cascadeSidecars() {
+	for _, r := range e.config.Resources {
+		s, ok := r.(*config.Sidecar)
+		if !ok {
+			continue
+		}
+
+		t, err := e.config.FindResource(s.Target)
+		if err != nil {
+			continue
+		}
+
+		switch t.Info().Status {
+		case config.PendingCreation, config.PendingModification, config.Failed:
+			if s.Status == config.Applied || s.Status == config.PendingUpdate {
+				e.log.Debug("Target for sidecar will be re-created, re-creating sidecar", "ref", s.Name, "target", s.Target)
+				s.Status = config.PendingModification
+			}
+		}
+	}
+}
+
+// cascadeSidecarDestroy marks sidecars to be destroyed when the container they are
+// attached to is going to be destroyed
+func (e *EngineImpl) cascadeSidecarDestroy() {
+	for _, r := range e.config.Resources {
+		s, ok := r.(*config.Sidecar)
+		if !ok || s.Status == config.Disabled {
+			continue
+		}
+
+		t, err := e.config.FindResource(s.Target)
+		if err != nil {
+			continue
+		}
+
+		if t.Info().Status == config.PendingUpdate {
+			s.Status = config.PendingUpdate
+		}
+	}
+}
+
+// failInitDependents marks the resources which were not created because an
+// init container they depend on failed as failed
+func (e *EngineImpl) failInitDependents() {
+	failed := []config.Resource{}
+	for _, r := range e.config.Resources {
+		if c, ok := r.(*config.Container); ok && c.Init && c.Status == config.Failed {
+			failed = append(failed, r)
+		}
+	}
+
+	for len(failed) > 0 {
+		r := failed[0]
+		failed = failed[1:]
+
+		ids, err := dependents(e.config, r)
+		if err != nil {
+			e.log.Debug("Unable to find dependents for init container", "ref", r.Info().Name, "error", err)
+			continue
+		}
+
+		for _, id := range ids {
+			// only resources which were waiting to be created are failed
+			dr, err := e.config.FindResource(id)
+			if err != nil || (dr.Info().Status != config.PendingCreation && dr.Info().Status != config.PendingModification) {
+				continue
+			}
+
+			e.log.Debug("Resource not created as init container failed", "ref", dr.Info().Name, "type", dr.Info().Type, "init", r.Info().Name)
+			e.updateStatus(dr, config.Failed)
+			failed = append(failed, dr)
+		}
+	}
+}
+
+// createWithRetry calls Create on the provider, when the resource defines a retry
+// block attempts which fail with a transient error are destroyed and retried with
+// an exponential backoff. Other errors are returned without retrying.
+func (e *EngineImpl) createWithRetry(r config.Resource, p providers.Provider) error {
+	attempts := 1
+	backoff := 1 * time.Second
+	var maxWait time.Duration
+
+	if rt := r.Info().Retry; rt != nil {
+		if rt.Attempts > 1 {
+			attempts = rt.Attempts
+		}
+
+		if rt.Backoff != "" {
+			d, err := time.ParseDuration(rt.Backoff)
+			if err != nil {
+				return xerrors.Errorf("Unable to parse retry backoff for resource %s.%s: %w", r.Info().Type, r.Info().Name, err)
+			}
+
+			backoff = d
+		}
+
+		if rt.MaxWait != "" {
+			d, err := time.ParseDuration(rt.MaxWait)
+			if err != nil {
+				return xerrors.Errorf("Unable to parse retry max_wait for resource %s.%s: %w", r.Info().Type, r.Info().Name, err)
+			}
+
+			maxWait = d
+		}
+	}
+
+	var err error
+	for i := 1; i <= attempts; i++ {
+		r.Info().Attempts = i
+
+		err = e.withTimeout(r, "create", p.Create)
+		if err == nil || i == attempts {
+			break
+		}
+
+		if !isRetryable(err) {
+			e.log.Debug("Create failed with an error which can not be retried", "ref", r.Info().Name, "type", r.Info().Type, "error", err)
+			break
+		}
+
+		if maxWait > 0 && backoff > maxWait {
+			backoff = maxWait
+		}
+
+		e.log.Warn("Unable to create resource, retrying", "ref", r.Info().Name, "type", r.Info().Type, "attempt", i, "backoff", backoff, "error", err)
+
+		// clean up anything which was partially created before trying again
+		derr := e.withTimeout(r, "destroy", p.Destroy)
+		if derr != nil {
+			e.log.Debug("Unable to destroy resource before retry", "ref", r.Info().Name, "type", r.Info().Type, "error", derr)
+		}
+
+		time.Sleep(backoff)
+		backoff = backoff * 2
+	}
+
+	return err
+}
+
 // Destroy the resources defined by the config
 func (e *EngineImpl) Destroy(path string, allResources bool) error {
+	return e.DestroyWithOptions(path, DestroyOptions{AllResources: allResources})
+}
+
+// DestroyWithOptions destroys the resources defined by the config at path.
+// When options.ContinueOnError is set resources which fail to destroy do not
+// stop the remaining resources being destroyed, the failures are returned as
+// a DestroyError and the failed resources are kept in the state.
+func (e *EngineImpl) DestroyWithOptions(path string, opts DestroyOptions) error {
+	// prevent other processes changing the state while destroying
+	err := config.LockStateFile(e.stateLockPath())
+	if err != nil {
+		return err
+	}
+	defer config.UnlockStateFile(e.stateLockPath())
+
 	d, err := e.readConfig(path, nil, "")
 	if err != nil {
 		return err
 	}
 
 	// make sure we destroy everything
-	if allResources {
+	if opts.AllResources {
 		for _, i := range e.config.Resources {
 			if i.Info().Status != config.Disabled {
 				i.Info().Status = config.PendingUpdate
@@ -288,6 +896,59 @@ func (e *EngineImpl) Destroy(path string, allResources bool) error {
 		}
 	}
 
+	e.cascadeSidecarDestroy()
+
+	// limit the number of concurrent operations for resource types
+	limiter := newTypeLimiter(e.config.Blueprint)
+	limiter.setConcurrency(e.concurrency)
+
+	// errors collected when continuing on error
+	destroyErrs := &DestroyError{}
+	errLock := sync.Mutex{}
+
+	// resources which have not been destroyed, when continuing on error the
+	// dependencies of these resources are not destroyed as they are still in use
+	notDestroyed := map[dag.Vertex]bool{}
+
+	// dependentExists returns true when a resource which depends on r has not been destroyed
+	dependentExists := func(r config.Resource) bool {
+		errLock.Lock()
+		defer errLock.Unlock()
+
+		for _, v := range d.DownEdges(r).List() {
+			if notDestroyed[v] {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// fail sets the resource as failed, when continuing on error the error is
+	// recorded and not returned so the walk does not skip unrelated resources
+	fail := func(r config.Resource, err error) tfdiags.Diagnostics {
+		var diags tfdiags.Diagnostics
+
+		r.Info().Status = config.Failed
+		e.emit(EventResourceFailed, r, err)
+
+		errLock.Lock()
+		notDestroyed[r] = true
+		errLock.Unlock()
+
+		if opts.ContinueOnError {
+			e.log.Error("Unable to destroy resource, continuing", "ref", r.Info().Name, "type", r.Info().Type, "error", err)
+
+			errLock.Lock()
+			destroyErrs.add(r, err)
+			errLock.Unlock()
+
+			return nil
+		}
+
+		return diags.Append(resourceError("destroy", r, err))
+	}
+
 	// walk the dag and apply the config
 	w := dag.Walker{}
 	w.Reverse = true
@@ -303,21 +964,34 @@ func (e *EngineImpl) Destroy(path string, allResources bool) error {
 					return nil
 				}
 
+				if dependentExists(r) {
+					e.log.Warn("Not destroying resource, a resource which depends on it was not destroyed", "ref", r.Info().Name, "type", r.Info().Type)
+
+					errLock.Lock()
+					notDestroyed[r] = true
+					errLock.Unlock()
+
+					return nil
+				}
+
 				// get the provider to create the resource
 				p := e.getProvider(r, e.clients)
 				if p == nil {
-					r.Info().Status = config.Failed
-					return diags.Append(fmt.Errorf("Unable to create provider for resource Name: %s, Type: %s", r.Info().Name, r.Info().Type))
+					return fail(r, fmt.Errorf("Unable to create provider for resource Name: %s, Type: %s", r.Info().Name, r.Info().Type))
 				}
 
 				// execute
-				destroyErr := p.Destroy()
+				release := limiter.acquire(r.Info().Type)
+				destroyErr := e.withTimeout(r, "destroy", p.Destroy)
+				release()
+
 				if destroyErr != nil {
-					r.Info().Status = config.Failed
-					return diags.Append(destroyErr)
+					return fail(r, destroyErr)
 				}
 
-				fallthrough
+				r.Info().Status = config.Destroyed
+				e.emit(EventResourceDestroyed, r, nil)
+				return nil
 			case config.Disabled:
 				// set the status
 				r.Info().Status = config.Destroyed
@@ -343,13 +1017,17 @@ func (e *EngineImpl) Destroy(path string, allResources bool) error {
 
 	// save the state regardless of error
 	if len(cn.Resources) > 0 {
-		err = cn.ToJSON(utils.StatePath())
+		err = cn.ToJSON(e.statePath())
 		if err != nil {
 			return err
 		}
 	} else {
 		// if no resources in the state delete
-		os.RemoveAll(utils.StatePath())
+		os.RemoveAll(e.statePath())
+	}
+
+	if len(destroyErrs.Errors) > 0 {
+		return destroyErrs
 	}
 
 	return tf.Err()
@@ -370,14 +1048,312 @@ func (e *EngineImpl) Blueprint() *config.Blueprint {
 	return e.config.Blueprint
 }
 
+// VerifyDestroy checks the resources which were removed by the previous
+// call to Destroy to ensure the underlying objects have been removed.
+// The ids of any resources which still exist are returned e.g. container.consul
+func (e *EngineImpl) VerifyDestroy() ([]string, error) {
+	survived := []string{}
+
+	if e.config == nil {
+		return survived, nil
+	}
+
+	for _, r := range e.config.Resources {
+		if r.Info().Status != config.Destroyed {
+			continue
+		}
+
+		p := e.getProvider(r, e.clients)
+		if p == nil {
+			continue
+		}
+
+		ids, err := p.Lookup()
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to lookup resource %s.%s: %w", r.Info().Type, r.Info().Name, err)
+		}
+
+		if len(ids) > 0 {
+			e.log.Debug("Resource still exists after destroy", "ref", r.Info().Name, "type", r.Info().Type, "ids", ids)
+			survived = append(survived, fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name))
+		}
+	}
+
+	return survived, nil
+}
+
+// RebuildState parses the configuration at path and creates a new state file
+// by matching the resources to live Docker objects. This can be used to recover
+// an environment where the state file has been lost or corrupted.
+// Resources which can not be matched are logged and marked for creation so that
+// they are created on the next Apply.
+func (e *EngineImpl) RebuildState(path string, variables map[string]string, variablesFile string) error {
+	e.log.Info("Rebuilding state from configuration", "path", path)
+
+	err := config.LockStateFile(e.stateLockPath())
+	if err != nil {
+		return err
+	}
+	defer config.UnlockStateFile(e.stateLockPath())
+
+	cc, err := parseConfig(path, variables, variablesFile)
+	if err != nil {
+		return err
+	}
+
+	e.config = cc
+
+	matched := map[config.Resource]bool{}
+	unmatched := []string{}
+
+	for _, r := range cc.Resources {
+		if r.Info().Status == config.Disabled {
+			continue
+		}
+
+		if e.matchResource(r, matched) {
+			r.Info().Status = config.Applied
+			continue
+		}
+
+		r.Info().Status = config.PendingCreation
+		unmatched = append(unmatched, fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name))
+	}
+
+	for _, u := range unmatched {
+		e.log.Warn("Unable to match resource to a running object, resource will be created on next apply", "ref", u)
+	}
+
+	err = os.MkdirAll(e.stateDir(), os.ModePerm)
+	if err != nil {
+		return xerrors.Errorf("Unable to create state folder: %w", err)
+	}
+
+	return cc.ToJSON(e.statePath())
+}
+
+// VariableUsage parses the configuration at path and returns the ids of the
+// resources which reference the variable, the variable name can be given with
+// or without the var. prefix
+func (e *EngineImpl) VariableUsage(path, variableName string) ([]string, error) {
+	cc, err := parseConfig(path, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimPrefix(variableName, "var.")
+	ids := []string{}
+
+	for _, r := range cc.Resources {
+		for _, v := range r.Info().Variables {
+			if v == name {
+				ids = append(ids, fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name))
+				break
+			}
+		}
+	}
+
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// matchResource returns true when the resource can be matched to a live object.
+// Resources which are not backed by a Docker object can not be looked up, these are
+// assumed to exist when all of the resources they depend on exist.
+func (e *EngineImpl) matchResource(r config.Resource, matched map[config.Resource]bool) bool {
+	if m, ok := matched[r]; ok {
+		return m
+	}
+
+	// guard against dependency cycles
+	matched[r] = false
+
+	m := false
+	switch r.Info().Type {
+	case config.TypeContainer, config.TypeSidecar, config.TypeK8sCluster, config.TypeNomadCluster, config.TypeNetwork:
+		p := e.getProvider(r, e.clients)
+		if p != nil {
+			ids, err := p.Lookup()
+			m = err == nil && len(ids) > 0
+		}
+	default:
+		m = true
+		for _, d := range r.Info().DependsOn {
+			dr, err := r.FindDependentResource(d)
+			if err != nil || !e.matchResource(dr, matched) {
+				m = false
+				break
+			}
+		}
+	}
+
+	matched[r] = m
+
+	return m
+}
+
+// SnapshotData archives the data in the volumes for the given container resource
+// each volume is written to a separate file in the folder dest
+func (e *EngineImpl) SnapshotData(resourceID, dest string) error {
+	vols, err := e.findDataVolumes(resourceID)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dest, os.ModePerm)
+	if err != nil {
+		return xerrors.Errorf("Unable to create snapshot folder: %w", err)
+	}
+
+	for _, v := range vols {
+		e.log.Info("Creating snapshot", "ref", resourceID, "volume", v.Destination)
+
+		err := e.clients.ContainerTasks.SnapshotVolume(context.Background(), v.Source, snapshotFile(dest, v))
+		if err != nil {
+			return xerrors.Errorf("Unable to snapshot volume %s: %w", v.Destination, err)
+		}
+	}
+
+	return nil
+}
+
+// RestoreData restores the snapshots created by SnapshotData from the folder src
+func (e *EngineImpl) RestoreData(resourceID, src string) error {
+	vols, err := e.findDataVolumes(resourceID)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range vols {
+		sf := snapshotFile(src, v)
+		if _, err := os.Stat(sf); err != nil {
+			e.log.Debug("No snapshot for volume, skipping", "ref", resourceID, "volume", v.Destination)
+			continue
+		}
+
+		e.log.Info("Restoring snapshot", "ref", resourceID, "volume", v.Destination)
+
+		err := e.clients.ContainerTasks.RestoreVolume(context.Background(), sf, v.Source)
+		if err != nil {
+			return xerrors.Errorf("Unable to restore volume %s: %w", v.Destination, err)
+		}
+	}
+
+	return nil
+}
+
+// findDataVolumes returns the bind and volume mounts for the container in the state
+func (e *EngineImpl) findDataVolumes(resourceID string) ([]config.Volume, error) {
+	_, err := e.readConfig("", nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := e.config.FindResource(resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, ok := r.(*config.Container)
+	if !ok {
+		return nil, fmt.Errorf("Resource %s is not a container, only container data can be snapshot", resourceID)
+	}
+
+	vols := []config.Volume{}
+	for _, v := range c.Volumes {
+		// tmpfs volumes do not persist data
+		if v.Type == "tmpfs" {
+			continue
+		}
+
+		vols = append(vols, v)
+	}
+
+	return vols, nil
+}
+
+// snapshotFile returns the file name for a volume snapshot
+func snapshotFile(folder string, v config.Volume) string {
+	name := strings.ReplaceAll(strings.Trim(v.Destination, "/"), "/", "_")
+	return filepath.Join(folder, fmt.Sprintf("%s.tar.gz", name))
+}
+
 func (e *EngineImpl) readConfig(path string, variables map[string]string, variablesFile string) (*dag.AcyclicGraph, error) {
+	paths := []string{}
+	if path != "" {
+		paths = append(paths, path)
+	}
+
+	return e.readConfigs(paths, variables, variablesFile)
+}
+
+// parseConfig parses the file or folder at path into a new config without
+// reading or modifying the current state
+func parseConfig(path string, variables map[string]string, variablesFile string) (*config.Config, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if variablesFile != "" {
+		variablesFile, err = filepath.Abs(variablesFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cc := config.New()
+	cc.AddResource(config.NewImageCache("docker-cache"))
+
+	if utils.IsHCLFile(path) || utils.IsYAMLFile(path) {
+		err = config.ParseSingleFile(path, cc, variables, variablesFile)
+	} else {
+		err = config.ParseFolder(path, cc, false, "", false, []string{}, variables, variablesFile)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = config.ParseReferences(cc)
+	if err != nil {
+		return nil, err
+	}
+
+	return cc, nil
+}
+
+// readConfigs parses the configuration at paths and merges it with the current state.
+// When multiple paths are specified later paths override resources with the same
+// id in earlier paths.
+func (e *EngineImpl) readConfigs(paths []string, variables map[string]string, variablesFile string) (*dag.AcyclicGraph, error) {
+	// check all required variables have values before parsing so that
+	// every missing variable is reported together
+	missing := []string{}
+	for _, path := range paths {
+		err := config.CheckRequiredVariables(path, variables, variablesFile)
+		if mv, ok := err.(config.MissingVariablesError); ok {
+			missing = append(missing, mv.Names...)
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, config.MissingVariablesError{Names: missing}
+	}
+
 	// create the new config
 	cc := config.New()
 
 	// load the existing state
 	sc := config.New()
-	if _, err := os.Stat(utils.StatePath()); err == nil {
-		err := sc.FromJSON(utils.StatePath())
+	if _, err := os.Stat(e.statePath()); err == nil {
+		err := sc.FromJSON(e.statePath())
 		if err != nil {
 			return nil, fmt.Errorf("Error parsing state: %s", err)
 		}
@@ -399,21 +1375,39 @@ func (e *EngineImpl) readConfig(path string, variables map[string]string, variab
 	// add the cache to the new config so we can parse networks
 	cc.AddResource(cache)
 
-	if path != "" {
-		if utils.IsHCLFile(path) {
-			err := config.ParseSingleFile(path, cc, variables, variablesFile)
+	for i, path := range paths {
+		// the first path is parsed into the config, any subsequent paths are
+		// overlays which replace or add resources to the config
+		pc := cc
+		if i > 0 {
+			pc = config.New()
+			pc.AddResource(cache)
+		}
+
+		if utils.IsHCLFile(path) || utils.IsYAMLFile(path) {
+			err := config.ParseSingleFile(path, pc, variables, variablesFile)
 			if err != nil {
 				return nil, err
 			}
 		} else {
-			err := config.ParseFolder(path, cc, false, "", false, []string{}, variables, variablesFile)
+			err := config.ParseFolder(path, pc, false, "", false, []string{}, variables, variablesFile)
 			if err != nil {
 				return nil, err
 			}
 		}
 
-		// if we are loading from files create the deps
-		config.ParseReferences(cc)
+		if i > 0 {
+			e.log.Debug("Applying overlay", "path", path)
+			overlayConfig(cc, pc)
+		}
+	}
+
+	// if we are loading from files create the deps
+	if len(paths) > 0 {
+		err := config.ParseReferences(cc)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// merge the state and items to be created or deleted
@@ -432,6 +1426,11 @@ func (e *EngineImpl) readConfig(path string, variables map[string]string, variab
 
 	err = d.Validate()
 	if err != nil {
+		// return the resources which form the cycle rather than the graph error
+		if cycle := findCycle(e.config); cycle != nil {
+			return nil, CycleError{Cycle: cycle}
+		}
+
 		return nil, xerrors.Errorf("Unable to validate dependency graph: %w", err)
 	}
 
@@ -441,6 +1440,8 @@ func (e *EngineImpl) readConfig(path string, variables map[string]string, variab
 // generateProviderImpl returns providers grouped together in order of execution
 func generateProviderImpl(c config.Resource, cc *Clients) providers.Provider {
 	switch c.Info().Type {
+	case config.TypeAssert:
+		return providers.NewAssert(c.(*config.Assert), cc.Logger)
 	case config.TypeContainer:
 		return providers.NewContainer(c.(*config.Container), cc.ContainerTasks, cc.HTTP, cc.Logger)
 	case config.TypeContainerIngress:
@@ -453,6 +1454,8 @@ func generateProviderImpl(c config.Resource, cc *Clients) providers.Provider {
 		return providers.NewRemoteExec(c.(*config.ExecRemote), cc.ContainerTasks, cc.Logger)
 	case config.TypeExecLocal:
 		return providers.NewExecLocal(c.(*config.ExecLocal), cc.Command, cc.Logger)
+	case config.TypeExternal:
+		return providers.NewExternal(c.(*config.External), cc.HTTP, cc.Logger)
 	case config.TypeHelm:
 		return providers.NewHelm(c.(*config.Helm), cc.Kubernetes, cc.Helm, cc.Getter, cc.Logger)
 	case config.TypeIngress:
@@ -465,6 +1468,8 @@ func generateProviderImpl(c config.Resource, cc *Clients) providers.Provider {
 		return providers.NewK8sConfig(c.(*config.K8sConfig), cc.Kubernetes, cc.Logger)
 	case config.TypeK8sIngress:
 		return providers.NewK8sIngress(c.(*config.K8sIngress), cc.ContainerTasks, cc.Logger)
+	case config.TypeK8sWait:
+		return providers.NewK8sWait(c.(*config.K8sWait), cc.Kubernetes, cc.Logger)
 	case config.TypeNomadCluster:
 		return providers.NewNomadCluster(c.(*config.NomadCluster), cc.ContainerTasks, cc.Nomad, cc.Logger)
 	case config.TypeNomadIngress:
@@ -472,11 +1477,21 @@ func generateProviderImpl(c config.Resource, cc *Clients) providers.Provider {
 	case config.TypeNomadJob:
 		return providers.NewNomadJob(c.(*config.NomadJob), cc.Nomad, cc.Logger)
 	case config.TypeNetwork:
-		return providers.NewNetwork(c.(*config.Network), cc.Docker, cc.Logger)
+		return providers.NewNetwork(c.(*config.Network), cc.Docker, cc.ContainerTasks.Workspace(), cc.Logger)
 	case config.TypeOutput:
 		return providers.NewNull(c.Info(), cc.Logger)
+	case config.TypeRegistry:
+		return providers.NewNull(c.Info(), cc.Logger)
+	case config.TypeSecret:
+		return providers.NewSecret(c.(*config.Secret), cc.Logger)
+	case config.TypeDockerConfig:
+		return providers.NewDockerConfig(c.(*config.DockerConfig), cc.Logger)
+	case config.TypeSSHKey:
+		return providers.NewSSHKey(c.(*config.SSHKey), cc.ContainerTasks, cc.Logger)
 	case config.TypeTemplate:
 		return providers.NewTemplate(c.(*config.Template), cc.Logger)
+	case config.TypeCopy:
+		return providers.NewCopy(c.(*config.Copy), cc.ContainerTasks, cc.Logger)
 	}
 
 	return nil
@@ -491,3 +1506,25 @@ func appendResources(cr *[]config.Resource, r config.Resource) {
 
 	*cr = append(*cr, r)
 }
+
+// overlayConfig adds the resources in the overlay to the config c, resources
+// in the overlay replace resources in c which have the same id
+func overlayConfig(c, overlay *config.Config) {
+	if overlay.Blueprint != nil {
+		c.Blueprint = overlay.Blueprint
+	}
+
+	for _, r := range overlay.Resources {
+		existing, err := c.FindResource(fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name))
+		if err == nil {
+			// the image cache is shared between all configs
+			if existing == r {
+				continue
+			}
+
+			c.RemoveResource(existing)
+		}
+
+		c.AddResource(r)
+	}
+}
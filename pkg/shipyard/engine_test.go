@@ -7,15 +7,18 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/hashicorp/go-hclog"
+	clientMocks "github.com/shipyard-run/shipyard/pkg/clients/mocks"
 	"github.com/shipyard-run/shipyard/pkg/config"
 	"github.com/shipyard-run/shipyard/pkg/providers"
 	"github.com/shipyard-run/shipyard/pkg/providers/mocks"
 	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/mock"
 
 	assert "github.com/stretchr/testify/require"
 )
@@ -86,6 +89,13 @@ func generateProviderMock(mp *[]*mocks.MockProvider, returnVals map[string]error
 		m.On("Create").Return(val)
 		m.On("Destroy").Return(val)
 
+		// resources with an error can not be found
+		if val != nil {
+			m.On("Lookup").Return([]string{}, nil)
+		} else {
+			m.On("Lookup").Return([]string{"123"}, nil)
+		}
+
 		*mp = append(*mp, m)
 		return m
 	}
@@ -130,6 +140,29 @@ func TestApplyWithSingleFile(t *testing.T) {
 	assert.Contains(t, []string{"consul", "docker-cache"}, (*mp)[2].Config().Info().Name)
 }
 
+func TestParseConfigReturnsAllMissingRequiredVariables(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	dir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "vars.hcl"), []byte(`
+variable "region" {}
+variable "token" {}
+`), os.ModePerm)
+	assert.NoError(t, err)
+
+	err = e.ParseConfigWithVariables(dir, nil, "")
+	assert.Error(t, err)
+
+	mv, ok := err.(config.MissingVariablesError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"region", "token"}, mv.Names)
+
+	req, err := e.RequiredVariables(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"region", "token"}, req)
+}
+
 func TestApplyAddsImageCache(t *testing.T) {
 	e, _, cleanup := setupTests(nil)
 	defer cleanup()
@@ -141,6 +174,50 @@ func TestApplyAddsImageCache(t *testing.T) {
 	assert.Equal(t, 1, dc)
 }
 
+func TestApplyWithRefreshImagesForcesPullAndClearsCache(t *testing.T) {
+	e, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	mt := &clientMocks.MockContainerTasks{}
+	mt.On("SetForcePull", true).Return()
+	e.(*EngineImpl).clients.ContainerTasks = mt
+
+	_, err := e.ApplyWithOptions([]string{"../../examples/single_file/container.hcl"}, ApplyOptions{RefreshImages: true})
+	assert.NoError(t, err)
+
+	mt.AssertCalled(t, "SetForcePull", true)
+
+	for _, p := range *mp {
+		if ic, ok := p.Config().(*config.ImageCache); ok {
+			assert.True(t, ic.Refresh)
+		}
+	}
+}
+
+func TestApplyReturnsErrorWhenStateLocked(t *testing.T) {
+	e, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	err := config.LockState()
+	assert.NoError(t, err)
+	defer config.UnlockState()
+
+	_, err = e.Apply("../../examples/single_file/container.hcl")
+	assert.IsType(t, config.StateLockedError{}, err)
+
+	testAssertMethodCalled(t, mp, "Create", 0)
+}
+
+func TestApplyRemovesStateLock(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	_, err := e.Apply("../../examples/single_file/container.hcl")
+	assert.NoError(t, err)
+
+	assert.NoFileExists(t, utils.StateLockPath())
+}
+
 func TestApplyWithSingleFileAndVariables(t *testing.T) {
 	e, mp, cleanup := setupTests(nil)
 	defer cleanup()
@@ -206,6 +283,85 @@ func TestApplyNotCallsProviderDestroyAndCreateForResourcesDisabled(t *testing.T)
 	assert.Equal(t, config.Disabled, r.Info().Status)
 }
 
+func TestApplySavesStateAfterEachResource(t *testing.T) {
+	e, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	var networkStatus config.Status
+
+	// when the cluster is created the network it depends on should already
+	// be recorded in the state file
+	genMock := generateProviderMock(mp, nil)
+	e.(*EngineImpl).getProvider = func(c config.Resource, cc *Clients) providers.Provider {
+		if c.Info().Type != config.TypeK8sCluster {
+			return genMock(c, cc)
+		}
+
+		m := mocks.New(c)
+		m.On("Create").Run(func(args mock.Arguments) {
+			sc := config.New()
+			sc.FromJSON(utils.StatePath())
+
+			n, err := sc.FindResource("network.cloud")
+			if err == nil {
+				networkStatus = n.Info().Status
+			}
+		}).Return(nil)
+
+		return m
+	}
+
+	_, err := e.Apply("../../examples/single_k3s_cluster")
+	assert.NoError(t, err)
+
+	assert.Equal(t, config.Applied, networkStatus)
+}
+
+func TestUpdateStatusDoesNotSaveResourcesWhichAreBeingApplied(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	err := e.ParseConfig("../../examples/single_k3s_cluster")
+	assert.NoError(t, err)
+
+	c := e.(*EngineImpl).config
+	n, err := c.FindResource("network.cloud")
+	assert.NoError(t, err)
+	k, err := c.FindResource("k8s_cluster.k3s")
+	assert.NoError(t, err)
+
+	err = e.(*EngineImpl).startSnapshots()
+	assert.NoError(t, err)
+
+	// the cluster is still being created when the network has been created
+	k.(*config.K8sCluster).ResolvedVersion = "v1.18.16"
+	e.(*EngineImpl).updateStatus(n, config.Applied)
+
+	sc := config.New()
+	err = sc.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	sk, err := sc.FindResource("k8s_cluster.k3s")
+	assert.NoError(t, err)
+	assert.Empty(t, sk.(*config.K8sCluster).ResolvedVersion)
+
+	sn, err := sc.FindResource("network.cloud")
+	assert.NoError(t, err)
+	assert.Equal(t, config.Applied, sn.Info().Status)
+
+	// once created the values set by the provider are saved
+	e.(*EngineImpl).updateStatus(k, config.Applied)
+	e.(*EngineImpl).stopSnapshots()
+
+	sc = config.New()
+	err = sc.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	sk, err = sc.FindResource("k8s_cluster.k3s")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.18.16", sk.(*config.K8sCluster).ResolvedVersion)
+}
+
 func TestApplyCallsProviderDestroyAndCreateForResourcesFailed(t *testing.T) {
 	e, mp, cleanup := setupTestsWithState(nil, failedState)
 	defer cleanup()
@@ -230,6 +386,32 @@ func TestApplyCallsProviderDestroyForResourcesPendingModification(t *testing.T)
 	testAssertMethodCalled(t, mp, "Create", 2) // ImageCache is always created
 }
 
+func TestApplyRecreatesSidecarWhenTargetPendingModification(t *testing.T) {
+	e, mp, cleanup := setupTestsWithState(nil, sidecarState)
+	defer cleanup()
+
+	_, err := e.Apply("")
+	assert.NoError(t, err)
+
+	// container and sidecar should be destroyed and re-created
+	testAssertMethodCalled(t, mp, "Destroy", 2)
+	testAssertMethodCalled(t, mp, "Create", 3) // ImageCache is always created
+
+	r, err := e.(*EngineImpl).config.FindResource("sidecar.envoy")
+	assert.NoError(t, err)
+	assert.Equal(t, config.Applied, r.Info().Status)
+}
+
+func TestDestroyDestroysSidecarWhenTargetDestroyed(t *testing.T) {
+	e, mp, cleanup := setupTestsWithState(nil, strings.Replace(sidecarState, "pending_modification", "pending_update", 1))
+	defer cleanup()
+
+	err := e.Destroy("", false)
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 2)
+}
+
 func TestApplyReturnsErrorWhenProviderDestroyForResourcesPendingorFailed(t *testing.T) {
 	e, mp, cleanup := setupTestsWithState(map[string]error{"dc1": fmt.Errorf("boom")}, failedState)
 	defer cleanup()
@@ -264,6 +446,28 @@ func TestApplyCallsProviderCreateErrorStopsExecution(t *testing.T) {
 	testAssertMethodCalled(t, mp, "Create", 1)
 }
 
+func TestApplyCreateErrorIncludesResourceLocation(t *testing.T) {
+	e, _, cleanup := setupTests(map[string]error{"cloud": fmt.Errorf("boom")})
+	defer cleanup()
+
+	_, err := e.Apply("../../examples/single_k3s_cluster")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unable to create resource network.cloud (defined in")
+	assert.Contains(t, err.Error(), "network.hcl:1): boom")
+}
+
+func TestApplyWithConcurrencyCreatesAllResources(t *testing.T) {
+	e, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	WithConcurrency(1)(e.(*EngineImpl))
+
+	_, err := e.Apply("../../examples/single_k3s_cluster")
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Create", len(*mp))
+}
+
 func TestApplySetsStatusForEachResource(t *testing.T) {
 	e, mp, cleanup := setupTestsWithState(nil, mergedState)
 	defer cleanup()
@@ -356,6 +560,67 @@ func TestDestroyFailSetsStatus(t *testing.T) {
 	assert.Equal(t, config.Failed, (*mp)[7].Config().Info().Status)
 }
 
+func TestDestroyWithContinueOnErrorDestroysRemainingResources(t *testing.T) {
+	e, mp, cleanup := setupTests(map[string]error{"k3s": fmt.Errorf("boom")})
+	defer cleanup()
+
+	err := e.DestroyWithOptions("../../examples/single_k3s_cluster", DestroyOptions{AllResources: true, ContinueOnError: true})
+	assert.Error(t, err)
+
+	de, ok := err.(*DestroyError)
+	assert.True(t, ok)
+	assert.Len(t, de.Errors, 1)
+	assert.Contains(t, de.Error(), "k8s_cluster.k3s")
+
+	// the resources the cluster depends on are still in use and are not destroyed
+	testAssertMethodCalled(t, mp, "Destroy", 6)
+
+	// the failed resource and its dependencies should remain in the state
+	c := config.New()
+	err = c.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+	assert.Len(t, c.Resources, 3)
+
+	_, err = c.FindResource("k8s_cluster.k3s")
+	assert.NoError(t, err)
+	_, err = c.FindResource("network.cloud")
+	assert.NoError(t, err)
+	_, err = c.FindResource("image_cache.docker-cache")
+	assert.NoError(t, err)
+}
+
+func TestFailInitDependentsMarksDependentsFailed(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	c := config.New()
+
+	ic := config.NewContainer("migrate")
+	ic.Init = true
+	ic.Status = config.Failed
+	c.AddResource(ic)
+
+	app := config.NewContainer("app")
+	app.DependsOn = []string{"container.migrate"}
+	c.AddResource(app)
+
+	web := config.NewContainer("web")
+	web.DependsOn = []string{"container.app"}
+	c.AddResource(web)
+
+	existing := config.NewContainer("existing")
+	existing.DependsOn = []string{"container.migrate"}
+	existing.Status = config.PendingUpdate
+	c.AddResource(existing)
+
+	e.(*EngineImpl).config = c
+	e.(*EngineImpl).failInitDependents()
+
+	assert.Equal(t, config.Failed, app.Status)
+	assert.Equal(t, config.Failed, web.Status)
+	assert.Equal(t, config.PendingUpdate, existing.Status)
+}
+
 func TestDestroyCallsProviderDestroyInCorrectOrder(t *testing.T) {
 	e, mp, cleanup := setupTests(nil)
 	defer cleanup()
@@ -393,6 +658,248 @@ func testAssertMethodCalled(t *testing.T, p *[]*mocks.MockProvider, method strin
 	}
 }
 
+func TestSnapshotDataCallsSnapshotForEachVolume(t *testing.T) {
+	e, _, cleanup := setupTestsWithState(nil, volumeState)
+	defer cleanup()
+
+	mc := &clientMocks.MockContainerTasks{}
+	mc.On("SnapshotVolume", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	e.(*EngineImpl).clients.ContainerTasks = mc
+
+	dest := t.TempDir()
+	err := e.SnapshotData("container.consul", dest)
+	assert.NoError(t, err)
+
+	mc.AssertNumberOfCalls(t, "SnapshotVolume", 2)
+	mc.AssertCalled(t, "SnapshotVolume", mock.Anything, "/tmp/config", filepath.Join(dest, "config.tar.gz"))
+	mc.AssertCalled(t, "SnapshotVolume", mock.Anything, "consul_data", filepath.Join(dest, "consul_data.tar.gz"))
+}
+
+func TestSnapshotDataReturnsErrorWhenNotContainer(t *testing.T) {
+	e, _, cleanup := setupTestsWithState(nil, volumeState)
+	defer cleanup()
+
+	err := e.SnapshotData("network.dc1", t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestRestoreDataSkipsMissingSnapshots(t *testing.T) {
+	e, _, cleanup := setupTestsWithState(nil, volumeState)
+	defer cleanup()
+
+	mc := &clientMocks.MockContainerTasks{}
+	mc.On("RestoreVolume", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	e.(*EngineImpl).clients.ContainerTasks = mc
+
+	src := t.TempDir()
+	ioutil.WriteFile(filepath.Join(src, "config.tar.gz"), []byte(""), os.ModePerm)
+
+	err := e.RestoreData("container.consul", src)
+	assert.NoError(t, err)
+
+	mc.AssertNumberOfCalls(t, "RestoreVolume", 1)
+	mc.AssertCalled(t, "RestoreVolume", mock.Anything, filepath.Join(src, "config.tar.gz"), "/tmp/config")
+}
+
+func TestRebuildStateWritesStateForLiveResources(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	err := e.RebuildState("../../examples/single_file/container.hcl", nil, "")
+	assert.NoError(t, err)
+
+	c := config.New()
+	err = c.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	r, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, config.Applied, r.Info().Status)
+
+	r, err = c.FindResource("network.onprem")
+	assert.NoError(t, err)
+	assert.Equal(t, config.Applied, r.Info().Status)
+}
+
+func TestRebuildStateMarksUnmatchedResourcesForCreation(t *testing.T) {
+	e, _, cleanup := setupTests(map[string]error{"onprem": fmt.Errorf("boom")})
+	defer cleanup()
+
+	err := e.RebuildState("../../examples/single_file/container.hcl", nil, "")
+	assert.NoError(t, err)
+
+	c := config.New()
+	err = c.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	r, err := c.FindResource("network.onprem")
+	assert.NoError(t, err)
+	assert.Equal(t, config.PendingCreation, r.Info().Status)
+
+	// the image cache depends on the network so can not be matched
+	r, err = c.FindResource("image_cache.docker-cache")
+	assert.NoError(t, err)
+	assert.Equal(t, config.PendingCreation, r.Info().Status)
+
+	r, err = c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, config.Applied, r.Info().Status)
+}
+
+func TestVerifyDestroyReturnsResourcesWhichStillExist(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	err := e.Destroy("../../examples/single_k3s_cluster", true)
+	assert.NoError(t, err)
+
+	// the mock provider lookup returns ids for all resources
+	survived, err := e.VerifyDestroy()
+	assert.NoError(t, err)
+	assert.Contains(t, survived, "k8s_cluster.k3s")
+}
+
+func TestVerifyDestroyReturnsEmptyWhenResourcesRemoved(t *testing.T) {
+	e, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	err := e.Destroy("../../examples/single_k3s_cluster", true)
+	assert.NoError(t, err)
+
+	e.(*EngineImpl).getProvider = func(c config.Resource, cc *Clients) providers.Provider {
+		m := mocks.New(c)
+		m.On("Lookup").Return([]string{}, nil)
+		*mp = append(*mp, m)
+
+		return m
+	}
+
+	survived, err := e.VerifyDestroy()
+	assert.NoError(t, err)
+	assert.Len(t, survived, 0)
+}
+
+func TestApplyWithOverlaysReplacesAndAddsResources(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	base := t.TempDir()
+	ioutil.WriteFile(filepath.Join(base, "base.hcl"), []byte(overlayBase), os.ModePerm)
+
+	overlay := t.TempDir()
+	ioutil.WriteFile(filepath.Join(overlay, "overlay.hcl"), []byte(overlayDev), os.ModePerm)
+
+	_, err := e.ApplyWithOverlays([]string{base, overlay}, nil, "")
+	assert.NoError(t, err)
+
+	c := e.(*EngineImpl).config
+
+	r, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.9.0", r.(*config.Container).Image.Name)
+
+	_, err = c.FindResource("container.vault")
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("network.onprem")
+	assert.NoError(t, err)
+}
+
+func TestApplyRetriesCreateWhenRetrySet(t *testing.T) {
+	e, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	e.(*EngineImpl).getProvider = func(c config.Resource, cc *Clients) providers.Provider {
+		lock.Lock()
+		defer lock.Unlock()
+
+		m := mocks.New(c)
+
+		if c.Info().Name == "consul" {
+			c.Info().Retry = &config.Retry{Attempts: 3, Backoff: "1ms"}
+			m.On("Create").Once().Return(fmt.Errorf("connection refused"))
+		}
+
+		m.On("Create").Return(nil)
+		m.On("Destroy").Return(nil)
+
+		*mp = append(*mp, m)
+		return m
+	}
+
+	_, err := e.Apply("../../examples/single_file/container.hcl")
+	assert.NoError(t, err)
+
+	r, err := e.(*EngineImpl).config.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, r.Info().Attempts)
+	assert.Equal(t, config.Applied, r.Info().Status)
+}
+
+func TestApplyReturnsErrorWhenRetryAttemptsExceeded(t *testing.T) {
+	e, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	e.(*EngineImpl).getProvider = func(c config.Resource, cc *Clients) providers.Provider {
+		lock.Lock()
+		defer lock.Unlock()
+
+		m := mocks.New(c)
+
+		if c.Info().Name == "consul" {
+			c.Info().Retry = &config.Retry{Attempts: 2, Backoff: "1ms"}
+			m.On("Create").Return(fmt.Errorf("connection refused"))
+		} else {
+			m.On("Create").Return(nil)
+		}
+
+		m.On("Destroy").Return(nil)
+
+		*mp = append(*mp, m)
+		return m
+	}
+
+	_, err := e.Apply("../../examples/single_file/container.hcl")
+	assert.Error(t, err)
+
+	r, err := e.(*EngineImpl).config.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, r.Info().Attempts)
+	assert.Equal(t, config.Failed, r.Info().Status)
+}
+
+func TestApplyDoesNotRetryErrorsWhichAreNotRetryable(t *testing.T) {
+	e, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	e.(*EngineImpl).getProvider = func(c config.Resource, cc *Clients) providers.Provider {
+		lock.Lock()
+		defer lock.Unlock()
+
+		m := mocks.New(c)
+
+		if c.Info().Name == "consul" {
+			c.Info().Retry = &config.Retry{Attempts: 3, Backoff: "1ms", MaxWait: "2ms"}
+			m.On("Create").Return(fmt.Errorf("invalid reference format"))
+		} else {
+			m.On("Create").Return(nil)
+		}
+
+		m.On("Destroy").Return(nil)
+
+		*mp = append(*mp, m)
+		return m
+	}
+
+	_, err := e.Apply("../../examples/single_file/container.hcl")
+	assert.Error(t, err)
+
+	r, err := e.(*EngineImpl).config.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, r.Info().Attempts)
+	assert.Equal(t, config.Failed, r.Info().Status)
+}
+
 var failedState = `
 {
   "blueprint": null,
@@ -421,6 +928,28 @@ var modificationState = `
 }
 `
 
+var sidecarState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "consul",
+      "status": "pending_modification",
+      "type": "container",
+      "image": {"name": "consul:1.8.1"}
+	},
+	{
+      "name": "envoy",
+      "status": "applied",
+      "type": "sidecar",
+      "target": "container.consul",
+      "depends_on": ["container.consul"],
+      "image": {"name": "envoyproxy/envoy:v1.14.1"}
+	}
+  ]
+}
+`
+
 var mergedState = `
 {
   "blueprint": null,
@@ -453,3 +982,86 @@ var disabledState = `
   ]
 }
 `
+
+var volumeState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "dc1",
+      "status": "created",
+      "subnet": "10.15.0.0/16",
+      "type": "network"
+	},
+	{
+      "name": "consul",
+      "status": "created",
+      "type": "container",
+      "image": {"name": "consul:1.8.1"},
+      "volumes": [
+        {"source": "/tmp/config", "destination": "/config"},
+        {"source": "consul_data", "destination": "/consul/data", "type": "volume"},
+        {"source": "", "destination": "/scratch", "type": "tmpfs"}
+      ]
+	}
+  ]
+}
+`
+
+var overlayBase = `
+network "onprem" {
+  subnet = "10.6.0.0/16"
+}
+
+container "consul" {
+  image {
+    name = "consul:1.8.1"
+  }
+
+  network {
+    name = "network.onprem"
+  }
+}
+`
+
+var overlayDev = `
+container "consul" {
+  image {
+    name = "consul:1.9.0"
+  }
+
+  network {
+    name = "network.onprem"
+  }
+}
+
+container "vault" {
+  image {
+    name = "vault:1.6.0"
+  }
+
+  network {
+    name = "network.onprem"
+  }
+}
+`
+
+func TestVariableUsageReturnsResourcesReferencingVariable(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	ids, err := e.VariableUsage("../../examples/variables/simple", "var.network")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"container.consul"}, ids)
+}
+
+func TestVariableUsageReturnsEmptyWhenVariableNotUsed(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	ids, err := e.VariableUsage("../../examples/variables/simple", "image_tag")
+	assert.NoError(t, err)
+
+	assert.Len(t, ids, 0)
+}
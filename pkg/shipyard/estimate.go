@@ -0,0 +1,233 @@
+package shipyard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/dag"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// maxHistory is the number of durations recorded for each resource
+const maxHistory = 5
+
+// defaultDurations are used to estimate the time to create a resource when
+// there is no history for the resource or its type
+var defaultDurations = map[config.ResourceType]time.Duration{
+	config.TypeK8sCluster:       90 * time.Second,
+	config.TypeNomadCluster:     60 * time.Second,
+	config.TypeHelm:             60 * time.Second,
+	config.TypeK8sConfig:        15 * time.Second,
+	config.TypeK8sWait:          30 * time.Second,
+	config.TypeNomadJob:         15 * time.Second,
+	config.TypeContainer:        10 * time.Second,
+	config.TypeSidecar:          10 * time.Second,
+	config.TypeImageCache:       10 * time.Second,
+	config.TypeIngress:          5 * time.Second,
+	config.TypeContainerIngress: 5 * time.Second,
+	config.TypeK8sIngress:       5 * time.Second,
+	config.TypeNomadIngress:     5 * time.Second,
+	config.TypeExecLocal:        5 * time.Second,
+	config.TypeExecRemote:       5 * time.Second,
+}
+
+// defaultDuration is used for resource types without a default
+const defaultDuration = 1 * time.Second
+
+// imagePullDuration is added to the estimate for containers when the image
+// has not been pulled by Shipyard before
+const imagePullDuration = 30 * time.Second
+
+// applyHistory records how long resources took to create
+type applyHistory struct {
+	// Resources holds the most recent durations keyed by resource id
+	Resources map[string][]time.Duration `json:"resources"`
+	// Types holds the most recent durations keyed by resource type
+	Types map[config.ResourceType][]time.Duration `json:"types"`
+
+	mutex sync.Mutex
+}
+
+// loadApplyHistory reads the history from the file at path, an empty
+// history is returned when the file does not exist or can not be read
+func loadApplyHistory(path string) *applyHistory {
+	h := &applyHistory{
+		Resources: map[string][]time.Duration{},
+		Types:     map[config.ResourceType][]time.Duration{},
+	}
+
+	d, err := ioutil.ReadFile(path)
+	if err != nil {
+		return h
+	}
+
+	json.Unmarshal(d, h)
+
+	if h.Resources == nil {
+		h.Resources = map[string][]time.Duration{}
+	}
+
+	if h.Types == nil {
+		h.Types = map[config.ResourceType][]time.Duration{}
+	}
+
+	return h
+}
+
+// record adds the duration for the resource to the history
+func (h *applyHistory) record(r config.Resource, d time.Duration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	id := fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name)
+	h.Resources[id] = appendDuration(h.Resources[id], d)
+	h.Types[r.Info().Type] = appendDuration(h.Types[r.Info().Type], d)
+}
+
+// save writes the history to the file at path
+func (h *applyHistory) save(path string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	d, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, d, os.ModePerm)
+}
+
+// estimate returns the average recorded duration for the resource, if the
+// resource has no history the average for the type is returned
+func (h *applyHistory) estimate(r config.Resource) (time.Duration, bool) {
+	id := fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name)
+
+	if d, ok := averageDuration(h.Resources[id]); ok {
+		return d, true
+	}
+
+	return averageDuration(h.Types[r.Info().Type])
+}
+
+func appendDuration(ds []time.Duration, d time.Duration) []time.Duration {
+	ds = append(ds, d)
+	if len(ds) > maxHistory {
+		ds = ds[len(ds)-maxHistory:]
+	}
+
+	return ds
+}
+
+func averageDuration(ds []time.Duration) (time.Duration, bool) {
+	if len(ds) == 0 {
+		return 0, false
+	}
+
+	var total time.Duration
+	for _, d := range ds {
+		total += d
+	}
+
+	return total / time.Duration(len(ds)), true
+}
+
+// EstimateApplyDuration parses the configuration at path and estimates how long it
+// would take to create all of the resources. Resources which do not depend on each
+// other are created in parallel so the estimate is the longest path through the
+// dependency graph.
+// The estimate for each resource uses the durations recorded by previous applies,
+// when no history exists a default for the resource type is used.
+func (e *EngineImpl) EstimateApplyDuration(path string, variables map[string]string, variablesFile string) (time.Duration, error) {
+	cc, err := parseConfig(path, variables, variablesFile)
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := cc.DoYaLikeDAGs()
+	if err != nil {
+		return 0, xerrors.Errorf("Unable to create dependency graph: %w", err)
+	}
+
+	h := loadApplyHistory(utils.ApplyHistoryPath())
+
+	pulled := map[string]bool{}
+	if e.clients != nil && e.clients.ImageLog != nil {
+		images, _ := e.clients.ImageLog.Read(clients.ImageTypeDocker)
+		for _, i := range images {
+			pulled[i] = true
+		}
+	}
+
+	finish := map[dag.Vertex]time.Duration{}
+
+	var finishTime func(v dag.Vertex) time.Duration
+	finishTime = func(v dag.Vertex) time.Duration {
+		if f, ok := finish[v]; ok {
+			return f
+		}
+
+		// a resource starts once all of its dependencies have been created
+		var start time.Duration
+		for _, u := range d.UpEdges(v).List() {
+			if f := finishTime(u); f > start {
+				start = f
+			}
+		}
+
+		f := start
+		if r, ok := v.(config.Resource); ok {
+			f += estimateResource(r, h, pulled)
+		}
+
+		finish[v] = f
+
+		return f
+	}
+
+	var total time.Duration
+	for _, v := range d.Vertices() {
+		if f := finishTime(v); f > total {
+			total = f
+		}
+	}
+
+	return total, nil
+}
+
+// estimateResource returns the estimated time to create a resource
+func estimateResource(r config.Resource, h *applyHistory, pulled map[string]bool) time.Duration {
+	if r.Info().Status == config.Disabled {
+		return 0
+	}
+
+	if d, ok := h.estimate(r); ok {
+		return d
+	}
+
+	d, ok := defaultDurations[r.Info().Type]
+	if !ok {
+		d = defaultDuration
+	}
+
+	// images which need to be downloaded add to the time taken
+	var image *config.Image
+	switch c := r.(type) {
+	case *config.Container:
+		image = c.Image
+	case *config.Sidecar:
+		image = &c.Image
+	}
+
+	if image != nil && image.Name != "" && !pulled[image.Name] {
+		d += imagePullDuration
+	}
+
+	return d
+}
@@ -0,0 +1,101 @@
+package shipyard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	clientMocks "github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupEstimate(t *testing.T) (Engine, string, func()) {
+	e, _, cleanup := setupTests(nil)
+
+	dir := t.TempDir()
+	bp := filepath.Join(dir, "blueprint.hcl")
+	err := ioutil.WriteFile(bp, []byte(estimateBlueprint), os.ModePerm)
+	assert.NoError(t, err)
+
+	return e, bp, cleanup
+}
+
+func TestEstimateUsesDefaultsWithoutHistory(t *testing.T) {
+	e, bp, cleanup := setupEstimate(t)
+	defer cleanup()
+
+	d, err := e.EstimateApplyDuration(bp, nil, "")
+	assert.NoError(t, err)
+
+	// network then container, the image has not been pulled
+	assert.Equal(t, defaultDurations[config.TypeContainer]+imagePullDuration+defaultDuration, d)
+}
+
+func TestEstimateDoesNotAddPullTimeForPulledImages(t *testing.T) {
+	e, bp, cleanup := setupEstimate(t)
+	defer cleanup()
+
+	il := &clientMocks.ImageLog{}
+	il.On("Read", mock.Anything).Return([]string{"consul:1.8.1"}, nil)
+	e.(*EngineImpl).clients.ImageLog = il
+
+	d, err := e.EstimateApplyDuration(bp, nil, "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, defaultDurations[config.TypeContainer]+defaultDuration, d)
+}
+
+func TestEstimateUsesHistory(t *testing.T) {
+	e, bp, cleanup := setupEstimate(t)
+	defer cleanup()
+
+	h := loadApplyHistory(utils.ApplyHistoryPath())
+	c := config.NewContainer("consul")
+	h.record(c, 100*time.Second)
+	h.record(c, 200*time.Second)
+
+	n := config.NewNetwork("other")
+	h.record(n, 4*time.Second)
+
+	err := h.save(utils.ApplyHistoryPath())
+	assert.NoError(t, err)
+
+	d, err := e.EstimateApplyDuration(bp, nil, "")
+	assert.NoError(t, err)
+
+	// container uses the average for the resource, network uses the average for the type
+	assert.Equal(t, 154*time.Second, d)
+}
+
+func TestApplyHistoryKeepsMostRecentDurations(t *testing.T) {
+	h := loadApplyHistory(filepath.Join(t.TempDir(), "history.json"))
+	c := config.NewContainer("consul")
+
+	for i := 1; i <= maxHistory+2; i++ {
+		h.record(c, time.Duration(i)*time.Second)
+	}
+
+	assert.Len(t, h.Resources["container.consul"], maxHistory)
+	assert.Equal(t, 3*time.Second, h.Resources["container.consul"][0])
+}
+
+var estimateBlueprint = `
+network "onprem" {
+  subnet = "10.6.0.0/16"
+}
+
+container "consul" {
+  image {
+    name = "consul:1.8.1"
+  }
+
+  network {
+    name = "network.onprem"
+  }
+}
+`
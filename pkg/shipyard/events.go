@@ -0,0 +1,75 @@
+package shipyard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+)
+
+// EventType is the type of lifecycle event emitted by the engine
+type EventType string
+
+// EventResourceStarted is emitted when the engine starts creating a resource
+const EventResourceStarted EventType = "resource_started"
+
+// EventResourceCreated is emitted when a resource has been successfully created
+const EventResourceCreated EventType = "resource_created"
+
+// EventResourceFailed is emitted when a resource could not be created or destroyed
+const EventResourceFailed EventType = "resource_failed"
+
+// EventResourceDestroyed is emitted when a resource has been successfully destroyed
+const EventResourceDestroyed EventType = "resource_destroyed"
+
+// Event describes a change to the status of a resource during Apply or Destroy
+type Event struct {
+	// Type of the event
+	Type EventType `json:"type"`
+	// ID of the resource e.g. container.consul
+	ID string `json:"id"`
+	// ResourceType is the type of the resource e.g. container
+	ResourceType config.ResourceType `json:"resource_type"`
+	// Status is the status of the resource after the event
+	Status config.Status `json:"status,omitempty"`
+	// Error is set for failed events
+	Error error `json:"-"`
+	// Time the event occurred
+	Time time.Time `json:"time"`
+}
+
+// EventSink receives the lifecycle events emitted by the engine.
+// Resources are created concurrently so Emit must be safe to call
+// from multiple goroutines.
+type EventSink interface {
+	Emit(Event)
+}
+
+// noopSink is the default EventSink which discards all events
+type noopSink struct{}
+
+func (n noopSink) Emit(Event) {}
+
+// WithEventSink sets the sink which receives the lifecycle events
+// emitted during Apply and Destroy
+func WithEventSink(s EventSink) Option {
+	return func(e *EngineImpl) {
+		e.events = s
+	}
+}
+
+// emit sends an event for the given resource to the engine's sink
+func (e *EngineImpl) emit(t EventType, r config.Resource, err error) {
+	if e.events == nil {
+		return
+	}
+
+	e.events.Emit(Event{
+		Type:         t,
+		ID:           fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name),
+		ResourceType: r.Info().Type,
+		Status:       r.Info().Status,
+		Error:        err,
+		Time:         time.Now(),
+	})
+}
@@ -0,0 +1,80 @@
+package shipyard
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+type testSink struct {
+	sync.Mutex
+	events []Event
+}
+
+func (s *testSink) Emit(e Event) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.events = append(s.events, e)
+}
+
+func (s *testSink) ofType(t EventType) []Event {
+	s.Lock()
+	defer s.Unlock()
+
+	ev := []Event{}
+	for _, e := range s.events {
+		if e.Type == t {
+			ev = append(ev, e)
+		}
+	}
+
+	return ev
+}
+
+func setupEventTests(returnVals map[string]error) (Engine, *testSink, func()) {
+	e, _, cleanup := setupTests(returnVals)
+
+	s := &testSink{}
+	WithEventSink(s)(e.(*EngineImpl))
+
+	return e, s, cleanup
+}
+
+func TestApplyEmitsStartedAndCreatedEvents(t *testing.T) {
+	e, s, cleanup := setupEventTests(nil)
+	defer cleanup()
+
+	_, err := e.Apply("../../examples/single_k3s_cluster")
+	assert.NoError(t, err)
+
+	count := e.ResourceCount()
+	assert.Len(t, s.ofType(EventResourceStarted), count)
+	assert.Len(t, s.ofType(EventResourceCreated), count)
+	assert.Len(t, s.ofType(EventResourceFailed), 0)
+}
+
+func TestApplyEmitsFailedEvent(t *testing.T) {
+	e, s, cleanup := setupEventTests(map[string]error{"cloud": fmt.Errorf("boom")})
+	defer cleanup()
+
+	_, err := e.Apply("../../examples/single_k3s_cluster")
+	assert.Error(t, err)
+
+	failed := s.ofType(EventResourceFailed)
+	assert.Len(t, failed, 1)
+	assert.Equal(t, "network.cloud", failed[0].ID)
+	assert.Error(t, failed[0].Error)
+}
+
+func TestDestroyEmitsDestroyedEvents(t *testing.T) {
+	e, s, cleanup := setupEventTests(nil)
+	defer cleanup()
+
+	err := e.Destroy("../../examples/single_k3s_cluster", true)
+	assert.NoError(t, err)
+
+	assert.Len(t, s.ofType(EventResourceDestroyed), 8)
+}
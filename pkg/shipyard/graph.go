@@ -0,0 +1,90 @@
+package shipyard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// Graph parses the configuration at path and returns the dependency graph
+// in Graphviz DOT format. Edges point from a resource to the resources which
+// depend on it, this includes the implicit dependencies added by the parser
+// such as the image cache depending on every network.
+//
+// e.g. shipyard graph ./blueprint | dot -Tpng > graph.png
+func (e *EngineImpl) Graph(path string, variables map[string]string, variablesFile string) (string, error) {
+	cc, err := parseConfig(path, variables, variablesFile)
+	if err != nil {
+		return "", err
+	}
+
+	nodes := []string{}
+	edges := []string{}
+
+	for _, r := range cc.Resources {
+		id := resourceID(r)
+		nodes = append(nodes, fmt.Sprintf("  %q [label=%q];", id, fmt.Sprintf("%s\n%s", r.Info().Type, r.Info().Name)))
+
+		for _, d := range r.Info().DependsOn {
+			deps := []config.Resource{}
+
+			if strings.HasPrefix(d, "module.") {
+				deps, err = cc.FindModuleResources(d)
+			} else {
+				var dr config.Resource
+				dr, err = cc.FindResource(d)
+				deps = append(deps, dr)
+			}
+
+			if err != nil {
+				return "", xerrors.Errorf("Unable to find dependency %s for resource %s: %w", d, id, err)
+			}
+
+			for _, dr := range deps {
+				edges = append(edges, fmt.Sprintf("  %q -> %q;", resourceID(dr), id))
+			}
+		}
+	}
+
+	sort.Strings(nodes)
+	sort.Strings(edges)
+
+	sb := strings.Builder{}
+	sb.WriteString("digraph shipyard {\n")
+	sb.WriteString("  rankdir=\"LR\";\n")
+	sb.WriteString("  node [shape=box];\n")
+
+	for _, n := range nodes {
+		sb.WriteString(n + "\n")
+	}
+
+	for _, e := range dedupe(edges) {
+		sb.WriteString(e + "\n")
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String(), nil
+}
+
+// resourceID returns the id for a resource e.g. container.consul
+func resourceID(r config.Resource) string {
+	return fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name)
+}
+
+// dedupe removes consecutive duplicates from a sorted slice
+func dedupe(s []string) []string {
+	out := []string{}
+	for i, v := range s {
+		if i > 0 && s[i-1] == v {
+			continue
+		}
+
+		out = append(out, v)
+	}
+
+	return out
+}
@@ -0,0 +1,40 @@
+package shipyard
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestGraphReturnsDOTForConfig(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	g, err := e.Graph("../../examples/single_file/container.hcl", nil, "")
+	assert.NoError(t, err)
+
+	assert.Contains(t, g, "digraph shipyard {")
+	assert.Contains(t, g, `"container.consul" [label="container\nconsul"];`)
+	assert.Contains(t, g, `"network.onprem" -> "container.consul";`)
+
+	// the image cache is attached to every network
+	assert.Contains(t, g, `"network.onprem" -> "image_cache.docker-cache";`)
+}
+
+func TestGraphDoesNotChangeConfig(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	_, err := e.Graph("../../examples/single_file/container.hcl", nil, "")
+	assert.NoError(t, err)
+
+	assert.Nil(t, e.(*EngineImpl).config)
+}
+
+func TestGraphReturnsErrorForInvalidConfig(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	_, err := e.Graph("../../examples/invalid", nil, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,273 @@
+package shipyard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// HealthResult is the result of a single health check for a resource
+type HealthResult struct {
+	// Resource is the id of the resource e.g. container.consul
+	Resource string
+	// Check is the type of check, http, tcp, exec, pods, condition, or nomad_jobs
+	Check string
+	// Target is the endpoint, selector, or job which was checked
+	Target string
+	// Error is set when the check fails
+	Error error
+}
+
+// Healthy returns true when the check passed
+func (h HealthResult) Healthy() bool {
+	return h.Error == nil
+}
+
+// RunHealthChecks runs the health checks for all the resources in the current state
+// without applying any changes. The result of every check is returned, an error is
+// only returned when the state can not be read.
+func (e *EngineImpl) RunHealthChecks() ([]HealthResult, error) {
+	_, err := e.readConfig("", nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	results := []HealthResult{}
+
+	for _, r := range e.config.Resources {
+		if r.Info().Status == config.Disabled {
+			continue
+		}
+
+		hc, cluster := healthCheckForResource(r)
+		if hc == nil {
+			continue
+		}
+
+		id := fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name)
+
+		timeout, err := time.ParseDuration(hc.Timeout)
+		if err != nil {
+			results = append(results, HealthResult{Resource: id, Check: "timeout", Target: hc.Timeout, Error: xerrors.Errorf("unable to parse healthcheck duration: %w", err)})
+			continue
+		}
+
+		if hc.HTTP != "" {
+			codes := hc.HTTPSuccessCodes
+			if codes == nil {
+				codes = []int{200}
+			}
+
+			err := e.clients.HTTP.HealthCheckHTTP(hc.HTTP, codes, timeout)
+			results = append(results, HealthResult{Resource: id, Check: "http", Target: hc.HTTP, Error: err})
+		}
+
+		if hc.TCP != "" {
+			err := e.clients.HTTP.HealthCheckTCP(hc.TCP, timeout)
+			results = append(results, HealthResult{Resource: id, Check: "tcp", Target: hc.TCP, Error: err})
+		}
+
+		if len(hc.Exec) > 0 && (r.Info().Type == config.TypeContainer || r.Info().Type == config.TypeSidecar) {
+			err := e.checkExec(r, hc.Exec)
+			results = append(results, HealthResult{Resource: id, Check: "exec", Target: strings.Join(hc.Exec, " "), Error: err})
+		}
+
+		if len(hc.Pods) > 0 || len(hc.Conditions) > 0 {
+			results = append(results, e.checkKubernetes(r, id, hc, cluster, timeout)...)
+		}
+
+		for _, j := range hc.NomadJobs {
+			err := e.checkNomadJob(r, cluster, j)
+			results = append(results, HealthResult{Resource: id, Check: "nomad_jobs", Target: j, Error: err})
+		}
+	}
+
+	return results, nil
+}
+
+// checkExec runs the exec health check command in the containers for the resource
+func (e *EngineImpl) checkExec(r config.Resource, command []string) error {
+	ids, err := e.clients.ContainerTasks.FindContainerIDs(r.Info().Name, r.Info().Type)
+	if err != nil {
+		return xerrors.Errorf("Unable to find container: %w", err)
+	}
+
+	if len(ids) == 0 {
+		return fmt.Errorf("Container %s is not running", r.Info().Name)
+	}
+
+	for _, cid := range ids {
+		err := e.clients.ContainerTasks.ExecuteCommand(cid, command, nil, "/", "", "", nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkKubernetes checks each pod selector and condition for the resource
+// separately so that every check has its own result
+func (e *EngineImpl) checkKubernetes(r config.Resource, id string, hc *config.HealthCheck, cluster string, timeout time.Duration) []HealthResult {
+	results := []HealthResult{}
+
+	namespace := "default"
+	if h, ok := r.(*config.Helm); ok && h.Namespace != "" {
+		namespace = h.Namespace
+	}
+
+	kc, err := e.kubernetesForCluster(r, cluster)
+
+	for _, p := range hc.Pods {
+		perr := err
+		if perr == nil {
+			perr = kc.HealthCheckPods([]string{p}, timeout)
+		}
+
+		results = append(results, HealthResult{Resource: id, Check: "pods", Target: p, Error: perr})
+	}
+
+	for _, c := range hc.Conditions {
+		cerr := err
+		if cerr == nil {
+			cerr = clients.HealthCheckCondition(kc, c, namespace, timeout)
+		}
+
+		results = append(results, HealthResult{Resource: id, Check: "condition", Target: fmt.Sprintf("%s/%s %s", c.Kind, c.Name, c.Condition), Error: cerr})
+	}
+
+	return results
+}
+
+// kubernetesForCluster returns a Kubernetes client for the cluster the resource is deployed to
+func (e *EngineImpl) kubernetesForCluster(r config.Resource, cluster string) (clients.Kubernetes, error) {
+	cl, err := r.FindDependentResource(cluster)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to find cluster: %w", err)
+	}
+
+	_, kubeConfig, _ := utils.CreateKubeConfigPath(cl.Info().Name)
+
+	kc, err := e.clients.Kubernetes.SetConfig(kubeConfig)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to create Kubernetes client: %w", err)
+	}
+
+	return kc, nil
+}
+
+func (e *EngineImpl) checkNomadJob(r config.Resource, cluster string, job string) error {
+	cl, err := r.FindDependentResource(cluster)
+	if err != nil {
+		return xerrors.Errorf("Unable to find cluster: %w", err)
+	}
+
+	cc, _ := utils.GetClusterConfig(string(cl.Info().Type) + "." + cl.Info().Name)
+	err = e.clients.Nomad.SetConfig(cc, string(utils.LocalContext))
+	if err != nil {
+		return xerrors.Errorf("Unable to create Nomad client: %w", err)
+	}
+
+	ok, err := e.clients.Nomad.JobRunning(job)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("Job %s is not running", job)
+	}
+
+	return nil
+}
+
+// healthCheckForResource returns the health check and the cluster for a resource
+func healthCheckForResource(r config.Resource) (*config.HealthCheck, string) {
+	switch v := r.(type) {
+	case *config.Container:
+		return v.HealthCheck, ""
+	case *config.Sidecar:
+		return v.HealthCheck, ""
+	case *config.External:
+		return v.HealthCheck, ""
+	case *config.Helm:
+		return v.HealthCheck, v.Cluster
+	case *config.K8sConfig:
+		return v.HealthCheck, v.Cluster
+	case *config.NomadJob:
+		return v.HealthCheck, v.Cluster
+	}
+
+	return nil, ""
+}
+
+// reasonUnhealthy is the reason shown in the plan for containers
+// which are recreated because Docker reports them as unhealthy
+const reasonUnhealthy = "unhealthy, will recreate"
+
+// recreateUnhealthy returns true when the blueprint enables the recreation
+// of unhealthy containers
+func recreateUnhealthy(c *config.Config) bool {
+	return c != nil && c.Blueprint != nil && c.Blueprint.RecreateUnhealthy
+}
+
+// containerUnhealthy returns true when Docker reports a container for the resource as
+// unhealthy, containers without a Docker health check are never unhealthy
+func (e *EngineImpl) containerUnhealthy(r config.Resource) bool {
+	if r.Info().Type != config.TypeContainer && r.Info().Type != config.TypeSidecar {
+		return false
+	}
+
+	if r.Info().Status != config.Applied && r.Info().Status != config.PendingUpdate {
+		return false
+	}
+
+	if e.clients == nil || e.clients.ContainerTasks == nil {
+		return false
+	}
+
+	ids, err := e.clients.ContainerTasks.FindContainerIDs(r.Info().Name, r.Info().Type)
+	if err != nil {
+		e.log.Debug("Unable to find container", "ref", r.Info().Name, "type", r.Info().Type, "error", err)
+		return false
+	}
+
+	for _, id := range ids {
+		info, err := e.clients.ContainerTasks.ContainerInfo(id)
+		if err != nil {
+			e.log.Debug("Unable to read container info", "ref", r.Info().Name, "id", id, "error", err)
+			continue
+		}
+
+		cj, ok := info.(types.ContainerJSON)
+		if !ok || cj.ContainerJSONBase == nil || cj.State == nil || cj.State.Health == nil {
+			continue
+		}
+
+		if cj.State.Health.Status == types.Unhealthy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// markUnhealthyContainers sets the status of containers which Docker reports as
+// unhealthy to PendingModification so that they are recreated
+func (e *EngineImpl) markUnhealthyContainers() {
+	if !recreateUnhealthy(e.config) {
+		return
+	}
+
+	for _, r := range e.config.Resources {
+		if e.containerUnhealthy(r) {
+			e.log.Info("Container is unhealthy, it will be recreated", "ref", r.Info().Name, "type", r.Info().Type)
+			r.Info().Status = config.PendingModification
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package shipyard
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	clientMocks "github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/providers/mocks"
+	"github.com/stretchr/testify/mock"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupHealthChecks(t *testing.T) (Engine, *clientMocks.MockHTTP, *clients.MockKubernetes, func()) {
+	e, _, cleanup := setupTestsWithState(nil, healthCheckState)
+
+	mh := &clientMocks.MockHTTP{}
+	mh.On("HealthCheckHTTP", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mh.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	mk := &clients.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("HealthCheckPods", []string{"app=vault"}, mock.Anything).Return(fmt.Errorf("boom"))
+	mk.On("HealthCheckPods", mock.Anything, mock.Anything).Return(nil)
+
+	mt := &clientMocks.MockContainerTasks{}
+	mt.On("FindContainerIDs", "consul", config.TypeContainer).Return([]string{"abc"}, nil)
+	mt.On("ExecuteCommand", "abc", []string{"consul", "members"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	e.(*EngineImpl).clients.HTTP = mh
+	e.(*EngineImpl).clients.Kubernetes = mk
+	e.(*EngineImpl).clients.ContainerTasks = mt
+
+	return e, mh, mk, cleanup
+}
+
+func TestRunHealthChecksReturnsResultForEachCheck(t *testing.T) {
+	e, mh, mk, cleanup := setupHealthChecks(t)
+	defer cleanup()
+
+	res, err := e.RunHealthChecks()
+	assert.NoError(t, err)
+	assert.Len(t, res, 5)
+
+	mh.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8500", []int{200}, mock.Anything)
+	mh.AssertCalled(t, "HealthCheckTCP", "localhost:8300", mock.Anything)
+	mk.AssertCalled(t, "HealthCheckPods", []string{"app=consul"}, mock.Anything)
+	mk.AssertCalled(t, "HealthCheckPods", []string{"app=vault"}, mock.Anything)
+}
+
+func TestRunHealthChecksRunsExecChecks(t *testing.T) {
+	e, _, _, cleanup := setupHealthChecks(t)
+	defer cleanup()
+
+	res, err := e.RunHealthChecks()
+	assert.NoError(t, err)
+
+	found := false
+	for _, r := range res {
+		if r.Check == "exec" {
+			found = true
+			assert.Equal(t, "consul members", r.Target)
+			assert.True(t, r.Healthy())
+		}
+	}
+
+	assert.True(t, found)
+}
+
+func TestRunHealthChecksReturnsFailedChecks(t *testing.T) {
+	e, _, _, cleanup := setupHealthChecks(t)
+	defer cleanup()
+
+	res, err := e.RunHealthChecks()
+	assert.NoError(t, err)
+
+	for _, r := range res {
+		if r.Check == "tcp" || r.Target == "app=vault" {
+			assert.False(t, r.Healthy())
+		} else {
+			assert.True(t, r.Healthy())
+		}
+	}
+}
+
+var healthCheckState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "k3s",
+      "status": "applied",
+      "type": "k8s_cluster"
+	},
+	{
+      "name": "consul",
+      "status": "applied",
+      "type": "container",
+      "image": {"name": "consul:1.8.1"},
+      "health_check": {
+        "timeout": "30s",
+        "http": "http://localhost:8500",
+        "tcp": "localhost:8300",
+        "exec": ["consul", "members"]
+      }
+	},
+	{
+      "name": "consul",
+      "status": "applied",
+      "type": "helm",
+      "cluster": "k8s_cluster.k3s",
+      "health_check": {
+        "timeout": "30s",
+        "pods": ["app=consul", "app=vault"]
+      }
+	}
+  ]
+}
+`
+
+func setupUnhealthyContainer(t *testing.T, state, health string) (Engine, *[]*mocks.MockProvider, func()) {
+	e, mp, cleanup := setupTestsWithState(nil, state)
+
+	mt := &clientMocks.MockContainerTasks{}
+	mt.On("FindContainerIDs", "consul", config.TypeContainer).Return([]string{"abc"}, nil)
+	mt.On("ContainerInfo", "abc").Return(types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			State: &types.ContainerState{Health: &types.Health{Status: health}},
+		},
+	}, nil)
+
+	e.(*EngineImpl).clients.ContainerTasks = mt
+
+	return e, mp, cleanup
+}
+
+func TestApplyRecreatesUnhealthyContainerWhenEnabled(t *testing.T) {
+	e, mp, cleanup := setupUnhealthyContainer(t, unhealthyState, types.Unhealthy)
+	defer cleanup()
+
+	_, err := e.Apply("")
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+	testAssertMethodCalled(t, mp, "Create", 2) // ImageCache is always created
+}
+
+func TestApplyDoesNotRecreateHealthyContainer(t *testing.T) {
+	e, mp, cleanup := setupUnhealthyContainer(t, unhealthyState, types.Healthy)
+	defer cleanup()
+
+	_, err := e.Apply("")
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+}
+
+func TestApplyDoesNotRecreateUnhealthyContainerWhenDisabled(t *testing.T) {
+	state := strings.Replace(unhealthyState, `"recreate_unhealthy": true`, `"recreate_unhealthy": false`, 1)
+	e, mp, cleanup := setupUnhealthyContainer(t, state, types.Unhealthy)
+	defer cleanup()
+
+	_, err := e.Apply("")
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+}
+
+var unhealthyState = `
+{
+  "blueprint": {
+    "recreate_unhealthy": true
+  },
+  "resources": [
+	{
+      "name": "consul",
+      "status": "applied",
+      "type": "container",
+      "image": {"name": "consul:1.8.1"}
+	}
+  ]
+}
+`
@@ -0,0 +1,123 @@
+package shipyard
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ImportK8s scans the namespace in the Kubernetes cluster clusterResource e.g. k8s_cluster.k3s
+// and generates resources which represent the deployed objects so that they can be adopted
+// into a blueprint.
+// Objects installed by Helm are grouped into a helm resource for each release, the chart for
+// these resources is set to the chart label and must be updated to point to the chart source.
+// All other objects are written as manifests to the Shipyard temp folder and returned as a
+// single k8s_config resource.
+func (e *EngineImpl) ImportK8s(clusterResource, namespace string) ([]config.Resource, error) {
+	_, err := e.readConfig("", nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	cl, err := e.config.FindResource(clusterResource)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to find cluster: %w", err)
+	}
+
+	if cl.Info().Type != config.TypeK8sCluster {
+		return nil, fmt.Errorf("Resource %s is not a Kubernetes cluster", clusterResource)
+	}
+
+	_, kubeConfig, _ := utils.CreateKubeConfigPath(cl.Info().Name)
+	kc, err := e.clients.Kubernetes.SetConfig(kubeConfig)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to create Kubernetes client: %w", err)
+	}
+
+	objs, err := kc.ExportResources(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(utils.ShipyardTemp(), "import", cl.Info().Name, namespace)
+	os.RemoveAll(dir)
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to create folder for manifests: %w", err)
+	}
+
+	releases := map[string]*config.Helm{}
+	paths := []string{}
+
+	for _, o := range objs {
+		if rel := helmRelease(o); rel != "" {
+			if _, ok := releases[rel]; !ok {
+				h := config.NewHelm(rel)
+				h.Cluster = clusterResource
+				h.Chart = o.GetLabels()["helm.sh/chart"]
+				h.Namespace = namespace
+
+				releases[rel] = h
+			}
+
+			continue
+		}
+
+		d, err := yaml.Marshal(o.Object)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to serialize %s %s: %w", o.GetKind(), o.GetName(), err)
+		}
+
+		f := filepath.Join(dir, fmt.Sprintf("%s_%s.yaml", strings.ToLower(o.GetKind()), o.GetName()))
+		err = ioutil.WriteFile(f, d, os.ModePerm)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to write manifest: %w", err)
+		}
+
+		paths = append(paths, f)
+	}
+
+	resources := []config.Resource{}
+
+	names := []string{}
+	for n := range releases {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		resources = append(resources, releases[n])
+	}
+
+	if len(paths) > 0 {
+		k := config.NewK8sConfig(namespace)
+		k.Cluster = clusterResource
+		k.Paths = paths
+		k.WaitUntilReady = true
+
+		resources = append(resources, k)
+	}
+
+	e.log.Info("Imported resources from cluster", "cluster", clusterResource, "namespace", namespace, "manifests", len(paths), "releases", len(releases))
+
+	return resources, nil
+}
+
+// helmRelease returns the name of the Helm release which manages the object
+// or an empty string if the object was not installed by Helm
+func helmRelease(o unstructured.Unstructured) string {
+	if o.GetLabels()["app.kubernetes.io/managed-by"] != "Helm" {
+		return ""
+	}
+
+	return o.GetAnnotations()["meta.helm.sh/release-name"]
+}
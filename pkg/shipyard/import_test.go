@@ -0,0 +1,95 @@
+package shipyard
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupImport(t *testing.T) (Engine, *clients.MockKubernetes, func()) {
+	e, _, cleanup := setupTestsWithState(nil, importState)
+
+	mk := &clients.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("ExportResources", "default").Return(importObjects(), nil)
+
+	e.(*EngineImpl).clients.Kubernetes = mk
+
+	return e, mk, cleanup
+}
+
+func TestImportK8sReturnsErrorWhenClusterNotFound(t *testing.T) {
+	e, _, cleanup := setupImport(t)
+	defer cleanup()
+
+	_, err := e.ImportK8s("k8s_cluster.missing", "default")
+	assert.Error(t, err)
+}
+
+func TestImportK8sGeneratesK8sConfigForManifests(t *testing.T) {
+	e, mk, cleanup := setupImport(t)
+	defer cleanup()
+
+	res, err := e.ImportK8s("k8s_cluster.k3s", "default")
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+
+	mk.AssertCalled(t, "ExportResources", "default")
+
+	k := res[1].(*config.K8sConfig)
+	assert.Equal(t, "k8s_cluster.k3s", k.Cluster)
+	assert.Len(t, k.Paths, 1)
+
+	d, err := ioutil.ReadFile(k.Paths[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(d), "name: web")
+}
+
+func TestImportK8sGeneratesHelmForReleases(t *testing.T) {
+	e, _, cleanup := setupImport(t)
+	defer cleanup()
+
+	res, err := e.ImportK8s("k8s_cluster.k3s", "default")
+	assert.NoError(t, err)
+
+	h := res[0].(*config.Helm)
+	assert.Equal(t, "consul", h.Name)
+	assert.Equal(t, "consul-0.22.0", h.Chart)
+	assert.Equal(t, "default", h.Namespace)
+}
+
+func importObjects() []unstructured.Unstructured {
+	web := unstructured.Unstructured{}
+	web.SetAPIVersion("apps/v1")
+	web.SetKind("Deployment")
+	web.SetName("web")
+
+	consul := unstructured.Unstructured{}
+	consul.SetAPIVersion("apps/v1")
+	consul.SetKind("StatefulSet")
+	consul.SetName("consul-server")
+	consul.SetLabels(map[string]string{"app.kubernetes.io/managed-by": "Helm", "helm.sh/chart": "consul-0.22.0"})
+	consul.SetAnnotations(map[string]string{"meta.helm.sh/release-name": "consul"})
+
+	return []unstructured.Unstructured{web, consul}
+}
+
+var importState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "k3s",
+      "status": "applied",
+      "type": "k8s_cluster",
+      "driver": "k3s"
+	}
+  ]
+}
+`
@@ -0,0 +1,64 @@
+package shipyard
+
+import (
+	"github.com/shipyard-run/shipyard/pkg/config"
+)
+
+// typeLimiter restricts the number of resources of a given type which
+// can be created or destroyed concurrently. Types without a limit
+// are not restricted.
+type typeLimiter struct {
+	sems map[config.ResourceType]chan struct{}
+	// all restricts the total number of resources regardless of type
+	all chan struct{}
+}
+
+// newTypeLimiter creates a limiter from the max_parallel settings in the blueprint
+func newTypeLimiter(bp *config.Blueprint) *typeLimiter {
+	tl := &typeLimiter{sems: map[config.ResourceType]chan struct{}{}}
+
+	if bp == nil {
+		return tl
+	}
+
+	for t, n := range bp.MaxParallel {
+		if n > 0 {
+			tl.sems[config.ResourceType(t)] = make(chan struct{}, n)
+		}
+	}
+
+	return tl
+}
+
+// acquire blocks until the resource type has capacity, the returned
+// function must be called to release the slot
+func (tl *typeLimiter) acquire(t config.ResourceType) func() {
+	sem, ok := tl.sems[t]
+	if ok {
+		sem <- struct{}{}
+	}
+
+	if tl.all != nil {
+		tl.all <- struct{}{}
+	}
+
+	return func() {
+		if tl.all != nil {
+			<-tl.all
+		}
+
+		if ok {
+			<-sem
+		}
+	}
+}
+
+// setConcurrency limits the total number of resources which can be created
+// or destroyed concurrently, a value of 0 removes the limit
+func (tl *typeLimiter) setConcurrency(n int) {
+	tl.all = nil
+
+	if n > 0 {
+		tl.all = make(chan struct{}, n)
+	}
+}
@@ -0,0 +1,92 @@
+package shipyard
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestTypeLimiterRestrictsConcurrency(t *testing.T) {
+	tl := newTypeLimiter(&config.Blueprint{MaxParallel: map[string]int{"k8s_cluster": 1}})
+
+	var running int32
+	var max int32
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			release := tl.acquire(config.TypeK8sCluster)
+			defer release()
+
+			n := atomic.AddInt32(&running, 1)
+			if n > atomic.LoadInt32(&max) {
+				atomic.StoreInt32(&max, n)
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), max)
+}
+
+func TestTypeLimiterDoesNotRestrictTypesWithoutLimit(t *testing.T) {
+	tl := newTypeLimiter(&config.Blueprint{MaxParallel: map[string]int{"k8s_cluster": 1}})
+
+	r1 := tl.acquire(config.TypeContainer)
+	r2 := tl.acquire(config.TypeContainer)
+
+	r1()
+	r2()
+}
+
+func TestTypeLimiterHandlesNilBlueprint(t *testing.T) {
+	tl := newTypeLimiter(nil)
+
+	release := tl.acquire(config.TypeK8sCluster)
+	release()
+
+	assert.Len(t, tl.sems, 0)
+}
+
+func TestTypeLimiterRestrictsTotalConcurrency(t *testing.T) {
+	tl := newTypeLimiter(nil)
+	tl.setConcurrency(2)
+
+	var running int32
+	var max int32
+	wg := sync.WaitGroup{}
+
+	types := []config.ResourceType{config.TypeContainer, config.TypeNetwork, config.TypeK8sCluster, config.TypeHelm}
+	for _, rt := range types {
+		wg.Add(1)
+		go func(rt config.ResourceType) {
+			defer wg.Done()
+
+			release := tl.acquire(rt)
+			defer release()
+
+			n := atomic.AddInt32(&running, 1)
+			if n > atomic.LoadInt32(&max) {
+				atomic.StoreInt32(&max, n)
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}(rt)
+	}
+
+	wg.Wait()
+
+	assert.LessOrEqual(t, max, int32(2))
+}
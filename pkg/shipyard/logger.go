@@ -0,0 +1,47 @@
+package shipyard
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+// LogOptions configures the logger created by NewLogger
+type LogOptions struct {
+	// Level is the lowest level which is written, trace, debug, info, warn, or error
+	// defaults to info
+	Level string
+	// JSON writes each line as a JSON object rather than text
+	JSON bool
+	// Output is where the log is written, defaults to stderr
+	Output io.Writer
+}
+
+// NewLogger creates a logger which can be passed to New. The engine
+// redirects the standard library log used by the dependency graph to this
+// logger at trace level, those messages are only written when Level is trace.
+// The returned logger is an hclog.InterceptLogger so that additional sinks
+// can be registered.
+func NewLogger(o LogOptions) (hclog.Logger, error) {
+	lev := hclog.Info
+	if o.Level != "" {
+		lev = hclog.LevelFromString(o.Level)
+		if lev == hclog.NoLevel {
+			return nil, fmt.Errorf("Unknown log level %s, must be one of trace, debug, info, warn, error", o.Level)
+		}
+	}
+
+	out := o.Output
+	if out == nil {
+		out = os.Stderr
+	}
+
+	opts := &hclog.LoggerOptions{Level: lev, Output: out, JSONFormat: o.JSON}
+	if !o.JSON {
+		opts.Color = hclog.AutoColor
+	}
+
+	return hclog.NewInterceptLogger(opts), nil
+}
@@ -0,0 +1,58 @@
+package shipyard
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestNewLoggerWithUnknownLevelReturnsError(t *testing.T) {
+	_, err := NewLogger(LogOptions{Level: "loud"})
+	assert.Error(t, err)
+}
+
+func TestNewLoggerWritesJSONAtLevel(t *testing.T) {
+	out := bytes.NewBufferString("")
+
+	l, err := NewLogger(LogOptions{Level: "warn", JSON: true, Output: out})
+	assert.NoError(t, err)
+
+	l.Info("Creating resources")
+	l.Warn("Unable to create resource", "ref", "consul")
+
+	line := map[string]interface{}{}
+	err = json.Unmarshal(out.Bytes(), &line)
+	assert.NoError(t, err)
+	assert.Equal(t, "Unable to create resource", line["@message"])
+	assert.Equal(t, "consul", line["ref"])
+}
+
+func TestNewLoggerStandardWriterRespectsLevel(t *testing.T) {
+	out := bytes.NewBufferString("")
+
+	l, err := NewLogger(LogOptions{Level: "debug", Output: out})
+	assert.NoError(t, err)
+
+	sl := log.New(l.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Trace}), "", 0)
+	sl.Print("walking graph")
+
+	assert.Empty(t, out.String())
+}
+
+func TestNewLoggerAllowsSinksToBeRegistered(t *testing.T) {
+	l, err := NewLogger(LogOptions{Output: bytes.NewBufferString("")})
+	assert.NoError(t, err)
+
+	il, ok := l.(hclog.InterceptLogger)
+	assert.True(t, ok)
+
+	out := bytes.NewBufferString("")
+	il.RegisterSink(hclog.NewSinkAdapter(&hclog.LoggerOptions{Output: out}))
+	il.Info("Creating resources")
+
+	assert.Contains(t, out.String(), "Creating resources")
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/shipyard-run/shipyard/pkg/config"
 	"github.com/shipyard-run/shipyard/pkg/shipyard"
 	"github.com/stretchr/testify/mock"
@@ -42,6 +44,36 @@ func (e *Engine) ApplyWithVariables(path string, vars map[string]string, varsFil
 	return nil, args.Error(1)
 }
 
+func (e *Engine) ApplyWithOverlays(paths []string, vars map[string]string, varsFile string) ([]config.Resource, error) {
+	args := e.Called(paths, vars, varsFile)
+
+	if r, ok := args.Get(0).([]config.Resource); ok {
+		return r, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) ApplyWithOptions(paths []string, opts shipyard.ApplyOptions) ([]config.Resource, error) {
+	args := e.Called(paths, opts)
+
+	if r, ok := args.Get(0).([]config.Resource); ok {
+		return r, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) ApplyTarget(path string, targets []string, variables map[string]string, variablesFile string) ([]config.Resource, error) {
+	args := e.Called(path, targets, variables, variablesFile)
+
+	if r, ok := args.Get(0).([]config.Resource); ok {
+		return r, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
 func (e *Engine) Destroy(path string, all bool) error {
 	args := e.Called(path, all)
 
@@ -73,3 +105,203 @@ func (e *Engine) ParseConfigWithVariables(path string, vars map[string]string, v
 	args := e.Called(path, vars, varsFile)
 	return args.Error(0)
 }
+
+func (e *Engine) ParseConfigWithOverlays(paths []string, vars map[string]string, varsFile string) error {
+	args := e.Called(paths, vars, varsFile)
+	return args.Error(0)
+}
+
+func (e *Engine) SnapshotData(resourceID, dest string) error {
+	args := e.Called(resourceID, dest)
+	return args.Error(0)
+}
+
+func (e *Engine) RestoreData(resourceID, src string) error {
+	args := e.Called(resourceID, src)
+	return args.Error(0)
+}
+
+func (e *Engine) RebuildState(path string, variables map[string]string, variablesFile string) error {
+	args := e.Called(path, variables, variablesFile)
+	return args.Error(0)
+}
+
+func (e *Engine) VerifyDestroy() ([]string, error) {
+	args := e.Called()
+
+	if r, ok := args.Get(0).([]string); ok {
+		return r, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) RunHealthChecks() ([]shipyard.HealthResult, error) {
+	args := e.Called()
+
+	if r, ok := args.Get(0).([]shipyard.HealthResult); ok {
+		return r, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) Policy(path, policyPath string, variables map[string]string, variablesFile string) ([]shipyard.PolicyViolation, error) {
+	args := e.Called(path, policyPath, variables, variablesFile)
+
+	if r, ok := args.Get(0).([]shipyard.PolicyViolation); ok {
+		return r, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) Plan(path string, variables map[string]string, variablesFile string) (*shipyard.Plan, error) {
+	args := e.Called(path, variables, variablesFile)
+
+	if r, ok := args.Get(0).(*shipyard.Plan); ok {
+		return r, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) ImportK8s(clusterResource, namespace string) ([]config.Resource, error) {
+	args := e.Called(clusterResource, namespace)
+
+	if r, ok := args.Get(0).([]config.Resource); ok {
+		return r, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) VariableUsage(path, variableName string) ([]string, error) {
+	args := e.Called(path, variableName)
+
+	if r, ok := args.Get(0).([]string); ok {
+		return r, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) EstimateApplyDuration(path string, variables map[string]string, variablesFile string) (time.Duration, error) {
+	args := e.Called(path, variables, variablesFile)
+
+	if d, ok := args.Get(0).(time.Duration); ok {
+		return d, args.Error(1)
+	}
+
+	return 0, args.Error(1)
+}
+
+func (e *Engine) ExternalDependencies(path string, variables map[string]string, variablesFile string) (*shipyard.Dependencies, error) {
+	args := e.Called(path, variables, variablesFile)
+
+	if d, ok := args.Get(0).(*shipyard.Dependencies); ok {
+		return d, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) Reconcile(path string, interval time.Duration, variables map[string]string, variablesFile string) error {
+	args := e.Called(path, interval, variables, variablesFile)
+
+	return args.Error(0)
+}
+
+func (e *Engine) StopReconcile() {
+	e.Called()
+}
+
+func (e *Engine) Pause() error {
+	args := e.Called()
+
+	return args.Error(0)
+}
+
+func (e *Engine) Resume() ([]config.Resource, error) {
+	args := e.Called()
+
+	if r, ok := args.Get(0).([]config.Resource); ok {
+		return r, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) ResourceStatus(id string) (string, error) {
+	args := e.Called(id)
+
+	return args.String(0), args.Error(1)
+}
+
+func (e *Engine) Statuses() map[string]string {
+	if s, ok := e.Called().Get(0).(map[string]string); ok {
+		return s
+	}
+
+	return nil
+}
+
+func (e *Engine) Workspace(name string) error {
+	args := e.Called(name)
+
+	return args.Error(0)
+}
+
+func (e *Engine) ListWorkspaces() ([]string, error) {
+	args := e.Called()
+
+	if ws, ok := args.Get(0).([]string); ok {
+		return ws, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) Prune(force bool) ([]string, error) {
+	args := e.Called(force)
+
+	if o, ok := args.Get(0).([]string); ok {
+		return o, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) DestroyWithOptions(path string, opts shipyard.DestroyOptions) error {
+	args := e.Called(path, opts)
+
+	return args.Error(0)
+}
+
+func (e *Engine) DestroyResource(id string, force bool) error {
+	args := e.Called(id, force)
+
+	return args.Error(0)
+}
+
+func (e *Engine) Validate(path string, variables map[string]string, variablesFile string) error {
+	args := e.Called(path, variables, variablesFile)
+
+	return args.Error(0)
+}
+
+func (e *Engine) RequiredVariables(path string) ([]string, error) {
+	args := e.Called(path)
+
+	if r, ok := args.Get(0).([]string); ok {
+		return r, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) Graph(path string, variables map[string]string, variablesFile string) (string, error) {
+	args := e.Called(path, variables, variablesFile)
+
+	return args.String(0), args.Error(1)
+}
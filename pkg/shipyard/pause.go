@@ -0,0 +1,218 @@
+package shipyard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// pauseStopTimeout is the time containers are given to stop before they are killed
+const pauseStopTimeout = 20 * time.Second
+
+// resumeTimeout is the time to wait for resumed containers to be running
+var resumeTimeout = 60 * time.Second
+
+// Pause stops the containers for the resources in the current state, the
+// containers and the state are not removed so the resources can be started
+// again with Resume
+func (e *EngineImpl) Pause() error {
+	_, err := e.readConfig("", nil, "")
+	if err != nil {
+		return err
+	}
+
+	sd := pauseStopTimeout
+
+	for _, r := range e.config.Resources {
+		ids, err := e.containerIDs(r)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			e.log.Info("Stopping container", "ref", r.Info().Name, "type", r.Info().Type, "id", id)
+
+			err := e.clients.Docker.ContainerStop(context.Background(), id, &sd)
+			if err != nil {
+				return xerrors.Errorf("Unable to stop container for %s.%s: %w", r.Info().Type, r.Info().Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Resume starts the containers for the resources in the current state which
+// are not running and waits until they have started. The resources which had
+// containers started are returned.
+func (e *EngineImpl) Resume() ([]config.Resource, error) {
+	_, err := e.readConfig("", nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	restarted := []config.Resource{}
+	started := []string{}
+
+	for _, r := range e.config.Resources {
+		ids, err := e.containerIDs(r)
+		if err != nil {
+			return nil, err
+		}
+
+		resumed := false
+		for _, id := range ids {
+			state, err := e.containerState(id)
+			if err != nil {
+				return nil, err
+			}
+
+			// containers with a restart policy may be restarting, Docker
+			// starts these containers so wait for them to be running
+			if state != nil && state.Restarting {
+				e.log.Debug("Container is restarting", "ref", r.Info().Name, "type", r.Info().Type, "id", id)
+				started = append(started, id)
+				continue
+			}
+
+			if state != nil && state.Running {
+				continue
+			}
+
+			e.log.Info("Starting container", "ref", r.Info().Name, "type", r.Info().Type, "id", id)
+
+			err = e.clients.Docker.ContainerStart(context.Background(), id, types.ContainerStartOptions{})
+			if err != nil {
+				return nil, xerrors.Errorf("Unable to start container for %s.%s: %w", r.Info().Type, r.Info().Name, err)
+			}
+
+			started = append(started, id)
+			resumed = true
+		}
+
+		if resumed {
+			restarted = append(restarted, r)
+		}
+	}
+
+	err = e.waitForRunning(started)
+	if err != nil {
+		return nil, err
+	}
+
+	return restarted, nil
+}
+
+// waitForRunning blocks until all the containers are running or the resume
+// timeout elapses
+func (e *EngineImpl) waitForRunning(ids []string) error {
+	st := time.Now()
+
+	for _, id := range ids {
+		for {
+			state, err := e.containerState(id)
+			if err != nil {
+				return err
+			}
+
+			// a restarting container is reported as running by Docker
+			if state != nil && state.Running && !state.Restarting {
+				break
+			}
+
+			if time.Since(st) > resumeTimeout {
+				return fmt.Errorf("Timeout waiting for container %s to start", id)
+			}
+
+			time.Sleep(1 * time.Second)
+		}
+	}
+
+	return nil
+}
+
+// containerState returns the state of the container with the given id, nil is
+// returned when Docker does not report a state
+func (e *EngineImpl) containerState(id string) (*types.ContainerState, error) {
+	info, err := e.clients.Docker.ContainerInspect(context.Background(), id)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to get status for container %s: %w", id, err)
+	}
+
+	if info.ContainerJSONBase == nil {
+		return nil, nil
+	}
+
+	return info.State, nil
+}
+
+// containerNames returns the names of the Docker containers created for the
+// resource, the names are converted to a container name with utils.FQDN
+func containerNames(r config.Resource) []string {
+	names := []string{}
+
+	switch v := r.(type) {
+	case *config.Container, *config.Sidecar, *config.ImageCache, *config.ContainerIngress,
+		*config.K8sIngress, *config.NomadIngress, *config.Docs:
+		names = append(names, r.Info().Name)
+	case *config.K8sCluster:
+		names = append(names, fmt.Sprintf("server.%s", v.Name))
+
+		for i := 1; i < v.Nodes; i++ {
+			names = append(names, fmt.Sprintf("%d.agent.%s", i, v.Name))
+		}
+	case *config.NomadCluster:
+		names = append(names, fmt.Sprintf("server.%s", v.Name))
+
+		for i := 0; i < v.ClientNodes; i++ {
+			names = append(names, fmt.Sprintf("%d.client.%s", i+1, v.Name))
+		}
+	}
+
+	return names
+}
+
+// containerIDs returns the ids of the Docker containers which back the
+// resource, resources which do not create containers return no ids
+func (e *EngineImpl) containerIDs(r config.Resource) ([]string, error) {
+	if r.Info().Status == config.Disabled {
+		return nil, nil
+	}
+
+	// init containers have exited and must not be started again
+	if c, ok := r.(*config.Container); ok && c.Init {
+		return nil, nil
+	}
+
+	names := containerNames(r)
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	// containers are labelled with the resource which created them, this finds
+	// containers which are no longer in the config such as removed cluster nodes
+	ids, err := e.clients.ContainerTasks.FindResourceContainerIDs(fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name))
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to find containers for %s.%s: %w", r.Info().Type, r.Info().Name, err)
+	}
+
+	if len(ids) > 0 {
+		return ids, nil
+	}
+
+	// containers created before labels were added are found by name
+	for _, n := range names {
+		found, err := e.clients.ContainerTasks.FindContainerIDs(n, r.Info().Type)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to find containers for %s.%s: %w", r.Info().Type, r.Info().Name, err)
+		}
+
+		ids = append(ids, found...)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,146 @@
+package shipyard
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	clientMocks "github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupPauseTests(t *testing.T) (Engine, *clientMocks.MockDocker, func()) {
+	e, _, cleanup := setupTestsWithState(nil, pauseState)
+
+	md := &clientMocks.MockDocker{}
+	md.On("ContainerStop", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	md.On("ContainerStart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	mt := &clientMocks.MockContainerTasks{}
+	mt.On("FindResourceContainerIDs", mock.Anything).Return([]string{}, nil)
+	mt.On("FindContainerIDs", "consul", config.TypeContainer).Return([]string{"consul"}, nil)
+	mt.On("FindContainerIDs", "server.k3s", config.TypeK8sCluster).Return([]string{"k3s"}, nil)
+	mt.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, nil)
+
+	e.(*EngineImpl).clients.Docker = md
+	e.(*EngineImpl).clients.ContainerTasks = mt
+
+	return e, md, cleanup
+}
+
+func TestPauseStopsContainerBackedResources(t *testing.T) {
+	e, md, cleanup := setupPauseTests(t)
+	defer cleanup()
+
+	err := e.Pause()
+	assert.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "ContainerStop", 2)
+	md.AssertCalled(t, "ContainerStop", mock.Anything, "consul", mock.Anything)
+	md.AssertCalled(t, "ContainerStop", mock.Anything, "k3s", mock.Anything)
+	md.AssertNotCalled(t, "ContainerRemove", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPauseFindsContainersUsingResourceLabel(t *testing.T) {
+	e, md, cleanup := setupPauseTests(t)
+	defer cleanup()
+
+	// the agent is no longer in the config but is labelled with the cluster
+	mt := &clientMocks.MockContainerTasks{}
+	mt.On("FindResourceContainerIDs", "k8s_cluster.k3s").Return([]string{"k3s", "agent"}, nil)
+	mt.On("FindResourceContainerIDs", mock.Anything).Return([]string{}, nil)
+	mt.On("FindContainerIDs", "consul", config.TypeContainer).Return([]string{"consul"}, nil)
+	mt.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, nil)
+	e.(*EngineImpl).clients.ContainerTasks = mt
+
+	err := e.Pause()
+	assert.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "ContainerStop", 3)
+	md.AssertCalled(t, "ContainerStop", mock.Anything, "agent", mock.Anything)
+	mt.AssertNotCalled(t, "FindContainerIDs", "server.k3s", config.TypeK8sCluster)
+}
+
+func TestResumeStartsStoppedContainers(t *testing.T) {
+	e, md, cleanup := setupPauseTests(t)
+	defer cleanup()
+
+	// containers report running once started
+	md.On("ContainerInspect", mock.Anything, mock.Anything).Times(2).Return(
+		types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Running: false}}},
+		nil,
+	)
+	md.On("ContainerInspect", mock.Anything, mock.Anything).Return(
+		types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Running: true}}},
+		nil,
+	)
+
+	res, err := e.Resume()
+	assert.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "ContainerStart", 2)
+	assert.Len(t, res, 2)
+}
+
+func TestResumeDoesNotStartRunningContainers(t *testing.T) {
+	e, md, cleanup := setupPauseTests(t)
+	defer cleanup()
+
+	md.On("ContainerInspect", mock.Anything, mock.Anything).Return(
+		types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Running: true}}},
+		nil,
+	)
+
+	res, err := e.Resume()
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "ContainerStart", mock.Anything, mock.Anything, mock.Anything)
+	assert.Len(t, res, 0)
+}
+
+func TestResumeWaitsForRestartingContainers(t *testing.T) {
+	e, md, cleanup := setupPauseTests(t)
+	defer cleanup()
+
+	// containers with a restart policy are restarted by Docker
+	md.On("ContainerInspect", mock.Anything, mock.Anything).Times(3).Return(
+		types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Running: true, Restarting: true}}},
+		nil,
+	)
+	md.On("ContainerInspect", mock.Anything, mock.Anything).Return(
+		types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Running: true}}},
+		nil,
+	)
+
+	res, err := e.Resume()
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "ContainerStart", mock.Anything, mock.Anything, mock.Anything)
+	md.AssertNumberOfCalls(t, "ContainerInspect", 5)
+	assert.Len(t, res, 0)
+}
+
+var pauseState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "consul",
+      "status": "applied",
+      "type": "container",
+      "image": {"name": "consul:1.8.1"}
+	},
+	{
+      "name": "k3s",
+      "status": "applied",
+      "type": "k8s_cluster"
+	},
+	{
+      "name": "vault",
+      "status": "applied",
+      "type": "helm"
+	}
+  ]
+}
+`
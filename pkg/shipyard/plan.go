@@ -0,0 +1,336 @@
+package shipyard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// PlanAction is the action Apply will take for a resource
+type PlanAction string
+
+const (
+	// PlanCreate means the resource does not exist and will be created
+	PlanCreate PlanAction = "create"
+	// PlanReplace means the resource is tainted or failed and will be destroyed and created
+	PlanReplace PlanAction = "replace"
+	// PlanUpdate means the resource will be created again without first being destroyed
+	PlanUpdate PlanAction = "update"
+	// PlanNone means Apply will not change the resource, changes to the attributes
+	// of existing resources are listed but are not applied
+	PlanNone PlanAction = "none"
+)
+
+// PlanFormatVersion is the version of the JSON representation of a Plan,
+// the version is incremented when fields are removed or renamed so that
+// tools which read the plan can detect incompatible changes
+const PlanFormatVersion = "1"
+
+// Plan describes the changes Apply would make to the resources
+type Plan struct {
+	// FormatVersion is the version of the JSON format for the plan
+	FormatVersion string         `json:"format_version"`
+	Resources     []PlanResource `json:"resources"`
+}
+
+// PlanResource is the planned change for a single resource
+type PlanResource struct {
+	// Resource is the id of the resource e.g. container.consul
+	Resource string `json:"resource"`
+	// Type is the type of the resource e.g. container
+	Type config.ResourceType `json:"type"`
+	// Name is the name of the resource e.g. consul
+	Name string `json:"name"`
+	// Action that Apply will take for the resource
+	Action PlanAction `json:"action"`
+	// Before is the attributes of the resource in the state, nil when the resource does not exist
+	Before map[string]interface{} `json:"before,omitempty"`
+	// After is the attributes of the resource in the configuration
+	After map[string]interface{} `json:"after,omitempty"`
+	// Changes contains the old and new value for each attribute which differs
+	Changes map[string]PlanChange `json:"changes,omitempty"`
+	// Reason explains the action when it is not caused by a change
+	Reason string `json:"reason,omitempty"`
+}
+
+const (
+	reasonNotInConfig = "not in configuration, will recreate"
+	reasonImageCache  = "attach to networks"
+	reasonNotApplied  = "changes are not applied to existing resources"
+)
+
+// PlanChange is the old and new value for an attribute
+type PlanChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Plan parses the configuration at path and compares it to the current state
+// returning the action and attribute level changes for each resource.
+// No resources are created and the state is not modified.
+func (e *EngineImpl) Plan(path string, variables map[string]string, variablesFile string) (*Plan, error) {
+	cc, err := parseConfig(path, variables, variablesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	sc := config.New()
+	if _, err := os.Stat(e.statePath()); err == nil {
+		err := sc.FromJSON(e.statePath())
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing state: %s", err)
+		}
+	}
+
+	// the blueprint in the configuration replaces the blueprint in the state when applied
+	recreate := recreateUnhealthy(sc)
+	if cc.Blueprint != nil {
+		recreate = recreateUnhealthy(cc)
+	}
+
+	p := &Plan{FormatVersion: PlanFormatVersion, Resources: []PlanResource{}}
+
+	for _, r := range cc.Resources {
+		// the image cache is managed by Shipyard
+		if r.Info().Type == config.TypeImageCache || r.Info().Status == config.Disabled {
+			continue
+		}
+
+		id := fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name)
+
+		after, err := planAttributes(r)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to read attributes for %s: %w", id, err)
+		}
+
+		pr := PlanResource{Resource: id, Type: r.Info().Type, Name: r.Info().Name, Action: PlanCreate}
+
+		pr.After, err = redactedAttributes(r)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to read attributes for %s: %w", id, err)
+		}
+
+		sr, err := sc.FindResource(id)
+		if err != nil {
+			p.Resources = append(p.Resources, pr)
+			continue
+		}
+
+		before, err := planAttributes(sr)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to read state attributes for %s: %w", id, err)
+		}
+
+		pr.Before, err = redactedAttributes(sr)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to read state attributes for %s: %w", id, err)
+		}
+
+		switch sr.Info().Status {
+		case config.PendingCreation:
+			pr.Action = PlanCreate
+		case config.PendingModification, config.Failed:
+			pr.Action = PlanReplace
+		default:
+			pr.Action = PlanNone
+
+			if recreate && e.containerUnhealthy(sr) {
+				pr.Action = PlanReplace
+				pr.Reason = reasonUnhealthy
+			}
+
+			if reason := e.updateReason(r, sr); reason != "" {
+				pr.Action = PlanUpdate
+				pr.Reason = reason
+			}
+
+			if reason := e.replaceReason(r, sr); reason != "" {
+				pr.Action = PlanReplace
+				pr.Reason = reason
+			}
+		}
+
+		// changes are found using the real values but only the redacted values are shown
+		pr.Changes = planChanges(before, after, pr.Before, pr.After)
+		if len(pr.Changes) > 0 && pr.Action == PlanNone {
+			pr.Reason = reasonNotApplied
+		}
+
+		p.Resources = append(p.Resources, pr)
+	}
+
+	// resources which are only in the state are left unchanged by Apply
+	// unless they are tainted or have failed
+	for _, sr := range sc.Resources {
+		if sr.Info().Type == config.TypeImageCache {
+			continue
+		}
+
+		id := fmt.Sprintf("%s.%s", sr.Info().Type, sr.Info().Name)
+		if _, err := cc.FindResource(id); err == nil {
+			continue
+		}
+
+		if sr.Info().Status == config.PendingModification || sr.Info().Status == config.Failed {
+			before, err := redactedAttributes(sr)
+			if err != nil {
+				return nil, xerrors.Errorf("Unable to read state attributes for %s: %w", id, err)
+			}
+
+			p.Resources = append(p.Resources, PlanResource{Resource: id, Type: sr.Info().Type, Name: sr.Info().Name, Action: PlanReplace, Before: before, Reason: reasonNotInConfig})
+		}
+	}
+
+	// the image cache is created by the first Apply, subsequent applies
+	// only update the cache when it needs to be attached to new networks
+	cache := PlanResource{
+		Resource: fmt.Sprintf("%s.docker-cache", config.TypeImageCache),
+		Type:     config.TypeImageCache,
+		Name:     "docker-cache",
+		Action:   PlanCreate,
+		Reason:   reasonImageCache,
+	}
+	if sr, err := sc.FindResource(cache.Resource); err == nil {
+		cache.Action = PlanNone
+		cache.Reason = ""
+
+		if added := addedCacheNetworks(cc, sr); len(added) > 0 {
+			cache.Action = PlanUpdate
+			cache.Reason = fmt.Sprintf("%s %s", reasonImageCache, strings.Join(added, ", "))
+		}
+	}
+
+	p.Resources = append(p.Resources, cache)
+
+	return p, nil
+}
+
+// addedCacheNetworks returns the networks in the configuration which the image
+// cache in the state sr is not attached to
+func addedCacheNetworks(cc *config.Config, sr config.Resource) []string {
+	added := []string{}
+
+	ic, ok := sr.(*config.ImageCache)
+	if !ok {
+		return added
+	}
+
+	r, err := cc.FindResource(fmt.Sprintf("%s.%s", sr.Info().Type, sr.Info().Name))
+	if err != nil {
+		return added
+	}
+
+	for _, d := range r.Info().DependsOn {
+		if !strings.HasPrefix(d, "network.") {
+			continue
+		}
+
+		attached := false
+		for _, n := range ic.Networks {
+			if n == d {
+				attached = true
+				break
+			}
+		}
+
+		if !attached {
+			added = append(added, d)
+		}
+	}
+
+	return added
+}
+
+// String returns a human readable summary of the plan
+func (p *Plan) String() string {
+	sb := strings.Builder{}
+
+	for _, r := range p.Resources {
+		if r.Reason != "" {
+			sb.WriteString(fmt.Sprintf("%-8s %s (%s)\n", r.Action, r.Resource, r.Reason))
+		} else {
+			sb.WriteString(fmt.Sprintf("%-8s %s\n", r.Action, r.Resource))
+		}
+
+		keys := []string{}
+		for k := range r.Changes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			c := r.Changes[k]
+			sb.WriteString(fmt.Sprintf("         %s: %v -> %v\n", k, c.Before, c.After))
+		}
+	}
+
+	return sb.String()
+}
+
+// planAttributes returns the attributes for the resource as a map, fields
+// which are managed by Shipyard such as the status are not included
+func planAttributes(r config.Resource) (map[string]interface{}, error) {
+	d, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := map[string]interface{}{}
+	err = json.Unmarshal(d, &attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range []string{"status", "attempts", "depends_on", "file", "line"} {
+		delete(attrs, k)
+	}
+
+	// fields tagged with state are computed when the resource is created
+	t := reflect.TypeOf(r).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Tag.Get("state") == "true" {
+			delete(attrs, strings.Split(f.Tag.Get("json"), ",")[0])
+		}
+	}
+
+	return attrs, nil
+}
+
+// redactedAttributes returns the attributes for the resource where the values
+// of fields tagged sensitive have been replaced so they can be safely displayed
+func redactedAttributes(r config.Resource) (map[string]interface{}, error) {
+	rr, err := config.RedactedResource(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return planAttributes(rr)
+}
+
+// planChanges returns the attributes which differ between before and after, the
+// values of the changes are taken from shownBefore and shownAfter so that
+// sensitive values are never included in the plan
+func planChanges(before, after, shownBefore, shownAfter map[string]interface{}) map[string]PlanChange {
+	changes := map[string]PlanChange{}
+
+	for k, v := range after {
+		if !reflect.DeepEqual(before[k], v) {
+			changes[k] = PlanChange{Before: shownBefore[k], After: shownAfter[k]}
+		}
+	}
+
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			changes[k] = PlanChange{Before: shownBefore[k], After: nil}
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,265 @@
+package shipyard
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	clientMocks "github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupPlan(t *testing.T, state string) (Engine, string, func()) {
+	e, _, cleanup := setupTestsWithState(nil, state)
+
+	dir := t.TempDir()
+	bp := filepath.Join(dir, "blueprint.hcl")
+	err := ioutil.WriteFile(bp, []byte(planBlueprint), os.ModePerm)
+	assert.NoError(t, err)
+
+	return e, bp, cleanup
+}
+
+func TestPlanReturnsCreateWhenNoState(t *testing.T) {
+	e, bp, cleanup := setupPlan(t, "")
+	defer cleanup()
+
+	p, err := e.Plan(bp, nil, "")
+	assert.NoError(t, err)
+	assert.Len(t, p.Resources, 3)
+
+	assert.Equal(t, "network.onprem", p.Resources[0].Resource)
+	assert.Equal(t, PlanCreate, p.Resources[0].Action)
+	assert.Nil(t, p.Resources[0].Before)
+	assert.Equal(t, "10.6.0.0/16", p.Resources[0].After["subnet"])
+}
+
+func TestPlanReturnsChangedAttributes(t *testing.T) {
+	e, bp, cleanup := setupPlan(t, planState)
+	defer cleanup()
+
+	p, err := e.Plan(bp, nil, "")
+	assert.NoError(t, err)
+	assert.Len(t, p.Resources, 3)
+
+	assert.Equal(t, "network.onprem", p.Resources[0].Resource)
+	assert.Equal(t, PlanNone, p.Resources[0].Action)
+	assert.Empty(t, p.Resources[0].Changes)
+
+	// changes to existing resources are not applied so the action is none
+	assert.Equal(t, "container.consul", p.Resources[1].Resource)
+	assert.Equal(t, PlanNone, p.Resources[1].Action)
+	assert.Equal(t, reasonNotApplied, p.Resources[1].Reason)
+	assert.Len(t, p.Resources[1].Changes, 1)
+	assert.Equal(t, map[string]interface{}{"name": "consul:1.8.0"}, p.Resources[1].Changes["image"].Before)
+	assert.Equal(t, map[string]interface{}{"name": "consul:1.8.1"}, p.Resources[1].Changes["image"].After)
+}
+
+func TestPlanReturnsReplaceWhenTainted(t *testing.T) {
+	e, bp, cleanup := setupPlan(t, strings.Replace(planState, `"status": "applied",
+      "type": "container"`, `"status": "pending_modification",
+      "type": "container"`, 1))
+	defer cleanup()
+
+	p, err := e.Plan(bp, nil, "")
+	assert.NoError(t, err)
+	assert.Equal(t, PlanReplace, p.Resources[1].Action)
+}
+
+func TestPlanReturnsReplaceWithReasonWhenUnhealthy(t *testing.T) {
+	e, bp, cleanup := setupPlan(t, strings.Replace(planState, `"blueprint": null`, `"blueprint": {"recreate_unhealthy": true}`, 1))
+	defer cleanup()
+
+	mt := &clientMocks.MockContainerTasks{}
+	mt.On("FindContainerIDs", "consul", config.TypeContainer).Return([]string{"abc"}, nil)
+	mt.On("ContainerInfo", "abc").Return(types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			State: &types.ContainerState{Health: &types.Health{Status: types.Unhealthy}},
+		},
+	}, nil)
+	e.(*EngineImpl).clients.ContainerTasks = mt
+
+	p, err := e.Plan(bp, nil, "")
+	assert.NoError(t, err)
+	assert.Equal(t, PlanReplace, p.Resources[1].Action)
+	assert.Equal(t, "unhealthy, will recreate", p.Resources[1].Reason)
+	assert.Contains(t, p.String(), "container.consul (unhealthy, will recreate)")
+}
+
+func TestPlanReturnsCreateForImageCacheWhenNoState(t *testing.T) {
+	e, bp, cleanup := setupPlan(t, "")
+	defer cleanup()
+
+	p, err := e.Plan(bp, nil, "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "image_cache.docker-cache", p.Resources[2].Resource)
+	assert.Equal(t, PlanCreate, p.Resources[2].Action)
+}
+
+func TestPlanReturnsNoneForImageCacheAttachedToNetworks(t *testing.T) {
+	e, bp, cleanup := setupPlan(t, strings.Replace(planState, `"resources": [`, `"resources": [
+	{
+      "name": "docker-cache",
+      "status": "applied",
+      "type": "image_cache",
+      "networks": ["network.onprem"]
+	},`, 1))
+	defer cleanup()
+
+	p, err := e.Plan(bp, nil, "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "image_cache.docker-cache", p.Resources[2].Resource)
+	assert.Equal(t, PlanNone, p.Resources[2].Action)
+	assert.Empty(t, p.Resources[2].Reason)
+}
+
+func TestPlanReturnsUpdateForImageCacheWithNewNetworks(t *testing.T) {
+	e, bp, cleanup := setupPlan(t, strings.Replace(planState, `"resources": [`, `"resources": [
+	{
+      "name": "docker-cache",
+      "status": "applied",
+      "type": "image_cache",
+      "networks": []
+	},`, 1))
+	defer cleanup()
+
+	p, err := e.Plan(bp, nil, "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "image_cache.docker-cache", p.Resources[2].Resource)
+	assert.Equal(t, PlanUpdate, p.Resources[2].Action)
+	assert.Equal(t, "attach to networks network.onprem", p.Resources[2].Reason)
+}
+
+func TestPlanReturnsReplaceForFailedResourceNotInConfig(t *testing.T) {
+	e, bp, cleanup := setupPlan(t, failedState)
+	defer cleanup()
+
+	p, err := e.Plan(bp, nil, "")
+	assert.NoError(t, err)
+	assert.Len(t, p.Resources, 4)
+
+	assert.Equal(t, "network.dc1", p.Resources[2].Resource)
+	assert.Equal(t, PlanReplace, p.Resources[2].Action)
+	assert.Equal(t, reasonNotInConfig, p.Resources[2].Reason)
+}
+
+func TestPlanSerializesToJSONWithStableFields(t *testing.T) {
+	e, bp, cleanup := setupPlan(t, planState)
+	defer cleanup()
+
+	p, err := e.Plan(bp, nil, "")
+	assert.NoError(t, err)
+
+	d, err := json.Marshal(p)
+	assert.NoError(t, err)
+
+	out := map[string]interface{}{}
+	err = json.Unmarshal(d, &out)
+	assert.NoError(t, err)
+
+	assert.Equal(t, PlanFormatVersion, out["format_version"])
+
+	r := out["resources"].([]interface{})[1].(map[string]interface{})
+	assert.Equal(t, "container.consul", r["resource"])
+	assert.Equal(t, "container", r["type"])
+	assert.Equal(t, "consul", r["name"])
+	assert.Equal(t, "none", r["action"])
+	assert.Contains(t, r["changes"], "image")
+}
+
+func TestPlanRedactsSensitiveValues(t *testing.T) {
+	e, _, cleanup := setupTestsWithState(nil, planSensitiveState)
+	defer cleanup()
+
+	dir := t.TempDir()
+	bp := filepath.Join(dir, "blueprint.hcl")
+	err := ioutil.WriteFile(bp, []byte(planSensitiveBlueprint), os.ModePerm)
+	assert.NoError(t, err)
+
+	p, err := e.Plan(bp, nil, "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "container.consul", p.Resources[0].Resource)
+	assert.Equal(t, map[string]interface{}{"TOKEN": "(sensitive)"}, p.Resources[0].Changes["env_var"].Before)
+	assert.Equal(t, map[string]interface{}{"TOKEN": "(sensitive)"}, p.Resources[0].Changes["env_var"].After)
+	assert.Equal(t, map[string]interface{}{"TOKEN": "(sensitive)"}, p.Resources[0].After["env_var"])
+
+	d, err := json.Marshal(p)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(d), "old-secret")
+	assert.NotContains(t, string(d), "new-secret")
+	assert.NotContains(t, p.String(), "old-secret")
+	assert.NotContains(t, p.String(), "new-secret")
+}
+
+var planBlueprint = `
+network "onprem" {
+  subnet = "10.6.0.0/16"
+}
+
+container "consul" {
+  image {
+    name = "consul:1.8.1"
+  }
+
+  network {
+    name = "network.onprem"
+  }
+}
+`
+
+var planState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "onprem",
+      "status": "applied",
+      "subnet": "10.6.0.0/16",
+      "type": "network"
+	},
+	{
+      "name": "consul",
+      "status": "applied",
+      "type": "container",
+      "image": {"name": "consul:1.8.0"},
+      "networks": [{"name": "network.onprem"}]
+	}
+  ]
+}
+`
+
+var planSensitiveBlueprint = `
+container "consul" {
+  image {
+    name = "consul:1.8.1"
+  }
+
+  env_var = {
+    TOKEN = "new-secret"
+  }
+}
+`
+
+var planSensitiveState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "consul",
+      "status": "applied",
+      "type": "container",
+      "image": {"name": "consul:1.8.1"},
+      "env_var": {"TOKEN": "old-secret"}
+	}
+  ]
+}
+`
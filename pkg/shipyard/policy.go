@@ -0,0 +1,127 @@
+package shipyard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// PolicyViolation describes a resource which breaks a policy rule
+type PolicyViolation struct {
+	// Resource is the id of the resource e.g. container.consul
+	Resource string
+	// Rule is the name of the rule which was violated
+	Rule string
+	// Message describes the violation
+	Message string
+}
+
+func (p PolicyViolation) String() string {
+	return fmt.Sprintf("%s violates rule %s: %s", p.Resource, p.Rule, p.Message)
+}
+
+// Policy evaluates the configuration at path against the rules in the policy file
+// at policyPath, no resources are created. The violations for every resource are
+// returned, an error is only returned when the configuration or policy can not be parsed.
+func (e *EngineImpl) Policy(path, policyPath string, variables map[string]string, variablesFile string) ([]PolicyViolation, error) {
+	p, err := config.ParsePolicyFile(policyPath)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to parse policy: %w", err)
+	}
+
+	cc, err := parseConfig(path, variables, variablesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := []PolicyViolation{}
+
+	for _, r := range cc.Resources {
+		if r.Info().Status == config.Disabled {
+			continue
+		}
+
+		for _, rule := range p.Rules {
+			msg := checkPolicyRule(r, rule)
+			if msg == "" {
+				continue
+			}
+
+			violations = append(violations, PolicyViolation{
+				Resource: fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name),
+				Rule:     rule.Name,
+				Message:  msg,
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+// checkPolicyRule returns a message describing the violation when the
+// resource does not satisfy the rule, or an empty string when it does.
+// Rules only apply to resources which run user defined images.
+func checkPolicyRule(r config.Resource, rule config.PolicyRule) string {
+	var privileged bool
+	var resources *config.Resources
+	var image string
+
+	switch v := r.(type) {
+	case *config.Container:
+		privileged = v.Privileged
+		resources = v.Resources
+		if v.Image != nil {
+			image = v.Image.Name
+		}
+	case *config.Sidecar:
+		privileged = v.Privileged
+		resources = v.Resources
+		image = v.Image.Name
+	default:
+		return ""
+	}
+
+	switch rule.Check {
+	case config.PolicyNoPrivileged:
+		if privileged {
+			return "container runs in privileged mode"
+		}
+	case config.PolicyMemoryLimit:
+		if resources == nil || resources.Memory == 0 {
+			return "container does not set a memory limit"
+		}
+	case config.PolicyAllowedRegistries:
+		// containers built from a Dockerfile do not have an image
+		if image == "" {
+			return ""
+		}
+
+		reg := imageRegistry(image)
+		for _, a := range rule.Values {
+			if reg == a {
+				return ""
+			}
+		}
+
+		return fmt.Sprintf("image %s is from registry %s which is not in the allowed registries %s", image, reg, strings.Join(rule.Values, ", "))
+	}
+
+	return ""
+}
+
+// imageRegistry returns the registry for an image reference,
+// images without a registry are pulled from docker.io
+func imageRegistry(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 {
+		return "docker.io"
+	}
+
+	if strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost" {
+		return parts[0]
+	}
+
+	return "docker.io"
+}
@@ -0,0 +1,103 @@
+package shipyard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupPolicy(t *testing.T, blueprint, policy string) (string, string, func()) {
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+
+	bp := filepath.Join(dir, "blueprint.hcl")
+	err = ioutil.WriteFile(bp, []byte(blueprint), os.ModePerm)
+	assert.NoError(t, err)
+
+	pp := filepath.Join(dir, "policy.hcl")
+	err = ioutil.WriteFile(pp, []byte(policy), os.ModePerm)
+	assert.NoError(t, err)
+
+	return bp, pp, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestPolicyReturnsViolations(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	bp, pp, cleanupPolicy := setupPolicy(t, policyBlueprint, policyRules)
+	defer cleanupPolicy()
+
+	v, err := e.Policy(bp, pp, nil, "")
+	assert.NoError(t, err)
+	assert.Len(t, v, 3)
+
+	assert.Equal(t, "container.privileged", v[0].Resource)
+	assert.Equal(t, "no_privileged", v[0].Rule)
+	assert.Equal(t, "container.privileged", v[1].Resource)
+	assert.Equal(t, "memory", v[1].Rule)
+	assert.Equal(t, "container.privileged", v[2].Resource)
+	assert.Equal(t, "registries", v[2].Rule)
+}
+
+func TestPolicyReturnsErrorWhenPolicyInvalid(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	bp, pp, cleanupPolicy := setupPolicy(t, policyBlueprint, `
+rule "unknown" {
+  check = "no_cats"
+}
+`)
+	defer cleanupPolicy()
+
+	_, err := e.Policy(bp, pp, nil, "")
+	assert.Error(t, err)
+}
+
+func TestImageRegistryReturnsRegistry(t *testing.T) {
+	assert.Equal(t, "docker.io", imageRegistry("consul:1.8.1"))
+	assert.Equal(t, "docker.io", imageRegistry("hashicorp/consul:1.8.1"))
+	assert.Equal(t, "ghcr.io", imageRegistry("ghcr.io/shipyard-run/consul:1.8.1"))
+	assert.Equal(t, "localhost:5000", imageRegistry("localhost:5000/consul"))
+}
+
+var policyBlueprint = `
+container "compliant" {
+  image {
+    name = "ghcr.io/shipyard-run/consul:1.8.1"
+  }
+
+  resources {
+    memory = 512
+  }
+}
+
+container "privileged" {
+  image {
+    name = "consul:1.8.1"
+  }
+
+  privileged = true
+}
+`
+
+var policyRules = `
+rule "no_privileged" {
+  check = "no_privileged"
+}
+
+rule "memory" {
+  check = "memory_limit"
+}
+
+rule "registries" {
+  check  = "allowed_registries"
+  values = ["ghcr.io"]
+}
+`
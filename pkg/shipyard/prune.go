@@ -0,0 +1,104 @@
+package shipyard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// Prune finds the Docker containers and networks created by Shipyard in the
+// active workspace which do not belong to a resource in the state, for example
+// objects left behind when Shipyard crashed. The names of the orphaned objects
+// are returned, when force is set the objects are also removed.
+func (e *EngineImpl) Prune(force bool) ([]string, error) {
+	// objects created by an apply which is in progress are not yet in the
+	// state, the state is locked so they are not removed
+	if force {
+		err := config.LockStateFile(e.stateLockPath())
+		if err != nil {
+			return nil, err
+		}
+		defer config.UnlockStateFile(e.stateLockPath())
+	}
+
+	_, err := e.readConfig("", nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]bool{}
+	for _, r := range e.config.Resources {
+		ids[fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name)] = true
+	}
+
+	// objects created by Shipyard are labeled with the resource and workspace
+	args := filters.NewArgs()
+	args.Add("label", fmt.Sprintf("%s=%s", clients.LabelWorkspace, e.currentWorkspace()))
+
+	cl, err := e.clients.Docker.ContainerList(context.Background(), types.ContainerListOptions{Filters: args, All: true})
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to list containers: %w", err)
+	}
+
+	orphans := []string{}
+	for _, c := range cl {
+		if ids[c.Labels[clients.LabelResourceID]] || len(c.Names) == 0 {
+			continue
+		}
+
+		// the image cache is shared by all workspaces
+		if c.Labels[clients.LabelType] == string(config.TypeImageCache) {
+			continue
+		}
+
+		name := strings.TrimPrefix(c.Names[0], "/")
+		orphans = append(orphans, name)
+
+		if !force {
+			continue
+		}
+
+		e.log.Info("Removing orphaned container", "name", name, "id", c.ID)
+
+		err := e.clients.ContainerTasks.RemoveContainer(c.ID, true)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to remove container %s: %w", name, err)
+		}
+	}
+
+	// networks are removed after the containers which may be attached to them
+	nl, err := e.clients.Docker.NetworkList(context.Background(), types.NetworkListOptions{Filters: args})
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to list networks: %w", err)
+	}
+
+	for _, n := range nl {
+		if ids[n.Labels[clients.LabelResourceID]] {
+			continue
+		}
+
+		orphans = append(orphans, n.Name)
+
+		if !force {
+			continue
+		}
+
+		e.log.Info("Removing orphaned network", "name", n.Name, "id", n.ID)
+
+		err := e.clients.Docker.NetworkRemove(context.Background(), n.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to remove network %s: %w", n.Name, err)
+		}
+	}
+
+	sort.Strings(orphans)
+
+	return orphans, nil
+}
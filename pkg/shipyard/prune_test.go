@@ -0,0 +1,91 @@
+package shipyard
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	clientMocks "github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupPruneTests(t *testing.T) (Engine, *clientMocks.MockDocker, *clientMocks.MockContainerTasks, func()) {
+	e, _, cleanup := setupTestsWithState(nil, pauseState)
+
+	md := &clientMocks.MockDocker{}
+	md.On("ContainerList", mock.Anything, mock.Anything).Return([]types.Container{
+		{ID: "1", Names: []string{"/consul.container.shipyard.run"}, Labels: clients.ResourceLabels("container.consul", config.TypeContainer, utils.DefaultWorkspace)},
+		{ID: "2", Names: []string{"/server.k3s.k8s-cluster.shipyard.run"}, Labels: clients.ResourceLabels("k8s_cluster.k3s", config.TypeK8sCluster, utils.DefaultWorkspace)},
+		{ID: "3", Names: []string{"/docker-cache.image-cache.shipyard.run"}, Labels: clients.ResourceLabels("image_cache.docker-cache", config.TypeImageCache, utils.DefaultWorkspace)},
+		{ID: "4", Names: []string{"/vault.container.shipyard.run"}, Labels: clients.ResourceLabels("container.vault", config.TypeContainer, utils.DefaultWorkspace)},
+	}, nil)
+	md.On("NetworkList", mock.Anything, mock.Anything).Return([]types.NetworkResource{
+		{ID: "5", Name: "cloud", Labels: clients.ResourceLabels("network.cloud", config.TypeNetwork, utils.DefaultWorkspace)},
+	}, nil)
+	md.On("NetworkRemove", mock.Anything, mock.Anything).Return(nil)
+
+	mt := &clientMocks.MockContainerTasks{}
+	mt.On("RemoveContainer", mock.Anything, mock.Anything).Return(nil)
+
+	e.(*EngineImpl).clients.Docker = md
+	e.(*EngineImpl).clients.ContainerTasks = mt
+
+	return e, md, mt, cleanup
+}
+
+func TestPruneReturnsObjectsNotInState(t *testing.T) {
+	e, md, mt, cleanup := setupPruneTests(t)
+	defer cleanup()
+
+	o, err := e.Prune(false)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"cloud", "vault.container.shipyard.run"}, o)
+	mt.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything)
+	md.AssertNotCalled(t, "NetworkRemove", mock.Anything, mock.Anything)
+}
+
+func TestPruneFiltersByWorkspaceLabel(t *testing.T) {
+	e, md, _, cleanup := setupPruneTests(t)
+	defer cleanup()
+
+	_, err := e.Prune(false)
+	assert.NoError(t, err)
+
+	args := filters.NewArgs()
+	args.Add("label", "run.shipyard.workspace=default")
+
+	md.AssertCalled(t, "ContainerList", mock.Anything, types.ContainerListOptions{Filters: args, All: true})
+	md.AssertCalled(t, "NetworkList", mock.Anything, types.NetworkListOptions{Filters: args})
+}
+
+func TestPruneWithForceRemovesOrphans(t *testing.T) {
+	e, md, mt, cleanup := setupPruneTests(t)
+	defer cleanup()
+
+	_, err := e.Prune(true)
+	assert.NoError(t, err)
+
+	mt.AssertNumberOfCalls(t, "RemoveContainer", 1)
+	mt.AssertCalled(t, "RemoveContainer", "4", true)
+	md.AssertCalled(t, "NetworkRemove", mock.Anything, "5")
+}
+
+func TestPruneWithForceReturnsErrorWhenStateLocked(t *testing.T) {
+	e, md, mt, cleanup := setupPruneTests(t)
+	defer cleanup()
+
+	err := config.LockState()
+	assert.NoError(t, err)
+	defer config.UnlockState()
+
+	_, err = e.Prune(true)
+	assert.IsType(t, config.StateLockedError{}, err)
+
+	md.AssertNotCalled(t, "ContainerList", mock.Anything, mock.Anything)
+	mt.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything)
+}
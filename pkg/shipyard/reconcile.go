@@ -0,0 +1,101 @@
+package shipyard
+
+import (
+	"time"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+)
+
+// Reconcile applies the configuration at path every interval until
+// StopReconcile is called. Resources which no longer match a live object, for
+// example a container which has been removed, are recreated on each pass,
+// all other resources are left unchanged.
+func (e *EngineImpl) Reconcile(path string, interval time.Duration, variables map[string]string, variablesFile string) error {
+	e.sync.Lock()
+	if e.stopReconcile == nil {
+		e.stopReconcile = make(chan struct{})
+	}
+	stop := e.stopReconcile
+	e.sync.Unlock()
+
+	// the channel is closed by StopReconcile, remove it so that the next
+	// call to Reconcile starts a new loop rather than returning immediately
+	defer func() {
+		e.sync.Lock()
+		defer e.sync.Unlock()
+
+		if e.stopReconcile == stop {
+			e.stopReconcile = nil
+		}
+	}()
+
+	e.log.Info("Starting reconcile loop", "path", path, "interval", interval)
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		_, err := e.ApplyWithOptions([]string{path}, ApplyOptions{Variables: variables, VariablesFile: variablesFile, RecreateDrifted: true})
+		if err != nil {
+			// keep reconciling, the next pass may succeed
+			e.log.Error("Unable to reconcile resources", "error", err)
+		}
+
+		select {
+		case <-stop:
+			e.log.Info("Stopping reconcile loop")
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// StopReconcile stops a running reconcile loop after the current pass, when no
+// loop is running the next call to Reconcile returns after its first pass
+func (e *EngineImpl) StopReconcile() {
+	e.sync.Lock()
+	defer e.sync.Unlock()
+
+	if e.stopReconcile == nil {
+		e.stopReconcile = make(chan struct{})
+	}
+
+	select {
+	case <-e.stopReconcile:
+		// already stopped
+	default:
+		close(e.stopReconcile)
+	}
+}
+
+// markDriftedResources sets the status of applied resources which can no longer
+// be found to PendingModification so that they are recreated
+func (e *EngineImpl) markDriftedResources() {
+	for _, r := range e.config.Resources {
+		if r.Info().Status != config.PendingUpdate {
+			continue
+		}
+
+		switch r.Info().Type {
+		case config.TypeContainer, config.TypeSidecar, config.TypeK8sCluster, config.TypeNomadCluster, config.TypeNetwork:
+		default:
+			continue
+		}
+
+		p := e.getProvider(r, e.clients)
+		if p == nil {
+			continue
+		}
+
+		ids, err := p.Lookup()
+		if err != nil {
+			e.log.Warn("Unable to lookup resource", "ref", r.Info().Name, "type", r.Info().Type, "error", err)
+			continue
+		}
+
+		if len(ids) == 0 {
+			e.log.Info("Resource has drifted from the configuration, it will be recreated", "ref", r.Info().Name, "type", r.Info().Type)
+			r.Info().Status = config.PendingModification
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package shipyard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/providers"
+	"github.com/shipyard-run/shipyard/pkg/providers/mocks"
+	assert "github.com/stretchr/testify/require"
+)
+
+// setupDriftedContainer returns an engine where the container in the state
+// can no longer be found
+func setupDriftedContainer(t *testing.T) (Engine, *[]*mocks.MockProvider, func()) {
+	e, _, cleanup := setupTestsWithState(nil, driftState)
+
+	mp := &[]*mocks.MockProvider{}
+	e.(*EngineImpl).getProvider = func(c config.Resource, cc *Clients) providers.Provider {
+		lock.Lock()
+		defer lock.Unlock()
+
+		m := mocks.New(c)
+		m.On("Create").Return(nil)
+		m.On("Destroy").Return(nil)
+		m.On("Lookup").Return([]string{}, nil)
+
+		*mp = append(*mp, m)
+		return m
+	}
+
+	return e, mp, cleanup
+}
+
+func TestApplyRecreatesDriftedResourcesWhenEnabled(t *testing.T) {
+	e, mp, cleanup := setupDriftedContainer(t)
+	defer cleanup()
+
+	_, err := e.ApplyWithOptions([]string{""}, ApplyOptions{RecreateDrifted: true})
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+	testAssertMethodCalled(t, mp, "Create", 2) // ImageCache is always created
+}
+
+func TestApplyDoesNotRecreateDriftedResourcesByDefault(t *testing.T) {
+	e, mp, cleanup := setupDriftedContainer(t)
+	defer cleanup()
+
+	_, err := e.ApplyWithOptions([]string{""}, ApplyOptions{})
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Lookup", 0)
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+}
+
+func TestReconcileReturnsWhenStopped(t *testing.T) {
+	e, mp, cleanup := setupDriftedContainer(t)
+	defer cleanup()
+
+	e.StopReconcile()
+
+	err := e.Reconcile("", time.Hour, nil, "")
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+}
+
+func TestReconcileCanBeRestartedAfterStop(t *testing.T) {
+	e, _, cleanup := setupDriftedContainer(t)
+	defer cleanup()
+
+	e.StopReconcile()
+
+	err := e.Reconcile("", time.Hour, nil, "")
+	assert.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- e.Reconcile("", time.Hour, nil, "")
+	}()
+
+	// the previous stop does not stop the new loop
+	select {
+	case <-done:
+		t.Fatal("Reconcile returned before it was stopped")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	e.StopReconcile()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Reconcile did not return after it was stopped")
+	}
+}
+
+var driftState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "consul",
+      "status": "applied",
+      "type": "container",
+      "image": {"name": "consul:1.8.1"}
+	}
+  ]
+}
+`
@@ -0,0 +1,117 @@
+package shipyard
+
+import (
+	"github.com/shipyard-run/shipyard/pkg/config"
+)
+
+const (
+	reasonRebuild = "build context changed, will rebuild"
+	reasonVersion = "version changed, will recreate"
+	reasonValues  = "values changed, will reinstall"
+	reasonPrune   = "manifests changed, will reapply and prune removed objects"
+)
+
+// replaceReason returns the reason the resource r needs to be replaced when values
+// computed at creation and stored in the state resource sr no longer match the
+// configuration, or an empty string when the resource does not need to be replaced
+func (e *EngineImpl) replaceReason(r, sr config.Resource) string {
+	switch c := r.(type) {
+	case *config.Container:
+		s, ok := sr.(*config.Container)
+		if ok && e.buildChanged(c, s.BuildChecksum) {
+			return reasonRebuild
+		}
+	case *config.K8sCluster:
+		s, ok := sr.(*config.K8sCluster)
+		if ok && s.ResolvedVersion != "" && c.RequestedVersion() != s.ResolvedVersion {
+			return reasonVersion
+		}
+	case *config.Helm:
+		s, ok := sr.(*config.Helm)
+		if ok && e.valuesChanged(c, s.ValuesChecksum) {
+			return reasonValues
+		}
+	}
+
+	return ""
+}
+
+// updateReason returns the reason the resource r needs to be updated in place
+// when values stored in the state resource sr no longer match the configuration,
+// or an empty string when the resource does not need to be updated. Resources
+// which are updated in place are created again without first being destroyed.
+func (e *EngineImpl) updateReason(r, sr config.Resource) string {
+	switch c := r.(type) {
+	case *config.K8sConfig:
+		s, ok := sr.(*config.K8sConfig)
+		if ok && c.Prune && e.manifestsChanged(c, s.ManifestChecksum) {
+			return reasonPrune
+		}
+	}
+
+	return ""
+}
+
+// buildChanged returns true when the container is built from a Dockerfile and
+// the build context or arguments no longer match the checksum of the last build
+func (e *EngineImpl) buildChanged(c *config.Container, checksum string) bool {
+	if c.Build == nil || checksum == "" {
+		return false
+	}
+
+	current, err := c.Build.Checksum()
+	if err != nil {
+		e.log.Warn("Unable to checksum build context", "ref", c.Name, "error", err)
+		return false
+	}
+
+	return current != checksum
+}
+
+// valuesChanged returns true when the values files or string values for the
+// Helm chart no longer match the checksum of the values it was installed with
+func (e *EngineImpl) valuesChanged(h *config.Helm, checksum string) bool {
+	if checksum == "" {
+		return false
+	}
+
+	current, err := h.Checksum()
+	if err != nil {
+		e.log.Warn("Unable to checksum Helm values", "ref", h.Name, "error", err)
+		return false
+	}
+
+	return current != checksum
+}
+
+// manifestsChanged returns true when the Kubernetes manifests or Vars no longer
+// match the checksum of the manifests when they were last applied
+func (e *EngineImpl) manifestsChanged(k *config.K8sConfig, checksum string) bool {
+	if checksum == "" {
+		return false
+	}
+
+	current, err := k.Checksum()
+	if err != nil {
+		e.log.Warn("Unable to checksum Kubernetes config", "ref", k.Name, "error", err)
+		return false
+	}
+
+	return current != checksum
+}
+
+// markReplacedResources sets the status of applied resources which need to be
+// replaced to PendingModification so that they are recreated
+func (e *EngineImpl) markReplacedResources() {
+	for _, r := range e.config.Resources {
+		if r.Info().Status != config.PendingUpdate {
+			continue
+		}
+
+		// the state values are merged into the configuration
+		if reason := e.replaceReason(r, r); reason != "" {
+			e.log.Info("Resource will be recreated", "ref", r.Info().Name, "type", r.Info().Type, "reason", reason)
+			r.Info().Status = config.PendingModification
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package shipyard
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupBuildTest(t *testing.T) (*EngineImpl, *config.Container) {
+	dir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine"), 0644)
+	assert.NoError(t, err)
+
+	co := config.NewContainer("app")
+	co.Build = &config.Build{Context: dir}
+	co.Status = config.PendingUpdate
+
+	co.BuildChecksum, err = co.Build.Checksum()
+	assert.NoError(t, err)
+
+	c := config.New()
+	c.AddResource(co)
+
+	return &EngineImpl{config: c, log: hclog.NewNullLogger()}, co
+}
+
+func TestMarkRebuiltContainersIgnoresUnchangedBuild(t *testing.T) {
+	e, co := setupBuildTest(t)
+
+	e.markReplacedResources()
+
+	assert.Equal(t, config.PendingUpdate, co.Status)
+}
+
+func TestMarkRebuiltContainersMarksChangedBuild(t *testing.T) {
+	e, co := setupBuildTest(t)
+
+	err := ioutil.WriteFile(filepath.Join(co.Build.Context, "Dockerfile"), []byte("FROM ubuntu"), 0644)
+	assert.NoError(t, err)
+
+	e.markReplacedResources()
+
+	assert.Equal(t, config.PendingModification, co.Status)
+}
+
+func TestMarkRebuiltContainersIgnoresContainersNotBuilt(t *testing.T) {
+	e, co := setupBuildTest(t)
+	co.BuildChecksum = ""
+
+	e.markReplacedResources()
+
+	assert.Equal(t, config.PendingUpdate, co.Status)
+}
+
+func TestMarkReplacedResourcesMarksClusterWithChangedVersion(t *testing.T) {
+	k := config.NewK8sCluster("k3s")
+	k.Status = config.PendingUpdate
+	k.Version = "v1.20.0"
+	k.ResolvedVersion = config.DefaultK8sVersion
+
+	c := config.New()
+	c.AddResource(k)
+
+	e := &EngineImpl{config: c, log: hclog.NewNullLogger()}
+	e.markReplacedResources()
+
+	assert.Equal(t, config.PendingModification, k.Status)
+}
+
+func TestMarkReplacedResourcesIgnoresClusterWithDefaultVersion(t *testing.T) {
+	k := config.NewK8sCluster("k3s")
+	k.Status = config.PendingUpdate
+	k.ResolvedVersion = config.DefaultK8sVersion
+
+	c := config.New()
+	c.AddResource(k)
+
+	e := &EngineImpl{config: c, log: hclog.NewNullLogger()}
+	e.markReplacedResources()
+
+	assert.Equal(t, config.PendingUpdate, k.Status)
+}
+
+func TestMarkReplacedResourcesMarksHelmWithChangedValues(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "values.yaml")
+	err := ioutil.WriteFile(f, []byte("replicas: 1"), 0644)
+	assert.NoError(t, err)
+
+	h := config.NewHelm("consul")
+	h.Status = config.PendingUpdate
+	h.ValuesFiles = []string{f}
+
+	h.ValuesChecksum, err = h.Checksum()
+	assert.NoError(t, err)
+
+	c := config.New()
+	c.AddResource(h)
+
+	e := &EngineImpl{config: c, log: hclog.NewNullLogger()}
+	e.markReplacedResources()
+	assert.Equal(t, config.PendingUpdate, h.Status)
+
+	err = ioutil.WriteFile(f, []byte("replicas: 3"), 0644)
+	assert.NoError(t, err)
+
+	e.markReplacedResources()
+	assert.Equal(t, config.PendingModification, h.Status)
+}
+
+func TestUpdateReasonReturnsPruneForK8sConfigWithChangedManifests(t *testing.T) {
+	dir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "app.yaml"), []byte("kind: Service"), 0644)
+	assert.NoError(t, err)
+
+	k := config.NewK8sConfig("app")
+	k.Status = config.PendingUpdate
+	k.Paths = []string{dir}
+
+	k.ManifestChecksum, err = k.Checksum()
+	assert.NoError(t, err)
+
+	c := config.New()
+	c.AddResource(k)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "app.yaml"), []byte("kind: Deployment"), 0644)
+	assert.NoError(t, err)
+
+	e := &EngineImpl{config: c, log: hclog.NewNullLogger()}
+	assert.Equal(t, "", e.updateReason(k, k))
+
+	k.Prune = true
+	assert.Equal(t, reasonPrune, e.updateReason(k, k))
+
+	// pruning re-applies the manifests, the resource is not replaced
+	e.markReplacedResources()
+	assert.Equal(t, config.PendingUpdate, k.Status)
+}
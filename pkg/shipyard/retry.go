@@ -0,0 +1,59 @@
+package shipyard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+// retryableMessages are fragments of error messages returned by the Docker,
+// Kubernetes, and HTTP clients for transient network failures. Providers do
+// not always wrap the original error so the message is checked as well as
+// the error type.
+var retryableMessages = []string{
+	"timeout",
+	"timed out",
+	"connection refused",
+	"connection reset",
+	"broken pipe",
+	"no such host",
+	"temporary failure",
+	"tls handshake",
+	"unexpected eof",
+	"too many requests",
+	"service unavailable",
+	"bad gateway",
+	"gateway timeout",
+}
+
+// isRetryable returns true when the error is caused by a transient network
+// failure or timeout, configuration and validation errors are not retryable
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+
+	var oe *net.OpError
+	if errors.As(err, &oe) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, m := range retryableMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+
+	return false
+}
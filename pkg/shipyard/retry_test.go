@@ -0,0 +1,25 @@
+package shipyard
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"golang.org/x/xerrors"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestIsRetryableReturnsTrueForNetworkErrors(t *testing.T) {
+	assert.True(t, isRetryable(&net.OpError{Op: "dial", Err: fmt.Errorf("refused")}))
+	assert.True(t, isRetryable(xerrors.Errorf("unable to pull: %w", context.DeadlineExceeded)))
+	assert.True(t, isRetryable(fmt.Errorf("Error response from daemon: Get https://registry-1.docker.io/v2/: net/http: TLS handshake timeout")))
+	assert.True(t, isRetryable(fmt.Errorf("dial tcp 127.0.0.1:6443: connect: connection refused")))
+}
+
+func TestIsRetryableReturnsFalseForOtherErrors(t *testing.T) {
+	assert.False(t, isRetryable(nil))
+	assert.False(t, isRetryable(fmt.Errorf("invalid reference format")))
+	assert.False(t, isRetryable(fmt.Errorf("Unable to parse retry backoff")))
+}
@@ -0,0 +1,160 @@
+package shipyard
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// rollbackTracker records the resources created by an apply so that they can
+// be removed if the apply fails.
+//
+// Only resources which did not exist before the apply are tracked, resources
+// which were tainted or had previously failed are destroyed and recreated by
+// the apply and can not be returned to their previous condition.
+// The image cache is never tracked, it is shared by all blueprints and is
+// recreated by every apply, rolling back leaves the cache running and
+// attached to any networks which existed before the apply.
+type rollbackTracker struct {
+	// hadState is true when a statefile existed before the apply
+	hadState bool
+	created  []config.Resource
+	mutex    sync.Mutex
+}
+
+// snapshotState copies the current state to the state backup so that it
+// can be restored if the apply fails
+func (e *EngineImpl) snapshotState() (*rollbackTracker, error) {
+	rt := &rollbackTracker{}
+
+	d, err := ioutil.ReadFile(e.statePath())
+	if os.IsNotExist(err) {
+		// remove any backup from a previous apply so it is not mistaken for this one
+		os.Remove(e.stateBackupPath())
+		return rt, nil
+	}
+
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to read state: %w", err)
+	}
+
+	err = ioutil.WriteFile(e.stateBackupPath(), d, 0644)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to write state backup: %w", err)
+	}
+
+	rt.hadState = true
+
+	return rt, nil
+}
+
+// record adds a resource which is created by the apply, resources are recorded
+// before they are created so that a create which fails part way through is
+// also rolled back
+func (rt *rollbackTracker) record(r config.Resource) {
+	if r.Info().Type == config.TypeImageCache {
+		return
+	}
+
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	rt.created = append(rt.created, r)
+}
+
+// rollback destroys the resources created by the apply and restores the state
+// which existed before the apply. A resource which can not be destroyed does
+// not stop the remaining resources being rolled back, it is kept in the state
+// as failed so that it can be removed with destroy.
+func (e *EngineImpl) rollback(rt *rollbackTracker) error {
+	e.log.Info("Apply failed, rolling back created resources", "count", len(rt.created))
+
+	derr := &DestroyError{}
+	remaining := []config.Resource{}
+
+	// resources are recorded in the order they are created, destroying in
+	// reverse order removes dependents first
+	for i := len(rt.created) - 1; i >= 0; i-- {
+		r := rt.created[i]
+
+		p := e.getProvider(r, e.clients)
+		if p == nil {
+			continue
+		}
+
+		e.log.Debug("Rolling back resource", "ref", r.Info().Name, "type", r.Info().Type)
+
+		err := e.withTimeout(r, "destroy", p.Destroy)
+		if err != nil {
+			e.log.Error("Unable to roll back resource", "ref", r.Info().Name, "type", r.Info().Type, "error", err)
+
+			derr.add(r, err)
+			remaining = append(remaining, r)
+		}
+	}
+
+	if len(remaining) == 0 {
+		return e.restoreState(rt)
+	}
+
+	// save the previous state with the resources which could not be destroyed
+	c := config.New()
+	if rt.hadState {
+		err := c.FromJSON(e.stateBackupPath())
+		if err != nil {
+			return xerrors.Errorf("Unable to read state backup: %w", err)
+		}
+	}
+
+	for _, r := range remaining {
+		r.Info().Status = config.Failed
+		c.AddResource(r)
+	}
+
+	e.config = c
+
+	err := e.saveState()
+	if err != nil {
+		return xerrors.Errorf("Unable to save state: %w", err)
+	}
+
+	return derr
+}
+
+// restoreState replaces the state with the state which existed before the
+// apply, the state is removed when there was no previous state
+func (e *EngineImpl) restoreState(rt *rollbackTracker) error {
+	if !rt.hadState {
+		e.config = config.New()
+
+		err := os.Remove(e.statePath())
+		if err != nil && !os.IsNotExist(err) {
+			return xerrors.Errorf("Unable to remove state: %w", err)
+		}
+
+		return nil
+	}
+
+	d, err := ioutil.ReadFile(e.stateBackupPath())
+	if err != nil {
+		return xerrors.Errorf("Unable to read state backup: %w", err)
+	}
+
+	err = ioutil.WriteFile(e.statePath(), d, 0644)
+	if err != nil {
+		return xerrors.Errorf("Unable to restore state: %w", err)
+	}
+
+	c := config.New()
+	err = c.FromJSON(e.statePath())
+	if err != nil {
+		return xerrors.Errorf("Unable to load restored state: %w", err)
+	}
+
+	e.config = c
+
+	return nil
+}
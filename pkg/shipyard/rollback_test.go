@@ -0,0 +1,119 @@
+package shipyard
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/providers"
+	"github.com/shipyard-run/shipyard/pkg/providers/mocks"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+// setupRollbackTests returns an engine where creating the named resource
+// fails and destroying it succeeds
+func setupRollbackTests(name, state string) (Engine, *[]*mocks.MockProvider, func()) {
+	e, mp, cleanup := setupTestsWithState(nil, state)
+
+	gp := e.(*EngineImpl).getProvider
+	e.(*EngineImpl).getProvider = func(c config.Resource, cc *Clients) providers.Provider {
+		p := gp(c, cc).(*mocks.MockProvider)
+
+		if c.Info().Name == name {
+			for _, ec := range p.ExpectedCalls {
+				if ec.Method == "Create" {
+					ec.ReturnArguments = mock.Arguments{fmt.Errorf("boom")}
+				}
+			}
+		}
+
+		return p
+	}
+
+	return e, mp, cleanup
+}
+
+func TestApplyWithRollbackDestroysCreatedResources(t *testing.T) {
+	e, mp, cleanup := setupRollbackTests("k3s", "")
+	defer cleanup()
+
+	_, err := e.ApplyWithOptions([]string{"../../examples/single_k3s_cluster"}, ApplyOptions{Rollback: true})
+	assert.Error(t, err)
+
+	// the network and the partially created cluster are destroyed, the image
+	// cache is never rolled back
+	testAssertMethodCalled(t, mp, "Destroy", 2)
+
+	destroyed := []string{}
+	for _, p := range *mp {
+		for _, c := range p.Calls {
+			if c.Method == "Destroy" {
+				destroyed = append(destroyed, p.Config().Info().Name)
+			}
+		}
+	}
+
+	assert.ElementsMatch(t, []string{"cloud", "k3s"}, destroyed)
+}
+
+func TestApplyWithRollbackContinuesAndSavesStateWhenDestroyFails(t *testing.T) {
+	e, mp, cleanup := setupTests(map[string]error{"k3s": fmt.Errorf("boom")})
+	defer cleanup()
+
+	_, err := e.ApplyWithOptions([]string{"../../examples/single_k3s_cluster"}, ApplyOptions{Rollback: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unable to roll back")
+
+	// the network is destroyed even though the cluster could not be
+	testAssertMethodCalled(t, mp, "Destroy", 2)
+
+	c := config.New()
+	err = c.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	assert.Len(t, c.Resources, 1)
+	assert.Equal(t, "k3s", c.Resources[0].Info().Name)
+	assert.Equal(t, config.Failed, c.Resources[0].Info().Status)
+}
+
+func TestApplyWithRollbackRemovesStateWhenNoPreviousState(t *testing.T) {
+	e, _, cleanup := setupRollbackTests("k3s", "")
+	defer cleanup()
+
+	_, err := e.ApplyWithOptions([]string{"../../examples/single_k3s_cluster"}, ApplyOptions{Rollback: true})
+	assert.Error(t, err)
+
+	assert.NoFileExists(t, utils.StatePath())
+	assert.Equal(t, 0, e.ResourceCount())
+}
+
+func TestApplyWithRollbackRestoresPreviousState(t *testing.T) {
+	e, _, cleanup := setupRollbackTests("k3s", driftState)
+	defer cleanup()
+
+	_, err := e.ApplyWithOptions([]string{"../../examples/single_k3s_cluster"}, ApplyOptions{Rollback: true})
+	assert.Error(t, err)
+
+	d, err := ioutil.ReadFile(utils.StatePath())
+	assert.NoError(t, err)
+	assert.Equal(t, driftState, string(d))
+
+	assert.FileExists(t, utils.StateBackupPath())
+}
+
+func TestApplyWithoutRollbackKeepsCreatedResources(t *testing.T) {
+	e, mp, cleanup := setupTests(map[string]error{"k3s": fmt.Errorf("boom")})
+	defer cleanup()
+
+	_, err := e.ApplyWithOptions([]string{"../../examples/single_k3s_cluster"}, ApplyOptions{})
+	assert.Error(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+
+	_, err = os.Stat(utils.StateBackupPath())
+	assert.True(t, os.IsNotExist(err))
+}
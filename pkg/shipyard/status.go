@@ -0,0 +1,59 @@
+package shipyard
+
+import (
+	"fmt"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+)
+
+// StatusUnknown is returned by ResourceStatus and Statuses for resources
+// which do not exist in the loaded config
+const StatusUnknown = "unknown"
+
+// ResourceStatus returns the status of the resource with the given id
+// e.g. container.consul from the config loaded by the engine.
+// If the resource does not exist StatusUnknown and an error are returned.
+func (e *EngineImpl) ResourceStatus(id string) (string, error) {
+	e.sync.Lock()
+	defer e.sync.Unlock()
+
+	if e.config == nil {
+		return StatusUnknown, config.ResourceNotFoundError{Name: id}
+	}
+
+	r, err := e.config.FindResource(id)
+	if err != nil {
+		return StatusUnknown, err
+	}
+
+	return resourceStatus(r), nil
+}
+
+// Statuses returns the status of every resource in the config loaded by
+// the engine keyed by the resource id e.g. container.consul
+func (e *EngineImpl) Statuses() map[string]string {
+	e.sync.Lock()
+	defer e.sync.Unlock()
+
+	statuses := map[string]string{}
+	if e.config == nil {
+		return statuses
+	}
+
+	for _, r := range e.config.Resources {
+		statuses[fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name)] = resourceStatus(r)
+	}
+
+	return statuses
+}
+
+// resourceStatus returns the status for a resource, resources which have
+// not yet been processed by the engine have no status and are reported as
+// pending creation
+func resourceStatus(r config.Resource) string {
+	if r.Info().Status == "" {
+		return string(config.PendingCreation)
+	}
+
+	return string(r.Info().Status)
+}
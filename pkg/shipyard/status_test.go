@@ -0,0 +1,80 @@
+package shipyard
+
+import (
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupStatusTests(t *testing.T) (Engine, func()) {
+	e, _, cleanup := setupTestsWithState(nil, "")
+
+	c := config.New()
+
+	con := config.NewContainer("consul")
+	con.Status = config.Applied
+	c.AddResource(con)
+
+	h := config.NewHelm("vault")
+	h.Status = config.Failed
+	c.AddResource(h)
+
+	c.AddResource(config.NewNetwork("cloud"))
+
+	e.(*EngineImpl).config = c
+
+	return e, cleanup
+}
+
+func TestResourceStatusReturnsStatus(t *testing.T) {
+	e, cleanup := setupStatusTests(t)
+	defer cleanup()
+
+	s, err := e.ResourceStatus("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, string(config.Applied), s)
+
+	s, err = e.ResourceStatus("helm.vault")
+	assert.NoError(t, err)
+	assert.Equal(t, string(config.Failed), s)
+}
+
+func TestResourceStatusReturnsPendingWhenNotProcessed(t *testing.T) {
+	e, cleanup := setupStatusTests(t)
+	defer cleanup()
+
+	s, err := e.ResourceStatus("network.cloud")
+	assert.NoError(t, err)
+	assert.Equal(t, string(config.PendingCreation), s)
+}
+
+func TestResourceStatusReturnsUnknownWhenNotFound(t *testing.T) {
+	e, cleanup := setupStatusTests(t)
+	defer cleanup()
+
+	s, err := e.ResourceStatus("container.missing")
+	assert.Error(t, err)
+	assert.Equal(t, StatusUnknown, s)
+}
+
+func TestResourceStatusReturnsUnknownWhenNoConfig(t *testing.T) {
+	e, _, cleanup := setupTestsWithState(nil, "")
+	defer cleanup()
+
+	s, err := e.ResourceStatus("container.consul")
+	assert.Error(t, err)
+	assert.Equal(t, StatusUnknown, s)
+}
+
+func TestStatusesReturnsAllResources(t *testing.T) {
+	e, cleanup := setupStatusTests(t)
+	defer cleanup()
+
+	s := e.Statuses()
+	assert.Equal(t, map[string]string{
+		"container.consul": string(config.Applied),
+		"helm.vault":       string(config.Failed),
+		"network.cloud":    string(config.PendingCreation),
+	}, s)
+}
@@ -0,0 +1,209 @@
+package shipyard
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform/dag"
+	"github.com/shipyard-run/shipyard/pkg/config"
+)
+
+// ApplyStrategy defines the order in which resources are created
+type ApplyStrategy string
+
+// StrategyDependency creates resources as soon as their dependencies have been created
+const StrategyDependency ApplyStrategy = "dependency"
+
+// StrategyTypeGrouped creates all networks, then all clusters, then all containers
+const StrategyTypeGrouped ApplyStrategy = "type-grouped"
+
+// StrategyFileOrder creates resources in the order they are declared in the blueprint
+const StrategyFileOrder ApplyStrategy = "file-order"
+
+// ApplyOptions defines the options for ApplyWithOptions
+type ApplyOptions struct {
+	// Variables to set when parsing the configuration
+	Variables map[string]string
+	// VariablesFile is a file containing variable values
+	VariablesFile string
+	// Strategy defines the order resources are created, defaults to StrategyDependency
+	Strategy ApplyStrategy
+	// RefreshImages pulls all images again and clears the image cache so that
+	// the latest version of images with unchanged tags is used
+	RefreshImages bool
+	// RecreateDrifted recreates applied resources which can no longer be found
+	RecreateDrifted bool
+	// Targets limits the apply to the resources with the given ids, e.g. container.app,
+	// and the resources they depend on
+	Targets []string
+	// Rollback destroys the resources created by the apply and restores the
+	// previous state when the apply fails, the previous state is kept in the
+	// state backup. Resources which existed before the apply and the
+	// image cache are not destroyed.
+	Rollback bool
+}
+
+// typeGroups are the groups of resource types used by StrategyTypeGrouped,
+// all resources in a group are created before the resources in the next group.
+// Types which are not in a group are only ordered by their dependencies.
+var typeGroups = [][]config.ResourceType{
+	{config.TypeNetwork},
+	{config.TypeK8sCluster, config.TypeNomadCluster},
+	{config.TypeContainer, config.TypeSidecar},
+}
+
+// orderGraph adds edges to the graph so the resources are walked using the
+// given strategy. Edges are only added when they do not create a cycle,
+// the dependencies between resources always take precedence.
+func orderGraph(d *dag.AcyclicGraph, c *config.Config, s ApplyStrategy) error {
+	switch s {
+	case "", StrategyDependency:
+		return nil
+
+	case StrategyFileOrder:
+		var prev config.Resource
+		for _, r := range c.Resources {
+			if prev != nil {
+				addOrderingEdge(d, prev, r)
+			}
+
+			prev = r
+		}
+
+	case StrategyTypeGrouped:
+		prev := []config.Resource{}
+		for _, g := range typeGroups {
+			group := []config.Resource{}
+			for _, t := range g {
+				group = append(group, c.FindResourcesByType(string(t))...)
+			}
+
+			if len(group) == 0 {
+				continue
+			}
+
+			for _, p := range prev {
+				for _, r := range group {
+					addOrderingEdge(d, p, r)
+				}
+			}
+
+			prev = group
+		}
+
+	default:
+		return fmt.Errorf("Unknown apply strategy %s, strategy must be one of %s, %s, %s", s, StrategyDependency, StrategyTypeGrouped, StrategyFileOrder)
+	}
+
+	return nil
+}
+
+// orderPriority adds edges to the graph so that resources at the same depth
+// in the dependency graph are walked in order of their priority, higher
+// priorities first. Resources with the same priority are walked in order of
+// their name. Depths where no resource sets a priority are not ordered so
+// those resources are walked concurrently.
+func orderPriority(d *dag.AcyclicGraph, c *config.Config) {
+	depths := map[dag.Vertex]int{}
+
+	var depth func(v dag.Vertex) int
+	depth = func(v dag.Vertex) int {
+		if dp, ok := depths[v]; ok {
+			return dp
+		}
+
+		dp := 0
+		for _, u := range d.UpEdges(v).List() {
+			if ud := depth(u) + 1; ud > dp {
+				dp = ud
+			}
+		}
+
+		depths[v] = dp
+		return dp
+	}
+
+	// group the resources at each depth by priority
+	levels := map[int]map[int][]config.Resource{}
+	for _, r := range c.Resources {
+		dp := depth(r)
+		if levels[dp] == nil {
+			levels[dp] = map[int][]config.Resource{}
+		}
+
+		levels[dp][r.Info().Priority] = append(levels[dp][r.Info().Priority], r)
+	}
+
+	for _, priorities := range levels {
+		if _, ok := priorities[0]; ok && len(priorities) == 1 {
+			continue
+		}
+
+		order := []int{}
+		for p := range priorities {
+			order = append(order, p)
+		}
+
+		sort.Sort(sort.Reverse(sort.IntSlice(order)))
+
+		for _, p := range order {
+			rs := priorities[p]
+			sort.Slice(rs, func(i, j int) bool {
+				if rs[i].Info().Name == rs[j].Info().Name {
+					return rs[i].Info().Type < rs[j].Info().Type
+				}
+
+				return rs[i].Info().Name < rs[j].Info().Name
+			})
+
+			for i := 1; i < len(rs); i++ {
+				addOrderingEdge(d, rs[i-1], rs[i])
+			}
+		}
+
+		for i := 1; i < len(order); i++ {
+			for _, before := range priorities[order[i-1]] {
+				for _, after := range priorities[order[i]] {
+					addOrderingEdge(d, before, after)
+				}
+			}
+		}
+	}
+}
+
+// addOrderingEdge ensures before is walked before after unless
+// after must already be created before
+func addOrderingEdge(d *dag.AcyclicGraph, before, after config.Resource) {
+	if before == after || walksBefore(d, after, before) {
+		return
+	}
+
+	d.Connect(dag.BasicEdge(before, after))
+}
+
+// walksBefore returns true when there is a path in the graph from
+// the vertex from to the vertex to
+func walksBefore(d *dag.AcyclicGraph, from, to dag.Vertex) bool {
+	visited := map[dag.Vertex]bool{}
+	next := []dag.Vertex{from}
+
+	for len(next) > 0 {
+		v := next[0]
+		next = next[1:]
+
+		if v == to {
+			return true
+		}
+
+		if visited[v] {
+			continue
+		}
+		visited[v] = true
+
+		for _, n := range d.DownEdges(v).List() {
+			next = append(next, n)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,129 @@
+package shipyard
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/dag"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStrategyConfig(t *testing.T) (*config.Config, *dag.AcyclicGraph) {
+	c := config.New()
+
+	// the container is declared before the network it depends on
+	co := config.NewContainer("consul")
+	co.DependsOn = []string{"network.cloud"}
+	c.AddResource(co)
+
+	c.AddResource(config.NewNetwork("cloud"))
+	c.AddResource(config.NewK8sCluster("k3s"))
+	c.AddResource(config.NewContainer("vault"))
+
+	d, err := c.DoYaLikeDAGs()
+	require.NoError(t, err)
+
+	return c, d
+}
+
+func TestOrderGraphDependencyDoesNotAddEdges(t *testing.T) {
+	c, d := setupStrategyConfig(t)
+	edges := len(d.Edges())
+
+	err := orderGraph(d, c, StrategyDependency)
+	assert.NoError(t, err)
+
+	assert.Len(t, d.Edges(), edges)
+}
+
+func TestOrderGraphTypeGroupedOrdersByType(t *testing.T) {
+	c, d := setupStrategyConfig(t)
+
+	err := orderGraph(d, c, StrategyTypeGrouped)
+	assert.NoError(t, err)
+
+	net, _ := c.FindResource("network.cloud")
+	k3s, _ := c.FindResource("k8s_cluster.k3s")
+	vault, _ := c.FindResource("container.vault")
+
+	assert.True(t, walksBefore(d, net, k3s))
+	assert.True(t, walksBefore(d, k3s, vault))
+	assert.NoError(t, d.Validate())
+}
+
+func TestOrderGraphFileOrderDoesNotViolateDependencies(t *testing.T) {
+	c, d := setupStrategyConfig(t)
+
+	err := orderGraph(d, c, StrategyFileOrder)
+	assert.NoError(t, err)
+
+	consul, _ := c.FindResource("container.consul")
+	net, _ := c.FindResource("network.cloud")
+	k3s, _ := c.FindResource("k8s_cluster.k3s")
+	vault, _ := c.FindResource("container.vault")
+
+	// the dependency is kept and the reverse file order edge is not added
+	assert.True(t, walksBefore(d, net, consul))
+	assert.False(t, walksBefore(d, consul, net))
+
+	assert.True(t, walksBefore(d, net, k3s))
+	assert.True(t, walksBefore(d, k3s, vault))
+	assert.NoError(t, d.Validate())
+}
+
+func TestOrderGraphUnknownStrategyReturnsError(t *testing.T) {
+	c, d := setupStrategyConfig(t)
+
+	err := orderGraph(d, c, "random")
+	assert.Error(t, err)
+}
+
+func TestOrderPriorityDoesNotAddEdgesForEqualPriorities(t *testing.T) {
+	c, d := setupStrategyConfig(t)
+	edges := len(d.Edges())
+
+	orderPriority(d, c)
+
+	assert.Len(t, d.Edges(), edges)
+}
+
+func TestOrderPriorityOrdersResourcesAtSameDepth(t *testing.T) {
+	c, d := setupStrategyConfig(t)
+
+	vault, _ := c.FindResource("container.vault")
+	vault.Info().Priority = 10
+
+	k3s, _ := c.FindResource("k8s_cluster.k3s")
+	k3s.Info().Priority = 5
+
+	orderPriority(d, c)
+
+	net, _ := c.FindResource("network.cloud")
+	consul, _ := c.FindResource("container.consul")
+
+	assert.True(t, walksBefore(d, vault, k3s))
+	assert.True(t, walksBefore(d, k3s, net))
+
+	// consul is not at the same depth and is only ordered by its dependencies
+	assert.False(t, walksBefore(d, consul, vault))
+	assert.NoError(t, d.Validate())
+}
+
+func TestOrderPriorityOrdersEqualPrioritiesByName(t *testing.T) {
+	c, d := setupStrategyConfig(t)
+
+	vault, _ := c.FindResource("container.vault")
+	vault.Info().Priority = 10
+
+	orderPriority(d, c)
+
+	net, _ := c.FindResource("network.cloud")
+	k3s, _ := c.FindResource("k8s_cluster.k3s")
+
+	// cloud and k3s have the same priority
+	assert.True(t, walksBefore(d, net, k3s))
+	assert.False(t, walksBefore(d, k3s, net))
+	assert.True(t, walksBefore(d, vault, net))
+	assert.NoError(t, d.Validate())
+}
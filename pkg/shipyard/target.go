@@ -0,0 +1,85 @@
+package shipyard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// ApplyTarget applies only the resources with the given ids, e.g. container.app,
+// and the resources they depend on. Other resources are not changed.
+func (e *EngineImpl) ApplyTarget(path string, targets []string, variables map[string]string, variablesFile string) ([]config.Resource, error) {
+	return e.ApplyWithOptions([]string{path}, ApplyOptions{Variables: variables, VariablesFile: variablesFile, Targets: targets})
+}
+
+// targetResources returns the resources with the given ids and all of their
+// transitive dependencies. The image cache is always included as it is
+// required to pull images for any targeted resource.
+func targetResources(c *config.Config, targets []string) (map[config.Resource]bool, error) {
+	set := map[config.Resource]bool{}
+
+	var add func(r config.Resource) error
+	add = func(r config.Resource) error {
+		if set[r] {
+			return nil
+		}
+
+		set[r] = true
+
+		for _, d := range r.Info().DependsOn {
+			dr, err := r.FindDependentResource(d)
+			if err != nil {
+				return xerrors.Errorf("Unable to find dependency %s for resource %s.%s: %w", d, r.Info().Type, r.Info().Name, err)
+			}
+
+			err = add(dr)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, t := range targets {
+		r, err := c.FindResource(strings.TrimPrefix(t, "resource."))
+		if err != nil {
+			return nil, fmt.Errorf("Unable to find target %s: %s", t, err)
+		}
+
+		err = add(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range c.FindResourcesByType(string(config.TypeImageCache)) {
+		err := add(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return set, nil
+}
+
+// restoreUntargeted resets the resources which were not part of a targeted
+// apply so that the state records them as they were before the apply
+func (e *EngineImpl) restoreUntargeted(targeted map[config.Resource]bool) {
+	for _, r := range append([]config.Resource{}, e.config.Resources...) {
+		if targeted[r] {
+			continue
+		}
+
+		switch r.Info().Status {
+		case config.PendingUpdate:
+			// previously applied and not changed by this apply
+			r.Info().Status = config.Applied
+		case config.PendingCreation:
+			// new resources which have not been created are not added to the state
+			e.config.RemoveResource(r)
+		}
+	}
+}
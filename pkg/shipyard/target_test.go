@@ -0,0 +1,58 @@
+package shipyard
+
+import (
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestApplyTargetCreatesTargetAndDependencies(t *testing.T) {
+	e, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	_, err := e.ApplyTarget("../../examples/single_k3s_cluster", []string{"k8s_cluster.k3s"}, nil, "")
+	assert.NoError(t, err)
+
+	// network, cluster, and image cache
+	testAssertMethodCalled(t, mp, "Create", 3)
+}
+
+func TestApplyTargetDoesNotSaveUntargetedResources(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	_, err := e.ApplyTarget("../../examples/single_k3s_cluster", []string{"resource.k8s_cluster.k3s"}, nil, "")
+	assert.NoError(t, err)
+
+	c := config.New()
+	err = c.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("k8s_cluster.k3s")
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("helm.vault")
+	assert.Error(t, err)
+}
+
+func TestApplyTargetLeavesAppliedResourcesUnchanged(t *testing.T) {
+	e, mp, cleanup := setupTestsWithState(nil, failedState)
+	defer cleanup()
+
+	_, err := e.ApplyTarget("../../examples/single_k3s_cluster", []string{"network.cloud"}, nil, "")
+	assert.NoError(t, err)
+
+	for _, p := range *mp {
+		assert.NotEqual(t, "dc1", p.Config().Info().Name)
+	}
+}
+
+func TestApplyTargetReturnsErrorForUnknownTarget(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	_, err := e.ApplyTarget("../../examples/single_k3s_cluster", []string{"container.missing"}, nil, "")
+	assert.Error(t, err)
+}
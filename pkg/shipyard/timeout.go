@@ -0,0 +1,79 @@
+package shipyard
+
+import (
+	"context"
+	"time"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// defaultResourceTimeout is the time to wait for a resource to be created or
+// destroyed when the resource does not set a timeout
+var defaultResourceTimeout = 300 * time.Second
+
+// timeoutGracePeriod is the time to wait for an operation to stop after its
+// context has been cancelled, not every provider is able to interrupt the
+// operation so the engine must not block forever
+var timeoutGracePeriod = 30 * time.Second
+
+// resourceTimeout returns the timeout for operations on the resource
+func resourceTimeout(r config.Resource) (time.Duration, error) {
+	t := r.Info().Timeout
+
+	// exec_local and k8s_wait have their own timeout attribute which replaces
+	// the timeout in the resource info
+	switch v := r.(type) {
+	case *config.ExecLocal:
+		t = v.Timeout
+	case *config.K8sWait:
+		t = v.Timeout
+	}
+
+	if t == "" {
+		return defaultResourceTimeout, nil
+	}
+
+	d, err := time.ParseDuration(t)
+	if err != nil {
+		return 0, xerrors.Errorf("Unable to parse timeout for resource %s.%s: %w", r.Info().Type, r.Info().Name, err)
+	}
+
+	return d, nil
+}
+
+// withTimeout runs the provider operation f, returning an error when the
+// operation does not complete before the timeout for the resource.
+// The context passed to the operation is cancelled when the timeout elapses,
+// withTimeout then waits up to the grace period for the operation to stop
+// before returning the error.
+func (e *EngineImpl) withTimeout(r config.Resource, operation string, f func(ctx context.Context) error) error {
+	t, err := resourceTimeout(r)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- f(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		e.log.Error("Timeout waiting for resource, waiting for operation to stop", "ref", r.Info().Name, "type", r.Info().Type, "operation", operation, "timeout", t)
+
+		// wait for the provider to observe the cancellation
+		select {
+		case <-done:
+		case <-time.After(timeoutGracePeriod):
+			e.log.Warn("Operation did not stop after timeout", "ref", r.Info().Name, "type", r.Info().Type, "operation", operation, "grace_period", timeoutGracePeriod)
+		}
+
+		return xerrors.Errorf("Timeout after %s waiting for %s of resource %s.%s: %w", t, operation, r.Info().Type, r.Info().Name, ctx.Err())
+	}
+}
@@ -0,0 +1,123 @@
+package shipyard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestResourceTimeoutReturnsDefault(t *testing.T) {
+	d, err := resourceTimeout(config.NewContainer("test"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, defaultResourceTimeout, d)
+}
+
+func TestResourceTimeoutReturnsResourceTimeout(t *testing.T) {
+	c := config.NewContainer("test")
+	c.Timeout = "30s"
+
+	d, err := resourceTimeout(c)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 30*time.Second, d)
+}
+
+func TestResourceTimeoutReturnsExecLocalTimeout(t *testing.T) {
+	c := config.NewExecLocal("test")
+	c.Timeout = "30m"
+
+	d, err := resourceTimeout(c)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 30*time.Minute, d)
+}
+
+func TestResourceTimeoutReturnsK8sWaitTimeout(t *testing.T) {
+	c := config.NewK8sWait("test")
+	c.Timeout = "2m"
+
+	d, err := resourceTimeout(c)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2*time.Minute, d)
+}
+
+func TestResourceTimeoutReturnsErrorWhenInvalid(t *testing.T) {
+	c := config.NewContainer("test")
+	c.Timeout = "thirty"
+
+	_, err := resourceTimeout(c)
+	assert.Error(t, err)
+}
+
+func TestWithTimeoutReturnsOperationError(t *testing.T) {
+	e := &EngineImpl{log: hclog.NewNullLogger()}
+
+	err := e.withTimeout(config.NewContainer("test"), "create", func(ctx context.Context) error {
+		return fmt.Errorf("boom")
+	})
+
+	assert.EqualError(t, err, "boom")
+}
+
+func TestWithTimeoutReturnsErrorWhenOperationTimesOut(t *testing.T) {
+	e := &EngineImpl{log: hclog.NewNullLogger()}
+	c := config.NewContainer("test")
+	c.Timeout = "10ms"
+
+	err := e.withTimeout(c, "create", func(ctx context.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Timeout after 10ms")
+}
+
+func TestWithTimeoutCancelsContextAndWaitsForOperation(t *testing.T) {
+	e := &EngineImpl{log: hclog.NewNullLogger()}
+	c := config.NewContainer("test")
+	c.Timeout = "10ms"
+
+	returned := false
+	err := e.withTimeout(c, "create", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		returned = true
+
+		return ctx.Err()
+	})
+
+	assert.Error(t, err)
+	assert.True(t, returned)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestWithTimeoutReturnsAfterGracePeriodWhenOperationDoesNotStop(t *testing.T) {
+	e := &EngineImpl{log: hclog.NewNullLogger()}
+	c := config.NewContainer("test")
+	c.Timeout = "10ms"
+
+	grace := timeoutGracePeriod
+	timeoutGracePeriod = 10 * time.Millisecond
+	defer func() { timeoutGracePeriod = grace }()
+
+	block := make(chan struct{})
+	defer close(block)
+
+	err := e.withTimeout(c, "create", func(ctx context.Context) error {
+		// ignores the context like a provider which can not be interrupted
+		<-block
+		return nil
+	})
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -0,0 +1,95 @@
+package shipyard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// ValidationError is returned by Validate and contains every problem
+// found with the configuration
+type ValidationError struct {
+	Errors []error
+}
+
+func (v *ValidationError) Error() string {
+	msgs := []string{}
+	for _, e := range v.Errors {
+		msgs = append(msgs, e.Error())
+	}
+
+	return fmt.Sprintf("Configuration is invalid:\n  %s", strings.Join(msgs, "\n  "))
+}
+
+// Validate parses the configuration at path and checks the resources and the
+// dependencies between them are valid. The state is never read and the config
+// loaded by the engine is not changed, so Validate can be run on machines
+// which do not have an existing environment.
+func (e *EngineImpl) Validate(path string, variables map[string]string, variablesFile string) error {
+	// check all required variables have values before parsing, the same as apply
+	err := config.CheckRequiredVariables(path, variables, variablesFile)
+	if err != nil {
+		return &ValidationError{Errors: []error{err}}
+	}
+
+	ve := &ValidationError{}
+
+	// every file is parsed so that all the syntax errors are reported together
+	diags, err := config.ParseDiagnostics(path)
+	if err != nil {
+		return &ValidationError{Errors: []error{err}}
+	}
+
+	for _, d := range diags {
+		if d.Severity == hcl.DiagError {
+			ve.Errors = append(ve.Errors, d)
+		}
+	}
+
+	if len(ve.Errors) > 0 {
+		return ve
+	}
+
+	cc, err := parseConfig(path, variables, variablesFile)
+	if err != nil {
+		return &ValidationError{Errors: []error{err}}
+	}
+
+	// check every dependency exists so all the missing dependencies are reported
+	for _, r := range cc.Resources {
+		for _, d := range r.Info().DependsOn {
+			if strings.HasPrefix(d, "module.") {
+				_, err = cc.FindModuleResources(d)
+			} else {
+				_, err = cc.FindResource(d)
+			}
+
+			if err != nil {
+				ve.Errors = append(ve.Errors, fmt.Errorf("Resource %s.%s depends on %s which does not exist", r.Info().Type, r.Info().Name, d))
+			}
+		}
+	}
+
+	if len(ve.Errors) > 0 {
+		return ve
+	}
+
+	d, err := cc.DoYaLikeDAGs()
+	if err != nil {
+		return &ValidationError{Errors: []error{xerrors.Errorf("Unable to create dependency graph: %w", err)}}
+	}
+
+	err = d.Validate()
+	if err != nil {
+		if cycle := findCycle(cc); cycle != nil {
+			return &ValidationError{Errors: []error{CycleError{Cycle: cycle}}}
+		}
+
+		return &ValidationError{Errors: []error{xerrors.Errorf("Unable to validate dependency graph: %w", err)}}
+	}
+
+	return nil
+}
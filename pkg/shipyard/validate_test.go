@@ -0,0 +1,134 @@
+package shipyard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestValidateReturnsNoErrorForValidConfig(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	err := e.Validate("../../examples/single_file/container.hcl", nil, "")
+	assert.NoError(t, err)
+
+	// the engine config and the state must not be changed
+	assert.Nil(t, e.(*EngineImpl).config)
+	assert.NoFileExists(t, utils.StatePath())
+}
+
+func TestValidateReturnsErrorForInvalidConfig(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	err := e.Validate("../../examples/invalid", nil, "")
+	assert.Error(t, err)
+
+	_, ok := err.(*ValidationError)
+	assert.True(t, ok)
+}
+
+func TestValidateReturnsAllMissingDependencies(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	dir := t.TempDir()
+	bp := filepath.Join(dir, "blueprint.hcl")
+	err := ioutil.WriteFile(bp, []byte(validateMissingDeps), os.ModePerm)
+	assert.NoError(t, err)
+
+	err = e.Validate(bp, nil, "")
+	assert.Error(t, err)
+
+	ve, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Len(t, ve.Errors, 2)
+}
+
+func TestValidateReturnsAllSyntaxErrors(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	dir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "consul.hcl"), []byte(validateSyntaxError), os.ModePerm)
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "vault.hcl"), []byte(validateSyntaxError), os.ModePerm)
+	assert.NoError(t, err)
+
+	err = e.Validate(dir, nil, "")
+	assert.Error(t, err)
+
+	ve, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Len(t, ve.Errors, 2)
+}
+
+func TestValidateReturnsErrorForMissingVariables(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	dir := t.TempDir()
+	bp := filepath.Join(dir, "blueprint.hcl")
+	err := ioutil.WriteFile(bp, []byte(validateRequiredVariable), os.ModePerm)
+	assert.NoError(t, err)
+
+	err = e.Validate(bp, nil, "")
+	assert.Error(t, err)
+
+	ve, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.IsType(t, config.MissingVariablesError{}, ve.Errors[0])
+
+	err = e.Validate(bp, map[string]string{"version": "1.8.1"}, "")
+	assert.NoError(t, err)
+}
+
+func TestValidateDoesNotReadState(t *testing.T) {
+	e, _, cleanup := setupTestsWithState(nil, "not json")
+	defer cleanup()
+
+	err := e.Validate("../../examples/single_file/container.hcl", nil, "")
+	assert.NoError(t, err)
+}
+
+var validateMissingDeps = `
+container "consul" {
+  depends_on = ["network.missing"]
+
+  image {
+    name = "consul:1.8.1"
+  }
+}
+
+container "vault" {
+  depends_on = ["container.missing"]
+
+  image {
+    name = "vault:1.6.1"
+  }
+}
+`
+
+var validateSyntaxError = `
+container "consul" {
+  image {
+    name = "consul:1.8.1"
+  }
+`
+
+var validateRequiredVariable = `
+variable "version" {}
+
+container "consul" {
+  image {
+    name = "consul:${var.version}"
+  }
+}
+`
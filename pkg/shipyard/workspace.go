@@ -0,0 +1,113 @@
+package shipyard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// Workspace selects the workspace used by the engine. Each workspace has its
+// own state file and container names so that more than one copy of the same
+// blueprint can run at once, changes only affect the selected workspace.
+func (e *EngineImpl) Workspace(name string) error {
+	if ok, err := utils.ValidateName(name); !ok {
+		return xerrors.Errorf("Invalid workspace name %s: %w", name, err)
+	}
+
+	e.sync.Lock()
+	defer e.sync.Unlock()
+
+	if name != utils.DefaultWorkspace {
+		err := os.MkdirAll(utils.WorkspaceStateDir(name), os.ModePerm)
+		if err != nil {
+			return xerrors.Errorf("Unable to create workspace %s: %w", name, err)
+		}
+	}
+
+	e.workspace = name
+
+	// container names, labels and networks include the workspace
+	if e.clients != nil && e.clients.ContainerTasks != nil {
+		e.clients.ContainerTasks.SetWorkspace(name)
+	}
+
+	// the loaded config belongs to the previous workspace
+	e.config = nil
+
+	return nil
+}
+
+// currentWorkspace returns the name of the workspace selected for the engine
+func (e *EngineImpl) currentWorkspace() string {
+	if e.workspace == "" {
+		return utils.DefaultWorkspace
+	}
+
+	return e.workspace
+}
+
+// statePath returns the location of the state file for the selected workspace
+func (e *EngineImpl) statePath() string {
+	if e.stateFile != "" {
+		return e.stateFile
+	}
+
+	return utils.WorkspaceStatePath(e.currentWorkspace())
+}
+
+// stateDir returns the folder containing the state for the selected workspace
+func (e *EngineImpl) stateDir() string {
+	if e.stateFile != "" {
+		return filepath.Dir(e.stateFile)
+	}
+
+	return utils.WorkspaceStateDir(e.currentWorkspace())
+}
+
+// stateLockPath returns the location of the lock file for the selected workspace
+func (e *EngineImpl) stateLockPath() string {
+	if e.stateFile != "" {
+		return e.stateFile + ".lock"
+	}
+
+	return utils.WorkspaceStateLockPath(e.currentWorkspace())
+}
+
+// stateBackupPath returns the location of the state backup for the selected workspace
+func (e *EngineImpl) stateBackupPath() string {
+	if e.stateFile != "" {
+		return e.stateFile + ".bak"
+	}
+
+	return utils.WorkspaceStateBackupPath(e.currentWorkspace())
+}
+
+// ListWorkspaces returns the names of the workspaces which have been created,
+// the default workspace is always returned
+func (e *EngineImpl) ListWorkspaces() ([]string, error) {
+	ws := []string{utils.DefaultWorkspace}
+
+	fi, err := ioutil.ReadDir(utils.WorkspacesDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ws, nil
+		}
+
+		return nil, xerrors.Errorf("Unable to list workspaces: %w", err)
+	}
+
+	names := []string{}
+	for _, f := range fi {
+		if f.IsDir() {
+			names = append(names, f.Name())
+		}
+	}
+
+	sort.Strings(names)
+
+	return append(ws, names...), nil
+}
@@ -0,0 +1,85 @@
+package shipyard
+
+import (
+	"io/ioutil"
+	"testing"
+
+	clientMocks "github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceWithInvalidNameReturnsError(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	err := e.Workspace("dev.test")
+	assert.Error(t, err)
+}
+
+func TestListWorkspacesReturnsDefaultAndCreatedWorkspaces(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	ws, err := e.ListWorkspaces()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default"}, ws)
+
+	err = e.Workspace("test")
+	assert.NoError(t, err)
+
+	err = e.Workspace("dev")
+	assert.NoError(t, err)
+
+	ws, err = e.ListWorkspaces()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default", "dev", "test"}, ws)
+}
+
+func TestDestroyResourceInWorkspaceOnlyChangesWorkspaceState(t *testing.T) {
+	e, _, cleanup := setupTestsWithState(nil, destroyResourceState)
+	defer cleanup()
+
+	defaultState := e.(*EngineImpl).statePath()
+
+	err := e.Workspace("dev")
+	assert.NoError(t, err)
+	assert.NotEqual(t, defaultState, e.(*EngineImpl).statePath())
+
+	// the workspace is kept on the engine not the process
+	assert.Equal(t, defaultState, utils.StatePath())
+
+	err = ioutil.WriteFile(e.(*EngineImpl).statePath(), []byte(destroyResourceState), 0644)
+	assert.NoError(t, err)
+
+	err = e.DestroyResource("container.consul", false)
+	assert.NoError(t, err)
+
+	c := config.New()
+	err = c.FromJSON(e.(*EngineImpl).statePath())
+	assert.NoError(t, err)
+	_, err = c.FindResource("container.consul")
+	assert.Error(t, err)
+
+	c = config.New()
+	err = c.FromJSON(defaultState)
+	assert.NoError(t, err)
+	_, err = c.FindResource("container.consul")
+	assert.NoError(t, err)
+}
+
+func TestWorkspaceSetsWorkspaceForContainerTasks(t *testing.T) {
+	e, _, cleanup := setupTests(nil)
+	defer cleanup()
+
+	mc := &clientMocks.MockContainerTasks{}
+	mc.On("SetWorkspace", "dev")
+	e.(*EngineImpl).clients.ContainerTasks = mc
+
+	err := e.Workspace("dev")
+	assert.NoError(t, err)
+
+	mc.AssertCalled(t, "SetWorkspace", "dev")
+	assert.Equal(t, utils.DefaultWorkspace, utils.Workspace())
+}
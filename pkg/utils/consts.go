@@ -12,6 +12,9 @@ const ImageVolumeName string = "images"
 // Name of the Cache resource
 const CacheResourceName string = "docker-cache"
 
+// Type of the Cache resource, this must match config.TypeImageCache
+const CacheResourceType string = "image_cache"
+
 // Address of the proxy used for caching docker images
 const ProxyAddress string = "http://docker-cache.image-cache.shipyard.run:3128"
 
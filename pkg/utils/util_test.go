@@ -102,6 +102,21 @@ func TestFQDNReplacesInvalidChars(t *testing.T) {
 	assert.Equal(t, "tes-t.k8s-cluster.shipyard.run", fq)
 }
 
+func TestFQDNInWorkspaceIncludesWorkspace(t *testing.T) {
+	os.Setenv(WorkspaceEnvName, "dev")
+	defer os.Unsetenv(WorkspaceEnvName)
+
+	assert.Equal(t, "test.type.dev.shipyard.run", FQDN("test", "type"))
+	assert.Equal(t, "docker-cache.image-cache.shipyard.run", FQDN(CacheResourceName, CacheResourceType))
+}
+
+func TestStatePathInWorkspaceReturnsWorkspaceState(t *testing.T) {
+	os.Setenv(WorkspaceEnvName, "dev")
+	defer os.Unsetenv(WorkspaceEnvName)
+
+	assert.Equal(t, filepath.Join(ShipyardHome(), "workspaces", "dev", "state.json"), StatePath())
+}
+
 func TestFQDNVolumeReturnsCorrectValue(t *testing.T) {
 	fq := FQDNVolumeName("test")
 	assert.Equal(t, "test.volume.shipyard.run", fq)
@@ -124,6 +139,17 @@ func TestStatePathReturnsCorrectValue(t *testing.T) {
 	assert.Equal(t, filepath.Join(os.Getenv(HomeEnvName()), ".shipyard/state/state.json"), h)
 }
 
+func TestStatePathsUseStatePathEnv(t *testing.T) {
+	sp := filepath.Join(t.TempDir(), "dev.json")
+	os.Setenv(StatePathEnvName, sp)
+	defer os.Unsetenv(StatePathEnvName)
+
+	assert.Equal(t, sp, StatePath())
+	assert.Equal(t, filepath.Dir(sp), StateDir())
+	assert.Equal(t, sp+".lock", StateLockPath())
+	assert.Equal(t, sp+".bak", StateBackupPath())
+}
+
 func TestCreateKubeConfigPathReturnsCorrectValues(t *testing.T) {
 	home := os.Getenv(HomeEnvName())
 	tmp, _ := ioutil.TempDir("", "")
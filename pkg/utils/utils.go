@@ -50,10 +50,23 @@ func ReplaceNonURIChars(s string) (string, error) {
 	return reg.ReplaceAllString(s, "-"), nil
 }
 
-// FQDN generates the full qualified name for a container
+// FQDN generates the full qualified name for a container in the workspace
+// selected by the environment, see WorkspaceFQDN
 func FQDN(name, typeName string) string {
+	return WorkspaceFQDN(name, typeName, Workspace())
+}
+
+// WorkspaceFQDN generates the full qualified name for a container, outside of the
+// default workspace the name includes the workspace so that copies of
+// the same resource in different workspaces do not clash
+func WorkspaceFQDN(name, typeName, workspace string) string {
 	fqdn := fmt.Sprintf("%s.%s.shipyard.run", name, typeName)
 
+	// the image cache is shared by all workspaces
+	if workspace != "" && workspace != DefaultWorkspace && typeName != CacheResourceType {
+		fqdn = fmt.Sprintf("%s.%s.%s.shipyard.run", name, typeName, workspace)
+	}
+
 	// ensure that the name is valid for URI schema
 	cleanName, err := ReplaceNonURIChars(fqdn)
 	if err != nil {
@@ -77,7 +90,7 @@ func FQDNVolumeName(name string) string {
 // CreateKubeConfigPath creates the file path for the KubeConfig file when
 // using Kubernetes cluster
 func CreateKubeConfigPath(name string) (dir, filePath string, dockerPath string) {
-	dir = filepath.Join(ShipyardHome(), "/config/", name)
+	dir = clusterConfigDir(name)
 	filePath = filepath.Join(dir, "/kubeconfig.yaml")
 	dockerPath = filepath.Join(dir, "/kubeconfig-docker.yaml")
 
@@ -105,7 +118,7 @@ func GetClusterConfig(name string) (ClusterConfig, string) {
 		return ClusterConfig{}, ""
 	}
 
-	dir := filepath.Join(ShipyardHome(), "/config/", parts[1])
+	dir := clusterConfigDir(parts[1])
 	filePath := filepath.Join(dir, "/config.json")
 
 	if _, err := os.Stat(filePath); err == nil {
@@ -177,9 +190,69 @@ func ShipyardTemp() string {
 	return dir
 }
 
+// clusterConfigDir returns the folder containing the config files for the
+// cluster with the given name in the active workspace
+func clusterConfigDir(name string) string {
+	if ws := Workspace(); ws != DefaultWorkspace {
+		return filepath.Join(WorkspacesDir(), ws, "/config/", name)
+	}
+
+	return filepath.Join(ShipyardHome(), "/config/", name)
+}
+
+// WorkspaceEnvName is the name of the environment variable which selects the
+// active workspace, each workspace has its own state and resource names
+const WorkspaceEnvName = "SHIPYARD_WORKSPACE"
+
+// DefaultWorkspace is the workspace used when no workspace has been selected
+const DefaultWorkspace = "default"
+
+// Workspace returns the name of the active workspace
+func Workspace() string {
+	if ws := os.Getenv(WorkspaceEnvName); ws != "" {
+		return ws
+	}
+
+	return DefaultWorkspace
+}
+
+// NetworkName returns the name of the Docker network for a network resource, outside
+// of the default workspace the name includes the workspace so that copies of the
+// same network in different workspaces do not clash
+func NetworkName(name, workspace string) string {
+	if workspace == "" || workspace == DefaultWorkspace {
+		return name
+	}
+
+	return fmt.Sprintf("%s.%s", name, workspace)
+}
+
+// WorkspacesDir returns the location of the state for workspaces other than
+// the default workspace, usually $HOME/.shipyard/workspaces
+func WorkspacesDir() string {
+	return filepath.Join(ShipyardHome(), "/workspaces")
+}
+
+// StatePathEnvName is the name of the environment variable which overrides the
+// location of the state file, this allows independent environments on one machine
+const StatePathEnvName = "SHIPYARD_STATE_PATH"
+
 // StateDir returns the location of the shipyard
 // state, usually $HOME/.shipyard/state
 func StateDir() string {
+	return WorkspaceStateDir(Workspace())
+}
+
+// WorkspaceStateDir returns the location of the state for the given workspace
+func WorkspaceStateDir(workspace string) string {
+	if sp := os.Getenv(StatePathEnvName); sp != "" {
+		return filepath.Dir(sp)
+	}
+
+	if workspace != "" && workspace != DefaultWorkspace {
+		return filepath.Join(WorkspacesDir(), workspace)
+	}
+
 	return filepath.Join(ShipyardHome(), "/state")
 }
 
@@ -205,7 +278,54 @@ func LogsDir() string {
 
 // StatePath returns the full path for the state file
 func StatePath() string {
-	return filepath.Join(StateDir(), "/state.json")
+	return WorkspaceStatePath(Workspace())
+}
+
+// WorkspaceStatePath returns the full path for the state file in the given workspace
+func WorkspaceStatePath(workspace string) string {
+	if sp := os.Getenv(StatePathEnvName); sp != "" {
+		return sp
+	}
+
+	return filepath.Join(WorkspaceStateDir(workspace), "/state.json")
+}
+
+// StateLockPath returns the location of the lock file which prevents
+// concurrent changes to the state
+func StateLockPath() string {
+	return WorkspaceStateLockPath(Workspace())
+}
+
+// WorkspaceStateLockPath returns the location of the state lock file in the given workspace
+func WorkspaceStateLockPath(workspace string) string {
+	// the lock and backup are kept alongside an overridden state file so that
+	// state files in the same folder do not share them
+	if sp := os.Getenv(StatePathEnvName); sp != "" {
+		return sp + ".lock"
+	}
+
+	return filepath.Join(WorkspaceStateDir(workspace), "/state.lock")
+}
+
+// StateBackupPath returns the location of the copy of the state which is
+// taken before an apply that can be rolled back
+func StateBackupPath() string {
+	return WorkspaceStateBackupPath(Workspace())
+}
+
+// WorkspaceStateBackupPath returns the location of the state backup in the given workspace
+func WorkspaceStateBackupPath(workspace string) string {
+	if sp := os.Getenv(StatePathEnvName); sp != "" {
+		return sp + ".bak"
+	}
+
+	return filepath.Join(WorkspaceStateDir(workspace), "/state.bak")
+}
+
+// ApplyHistoryPath returns the location of the file which records how long
+// resources took to create
+func ApplyHistoryPath() string {
+	return filepath.Join(ShipyardHome(), "history.json")
 }
 
 // ImageCacheLog returns the location of the image cache log
@@ -249,6 +369,25 @@ func IsHCLFile(path string) bool {
 	return true
 }
 
+// IsYAMLFile tests if the given path resolves to a YAML config file
+func IsYAMLFile(path string) bool {
+	s, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	if s.IsDir() {
+		return false
+	}
+
+	switch filepath.Ext(s.Name()) {
+	case ".yaml", ".yml":
+		return true
+	}
+
+	return false
+}
+
 func sanitizeBlueprintFolder(blueprint string) string {
 	blueprint = strings.ReplaceAll(blueprint, "//", "/")
 	blueprint = strings.ReplaceAll(blueprint, "?", "/")